@@ -0,0 +1,152 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+)
+
+// IsDone returns true when the upgrade reached a final phase. A completed
+// upgrade is not done, as it can still be rolled back
+func (upgrade *BlueGreenUpgrade) IsDone() bool {
+	return upgrade.Status.Phase == BlueGreenUpgradePhaseRolledBack ||
+		upgrade.Status.Phase == BlueGreenUpgradePhaseFailed
+}
+
+// IsRollbackRequested returns true when a rollback has been requested
+// and the cutover already started
+func (upgrade *BlueGreenUpgrade) IsRollbackRequested() bool {
+	return upgrade.Spec.Rollback &&
+		(upgrade.IsCutoverInProgress() || upgrade.Status.Phase == BlueGreenUpgradePhaseCompleted)
+}
+
+// GetServingClusterName gets the name of the cluster the services of the
+// upgrade point to, which is the target cluster once the cutover is completed
+func (upgrade *BlueGreenUpgrade) GetServingClusterName() string {
+	if upgrade.Status.Phase == BlueGreenUpgradePhaseCompleted {
+		return upgrade.Spec.TargetClusterName
+	}
+
+	return upgrade.Spec.ClusterRef.Name
+}
+
+// GetServiceReadName gets the name of the service pointing to every
+// instance of the serving cluster
+func (upgrade *BlueGreenUpgrade) GetServiceReadName() string {
+	return fmt.Sprintf("%v%v", upgrade.Name, ServiceReadSuffix)
+}
+
+// GetServiceReadOnlyName gets the name of the service pointing to the
+// replicas of the serving cluster
+func (upgrade *BlueGreenUpgrade) GetServiceReadOnlyName() string {
+	return fmt.Sprintf("%v%v", upgrade.Name, ServiceReadOnlySuffix)
+}
+
+// GetServiceReadWriteName gets the name of the service pointing to the
+// primary of the serving cluster
+func (upgrade *BlueGreenUpgrade) GetServiceReadWriteName() string {
+	return fmt.Sprintf("%v%v", upgrade.Name, ServiceReadWriteSuffix)
+}
+
+// GetServiceAltDNSNames gets the DNS names of the services of the upgrade,
+// for which the server certificates of both clusters must be valid
+func (upgrade *BlueGreenUpgrade) GetServiceAltDNSNames() []string {
+	serviceNames := []string{
+		upgrade.GetServiceReadWriteName(),
+		upgrade.GetServiceReadName(),
+		upgrade.GetServiceReadOnlyName(),
+	}
+
+	altDNSNames := make([]string, 0, 4*len(serviceNames))
+	for _, serviceName := range serviceNames {
+		altDNSNames = append(altDNSNames,
+			serviceName,
+			fmt.Sprintf("%v.%v", serviceName, upgrade.Namespace),
+			fmt.Sprintf("%v.%v.svc", serviceName, upgrade.Namespace),
+			fmt.Sprintf("%v.%v.svc.%s", serviceName, upgrade.Namespace, configuration.Current.KubernetesClusterDomain),
+		)
+	}
+
+	return altDNSNames
+}
+
+// IsCutoverInProgress returns true when the cutover started and is not
+// yet completed
+func (upgrade *BlueGreenUpgrade) IsCutoverInProgress() bool {
+	switch upgrade.Status.Phase {
+	case BlueGreenUpgradePhaseFencingWrites,
+		BlueGreenUpgradePhaseCatchingUp,
+		BlueGreenUpgradePhaseSynchronizingSequences,
+		BlueGreenUpgradePhaseDetaching,
+		BlueGreenUpgradePhaseSwitchingServices:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetAsFailed sets the upgrade as failed with the given error
+func (upgrade *BlueGreenUpgrade) SetAsFailed(err error) {
+	upgrade.Status.Phase = BlueGreenUpgradePhaseFailed
+	upgrade.Status.Message = err.Error()
+}
+
+// GetReplicationObjectName gets the name of the Publication and Subscription
+// objects replicating the database with the given index in the list of the
+// databases to be upgraded
+func (upgrade *BlueGreenUpgrade) GetReplicationObjectName(index int, database string) string {
+	name := fmt.Sprintf("%s-%s", upgrade.Name, database)
+	if len(validation.IsDNS1123Subdomain(name)) == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s-%d", upgrade.Name, index)
+}
+
+// GetReplicationPostgresName gets the name of the publication, the
+// subscription and the replication slot replicating the database with the
+// given index. The name is unique per database, as replication slots are
+// shared by the whole source cluster, and fits PostgreSQL identifiers
+func (upgrade *BlueGreenUpgrade) GetReplicationPostgresName(index int) string {
+	const maxIdentifierLength = 63
+
+	name := strings.NewReplacer("-", "_", ".", "_").Replace(upgrade.Name)
+	suffix := fmt.Sprintf("_%d", index)
+	if len(name)+len(suffix) > maxIdentifierLength {
+		name = name[:maxIdentifierLength-len(suffix)]
+	}
+
+	return name + suffix
+}
+
+// GetTargetImage gets the image name or the image catalog reference that
+// the target cluster will use
+func (upgrade *BlueGreenUpgrade) GetTargetImage() (string, *ImageCatalogRef) {
+	if upgrade.Spec.ImageCatalogRef != nil {
+		return "", upgrade.Spec.ImageCatalogRef.DeepCopy()
+	}
+
+	return upgrade.Spec.ImageName, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BlueGreenUpgrade", func() {
+	var upgrade *BlueGreenUpgrade
+
+	BeforeEach(func() {
+		upgrade = &BlueGreenUpgrade{
+			ObjectMeta: metav1.ObjectMeta{Name: "upgrade-to-18"},
+		}
+	})
+
+	It("detects when the upgrade is done", func() {
+		Expect(upgrade.IsDone()).To(BeFalse())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseReplicating
+		Expect(upgrade.IsDone()).To(BeFalse())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseCompleted
+		Expect(upgrade.IsDone()).To(BeFalse())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseRolledBack
+		Expect(upgrade.IsDone()).To(BeTrue())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseFailed
+		Expect(upgrade.IsDone()).To(BeTrue())
+	})
+
+	It("detects when a rollback is requested", func() {
+		upgrade.Spec.Rollback = true
+		upgrade.Status.Phase = BlueGreenUpgradePhaseReplicating
+		Expect(upgrade.IsRollbackRequested()).To(BeFalse())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseCatchingUp
+		Expect(upgrade.IsRollbackRequested()).To(BeTrue())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseCompleted
+		Expect(upgrade.IsRollbackRequested()).To(BeTrue())
+		upgrade.Spec.Rollback = false
+		Expect(upgrade.IsRollbackRequested()).To(BeFalse())
+	})
+
+	It("points the services to the target cluster once the cutover is completed", func() {
+		upgrade.Spec.ClusterRef.Name = "blue"
+		upgrade.Spec.TargetClusterName = "green"
+		upgrade.Status.Phase = BlueGreenUpgradePhaseSwitchingServices
+		Expect(upgrade.GetServingClusterName()).To(Equal("blue"))
+		upgrade.Status.Phase = BlueGreenUpgradePhaseCompleted
+		Expect(upgrade.GetServingClusterName()).To(Equal("green"))
+		upgrade.Status.Phase = BlueGreenUpgradePhaseRollingBack
+		Expect(upgrade.GetServingClusterName()).To(Equal("blue"))
+	})
+
+	It("names the services after the upgrade", func() {
+		upgrade.Namespace = "default"
+		Expect(upgrade.GetServiceReadWriteName()).To(Equal("upgrade-to-18-rw"))
+		Expect(upgrade.GetServiceReadName()).To(Equal("upgrade-to-18-r"))
+		Expect(upgrade.GetServiceReadOnlyName()).To(Equal("upgrade-to-18-ro"))
+		Expect(upgrade.GetServiceAltDNSNames()).To(HaveLen(12))
+		Expect(upgrade.GetServiceAltDNSNames()).To(ContainElements(
+			"upgrade-to-18-rw",
+			"upgrade-to-18-ro.default",
+			"upgrade-to-18-r.default.svc",
+		))
+	})
+
+	It("detects when the cutover is in progress", func() {
+		Expect(upgrade.IsCutoverInProgress()).To(BeFalse())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseCatchingUp
+		Expect(upgrade.IsCutoverInProgress()).To(BeTrue())
+		upgrade.Status.Phase = BlueGreenUpgradePhaseCompleted
+		Expect(upgrade.IsCutoverInProgress()).To(BeFalse())
+	})
+
+	It("names the replication objects after the database when possible", func() {
+		Expect(upgrade.GetReplicationObjectName(0, "app")).To(Equal("upgrade-to-18-app"))
+		Expect(upgrade.GetReplicationObjectName(1, "My_DB")).To(Equal("upgrade-to-18-1"))
+	})
+
+	It("generates PostgreSQL names valid for replication slots", func() {
+		Expect(upgrade.GetReplicationPostgresName(2)).To(Equal("upgrade_to_18_2"))
+
+		upgrade.Name = strings.Repeat("a", 100)
+		name := upgrade.GetReplicationPostgresName(10)
+		Expect(name).To(HaveLen(63))
+		Expect(name).To(HaveSuffix("a_10"))
+	})
+
+	It("gets the target image", func() {
+		upgrade.Spec.ImageName = "postgres:18"
+		imageName, catalogRef := upgrade.GetTargetImage()
+		Expect(imageName).To(Equal("postgres:18"))
+		Expect(catalogRef).To(BeNil())
+
+		upgrade.Spec.ImageName = ""
+		upgrade.Spec.ImageCatalogRef = &ImageCatalogRef{Major: 18}
+		imageName, catalogRef = upgrade.GetTargetImage()
+		Expect(imageName).To(BeEmpty())
+		Expect(catalogRef.Major).To(Equal(18))
+	})
+})
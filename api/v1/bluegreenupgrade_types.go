@@ -0,0 +1,215 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BlueGreenUpgradePhase is the phase of a blue-green major upgrade
+type BlueGreenUpgradePhase string
+
+const (
+	// BlueGreenUpgradePhaseCreatingCluster means the operator is creating the
+	// target cluster, importing the schema of the source one
+	BlueGreenUpgradePhaseCreatingCluster BlueGreenUpgradePhase = "CreatingCluster"
+
+	// BlueGreenUpgradePhaseReplicating means the databases of the source cluster
+	// are being replicated to the target cluster via logical replication
+	BlueGreenUpgradePhaseReplicating BlueGreenUpgradePhase = "Replicating"
+
+	// BlueGreenUpgradePhaseFencingWrites means the cutover started, and the
+	// client connections to the source cluster are being fenced
+	BlueGreenUpgradePhaseFencingWrites BlueGreenUpgradePhase = "FencingWrites"
+
+	// BlueGreenUpgradePhaseCatchingUp means the operator is waiting for the
+	// subscriptions to receive every change made before the writes were fenced
+	BlueGreenUpgradePhaseCatchingUp BlueGreenUpgradePhase = "CatchingUp"
+
+	// BlueGreenUpgradePhaseSynchronizingSequences means the sequences of the
+	// target cluster are being synchronized with the source ones
+	BlueGreenUpgradePhaseSynchronizingSequences BlueGreenUpgradePhase = "SynchronizingSequences"
+
+	// BlueGreenUpgradePhaseDetaching means the subscriptions of the target
+	// cluster are being dropped
+	BlueGreenUpgradePhaseDetaching BlueGreenUpgradePhase = "Detaching"
+
+	// BlueGreenUpgradePhaseSwitchingServices means the services of the
+	// upgrade and the poolers are being switched to the target cluster
+	BlueGreenUpgradePhaseSwitchingServices BlueGreenUpgradePhase = "SwitchingServices"
+
+	// BlueGreenUpgradePhaseCompleted means the cutover is completed, and the
+	// applications are served by the target cluster
+	BlueGreenUpgradePhaseCompleted BlueGreenUpgradePhase = "Completed"
+
+	// BlueGreenUpgradePhaseRollingBack means a rollback has been requested,
+	// and the applications are being switched back to the source cluster
+	BlueGreenUpgradePhaseRollingBack BlueGreenUpgradePhase = "RollingBack"
+
+	// BlueGreenUpgradePhaseRolledBack means the applications are served by
+	// the source cluster again
+	BlueGreenUpgradePhaseRolledBack BlueGreenUpgradePhase = "RolledBack"
+
+	// BlueGreenUpgradePhaseFailed means the upgrade cannot proceed
+	BlueGreenUpgradePhaseFailed BlueGreenUpgradePhase = "Failed"
+)
+
+// BlueGreenUpgradeSpec defines the desired state of a BlueGreenUpgrade
+// +kubebuilder:validation:XValidation:rule="has(self.imageName) != has(self.imageCatalogRef)",message="exactly one of imageName and imageCatalogRef is required"
+// +kubebuilder:validation:XValidation:rule="self.targetClusterName != self.cluster.name",message="targetClusterName must be different from the name of the source cluster"
+// +kubebuilder:validation:XValidation:rule="!has(self.rollback) || !self.rollback || (has(self.cutover) && self.cutover)",message="rollback requires cutover"
+type BlueGreenUpgradeSpec struct {
+	// The cluster to be upgraded ("blue")
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cluster is immutable"
+	ClusterRef corev1.LocalObjectReference `json:"cluster"`
+
+	// The name of the cluster that will be created running the new major
+	// version of PostgreSQL ("green")
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="targetClusterName is immutable"
+	// +kubebuilder:validation:MinLength=1
+	TargetClusterName string `json:"targetClusterName"`
+
+	// Name of the container image of the new major version
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="imageName is immutable"
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// Reference to the image catalog entry of the new major version
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="imageCatalogRef is immutable"
+	// +optional
+	ImageCatalogRef *ImageCatalogRef `json:"imageCatalogRef,omitempty"`
+
+	// The databases to be replicated to the target cluster. Defaults to the
+	// application database and to the databases managed by `Database`
+	// objects of the source cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="databases is immutable"
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// When set to true, the operator switches the applications to the target
+	// cluster as soon as every database has been replicated. The cutover
+	// cannot be canceled once started, but can be rolled back
+	// +optional
+	Cutover bool `json:"cutover,omitempty"`
+
+	// When set to true, the operator switches the applications back to the
+	// source cluster, and lifts the fence of its client connections. The
+	// changes written to the target cluster after the cutover are not
+	// copied back to the source one
+	// +kubebuilder:validation:XValidation:rule="!oldSelf || self",message="rollback cannot be canceled"
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// BlueGreenUpgradeStatus defines the observed state of a BlueGreenUpgrade
+type BlueGreenUpgradeStatus struct {
+	// The current phase of the upgrade
+	// +optional
+	Phase BlueGreenUpgradePhase `json:"phase,omitempty"`
+
+	// Message is a human-readable explanation of the current phase
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The replication status of each database
+	// +optional
+	Databases []BlueGreenUpgradeDatabaseStatus `json:"databases,omitempty"`
+
+	// The write-ahead log location of the source cluster after its
+	// client connections were fenced
+	// +optional
+	FenceLSN string `json:"fenceLSN,omitempty"`
+
+	// The time when the cutover started
+	// +optional
+	CutoverStartedAt *metav1.Time `json:"cutoverStartedAt,omitempty"`
+
+	// The time when the cutover was completed
+	// +optional
+	CutoverCompletedAt *metav1.Time `json:"cutoverCompletedAt,omitempty"`
+
+	// The poolers which have been switched to the target cluster
+	// +optional
+	SwitchedPoolers []string `json:"switchedPoolers,omitempty"`
+}
+
+// BlueGreenUpgradeDatabaseStatus is the replication status of a database
+type BlueGreenUpgradeDatabaseStatus struct {
+	// The name of the database
+	Name string `json:"name"`
+
+	// The name of the Publication object in the source cluster
+	PublicationName string `json:"publicationName"`
+
+	// The name of the Subscription object in the target cluster
+	SubscriptionName string `json:"subscriptionName"`
+
+	// Replicating is true when the apply worker is running and every
+	// table has been synchronized
+	// +optional
+	Replicating bool `json:"replicating,omitempty"`
+
+	// The last write-ahead log location received by the subscription
+	// +optional
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+
+	// The apply lag of the subscription
+	// +optional
+	ApplyLag *metav1.Duration `json:"applyLag,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetClusterName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",description="Latest reconciliation message"
+// +kubebuilder:validation:XValidation:rule="self.metadata.name != self.spec.cluster.name && self.metadata.name != self.spec.targetClusterName",message="the name of the upgrade must be different from the names of the clusters, as it names its services"
+
+// BlueGreenUpgrade is the Schema for the bluegreenupgrades API
+type BlueGreenUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired behavior of the upgrade.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec BlueGreenUpgradeSpec `json:"spec"`
+	// Most recently observed status of the upgrade. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status BlueGreenUpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BlueGreenUpgradeList contains a list of BlueGreenUpgrade
+type BlueGreenUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BlueGreenUpgrade `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BlueGreenUpgrade{}, &BlueGreenUpgradeList{})
+}
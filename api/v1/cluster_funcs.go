@@ -41,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	contextutils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils/context"
@@ -420,6 +421,82 @@ func (roleConfiguration *RoleConfiguration) GetRoleSecretsName() string {
 	return ""
 }
 
+// GetRolesWithClientCertificate returns the managed roles requiring a
+// TLS client certificate
+func (cluster *Cluster) GetRolesWithClientCertificate() []RoleConfiguration {
+	if cluster.Spec.Managed == nil {
+		return nil
+	}
+
+	var roles []RoleConfiguration
+	for _, role := range cluster.Spec.Managed.Roles {
+		if role.ClientCertificate != nil && role.Ensure != EnsureAbsent {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetPoolerCertificateMappings returns, for every integrated pooler
+// authenticating its clients through TLS certificates, the common name
+// of its client certificate and the managed roles having a client
+// certificate it can connect as, on behalf of its clients
+func (cluster *Cluster) GetPoolerCertificateMappings() []postgres.PoolerCertificateMapping {
+	if cluster.Status.PoolerIntegrations == nil {
+		return nil
+	}
+
+	certificateRoles := stringset.New()
+	for _, role := range cluster.GetRolesWithClientCertificate() {
+		certificateRoles.Put(role.Name)
+	}
+
+	poolers := cluster.Status.PoolerIntegrations.PgBouncerIntegration.CertificateAuthentication
+	mappings := make([]postgres.PoolerCertificateMapping, 0, len(poolers))
+	for _, pooler := range poolers {
+		mapping := postgres.PoolerCertificateMapping{CommonName: pooler.CommonName}
+		for _, user := range pooler.Users {
+			if certificateRoles.Has(user) {
+				mapping.Roles = append(mapping.Roles, user)
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+// GetPreviousPasswordSecretName gets the name of the secret keeping the
+// previous credentials of the role after a password rotation
+func (roleConfiguration *RoleConfiguration) GetPreviousPasswordSecretName() string {
+	if roleConfiguration.PasswordSecret == nil {
+		return ""
+	}
+	return roleConfiguration.PasswordSecret.Name + PreviousPasswordSecretSuffix
+}
+
+// GetGracePeriod returns the time during which the previous credentials
+// are kept after a password rotation
+func (policy *PasswordRotationPolicy) GetGracePeriod() time.Duration {
+	if policy.GracePeriod == nil {
+		return DefaultPasswordRotationGracePeriod
+	}
+	return policy.GracePeriod.Duration
+}
+
+// GetNextRotation returns the time of the next password rotation,
+// given the time of the last one
+func (policy *PasswordRotationPolicy) GetNextRotation(rotatedAt time.Time) time.Time {
+	return rotatedAt.Add(policy.Interval.Duration)
+}
+
+// GetPasswordExpiration returns the time after which a password generated
+// at the passed time is no longer valid. The password is kept valid for
+// the grace period following the next scheduled rotation, so that a
+// delayed rotation doesn't immediately lock the applications out
+func (policy *PasswordRotationPolicy) GetPasswordExpiration(rotatedAt time.Time) time.Time {
+	return policy.GetNextRotation(rotatedAt).Add(policy.GetGracePeriod())
+}
+
 // GetRoleInherit return the inherit attribute of a roleConfiguration
 func (roleConfiguration *RoleConfiguration) GetRoleInherit() bool {
 	if roleConfiguration.Inherit != nil {
@@ -550,13 +627,20 @@ func (cluster *Cluster) GetExternalClusterSecrets() *stringset.Data {
 	return secrets
 }
 
-// UsesSecretInManagedRoles checks if the given secret name is used in a managed role
+// UsesSecretInManagedRoles checks if the given secret name is used in a managed role,
+// including the secret keeping the previous password after a rotation
 func (cluster *Cluster) UsesSecretInManagedRoles(secretName string) bool {
 	if !cluster.ContainsManagedRolesConfiguration() {
 		return false
 	}
 	for _, role := range cluster.Spec.Managed.Roles {
-		if role.PasswordSecret != nil && role.PasswordSecret.Name == secretName {
+		if role.PasswordSecret == nil {
+			continue
+		}
+		if role.PasswordSecret.Name == secretName {
+			return true
+		}
+		if role.PasswordRotation != nil && role.GetPreviousPasswordSecretName() == secretName {
 			return true
 		}
 	}
@@ -979,6 +1063,59 @@ func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// ShouldCreateLogSinksVolume returns true if the volume storing the
+// files written by the file log sinks should be created
+func (cluster *Cluster) ShouldCreateLogSinksVolume() bool {
+	if cluster.Spec.Logging == nil {
+		return false
+	}
+
+	for _, sink := range cluster.Spec.Logging.Sinks {
+		if sink.File != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetLogSinksVolumeUsage returns the maximum space, in bytes, used by the
+// files written by the file log sinks, which keep the current file and
+// the rotated ones
+func (cluster *Cluster) GetLogSinksVolumeUsage() int64 {
+	if cluster.Spec.Logging == nil {
+		return 0
+	}
+
+	var result int64
+	for _, sink := range cluster.Spec.Logging.Sinks {
+		if sink.File != nil {
+			result += sink.File.GetMaxSize() * int64(sink.File.GetMaxFiles()+1)
+		}
+	}
+
+	return result
+}
+
+// GetLogSinksVolumeSizeLimit returns the size limit of the volume storing
+// the files written by the file log sinks. When not specified, it is the
+// maximum space used by the files
+func (cluster *Cluster) GetLogSinksVolumeSizeLimit() *resource.Quantity {
+	if cluster.Spec.Logging == nil {
+		return nil
+	}
+
+	if cluster.Spec.Logging.VolumeSizeLimit != nil {
+		return cluster.Spec.Logging.VolumeSizeLimit
+	}
+
+	if usage := cluster.GetLogSinksVolumeUsage(); usage > 0 {
+		return resource.NewQuantity(usage, resource.BinarySI)
+	}
+
+	return nil
+}
+
 // ShouldPromoteFromReplicaCluster returns true if the cluster should promote
 func (cluster *Cluster) ShouldPromoteFromReplicaCluster() bool {
 	// If there's no replica cluster configuration there's no
@@ -1035,6 +1172,82 @@ func (cluster Cluster) ExternalCluster(name string) (ExternalCluster, bool) {
 	return ExternalCluster{}, false
 }
 
+// GetExtensionUpdatePolicy returns the policy used to update the extensions
+// after the images of the cluster change
+func (cluster Cluster) GetExtensionUpdatePolicy() ExtensionUpdatePolicy {
+	if cluster.Spec.PostgresConfiguration.ExtensionUpdatePolicy == "" {
+		return ExtensionUpdatePolicyManual
+	}
+
+	return cluster.Spec.PostgresConfiguration.ExtensionUpdatePolicy
+}
+
+// GetExtensionUpdateImages returns the PostgreSQL image followed by the
+// sorted images of the extensions. A change in this list triggers the
+// automatic update of the extensions
+func (cluster Cluster) GetExtensionUpdateImages() []string {
+	images := []string{cluster.Status.Image}
+	for _, extension := range cluster.Spec.PostgresConfiguration.Extensions {
+		images = append(images, extension.ImageVolumeSource.Reference)
+	}
+	slices.Sort(images[1:])
+
+	return images
+}
+
+// GetOnlineImport returns the import configuration when the cluster
+// has been bootstrapped with an online import, nil otherwise
+func (cluster Cluster) GetOnlineImport() *Import {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
+	importSpec := cluster.Spec.Bootstrap.InitDB.Import
+	if importSpec == nil || !importSpec.Online {
+		return nil
+	}
+
+	return importSpec
+}
+
+// GetPgRestoreJobs returns the number of concurrent jobs `pg_restore`
+// should use to import the passed source database
+func (i *Import) GetPgRestoreJobs(database string) int {
+	for _, options := range i.DatabaseOptions {
+		if options.Name == database && options.PgRestoreJobs > 0 {
+			return options.PgRestoreJobs
+		}
+	}
+
+	return 1
+}
+
+// GetDatabase returns the progress of the import of the passed database,
+// or nil if it has not been started
+func (s *LogicalImportStatus) GetDatabase(name string) *LogicalImportDatabaseStatus {
+	if s == nil {
+		return nil
+	}
+
+	for i := range s.Databases {
+		if s.Databases[i].Name == name {
+			return &s.Databases[i]
+		}
+	}
+
+	return nil
+}
+
+// GetSourceDatabaseName returns the name of the database of the source
+// cluster that has been imported into the passed database
+func (i *Import) GetSourceDatabaseName(database string) string {
+	if i.Type == MicroserviceSnapshotType && len(i.Databases) == 1 {
+		return i.Databases[0]
+	}
+
+	return database
+}
+
 // IsReplica checks if this is a replica cluster or not
 func (cluster Cluster) IsReplica() bool {
 	// Before introducing the "primary" field, the
@@ -1126,6 +1339,9 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 	if _, ok := cluster.Status.SecretsResourceVersion.Metrics[secret]; ok {
 		return true
 	}
+	if _, ok := cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions[secret]; ok {
+		return true
+	}
 	certificates := cluster.Status.Certificates
 	switch secret {
 	case cluster.GetSuperuserSecretName(),
@@ -1166,6 +1382,9 @@ func (cluster *Cluster) UsesConfigMap(config string) (ok bool) {
 	if _, ok := cluster.Status.ConfigMapResourceVersion.Metrics[config]; ok {
 		return true
 	}
+	if _, ok := cluster.Status.ConfigMapResourceVersion.DeclarativeConfigMapVersions[config]; ok {
+		return true
+	}
 	return false
 }
 
@@ -1320,6 +1539,45 @@ func (cluster *Cluster) GetTablespaceConfiguration(name string) *TablespaceConfi
 	return nil
 }
 
+// IsEphemeral returns true if the tablespace is hosted on an ephemeral
+// volume instead of a persistent volume claim
+func (tbsConfig *TablespaceConfiguration) IsEphemeral() bool {
+	return tbsConfig.Ephemeral != nil
+}
+
+// IsTablespaceRetired returns true if the tablespace with the given name
+// is marked as absent and has already been dropped from PostgreSQL,
+// meaning that its volumes are not needed anymore
+func (cluster *Cluster) IsTablespaceRetired(name string) bool {
+	tbsConfig := cluster.GetTablespaceConfiguration(name)
+	if tbsConfig == nil || tbsConfig.Ensure != EnsureAbsent {
+		return false
+	}
+
+	for _, state := range cluster.Status.TablespacesStatus {
+		if state.Name == name {
+			return state.State == TablespaceStatusDropped
+		}
+	}
+
+	return false
+}
+
+// GetProvisionedTablespaces returns the configuration of the tablespaces
+// needing a volume, that is every declared tablespace except the
+// retired ones
+func (cluster *Cluster) GetProvisionedTablespaces() []TablespaceConfiguration {
+	result := make([]TablespaceConfiguration, 0, len(cluster.Spec.Tablespaces))
+	for _, tbsConfig := range cluster.Spec.Tablespaces {
+		if cluster.IsTablespaceRetired(tbsConfig.Name) {
+			continue
+		}
+		result = append(result, tbsConfig)
+	}
+
+	return result
+}
+
 // GetServerCASecretObjectKey returns a types.NamespacedName pointing to the secret
 func (cluster *Cluster) GetServerCASecretObjectKey() types.NamespacedName {
 	return types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.GetServerCASecretName()}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -455,6 +456,81 @@ var _ = Describe("external cluster list", func() {
 	})
 })
 
+var _ = Describe("online import", func() {
+	It("is not detected when the cluster is not bootstrapped via import", func() {
+		Expect(Cluster{}.GetOnlineImport()).To(BeNil())
+
+		cluster := Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{InitDB: &BootstrapInitDB{}}}}
+		Expect(cluster.GetOnlineImport()).To(BeNil())
+	})
+
+	It("is detected only when the online option is enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Import: &Import{Type: MonolithSnapshotType, Databases: []string{"*"}},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetOnlineImport()).To(BeNil())
+
+		cluster.Spec.Bootstrap.InitDB.Import.Online = true
+		Expect(cluster.GetOnlineImport()).To(BeIdenticalTo(cluster.Spec.Bootstrap.InitDB.Import))
+	})
+
+	It("maps the imported databases to the source ones", func() {
+		microservice := &Import{Type: MicroserviceSnapshotType, Databases: []string{"source"}}
+		Expect(microservice.GetSourceDatabaseName("app")).To(Equal("source"))
+
+		monolith := &Import{Type: MonolithSnapshotType, Databases: []string{"db1", "db2"}}
+		Expect(monolith.GetSourceDatabaseName("db2")).To(Equal("db2"))
+	})
+
+	It("gets the pg_restore jobs of every database", func() {
+		importSpec := &Import{
+			DatabaseOptions: []ImportDatabaseOptions{{Name: "db1", PgRestoreJobs: 4}},
+		}
+		Expect(importSpec.GetPgRestoreJobs("db1")).To(Equal(4))
+		Expect(importSpec.GetPgRestoreJobs("db2")).To(Equal(1))
+	})
+
+	It("looks up the import progress of a database", func() {
+		var missing *LogicalImportStatus
+		Expect(missing.GetDatabase("db1")).To(BeNil())
+
+		status := &LogicalImportStatus{Databases: []LogicalImportDatabaseStatus{{Name: "db1"}}}
+		Expect(status.GetDatabase("db1")).ToNot(BeNil())
+		Expect(status.GetDatabase("db2")).To(BeNil())
+	})
+})
+
+var _ = Describe("extension updates", func() {
+	It("uses the manual policy by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetExtensionUpdatePolicy()).To(Equal(ExtensionUpdatePolicyManual))
+
+		cluster.Spec.PostgresConfiguration.ExtensionUpdatePolicy = ExtensionUpdatePolicyAutomatic
+		Expect(cluster.GetExtensionUpdatePolicy()).To(Equal(ExtensionUpdatePolicyAutomatic))
+	})
+
+	It("lists the PostgreSQL image followed by the sorted extension images", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ExtensionConfiguration{
+						{Name: "vector", ImageVolumeSource: corev1.ImageVolumeSource{Reference: "vector:0.8"}},
+						{Name: "postgis", ImageVolumeSource: corev1.ImageVolumeSource{Reference: "postgis:3.5"}},
+					},
+				},
+			},
+			Status: ClusterStatus{Image: "postgresql:17.5"},
+		}
+		Expect(cluster.GetExtensionUpdateImages()).To(Equal([]string{"postgresql:17.5", "postgis:3.5", "vector:0.8"}))
+	})
+})
+
 var _ = Describe("look up for secrets", Ordered, func() {
 	cluster := Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1036,12 +1112,91 @@ var _ = Describe("Managed Roles", func() {
 		Expect(cluster.Spec.Managed.Roles[0].GetRoleSecretsName()).To(Equal("test_user_secrets"))
 	})
 
+	It("Uses the secret keeping the previous password of the rotated roles", func() {
+		role := RoleConfiguration{
+			Name:           "test_user",
+			PasswordSecret: &LocalObjectReference{Name: "test_user_secrets"},
+		}
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{Roles: []RoleConfiguration{role}},
+			},
+		}
+		Expect(cluster.UsesSecretInManagedRoles("test_user_secrets-previous")).To(BeFalse())
+
+		cluster.Spec.Managed.Roles[0].PasswordRotation = &PasswordRotationPolicy{
+			Interval: metav1.Duration{Duration: 720 * time.Hour},
+		}
+		Expect(cluster.UsesSecretInManagedRoles("test_user_secrets-previous")).To(BeTrue())
+	})
+
 	It("Verifies default values when there are no managed roles", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{},
 		}
 		Expect(cluster.ContainsManagedRolesConfiguration()).To(BeFalse())
 		Expect(cluster.UsesSecretInManagedRoles("test_user_secrets")).To(BeFalse())
+		Expect(cluster.GetRolesWithClientCertificate()).To(BeEmpty())
+	})
+
+	It("Detects the roles requiring a client certificate", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:              "with_certificate",
+							ClientCertificate: &RoleClientCertificate{SecretName: "with-certificate"},
+						},
+						{
+							Name:              "absent",
+							Ensure:            EnsureAbsent,
+							ClientCertificate: &RoleClientCertificate{SecretName: "absent"},
+						},
+						{
+							Name: "without_certificate",
+						},
+					},
+				},
+			},
+		}
+		roles := cluster.GetRolesWithClientCertificate()
+		Expect(roles).To(HaveLen(1))
+		Expect(roles[0].Name).To(Equal("with_certificate"))
+	})
+
+	It("Allows every pooler to use the roles its clients are mapped to only", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:              "with_certificate",
+							ClientCertificate: &RoleClientCertificate{SecretName: "with-certificate"},
+						},
+						{
+							Name: "without_certificate",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetPoolerCertificateMappings()).To(BeEmpty())
+
+		cluster.Status.PoolerIntegrations = &PoolerIntegrations{
+			PgBouncerIntegration: PgBouncerIntegrationStatus{
+				CertificateAuthentication: []PgBouncerCertificateAuthenticationStatus{
+					{
+						SecretName: "pooler-certificate-authentication",
+						CommonName: "cnpg_pooler_certificate_pooler",
+						Users:      []string{"with_certificate", "without_certificate"},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetPoolerCertificateMappings()).To(Equal([]postgres.PoolerCertificateMapping{
+			{CommonName: "cnpg_pooler_certificate_pooler", Roles: []string{"with_certificate"}},
+		}))
 	})
 })
 
@@ -1177,6 +1332,36 @@ var _ = Describe("Tablespaces", func() {
 			Expect(cluster.GetTablespaceConfiguration("non_existing_tablespace")).To(BeNil())
 		})
 	})
+	When("a tablespace is marked as absent", func() {
+		retiringCluster := Cluster{
+			Spec: ClusterSpec{
+				Tablespaces: []TablespaceConfiguration{
+					{Name: "first_tablespace"},
+					{Name: "second_tablespace", Ensure: EnsureAbsent},
+				},
+			},
+		}
+
+		It("is not retired until it has been dropped", func() {
+			retiringCluster.Status.TablespacesStatus = []TablespaceState{
+				{Name: "second_tablespace", State: TablespaceStatusPendingReconciliation},
+			}
+			Expect(retiringCluster.IsTablespaceRetired("second_tablespace")).To(BeFalse())
+			Expect(retiringCluster.GetProvisionedTablespaces()).To(HaveLen(2))
+		})
+
+		It("is retired and not provisioned once dropped", func() {
+			retiringCluster.Status.TablespacesStatus = []TablespaceState{
+				{Name: "first_tablespace", State: TablespaceStatusReconciled},
+				{Name: "second_tablespace", State: TablespaceStatusDropped},
+			}
+			Expect(retiringCluster.IsTablespaceRetired("first_tablespace")).To(BeFalse())
+			Expect(retiringCluster.IsTablespaceRetired("second_tablespace")).To(BeTrue())
+			provisioned := retiringCluster.GetProvisionedTablespaces()
+			Expect(provisioned).To(HaveLen(1))
+			Expect(provisioned[0].Name).To(Equal("first_tablespace"))
+		})
+	})
 })
 
 var _ = Describe("SynchronizeReplicasConfiguration", func() {
@@ -1779,3 +1964,50 @@ var _ = Describe("Failover quorum annotation", func() {
 		Entry("with invalid annotation", clusterWithAnnotation("xxx"), false, false),
 	)
 })
+
+var _ = Describe("Log sinks volume", func() {
+	It("is not limited without file sinks", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Logging: &LoggingConfiguration{
+					Sinks: []LogSinkConfiguration{
+						{Name: "remote", Syslog: &SyslogLogSinkConfiguration{Address: "syslog:514"}},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetLogSinksVolumeUsage()).To(BeZero())
+		Expect(cluster.GetLogSinksVolumeSizeLimit()).To(BeNil())
+	})
+
+	It("is limited by default to the space used by the file sinks", func() {
+		maxSize := resource.MustParse("10Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Logging: &LoggingConfiguration{
+					Sinks: []LogSinkConfiguration{
+						{Name: "audit", File: &FileLogSinkConfiguration{MaxSize: &maxSize, MaxFiles: 2}},
+						{Name: "all", File: &FileLogSinkConfiguration{}},
+					},
+				},
+			},
+		}
+		Expect(cluster.GetLogSinksVolumeUsage()).To(Equal(int64(630 * 1024 * 1024)))
+		Expect(cluster.GetLogSinksVolumeSizeLimit().String()).To(Equal("630Mi"))
+	})
+
+	It("uses the requested size limit", func() {
+		limit := resource.MustParse("2Gi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Logging: &LoggingConfiguration{
+					Sinks: []LogSinkConfiguration{
+						{Name: "audit", File: &FileLogSinkConfiguration{}},
+					},
+					VolumeSizeLimit: &limit,
+				},
+			},
+		}
+		Expect(cluster.GetLogSinksVolumeSizeLimit()).To(Equal(&limit))
+	})
+})
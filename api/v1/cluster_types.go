@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -103,6 +105,49 @@ const (
 	// DefaultPgBouncerPoolerSecretSuffix is the suffix for the default pgbouncer Pooler secret
 	DefaultPgBouncerPoolerSecretSuffix = "-pooler"
 
+	// PgBouncerCertificateAuthenticationSecretSuffix is the suffix of the
+	// secret containing the client certificate used by a pgbouncer Pooler
+	// authenticating its clients through TLS certificates
+	PgBouncerCertificateAuthenticationSecretSuffix = "-certificate-authentication"
+
+	// PgBouncerCertificateAuthenticationCommonNamePrefix is the prefix of the
+	// common name of the client certificate used by a pgbouncer Pooler
+	// authenticating its clients through TLS certificates
+	PgBouncerCertificateAuthenticationCommonNamePrefix = "cnpg_pooler_certificate_"
+
+	// PreviousPasswordSecretSuffix is the suffix appended to the name of
+	// the password secret of a managed role to get the name of the secret
+	// keeping the previous credentials after a password rotation
+	PreviousPasswordSecretSuffix = "-previous" // #nosec
+
+	// DefaultPasswordRotationGracePeriod is the default time after a
+	// password rotation during which the previous credentials are kept
+	DefaultPasswordRotationGracePeriod = time.Hour
+
+	// DefaultOpenTelemetryExportInterval is the default interval between
+	// two OpenTelemetry metrics exports
+	DefaultOpenTelemetryExportInterval = time.Minute
+
+	// DefaultPgStatStatementsTopN is the default number of statements
+	// exported from every ranking of the pg_stat_statements exporter
+	DefaultPgStatStatementsTopN = 10
+
+	// DefaultPgStatStatementsQueryTextMaxLength is the default maximum
+	// length of the query text exported by the pg_stat_statements exporter
+	DefaultPgStatStatementsQueryTextMaxLength = 120
+
+	// LogSinkStdout is the name of the built-in log sink writing the
+	// log records to the standard output of the instance manager
+	LogSinkStdout = "stdout"
+
+	// DefaultFileLogSinkMaxSize is the default size, in bytes, after
+	// which the file written by a file log sink is rotated
+	DefaultFileLogSinkMaxSize = 100 * 1024 * 1024
+
+	// DefaultFileLogSinkMaxFiles is the default number of rotated files
+	// kept by a file log sink
+	DefaultFileLogSinkMaxFiles = 5
+
 	// PendingFailoverMarker is used as target primary to signal that a failover is required
 	PendingFailoverMarker = "pending"
 
@@ -435,6 +480,12 @@ type ClusterSpec struct {
 	// +optional
 	LogLevel string `json:"logLevel,omitempty"`
 
+	// The sinks receiving the PostgreSQL logs collected by the instance
+	// manager and the rules choosing them. When not specified, every log
+	// record is written to the standard output
+	// +optional
+	Logging *LoggingConfiguration `json:"logging,omitempty"`
+
 	// Template to be used to define projected volumes, projected volumes will be mounted
 	// under `/projected` base folder
 	// +optional
@@ -742,6 +793,10 @@ type PasswordState struct {
 	// the resource version of the password secret
 	// +optional
 	SecretResourceVersion string `json:"resourceVersion,omitempty"`
+	// the time of the last password rotation, for roles having a
+	// password rotation policy
+	// +optional
+	RotatedAt *metav1.Time `json:"rotatedAt,omitempty"`
 }
 
 // ManagedRoles tracks the status of a cluster's managed roles
@@ -755,7 +810,8 @@ type ManagedRoles struct {
 	// +optional
 	CannotReconcile map[string][]string `json:"cannotReconcile,omitempty"`
 
-	// PasswordStatus gives the last transaction id and password secret version for each managed role
+	// PasswordStatus gives the last transaction id, password secret version
+	// and the last password rotation time for each managed role
 	// +optional
 	PasswordStatus map[string]PasswordState `json:"passwordStatus,omitempty"`
 }
@@ -775,6 +831,31 @@ type TablespaceState struct {
 	// Error is the reconciliation error, if any
 	// +optional
 	Error string `json:"error,omitempty"`
+
+	// Relations is the state of the relations to be moved into
+	// the tablespace
+	// +optional
+	Relations []TablespaceRelationState `json:"relations,omitempty"`
+}
+
+// TablespaceRelationState represents the state of a relation to be
+// moved into a tablespace
+type TablespaceRelationState struct {
+	// Database is the name of the database containing the relation
+	Database string `json:"database"`
+
+	// Schema is the schema of the relation
+	Schema string `json:"schema"`
+
+	// Name is the name of the relation
+	Name string `json:"name"`
+
+	// Moved is true when the relation is stored in the tablespace
+	Moved bool `json:"moved"`
+
+	// Error is the error encountered while moving the relation, if any
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // TablespaceStatus represents the status of a tablespace in the cluster
@@ -786,6 +867,10 @@ const (
 
 	// TablespaceStatusPendingReconciliation indicates the tablespace in Spec requires creation in the DB
 	TablespaceStatusPendingReconciliation TablespaceStatus = "pending"
+
+	// TablespaceStatusDropped indicates the tablespace marked as absent in Spec
+	// has been dropped from the DB, and its volumes can be deleted
+	TablespaceStatusDropped TablespaceStatus = "dropped"
 )
 
 // AvailableArchitecture represents the state of a cluster's architecture
@@ -1011,6 +1096,172 @@ type ClusterStatus struct {
 	// SystemID is the latest detected PostgreSQL SystemID
 	// +optional
 	SystemID string `json:"systemID,omitempty"`
+
+	// LogicalImport reports the progress of the logical import of the
+	// databases while bootstrapping the cluster
+	// +optional
+	LogicalImport *LogicalImportStatus `json:"logicalImport,omitempty"`
+
+	// OnlineImport is the status of the logical replication catch-up
+	// of an online import
+	// +optional
+	OnlineImport *OnlineImportStatus `json:"onlineImport,omitempty"`
+
+	// ExtensionUpdates reports the automatic updates of the extensions
+	// applied after the latest change of the images
+	// +optional
+	ExtensionUpdates *ExtensionUpdatesStatus `json:"extensionUpdates,omitempty"`
+}
+
+// ExtensionUpdatesStatus reports the automatic updates of the extensions
+// applied after the images of the cluster changed
+type ExtensionUpdatesStatus struct {
+	// Images is the list of images, of PostgreSQL and of its extensions,
+	// for which the extensions have been updated
+	// +optional
+	Images []string `json:"images,omitempty"`
+
+	// LastCheckTime is the time when the extensions have been last
+	// checked for updates
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Updates is the list of the extensions that have been updated
+	// +optional
+	Updates []ExtensionUpdate `json:"updates,omitempty"`
+
+	// Message is the latest error encountered while updating the
+	// extensions. The update is retried until it succeeds
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExtensionUpdate reports the update of an extension in a database
+type ExtensionUpdate struct {
+	// Database is the name of the database containing the extension
+	Database string `json:"database"`
+
+	// Name is the name of the extension
+	Name string `json:"name"`
+
+	// FromVersion is the version of the extension before the update
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version of the extension after the update
+	// +optional
+	ToVersion string `json:"toVersion,omitempty"`
+
+	// Error is the error encountered while updating the extension, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// LogicalImportStatus reports the progress of a logical import
+type LogicalImportStatus struct {
+	// StartTime is the time when the import has been started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Databases contains the progress of the import of every database
+	// +optional
+	Databases []LogicalImportDatabaseStatus `json:"databases,omitempty"`
+}
+
+// LogicalImportDatabaseStatus reports the progress of the import of a
+// database. Every step is recorded with the time it has been completed
+type LogicalImportDatabaseStatus struct {
+	// Name is the name of the database in the source cluster
+	Name string `json:"name"`
+
+	// DumpTime is the time when `pg_dump` has exported the database
+	// +optional
+	DumpTime *metav1.Time `json:"dumpTime,omitempty"`
+
+	// RestoreTime is the time when `pg_restore` has imported the database
+	// +optional
+	RestoreTime *metav1.Time `json:"restoreTime,omitempty"`
+
+	// PostImportSQLTime is the time when the post-import SQL queries
+	// have been executed
+	// +optional
+	PostImportSQLTime *metav1.Time `json:"postImportSQLTime,omitempty"`
+
+	// AnalyzeTime is the time when the statistics of the database have
+	// been collected
+	// +optional
+	AnalyzeTime *metav1.Time `json:"analyzeTime,omitempty"`
+
+	// CompletionTime is the time when the import of the database has
+	// been completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// OnlineImportPhase is the phase of an online import
+type OnlineImportPhase string
+
+const (
+	// OnlineImportPhaseCatchingUp means that the subscriptions are copying
+	// the data of the source and applying its changes
+	OnlineImportPhaseCatchingUp OnlineImportPhase = "catching-up"
+
+	// OnlineImportPhaseCompleted means that the cutover has been completed,
+	// and the subscriptions have been dropped
+	OnlineImportPhaseCompleted OnlineImportPhase = "completed"
+)
+
+// OnlineImportStatus is the status of an online import
+type OnlineImportStatus struct {
+	// Phase is the current phase of the online import
+	// +optional
+	Phase OnlineImportPhase `json:"phase,omitempty"`
+
+	// Databases contains the replication status of every imported database
+	// +optional
+	Databases []OnlineImportDatabaseStatus `json:"databases,omitempty"`
+
+	// LastUpdateTime is the time when the replication status has been
+	// last refreshed
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// CompletionTime is the time when the cutover has been completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message is the latest error encountered while refreshing the
+	// replication status or completing the cutover
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OnlineImportDatabaseStatus is the replication status of a database
+// imported online
+type OnlineImportDatabaseStatus struct {
+	// Name is the name of the imported database
+	Name string `json:"name"`
+
+	// SourceDatabase is the name of the database in the source cluster
+	SourceDatabase string `json:"sourceDatabase"`
+
+	// SlotName is the name of the replication slot and of the publication
+	// created in the source database
+	SlotName string `json:"slotName"`
+
+	// WorkerActive is true when the apply worker of the subscription
+	// is running
+	// +optional
+	WorkerActive bool `json:"workerActive,omitempty"`
+
+	// TablesNotReady is the number of tables whose initial data copy
+	// has not been completed yet
+	// +optional
+	TablesNotReady int `json:"tablesNotReady,omitempty"`
+
+	// LagBytes is the amount of WAL generated in the source cluster that
+	// has not been confirmed by the subscription yet
+	// +optional
+	LagBytes *int64 `json:"lagBytes,omitempty"`
 }
 
 // ImageInfo contains the information about a PostgreSQL image
@@ -1123,6 +1374,26 @@ type PoolerIntegrations struct {
 type PgBouncerIntegrationStatus struct {
 	// +optional
 	Secrets []string `json:"secrets,omitempty"`
+
+	// The integrated poolers authenticating their clients
+	// through TLS certificates
+	// +optional
+	CertificateAuthentication []PgBouncerCertificateAuthenticationStatus `json:"certificateAuthentication,omitempty"`
+}
+
+// PgBouncerCertificateAuthenticationStatus describes an integrated pooler
+// authenticating its clients through TLS certificates
+type PgBouncerCertificateAuthenticationStatus struct {
+	// The name of the secret containing the client certificate
+	// the pooler uses to connect on behalf of its clients
+	SecretName string `json:"secretName"`
+
+	// The common name of the client certificate
+	CommonName string `json:"commonName"`
+
+	// The database users the clients of the pooler are mapped to
+	// +optional
+	Users []string `json:"users,omitempty"`
 }
 
 // ReplicaClusterConfiguration encapsulates the configuration of a replica
@@ -1438,8 +1709,32 @@ type PostgresConfiguration struct {
 	// The configuration of the extensions to be added
 	// +optional
 	Extensions []ExtensionConfiguration `json:"extensions,omitempty"`
+
+	// Whether the extensions installed in the databases are updated to
+	// the default version shipped with the images after they change:
+	// `manual` (default) or `automatic`. Extensions managed by a
+	// `Database` resource can override this setting
+	// +kubebuilder:default:=manual
+	// +kubebuilder:validation:Enum:=manual;automatic
+	// +optional
+	ExtensionUpdatePolicy ExtensionUpdatePolicy `json:"extensionUpdatePolicy,omitempty"`
 }
 
+// ExtensionUpdatePolicy defines whether the extensions are updated to the
+// default version available in the images after they change
+type ExtensionUpdatePolicy string
+
+const (
+	// ExtensionUpdatePolicyManual means that the extensions are updated
+	// only when requested by the user (`manual`, default)
+	ExtensionUpdatePolicyManual ExtensionUpdatePolicy = "manual"
+
+	// ExtensionUpdatePolicyAutomatic means that the instance manager
+	// updates the extensions to their default version after the images
+	// of the cluster change (`automatic`)
+	ExtensionUpdatePolicyAutomatic ExtensionUpdatePolicy = "automatic"
+)
+
 // ExtensionConfiguration is the configuration used to add
 // PostgreSQL extensions to the Cluster.
 type ExtensionConfiguration struct {
@@ -1782,6 +2077,16 @@ type Import struct {
 	// +optional
 	SchemaOnly bool `json:"schemaOnly,omitempty"`
 
+	// When set to true, only the schema is imported with `pg_restore`,
+	// while the data is copied via logical replication: a publication is
+	// created in every source database and a subscription in the
+	// corresponding imported one. The new cluster keeps applying the
+	// changes happening in the source until the cutover is requested via
+	// the `cnpg.io/completeOnlineImport` annotation. Requires
+	// `wal_level = logical` in the source. Default: `false`.
+	// +optional
+	Online bool `json:"online,omitempty"`
+
 	// List of custom options to pass to the `pg_dump` command. IMPORTANT:
 	// Use these options with caution and at your own risk, as the operator
 	// does not validate their content. Be aware that certain options may
@@ -1795,6 +2100,25 @@ type Import struct {
 	// conflict with the operator's intended functionality or design.
 	// +optional
 	PgRestoreExtraOptions []string `json:"pgRestoreExtraOptions,omitempty"`
+
+	// The import options of specific databases, identified by their name
+	// in the source cluster
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	DatabaseOptions []ImportDatabaseOptions `json:"databaseOptions,omitempty"`
+}
+
+// ImportDatabaseOptions contains the import options of a database
+type ImportDatabaseOptions struct {
+	// The name of the database in the source cluster
+	Name string `json:"name"`
+
+	// The number of concurrent jobs used by `pg_restore` to import the
+	// database. Default: `1`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PgRestoreJobs int `json:"pgRestoreJobs,omitempty"`
 }
 
 // ImportSource describes the source for the logical snapshot
@@ -1999,8 +2323,16 @@ type TablespaceConfiguration struct {
 	// The name of the tablespace
 	Name string `json:"name"`
 
-	// The storage configuration for the tablespace
-	Storage StorageConfiguration `json:"storage"`
+	// The storage configuration for the tablespace. It's not needed when
+	// the tablespace is hosted on an ephemeral volume
+	// +optional
+	Storage StorageConfiguration `json:"storage,omitempty"`
+
+	// Ephemeral makes a temporary tablespace be hosted on an ephemeral
+	// volume, created and destroyed together with the Pod, instead of
+	// a persistent volume claim
+	// +optional
+	Ephemeral *TablespaceEphemeralStorage `json:"ephemeral,omitempty"`
 
 	// Owner is the PostgreSQL user owning the tablespace
 	// +optional
@@ -2013,6 +2345,47 @@ type TablespaceConfiguration struct {
 	// +optional
 	// +kubebuilder:default:=false
 	Temporary bool `json:"temporary,omitempty"`
+
+	// Specifies whether the tablespace should be present or absent in
+	// PostgreSQL. An absent tablespace is dropped once it's empty, and its
+	// volumes are then deleted, rolling out the instances. Only then it
+	// can be removed from the list of tablespaces
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The relations to be moved into this tablespace
+	// +optional
+	Relations []TablespaceRelation `json:"relations,omitempty"`
+}
+
+// TablespaceEphemeralStorage is the configuration of the ephemeral volume
+// hosting a temporary tablespace
+type TablespaceEphemeralStorage struct {
+	// VolumeSource is the source of a generic ephemeral volume, for example
+	// one provisioned by a storage class backed by local NVMe disks
+	// +optional
+	VolumeSource *corev1.EphemeralVolumeSource `json:"volumeSource,omitempty"`
+
+	// SizeLimit is the size limit of the `emptyDir` volume used when no
+	// volume source is specified
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// TablespaceRelation identifies a relation to be moved into a tablespace
+type TablespaceRelation struct {
+	// The name of the database containing the relation
+	Database string `json:"database"`
+
+	// The schema of the relation
+	// +kubebuilder:default:="public"
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The name of the relation: a table, an index or a materialized view
+	Name string `json:"name"`
 }
 
 // DatabaseRoleRef is a reference an a role available inside PostgreSQL
@@ -2149,6 +2522,14 @@ type MonitoringConfiguration struct {
 	// +optional
 	CustomQueriesSecret []SecretKeySelector `json:"customQueriesSecret,omitempty"`
 
+	// The maximum number of custom queries executed at the same time
+	// during a scrape. Default: `1`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +kubebuilder:default:=1
+	// +optional
+	CustomQueriesConcurrency int32 `json:"customQueriesConcurrency,omitempty"`
+
 	// Enable or disable the `PodMonitor`
 	// +kubebuilder:default:=false
 	// +optional
@@ -2166,6 +2547,249 @@ type MonitoringConfiguration struct {
 	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
 	// +optional
 	PodMonitorRelabelConfigs []monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+
+	// Push metrics and traces to an OpenTelemetry collector via OTLP/HTTP
+	// +optional
+	OpenTelemetry *OpenTelemetryConfiguration `json:"openTelemetry,omitempty"`
+
+	// Export the statistics of the most expensive statements
+	// tracked by `pg_stat_statements`
+	// +optional
+	PgStatStatements *PgStatStatementsConfiguration `json:"pgStatStatements,omitempty"`
+}
+
+// PgStatStatementsConfiguration is the type containing the configuration
+// of the built-in exporter of the `pg_stat_statements` statistics.
+// For each database, only the statements ranking in the top N by total
+// execution time, by calls, or by blocks read and written are exported,
+// while the remaining ones are aggregated in a single series
+type PgStatStatementsConfiguration struct {
+	// The number of statements to be exported, for each database,
+	// from every ranking. Default: `10`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default:=10
+	// +optional
+	TopN int32 `json:"topN,omitempty"`
+
+	// Add the normalized text of the statement as the `query` label
+	// +kubebuilder:default:=false
+	// +optional
+	IncludeQueryText bool `json:"includeQueryText,omitempty"`
+
+	// The maximum number of characters of the `query` label. Default: `120`
+	// +kubebuilder:validation:Minimum=16
+	// +kubebuilder:validation:Maximum=1024
+	// +kubebuilder:default:=120
+	// +optional
+	QueryTextMaxLength int32 `json:"queryTextMaxLength,omitempty"`
+
+	// The list of databases whose statements are exported.
+	// Default: all the databases
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+}
+
+// GetTopN returns the number of statements exported from every ranking
+func (c *PgStatStatementsConfiguration) GetTopN() int {
+	if c == nil || c.TopN <= 0 {
+		return DefaultPgStatStatementsTopN
+	}
+	return int(c.TopN)
+}
+
+// GetQueryTextMaxLength returns the maximum length of the query label
+func (c *PgStatStatementsConfiguration) GetQueryTextMaxLength() int {
+	if c == nil || c.QueryTextMaxLength <= 0 {
+		return DefaultPgStatStatementsQueryTextMaxLength
+	}
+	return int(c.QueryTextMaxLength)
+}
+
+// OpenTelemetryConfiguration is the type containing the configuration
+// for exporting metrics and traces via the OpenTelemetry protocol (OTLP)
+type OpenTelemetryConfiguration struct {
+	// The URL of the OTLP/HTTP endpoint of the collector, like
+	// `http://otel-collector.monitoring:4318`. Plain `http` disables TLS.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// The interval between two metrics exports. Default: `60s`
+	// +optional
+	ExportInterval *metav1.Duration `json:"exportInterval,omitempty"`
+
+	// Whether metrics should be exported. Every export collects all the
+	// metrics, running the monitoring queries like a scrape of the
+	// metrics endpoint does. Default: `false`
+	// +kubebuilder:default:=false
+	// +optional
+	Metrics *bool `json:"metrics,omitempty"`
+
+	// Whether traces should be exported. Default: `true`
+	// +kubebuilder:default:=true
+	// +optional
+	Traces *bool `json:"traces,omitempty"`
+
+	// Additional resource attributes attached to every exported
+	// metric and span
+	// +optional
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+}
+
+// IsMetricsEnabled returns true when metrics should be exported
+func (c *OpenTelemetryConfiguration) IsMetricsEnabled() bool {
+	return c != nil && c.Metrics != nil && *c.Metrics
+}
+
+// IsTracesEnabled returns true when traces should be exported
+func (c *OpenTelemetryConfiguration) IsTracesEnabled() bool {
+	return c != nil && (c.Traces == nil || *c.Traces)
+}
+
+// GetExportInterval returns the interval between two metrics exports
+func (c *OpenTelemetryConfiguration) GetExportInterval() time.Duration {
+	if c == nil || c.ExportInterval == nil || c.ExportInterval.Duration <= 0 {
+		return DefaultOpenTelemetryExportInterval
+	}
+	return c.ExportInterval.Duration
+}
+
+// LoggingConfiguration is the type containing the configuration of the
+// sinks receiving the PostgreSQL log records
+type LoggingConfiguration struct {
+	// The sinks the log records can be sent to, in addition to the
+	// built-in `stdout` one
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Sinks []LogSinkConfiguration `json:"sinks,omitempty"`
+
+	// The rules choosing the sinks of every log record. Rules are evaluated
+	// in order and the first one matching a record wins. Records not
+	// matching any rule are written to the `stdout` sink
+	// +optional
+	Routes []LogRouteConfiguration `json:"routes,omitempty"`
+
+	// The size limit of the volume storing the files written by the
+	// file sinks, which must fit the current and the rotated files of
+	// every file sink. Default: the sum, for every file sink, of
+	// `maxSize` multiplied by `maxFiles` plus one
+	// +optional
+	VolumeSizeLimit *resource.Quantity `json:"volumeSizeLimit,omitempty"`
+}
+
+// LogSinkConfiguration is the configuration of a destination of the
+// PostgreSQL log records. Exactly one of `file`, `syslog` and
+// `openTelemetry` must be specified
+// +kubebuilder:validation:XValidation:rule="(has(self.file) ? 1 : 0) + (has(self.syslog) ? 1 : 0) + (has(self.openTelemetry) ? 1 : 0) == 1",message="exactly one of file, syslog and openTelemetry is required"
+type LogSinkConfiguration struct {
+	// The name of the sink, referenced by the routes. `stdout` is reserved
+	// for the built-in sink
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Write the log records to a rotating file in the log sinks volume
+	// +optional
+	File *FileLogSinkConfiguration `json:"file,omitempty"`
+
+	// Forward the log records to a syslog server
+	// +optional
+	Syslog *SyslogLogSinkConfiguration `json:"syslog,omitempty"`
+
+	// Push the log records to an OpenTelemetry collector
+	// +optional
+	OpenTelemetry *OpenTelemetryLogSinkConfiguration `json:"openTelemetry,omitempty"`
+}
+
+// FileLogSinkConfiguration is the configuration of a log sink writing
+// the log records, in JSON format, to the `<name>.log` file in the
+// log sinks volume
+type FileLogSinkConfiguration struct {
+	// The size after which the file is rotated. Default: `100Mi`
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// The number of rotated files to keep. Default: `5`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxFiles int32 `json:"maxFiles,omitempty"`
+}
+
+// GetMaxSize returns the size, in bytes, after which the file is rotated
+func (c *FileLogSinkConfiguration) GetMaxSize() int64 {
+	if c == nil || c.MaxSize == nil || c.MaxSize.Value() <= 0 {
+		return DefaultFileLogSinkMaxSize
+	}
+	return c.MaxSize.Value()
+}
+
+// GetMaxFiles returns the number of rotated files to keep
+func (c *FileLogSinkConfiguration) GetMaxFiles() int {
+	if c == nil || c.MaxFiles <= 0 {
+		return DefaultFileLogSinkMaxFiles
+	}
+	return int(c.MaxFiles)
+}
+
+// SyslogLogSinkConfiguration is the configuration of a log sink forwarding
+// the log records, in JSON format, to a syslog server
+type SyslogLogSinkConfiguration struct {
+	// The address of the syslog server, in the `host:port` format
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// The transport protocol, `udp` or `tcp`. Default: `udp`
+	// +kubebuilder:validation:Enum=udp;tcp
+	// +kubebuilder:default:=udp
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// The syslog facility, from `local0` to `local7`. Default: `local0`
+	// +kubebuilder:validation:Enum=local0;local1;local2;local3;local4;local5;local6;local7
+	// +kubebuilder:default:=local0
+	// +optional
+	Facility string `json:"facility,omitempty"`
+
+	// The syslog tag of the messages. Default: `postgres`
+	// +optional
+	Tag string `json:"tag,omitempty"`
+}
+
+// OpenTelemetryLogSinkConfiguration is the configuration of a log sink
+// pushing the log records via the OpenTelemetry protocol (OTLP)
+type OpenTelemetryLogSinkConfiguration struct {
+	// The URL of the OTLP/HTTP endpoint of the collector, like
+	// `http://otel-collector.monitoring:4318`. Plain `http` disables TLS.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Additional resource attributes attached to every exported
+	// log record
+	// +optional
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+}
+
+// LogRouteConfiguration is a rule choosing the sinks of the log records
+type LogRouteConfiguration struct {
+	// The loggers whose records match this rule, like `postgres`, `pgaudit`
+	// or `wal`. A logger also matches the records of its children, i.e.
+	// `wal` matches `wal-archive` and `wal-restore`. When empty, the
+	// records of every logger match
+	// +optional
+	Loggers []string `json:"loggers,omitempty"`
+
+	// The minimum severity of the records matching this rule, in the
+	// PostgreSQL `log_min_messages` order. When empty, every severity matches
+	// +kubebuilder:validation:Enum=DEBUG5;DEBUG4;DEBUG3;DEBUG2;DEBUG1;INFO;NOTICE;WARNING;ERROR;LOG;FATAL;PANIC
+	// +optional
+	MinSeverity string `json:"minSeverity,omitempty"`
+
+	// The names of the sinks receiving the matching records. Use `stdout`
+	// for the standard output of the instance manager
+	// +kubebuilder:validation:MinItems=1
+	Sinks []string `json:"sinks"`
 }
 
 // ClusterMonitoringTLSConfiguration is the type containing the TLS configuration
@@ -2388,10 +3012,25 @@ type RoleConfiguration struct {
 	ConnectionLimit int64 `json:"connectionLimit,omitempty"`
 
 	// Date and time after which the role's password is no longer valid.
-	// When omitted, the password will never expire (default).
+	// When omitted, the password will never expire (default), unless
+	// a password rotation policy is defined.
 	// +optional
 	ValidUntil *metav1.Time `json:"validUntil,omitempty"`
 
+	// PasswordRotation enables the periodic rotation of the password
+	// stored in the `passwordSecret`, which is regenerated by the operator
+	// on schedule. The role keeps its previous password, stored in the
+	// `<passwordSecret>-previous` Secret, until the end of the grace period
+	// +optional
+	PasswordRotation *PasswordRotationPolicy `json:"passwordRotation,omitempty"`
+
+	// ClientCertificate requests a TLS client certificate for the role,
+	// signed by the client CA of the cluster and renewed before its
+	// expiration. When set, the role is required to authenticate with
+	// its certificate on TLS connections
+	// +optional
+	ClientCertificate *RoleClientCertificate `json:"clientCertificate,omitempty"`
+
 	// List of one or more existing roles to which this role will be
 	// immediately added as a new member. Default empty.
 	// +optional
@@ -2449,6 +3088,31 @@ type RoleConfiguration struct {
 	BypassRLS bool `json:"bypassrls,omitempty"` // Row-Level Security
 }
 
+// RoleClientCertificate defines the TLS client certificate issued by the
+// operator for a managed role
+type RoleClientCertificate struct {
+	// SecretName is the name of the TLS secret where the operator stores
+	// the client certificate and its private key
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// PasswordRotationPolicy defines how often the operator rotates the
+// password of a managed role
+type PasswordRotationPolicy struct {
+	// Interval between two consecutive password rotations, e.g. `720h`
+	// for a 30 days rotation
+	Interval metav1.Duration `json:"interval"`
+
+	// GracePeriod is the time after a rotation during which the role
+	// keeps the previous password, stored in the `<passwordSecret>-previous`
+	// Secret, allowing applications to load the new one before it is applied.
+	// It is also added to the interval when computing the expiration
+	// of the password. Defaults to one hour.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
@@ -2535,6 +3199,12 @@ type SecretsResourceVersion struct {
 	// Map keys are the secret names, map values are the versions
 	// +optional
 	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// The resource versions of the secrets referenced by the Database
+	// and Role objects. The version is empty for the secrets which don't
+	// exist or don't allow being referenced
+	// +optional
+	DeclarativeSecretVersions map[string]string `json:"declarativeSecretVersion,omitempty"`
 }
 
 // ConfigMapResourceVersion is the resource versions of the secrets
@@ -2544,6 +3214,12 @@ type ConfigMapResourceVersion struct {
 	// Map keys are the config map names, map values are the versions
 	// +optional
 	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// The resource versions of the config maps referenced by the Database
+	// objects. The version is empty for the config maps which don't
+	// exist or don't allow being referenced
+	// +optional
+	DeclarativeConfigMapVersions map[string]string `json:"declarativeConfigMapVersion,omitempty"`
 }
 
 func init() {
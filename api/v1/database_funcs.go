@@ -20,6 +20,9 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 )
@@ -88,3 +91,124 @@ func (dbObject DatabaseObjectSpec) GetEnsure() EnsureOption {
 func (dbObject DatabaseObjectSpec) GetName() string {
 	return dbObject.Name
 }
+
+// GetEnsure gets the ensure status of the resource
+func (m UserMappingSpec) GetEnsure() EnsureOption {
+	return m.Ensure
+}
+
+// GetName gets the name identifying the user mapping
+func (m UserMappingSpec) GetName() string {
+	return fmt.Sprintf("%s@%s", m.User, m.Server)
+}
+
+// GetEnsure gets the ensure status of the resource
+func (p PrivilegeSpec) GetEnsure() EnsureOption {
+	return p.Ensure
+}
+
+// GetName gets a human-readable description of the privileges, which
+// is used to identify them in the status
+func (p PrivilegeSpec) GetName() string {
+	var target string
+	switch {
+	case p.ObjectType == PrivilegeObjectTypeDatabase:
+		target = "DATABASE"
+	case p.ObjectType == PrivilegeObjectTypeSchema:
+		target = fmt.Sprintf("SCHEMA %s", p.Schema)
+	case p.ObjectType == PrivilegeObjectTypeForeignDataWrapper:
+		target = fmt.Sprintf("FOREIGN DATA WRAPPER %s", strings.Join(p.Objects, ", "))
+	case p.ObjectType == PrivilegeObjectTypeForeignServer:
+		target = fmt.Sprintf("FOREIGN SERVER %s", strings.Join(p.Objects, ", "))
+	case len(p.Objects) == 0:
+		target = fmt.Sprintf("ALL %sS IN SCHEMA %s", strings.ToUpper(string(p.ObjectType)), p.Schema)
+	default:
+		qualifiedNames := make([]string, len(p.Objects))
+		for i, name := range p.Objects {
+			qualifiedNames[i] = fmt.Sprintf("%s.%s", p.Schema, name)
+		}
+		target = fmt.Sprintf("%s %s", strings.ToUpper(string(p.ObjectType)), strings.Join(qualifiedNames, ", "))
+	}
+
+	return fmt.Sprintf("%s ON %s TO %s", strings.Join(p.Privileges, ", "), target, p.Grantee)
+}
+
+// GetEnsure gets the ensure status of the resource
+func (p DefaultPrivilegeSpec) GetEnsure() EnsureOption {
+	return p.Ensure
+}
+
+// GetName gets a human-readable description of the default privileges,
+// which is used to identify them in the status
+func (p DefaultPrivilegeSpec) GetName() string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("FOR ROLE %s ", p.Role))
+	if len(p.Schema) > 0 {
+		result.WriteString(fmt.Sprintf("IN SCHEMA %s ", p.Schema))
+	}
+	result.WriteString(fmt.Sprintf("%s ON %s TO %s",
+		strings.Join(p.Privileges, ", "), strings.ToUpper(string(p.ObjectType)), p.Grantee))
+	return result.String()
+}
+
+// GetAvailablePrivileges returns the privileges that can be granted
+// on this type of objects, as expanded by the `ALL` keyword
+func (t PrivilegeObjectType) GetAvailablePrivileges() []string {
+	switch t {
+	case PrivilegeObjectTypeDatabase:
+		return []string{"CREATE", "CONNECT", "TEMPORARY"}
+	case PrivilegeObjectTypeSchema:
+		return []string{"USAGE", "CREATE"}
+	case PrivilegeObjectTypeTable:
+		return []string{"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"}
+	case PrivilegeObjectTypeSequence:
+		return []string{"USAGE", "SELECT", "UPDATE"}
+	case PrivilegeObjectTypeFunction:
+		return []string{"EXECUTE"}
+	case PrivilegeObjectTypeForeignDataWrapper, PrivilegeObjectTypeForeignServer:
+		return []string{"USAGE"}
+	default:
+		return nil
+	}
+}
+
+// GetAvailablePrivileges returns the privileges that can be granted
+// on this type of objects, as expanded by the `ALL` keyword
+func (t DefaultPrivilegeObjectType) GetAvailablePrivileges() []string {
+	switch t {
+	case DefaultPrivilegeObjectTypeTables:
+		return PrivilegeObjectTypeTable.GetAvailablePrivileges()
+	case DefaultPrivilegeObjectTypeSequences:
+		return PrivilegeObjectTypeSequence.GetAvailablePrivileges()
+	case DefaultPrivilegeObjectTypeFunctions:
+		return PrivilegeObjectTypeFunction.GetAvailablePrivileges()
+	case DefaultPrivilegeObjectTypeTypes:
+		return []string{"USAGE"}
+	case DefaultPrivilegeObjectTypeSchemas:
+		return PrivilegeObjectTypeSchema.GetAvailablePrivileges()
+	default:
+		return nil
+	}
+}
+
+// DefaultMigrationsTrackingTable is the default name of the table
+// recording the applied schema migrations
+const DefaultMigrationsTrackingTable = "cnpg_schema_migrations"
+
+// GetTrackingTable returns the name of the table recording the
+// applied schema migrations
+func (migrations *DatabaseMigrations) GetTrackingTable() string {
+	if migrations.TrackingTable == "" {
+		return DefaultMigrationsTrackingTable
+	}
+	return migrations.TrackingTable
+}
+
+// GetSourcesResourceVersion returns the resource version of the
+// ConfigMaps and Secrets containing the migrations
+func (status *DatabaseMigrationsStatus) GetSourcesResourceVersion() map[string]string {
+	if status == nil {
+		return nil
+	}
+	return status.SourcesResourceVersion
+}
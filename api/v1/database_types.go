@@ -38,6 +38,22 @@ const (
 	DatabaseReclaimRetain DatabaseReclaimPolicy = "retain"
 )
 
+// TablespaceMoveSessionPolicy describes how the sessions connected to a
+// database are handled while moving it to another tablespace
+// +enum
+type TablespaceMoveSessionPolicy string
+
+const (
+	// TablespaceMoveSessionWait means that the database is moved once
+	// no session is connected to it. This is the default policy.
+	TablespaceMoveSessionWait TablespaceMoveSessionPolicy = "wait"
+
+	// TablespaceMoveSessionTerminate means that new connections to the
+	// database are prevented and the existing sessions are terminated
+	// before moving it
+	TablespaceMoveSessionTerminate TablespaceMoveSessionPolicy = "terminate"
+)
+
 // DatabaseSpec is the specification of a Postgresql Database, built around the
 // `CREATE DATABASE`, `ALTER DATABASE`, and `DROP DATABASE` SQL commands of
 // PostgreSQL.
@@ -160,6 +176,16 @@ type DatabaseSpec struct {
 	// +optional
 	Tablespace string `json:"tablespace,omitempty"`
 
+	// How the sessions connected to the database are handled when it's
+	// moved to another tablespace, as PostgreSQL requires no session to be
+	// connected. With `wait` (default) the move is retried until no session
+	// is connected, with `terminate` new connections are prevented and the
+	// existing sessions are terminated
+	// +kubebuilder:validation:Enum=wait;terminate
+	// +kubebuilder:default:=wait
+	// +optional
+	TablespaceMoveSessionPolicy TablespaceMoveSessionPolicy `json:"tablespaceMoveSessionPolicy,omitempty"`
+
 	// The policy for end-of-life maintenance of this database.
 	// +kubebuilder:validation:Enum=delete;retain
 	// +kubebuilder:default:=retain
@@ -173,6 +199,33 @@ type DatabaseSpec struct {
 	// The list of extensions to be managed in the database
 	// +optional
 	Extensions []ExtensionSpec `json:"extensions,omitempty"`
+
+	// The list of foreign data wrappers to be managed in the database
+	// +optional
+	FDWs []FDWSpec `json:"fdws,omitempty"`
+
+	// The list of foreign servers to be managed in the database
+	// +optional
+	Servers []ServerSpec `json:"servers,omitempty"`
+
+	// The list of user mappings to be managed in the database
+	// +optional
+	UserMappings []UserMappingSpec `json:"userMappings,omitempty"`
+
+	// The list of privileges to be granted or revoked on the database
+	// and on the objects it contains
+	// +optional
+	Privileges []PrivilegeSpec `json:"privileges,omitempty"`
+
+	// The list of default privileges to be applied to the objects that
+	// will be created in the database
+	// +optional
+	DefaultPrivileges []DefaultPrivilegeSpec `json:"defaultPrivileges,omitempty"`
+
+	// The versioned schema migrations to be applied to the database,
+	// after the other database objects have been reconciled
+	// +optional
+	Migrations *DatabaseMigrations `json:"migrations,omitempty"`
 }
 
 // DatabaseObjectSpec contains the fields which are common to every
@@ -218,6 +271,270 @@ type ExtensionSpec struct {
 	// specify a schema either, the current default object creation schema
 	// is used.
 	Schema string `json:"schema,omitempty"`
+
+	// Whether the extension is updated to the default version shipped
+	// with the images after they change, overriding the
+	// `extensionUpdatePolicy` of the cluster. Extensions having a
+	// `version` are never updated automatically
+	// +kubebuilder:validation:Enum:=manual;automatic
+	// +optional
+	UpdatePolicy ExtensionUpdatePolicy `json:"updatePolicy,omitempty"`
+}
+
+// FDWSpec configures a foreign data wrapper in a database
+type FDWSpec struct {
+	// Common fields
+	DatabaseObjectSpec `json:",inline"`
+
+	// The name of the handler function of the foreign data wrapper. It
+	// maps to the `HANDLER` clause of `CREATE FOREIGN DATA WRAPPER` and
+	// `ALTER FOREIGN DATA WRAPPER`. Use `-` to remove the handler
+	// +optional
+	Handler string `json:"handler,omitempty"`
+
+	// The name of the validator function of the foreign data wrapper.
+	// It maps to the `VALIDATOR` clause of `CREATE FOREIGN DATA WRAPPER`
+	// and `ALTER FOREIGN DATA WRAPPER`. Use `-` to remove the validator
+	// +optional
+	Validator string `json:"validator,omitempty"`
+
+	// The role name of the user who owns the foreign data wrapper. It
+	// maps to the `OWNER TO` command of `ALTER FOREIGN DATA WRAPPER`
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// The options of the foreign data wrapper. Options which are not
+	// listed here are removed
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// ServerSpec configures a foreign server in a database
+type ServerSpec struct {
+	// Common fields
+	DatabaseObjectSpec `json:",inline"`
+
+	// The name of the foreign data wrapper managing the server. It maps
+	// to the `FOREIGN DATA WRAPPER` clause of `CREATE SERVER`. This setting
+	// cannot be changed
+	FdwName string `json:"fdw"`
+
+	// The role name of the user who owns the foreign server. It maps to
+	// the `OWNER TO` command of `ALTER SERVER`
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// The options of the foreign server, such as `host`, `port` and
+	// `dbname` when using `postgres_fdw`. Options which are not listed here
+	// are removed
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// UserMappingSpec configures the mapping of a role to a foreign server
+type UserMappingSpec struct {
+	// Specifies whether the user mapping should be present or absent in
+	// the database
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The role name being mapped to the foreign server. Use `PUBLIC` to
+	// define a mapping for every role without a specific one
+	User string `json:"user"`
+
+	// The name of the foreign server
+	Server string `json:"server"`
+
+	// The options of the user mapping. Options which are not listed here
+	// are removed
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// The Secret containing the credentials used to connect to the
+	// foreign server. The `username` and `password` keys of the secret
+	// are set as the `user` and `password` options of the user mapping.
+	// The secret must be in the same namespace of the Database
+	// +optional
+	CredentialsSecret *LocalObjectReference `json:"credentialsSecret,omitempty"`
+}
+
+// PrivilegeObjectType is the type of object a privilege refers to
+// +enum
+type PrivilegeObjectType string
+
+const (
+	// PrivilegeObjectTypeDatabase refers to the database itself
+	PrivilegeObjectTypeDatabase PrivilegeObjectType = "database"
+
+	// PrivilegeObjectTypeSchema refers to a schema
+	PrivilegeObjectTypeSchema PrivilegeObjectType = "schema"
+
+	// PrivilegeObjectTypeTable refers to tables, views, materialized
+	// views and foreign tables
+	PrivilegeObjectTypeTable PrivilegeObjectType = "table"
+
+	// PrivilegeObjectTypeSequence refers to sequences
+	PrivilegeObjectTypeSequence PrivilegeObjectType = "sequence"
+
+	// PrivilegeObjectTypeFunction refers to functions
+	PrivilegeObjectTypeFunction PrivilegeObjectType = "function"
+
+	// PrivilegeObjectTypeForeignDataWrapper refers to foreign data wrappers
+	PrivilegeObjectTypeForeignDataWrapper PrivilegeObjectType = "foreignDataWrapper"
+
+	// PrivilegeObjectTypeForeignServer refers to foreign servers
+	PrivilegeObjectTypeForeignServer PrivilegeObjectType = "foreignServer"
+)
+
+// DefaultPrivilegeObjectType is the type of object a default
+// privilege refers to
+// +enum
+type DefaultPrivilegeObjectType string
+
+const (
+	// DefaultPrivilegeObjectTypeTables refers to the tables that will be created
+	DefaultPrivilegeObjectTypeTables DefaultPrivilegeObjectType = "tables"
+
+	// DefaultPrivilegeObjectTypeSequences refers to the sequences that will be created
+	DefaultPrivilegeObjectTypeSequences DefaultPrivilegeObjectType = "sequences"
+
+	// DefaultPrivilegeObjectTypeFunctions refers to the functions that will be created
+	DefaultPrivilegeObjectTypeFunctions DefaultPrivilegeObjectType = "functions"
+
+	// DefaultPrivilegeObjectTypeTypes refers to the types that will be created
+	DefaultPrivilegeObjectTypeTypes DefaultPrivilegeObjectType = "types"
+
+	// DefaultPrivilegeObjectTypeSchemas refers to the schemas that will be created
+	DefaultPrivilegeObjectTypeSchemas DefaultPrivilegeObjectType = "schemas"
+)
+
+// PrivilegeSpec configures the privileges a role has on the database or
+// on a set of objects contained in it. It is built around the `GRANT` and
+// `REVOKE` SQL commands of PostgreSQL.
+// +kubebuilder:validation:XValidation:rule="self.objectType != 'database' || (!has(self.schema) && !has(self.objects))",message="schema and objects cannot be set when objectType is `database`"
+// +kubebuilder:validation:XValidation:rule="self.objectType in ['database', 'foreignDataWrapper', 'foreignServer'] || has(self.schema)",message="schema is required when objectType is `schema`, `table`, `sequence` or `function`"
+// +kubebuilder:validation:XValidation:rule="self.objectType != 'schema' || !has(self.objects)",message="objects cannot be set when objectType is `schema`"
+// +kubebuilder:validation:XValidation:rule="!(self.objectType in ['foreignDataWrapper', 'foreignServer']) || (has(self.objects) && !has(self.schema))",message="objects is required, and schema cannot be set, when objectType is `foreignDataWrapper` or `foreignServer`"
+type PrivilegeSpec struct {
+	// Specifies whether the privileges should be granted (`present`) or
+	// revoked (`absent`). It maps to the `GRANT` and `REVOKE` commands.
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The type of the objects the privileges refer to
+	// +kubebuilder:validation:Enum=database;schema;table;sequence;function;foreignDataWrapper;foreignServer
+	ObjectType PrivilegeObjectType `json:"objectType"`
+
+	// The schema containing the objects or, when `objectType` is
+	// `schema`, the schema the privileges refer to
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The names of the objects the privileges refer to. If empty, the
+	// privileges on tables, sequences and functions are applied to every
+	// object of the given type in the schema, using the `ALL ... IN SCHEMA`
+	// form. Functions must be referred to by signature, like `add(integer, integer)`
+	// +optional
+	Objects []string `json:"objects,omitempty"`
+
+	// The privileges to be granted or revoked
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=ALL;SELECT;INSERT;UPDATE;DELETE;TRUNCATE;REFERENCES;TRIGGER;USAGE;CREATE;CONNECT;TEMPORARY;EXECUTE
+	Privileges []string `json:"privileges"`
+
+	// The role name receiving the privileges. Use `PUBLIC` to refer to
+	// every role
+	Grantee string `json:"grantee"`
+
+	// Maps to the `WITH GRANT OPTION` clause of `GRANT`. If true, the
+	// grantee will be able to grant the privileges to others
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+}
+
+// DefaultPrivilegeSpec configures the privileges that will be applied
+// to the objects created in the future by a role. It is built around the
+// `ALTER DEFAULT PRIVILEGES` SQL command of PostgreSQL.
+// +kubebuilder:validation:XValidation:rule="self.objectType != 'schemas' || !has(self.schema)",message="schema cannot be set when objectType is `schemas`"
+type DefaultPrivilegeSpec struct {
+	// Specifies whether the default privileges should be granted
+	// (`present`) or revoked (`absent`)
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The role creating the objects. It maps to the `FOR ROLE` clause
+	// of `ALTER DEFAULT PRIVILEGES`
+	Role string `json:"role"`
+
+	// The schema where the objects will be created. It maps to the
+	// `IN SCHEMA` clause of `ALTER DEFAULT PRIVILEGES`. If empty, the
+	// default privileges are applied to the whole database
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The type of the objects the default privileges refer to
+	// +kubebuilder:validation:Enum=tables;sequences;functions;types;schemas
+	ObjectType DefaultPrivilegeObjectType `json:"objectType"`
+
+	// The privileges to be granted or revoked
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=ALL;SELECT;INSERT;UPDATE;DELETE;TRUNCATE;REFERENCES;TRIGGER;USAGE;CREATE;EXECUTE
+	Privileges []string `json:"privileges"`
+
+	// The role name receiving the privileges. Use `PUBLIC` to refer to
+	// every role
+	Grantee string `json:"grantee"`
+
+	// Maps to the `WITH GRANT OPTION` clause of `GRANT`
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+}
+
+// DatabaseMigrations defines the versioned schema migrations of a database.
+// Every referenced key contains the SQL code of a migration, and its name
+// must follow the `V<version>__<description>.sql` format, e.g.
+// `V1.1__add_customers_index.sql`. The migrations are applied in version
+// order, each in its own transaction, and the applied versions are recorded
+// in a tracking table together with the checksum of their content
+type DatabaseMigrations struct {
+	// The references to the ConfigMaps and Secrets containing the
+	// migrations. The order of the references is not relevant
+	SQLRefs `json:",inline"`
+
+	// The name of the table, in the `public` schema, where the applied
+	// migrations are recorded
+	// +kubebuilder:default:=cnpg_schema_migrations
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="trackingTable is immutable"
+	// +optional
+	TrackingTable string `json:"trackingTable,omitempty"`
+}
+
+// DatabaseMigrationsStatus is the status of the schema migrations
+// of a database
+type DatabaseMigrationsStatus struct {
+	// The version of the latest applied migration
+	// +optional
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// The number of declared migrations which have been applied
+	// +optional
+	Applied int `json:"applied,omitempty"`
+
+	// The number of declared migrations which are still to be applied
+	// +optional
+	Pending int `json:"pending,omitempty"`
+
+	// SourcesResourceVersion contains the resource version of the
+	// ConfigMaps and Secrets containing the migrations, as seen during
+	// the latest reconciliation, indexed by `<kind>/<name>`
+	// +optional
+	SourcesResourceVersion map[string]string `json:"sourcesResourceVersion,omitempty"`
 }
 
 // DatabaseStatus defines the observed state of Database
@@ -242,6 +559,36 @@ type DatabaseStatus struct {
 	// Extensions is the status of the managed extensions
 	// +optional
 	Extensions []DatabaseObjectStatus `json:"extensions,omitempty"`
+
+	// Privileges is the status of the managed privileges
+	// +optional
+	Privileges []DatabaseObjectStatus `json:"privileges,omitempty"`
+
+	// DefaultPrivileges is the status of the managed default privileges
+	// +optional
+	DefaultPrivileges []DatabaseObjectStatus `json:"defaultPrivileges,omitempty"`
+
+	// FDWs is the status of the managed foreign data wrappers
+	// +optional
+	FDWs []DatabaseObjectStatus `json:"fdws,omitempty"`
+
+	// Servers is the status of the managed foreign servers
+	// +optional
+	Servers []DatabaseObjectStatus `json:"servers,omitempty"`
+
+	// UserMappings is the status of the managed user mappings
+	// +optional
+	UserMappings []DatabaseObjectStatus `json:"userMappings,omitempty"`
+
+	// SecretsResourceVersion contains the resource version of the
+	// secrets used by the user mappings, as seen during the latest
+	// reconciliation
+	// +optional
+	SecretsResourceVersion map[string]string `json:"secretsResourceVersion,omitempty"`
+
+	// Migrations is the status of the schema migrations
+	// +optional
+	Migrations *DatabaseMigrationsStatus `json:"migrations,omitempty"`
 }
 
 // DatabaseObjectStatus is the status of the managed database objects
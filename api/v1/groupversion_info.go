@@ -48,6 +48,12 @@ const (
 
 	// DatabaseKind is the kind name of databases
 	DatabaseKind = "Database"
+
+	// BlueGreenUpgradeKind is the kind name of blue-green upgrades
+	BlueGreenUpgradeKind = "BlueGreenUpgrade"
+
+	// ScheduledSQLKind is the kind name of scheduled SQL jobs
+	ScheduledSQLKind = "ScheduledSQL"
 )
 
 var (
@@ -19,7 +19,28 @@ SPDX-License-Identifier: Apache-2.0
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultPoolerAutoscalingCooldown is the default minimum time
+	// between two scaling operations of an autoscaled Pooler
+	DefaultPoolerAutoscalingCooldown = 5 * time.Minute
+
+	// DefaultPoolerAutoscalingTolerance is the default percentage by which
+	// the load of an autoscaled Pooler can differ from the target
+	DefaultPoolerAutoscalingTolerance = 10
+
+	// DefaultPoolerScaleDownStabilizationWindow is the default time window
+	// considered when scaling down an autoscaled Pooler
+	DefaultPoolerScaleDownStabilizationWindow = 5 * time.Minute
+)
 
 // IsPaused returns whether all database should be paused or not.
 func (in PgBouncerSpec) IsPaused() bool {
@@ -36,6 +57,35 @@ func (in *Pooler) GetAuthQuerySecretName() string {
 	return in.Spec.Cluster.Name + DefaultPgBouncerPoolerSecretSuffix
 }
 
+// GetCertificateAuthenticationSecretName returns the name of the secret
+// containing the client certificate the pooler uses to connect on behalf
+// of the clients authenticated through TLS certificates
+func (in *Pooler) GetCertificateAuthenticationSecretName() string {
+	return in.Name + PgBouncerCertificateAuthenticationSecretSuffix
+}
+
+// GetCertificateAuthenticationCommonName returns the common name of the
+// client certificate the pooler uses to connect on behalf of the clients
+// authenticated through TLS certificates
+func (in *Pooler) GetCertificateAuthenticationCommonName() string {
+	return PgBouncerCertificateAuthenticationCommonNamePrefix + in.Name
+}
+
+// GetCertificateAuthenticationUsers returns the sorted names of the
+// database users the clients authenticated through TLS certificates
+// are mapped to
+func (in *Pooler) GetCertificateAuthenticationUsers() []string {
+	if in.Spec.PgBouncer == nil || in.Spec.PgBouncer.CertificateAuthentication == nil {
+		return nil
+	}
+
+	users := stringset.New()
+	for _, mapping := range in.Spec.PgBouncer.CertificateAuthentication.UserMappings {
+		users.Put(mapping.User)
+	}
+	return users.ToSortedList()
+}
+
 // GetAuthQuery returns the specified AuthQuery name for PgBouncer
 // if provided or the default name otherwise.
 func (in *Pooler) GetAuthQuery() string {
@@ -73,3 +123,55 @@ func (in *Pooler) GetResourcesRequirements() corev1.ResourceRequirements {
 
 	return *in.Spec.Template.Spec.Resources
 }
+
+// GetCooldown returns the minimum time between two scaling operations
+func (in *PoolerAutoscalingConfiguration) GetCooldown() time.Duration {
+	if in.Cooldown == nil {
+		return DefaultPoolerAutoscalingCooldown
+	}
+
+	return in.Cooldown.Duration
+}
+
+// GetTolerance returns the fraction by which the observed load can
+// differ from the target without changing the number of instances
+func (in *PoolerAutoscalingConfiguration) GetTolerance() float64 {
+	if in.Tolerance == nil {
+		return DefaultPoolerAutoscalingTolerance / 100.0
+	}
+
+	return float64(*in.Tolerance) / 100
+}
+
+// GetScaleDownStabilizationWindow returns the time window during which
+// the highest recommended number of instances is kept when scaling down
+func (in *PoolerAutoscalingConfiguration) GetScaleDownStabilizationWindow() time.Duration {
+	if in.ScaleDownStabilizationWindow == nil {
+		return DefaultPoolerScaleDownStabilizationWindow
+	}
+
+	return in.ScaleDownStabilizationWindow.Duration
+}
+
+// IsReplicaRoutingEnabled returns whether the traffic of the Pooler
+// is routed only to the replicas whose lag is within the configured limit
+func (in *Pooler) IsReplicaRoutingEnabled() bool {
+	return in.Spec.Type == PoolerTypeRO && in.Spec.ReplicaRouting != nil
+}
+
+// GetServerHost returns the host PgBouncer connects to. This is the
+// service matching the type of the Pooler, unless lag-aware replica
+// routing selected the qualifying replicas or fell back to the primary
+func (in *Pooler) GetServerHost() string {
+	status := in.Status.ReplicaRouting
+	if in.IsReplicaRoutingEnabled() && status != nil {
+		switch {
+		case status.FallbackToPrimary:
+			return fmt.Sprintf("%s-%s", in.Spec.Cluster.Name, PoolerTypeRW)
+		case len(status.Hosts) > 0:
+			return strings.Join(status.Hosts, ",")
+		}
+	}
+
+	return fmt.Sprintf("%s-%s", in.Spec.Cluster.Name, in.Spec.Type)
+}
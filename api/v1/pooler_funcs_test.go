@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -37,4 +39,43 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+
+	Context("server host", func() {
+		var pooler *Pooler
+
+		BeforeEach(func() {
+			pooler = &Pooler{
+				Spec: PoolerSpec{
+					Cluster: LocalObjectReference{Name: "cluster-example"},
+					Type:    PoolerTypeRO,
+					ReplicaRouting: &PoolerReplicaRoutingConfiguration{
+						MaximumLag: resource.MustParse("16Mi"),
+					},
+				},
+			}
+		})
+
+		It("uses the service matching the type when no replica has been selected yet", func() {
+			Expect(pooler.GetServerHost()).To(Equal("cluster-example-ro"))
+		})
+
+		It("uses the selected replicas", func() {
+			pooler.Status.ReplicaRouting = &PoolerReplicaRoutingStatus{
+				Instances: []string{"cluster-example-2", "cluster-example-3"},
+				Hosts:     []string{"10.0.0.2", "10.0.0.3"},
+			}
+			Expect(pooler.GetServerHost()).To(Equal("10.0.0.2,10.0.0.3"))
+		})
+
+		It("falls back to the primary when no replica qualifies", func() {
+			pooler.Status.ReplicaRouting = &PoolerReplicaRoutingStatus{FallbackToPrimary: true}
+			Expect(pooler.GetServerHost()).To(Equal("cluster-example-rw"))
+		})
+
+		It("ignores the routing status when the routing is disabled", func() {
+			pooler.Spec.ReplicaRouting = nil
+			pooler.Status.ReplicaRouting = &PoolerReplicaRoutingStatus{FallbackToPrimary: true}
+			Expect(pooler.GetServerHost()).To(Equal("cluster-example-ro"))
+		})
+	})
 })
@@ -23,6 +23,7 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -80,7 +81,9 @@ type PoolerSpec struct {
 	// The PgBouncer configuration
 	PgBouncer *PgBouncerSpec `json:"pgbouncer"`
 
-	// The deployment strategy to use for pgbouncer to replace existing pods with new ones
+	// The deployment strategy to use for pgbouncer to replace existing pods with new ones.
+	// Defaults to a rolling update creating one new pod at a time and never
+	// reducing the number of available pods
 	// +optional
 	DeploymentStrategy *appsv1.DeploymentStrategy `json:"deploymentStrategy,omitempty"`
 
@@ -91,6 +94,69 @@ type PoolerSpec struct {
 	// Template for the Service to be created
 	// +optional
 	ServiceTemplate *ServiceTemplateSpec `json:"serviceTemplate,omitempty"`
+
+	// The automatic scaling policy of the PgBouncer instances, based on
+	// the load reported by PgBouncer. When set, the operator manages the
+	// `instances` field
+	// +optional
+	Autoscaling *PoolerAutoscalingConfiguration `json:"autoscaling,omitempty"`
+
+	// The routing of the traffic of a `ro` Pooler to the replicas,
+	// excluding the ones lagging behind the primary. When set, PgBouncer
+	// connects directly to the qualifying instances instead of using
+	// the `-ro` service
+	// +optional
+	ReplicaRouting *PoolerReplicaRoutingConfiguration `json:"replicaRouting,omitempty"`
+}
+
+// PoolerReplicaRoutingConfiguration describes which replicas are
+// eligible to receive the traffic of a read-only Pooler
+type PoolerReplicaRoutingConfiguration struct {
+	// The maximum amount of WAL a replica can have yet to replay,
+	// compared to the current position of the primary, to receive
+	// the traffic of the Pooler. When no replica qualifies, the
+	// traffic is sent to the primary
+	MaximumLag resource.Quantity `json:"maximumLag"`
+}
+
+// PoolerAutoscalingConfiguration describes how the number of PgBouncer
+// instances is adjusted depending on the load reported by PgBouncer
+type PoolerAutoscalingConfiguration struct {
+	// The minimum number of instances
+	// +kubebuilder:validation:Minimum=1
+	MinInstances int32 `json:"minInstances"`
+
+	// The maximum number of instances
+	// +kubebuilder:validation:Minimum=1
+	MaxInstances int32 `json:"maxInstances"`
+
+	// The target number of client connections waiting for a server
+	// connection (`cl_waiting`), averaged across the instances
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetClientsWaiting *int32 `json:"targetClientsWaiting,omitempty"`
+
+	// The target time spent by clients waiting for a server connection
+	// (`avg_wait_time`), averaged across the instances
+	// +optional
+	TargetAverageWaitTime *metav1.Duration `json:"targetAverageWaitTime,omitempty"`
+
+	// The minimum time between two scaling operations. Default: `5m`
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+
+	// The percentage by which the observed load can differ from the
+	// target without changing the number of instances. Default: `10`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Tolerance *int32 `json:"tolerance,omitempty"`
+
+	// The time window during which the highest number of instances
+	// recommended by the observed load is kept when scaling down,
+	// preventing the flapping caused by a fluctuating load. Default: `5m`
+	// +optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
 }
 
 // PoolerMonitoringConfiguration is the type containing all the monitoring
@@ -111,6 +177,10 @@ type PoolerMonitoringConfiguration struct {
 	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
 	// +optional
 	PodMonitorRelabelConfigs []monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+
+	// Push metrics and traces to an OpenTelemetry collector via OTLP/HTTP
+	// +optional
+	OpenTelemetry *OpenTelemetryConfiguration `json:"openTelemetry,omitempty"`
 }
 
 // PodTemplateSpec is a structure allowing the user to set
@@ -188,6 +258,101 @@ type PgBouncerSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// Per-database PgBouncer settings. Every entry generates a line in the
+	// `[databases]` section, taking precedence over the wildcard entry
+	// used for every other database
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PgBouncerDatabase `json:"databases,omitempty"`
+
+	// Per-user PgBouncer settings, generating the `[users]` section
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Users []PgBouncerUser `json:"users,omitempty"`
+
+	// Authentication of the clients through TLS certificates signed by
+	// the client CA of the cluster, instead of passwords. PgBouncer
+	// connects to PostgreSQL on behalf of the clients with a client
+	// certificate dedicated to the pooler, which requires the automated
+	// integration with the cluster
+	// +optional
+	CertificateAuthentication *PgBouncerCertificateAuthentication `json:"certificateAuthentication,omitempty"`
+}
+
+// PgBouncerCertificateAuthentication configures the authentication
+// of the PgBouncer clients through TLS client certificates
+type PgBouncerCertificateAuthentication struct {
+	// Maps the common names of the client certificates to the database
+	// users, which are also accessible with a certificate whose common
+	// name matches the user. The clients of the users without a mapping
+	// keep authenticating with passwords
+	// +optional
+	UserMappings []PgBouncerUserMapping `json:"userMappings,omitempty"`
+}
+
+// PgBouncerUserMapping allows the holder of a client
+// certificate to connect as a database user
+type PgBouncerUserMapping struct {
+	// The common name of the client certificate
+	// +kubebuilder:validation:MinLength=1
+	CommonName string `json:"commonName"`
+
+	// The database user
+	// +kubebuilder:validation:MinLength=1
+	User string `json:"user"`
+}
+
+// PgBouncerDatabase contains the PgBouncer settings of a database
+type PgBouncerDatabase struct {
+	// The name of the database, as requested by the clients
+	Name string `json:"name"`
+
+	// The name of the database in the PostgreSQL cluster. When it differs
+	// from `name`, the entry is an alias of this database.
+	// Defaults to `name`
+	// +optional
+	DBName string `json:"dbname,omitempty"`
+
+	// The pool mode of the database, overriding the global one
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections of each user/database pair
+	// (`pool_size`), overriding `default_pool_size`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The number of additional connections allowed to the pool when
+	// clients are waiting (`reserve_pool`), overriding `reserve_pool_size`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReservePoolSize *int32 `json:"reservePoolSize,omitempty"`
+
+	// The maximum number of server connections to the database, across
+	// every pool (`max_db_connections`)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDBConnections *int32 `json:"maxDBConnections,omitempty"`
+}
+
+// PgBouncerUser contains the PgBouncer settings of a user
+type PgBouncerUser struct {
+	// The name of the user
+	Name string `json:"name"`
+
+	// The pool mode of the user, overriding the database and the global ones
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum number of server connections of the user, across
+	// every pool (`max_user_connections`)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUserConnections *int32 `json:"maxUserConnections,omitempty"`
 }
 
 // PoolerStatus defines the observed state of Pooler
@@ -198,6 +363,124 @@ type PoolerStatus struct {
 	// The number of pods trying to be scheduled
 	// +optional
 	Instances int32 `json:"instances,omitempty"`
+
+	// The number of pods ready to accept connections
+	// +optional
+	ReadyInstances int32 `json:"readyInstances,omitempty"`
+
+	// The name of the PostgreSQL instance receiving
+	// the traffic of a Pooler of type rw
+	// +optional
+	TargetPrimary string `json:"targetPrimary,omitempty"`
+
+	// The names of the PostgreSQL instances receiving
+	// the traffic of a Pooler of type ro or r
+	// +optional
+	TargetInstances []string `json:"targetInstances,omitempty"`
+
+	// The connections handled by the PgBouncer pods, in aggregate
+	// +optional
+	Connections *PoolerConnectionsStatus `json:"connections,omitempty"`
+
+	// The status of every PgBouncer pod
+	// +optional
+	InstancesStatus []PoolerInstanceStatus `json:"instancesStatus,omitempty"`
+
+	// The status of the automatic scaling of the instances
+	// +optional
+	Autoscaling *PoolerAutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// The instances receiving the traffic of a Pooler
+	// using lag-aware replica routing
+	// +optional
+	ReplicaRouting *PoolerReplicaRoutingStatus `json:"replicaRouting,omitempty"`
+}
+
+// PoolerConnectionsStatus contains the number of connections
+// handled by PgBouncer, as reported by its metrics
+type PoolerConnectionsStatus struct {
+	// The number of client connections linked to a server connection
+	ClientsActive int32 `json:"clientsActive"`
+
+	// The number of client connections waiting for a server connection
+	ClientsWaiting int32 `json:"clientsWaiting"`
+
+	// The number of server connections linked to a client connection
+	ServersActive int32 `json:"serversActive"`
+
+	// The number of server connections available for a client connection
+	ServersIdle int32 `json:"serversIdle"`
+}
+
+// PoolerInstanceStatus contains the status of a PgBouncer pod
+type PoolerInstanceStatus struct {
+	// The name of the pod
+	PodName string `json:"podName"`
+
+	// True when the pod is ready to accept connections
+	Ready bool `json:"ready"`
+
+	// True when PgBouncer is paused
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// The connections handled by PgBouncer
+	// +optional
+	Connections *PoolerConnectionsStatus `json:"connections,omitempty"`
+
+	// The time when PgBouncer loaded its configuration for the last time
+	// +optional
+	LastReloadTime *metav1.Time `json:"lastReloadTime,omitempty"`
+
+	// The error encountered while reading the metrics of the pod
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// PoolerReplicaRoutingStatus contains the instances selected
+// by the lag-aware replica routing of a Pooler
+type PoolerReplicaRoutingStatus struct {
+	// The names of the replicas whose lag is within the configured limit
+	// +optional
+	Instances []string `json:"instances,omitempty"`
+
+	// The addresses of the replicas whose lag is within the
+	// configured limit, used by PgBouncer to reach them
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+
+	// True when no replica qualifies and the traffic
+	// is sent to the primary
+	// +optional
+	FallbackToPrimary bool `json:"fallbackToPrimary,omitempty"`
+}
+
+// PoolerAutoscalingStatus contains the decisions taken by the
+// automatic scaling of a Pooler
+type PoolerAutoscalingStatus struct {
+	// The time of the last scaling operation
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// The most recent scaling decisions, from the oldest to the newest
+	// +optional
+	Decisions []PoolerScalingDecision `json:"decisions,omitempty"`
+}
+
+// PoolerScalingDecision is a change of the number of instances
+// decided by the automatic scaling of a Pooler
+type PoolerScalingDecision struct {
+	// The time of the decision
+	Time metav1.Time `json:"time"`
+
+	// The number of instances before the decision
+	PreviousInstances int32 `json:"previousInstances"`
+
+	// The number of instances after the decision
+	Instances int32 `json:"instances"`
+
+	// The reason of the decision, including the observed load
+	Reason string `json:"reason"`
 }
 
 // PoolerSecrets contains the versions of all the secrets used
@@ -225,6 +508,12 @@ type PgBouncerSecrets struct {
 	// The auth query secret version
 	// +optional
 	AuthQuery SecretVersion `json:"authQuery,omitempty"`
+
+	// The version of the secret containing the client certificate used
+	// to connect on behalf of the clients authenticated through TLS
+	// certificates
+	// +optional
+	CertificateAuthentication SecretVersion `json:"certificateAuthentication,omitempty"`
 }
 
 // SecretVersion contains a secret name and its ResourceVersion
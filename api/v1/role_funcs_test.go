@@ -0,0 +1,64 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role conflicts with the cluster managed roles", func() {
+	var (
+		cluster *Cluster
+		role    *Role
+	)
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		}
+		role = &Role{
+			Spec: RoleSpec{
+				RoleConfiguration: RoleConfiguration{Name: "reader"},
+			},
+		}
+	})
+
+	It("doesn't complain when the cluster has no managed roles", func() {
+		Expect(role.MustNotConflictWithManagedRoles(cluster)).To(Succeed())
+	})
+
+	It("doesn't complain when the role is not managed by the cluster", func() {
+		cluster.Spec.Managed = &ManagedConfiguration{
+			Roles: []RoleConfiguration{{Name: "writer"}},
+		}
+		Expect(role.MustNotConflictWithManagedRoles(cluster)).To(Succeed())
+	})
+
+	It("complains when the role is managed by the cluster", func() {
+		cluster.Spec.Managed = &ManagedConfiguration{
+			Roles: []RoleConfiguration{{Name: "writer"}, {Name: "reader"}},
+		}
+		Expect(role.MustNotConflictWithManagedRoles(cluster)).To(
+			MatchError(`"reader" is already managed by cluster "cluster-example"`))
+	})
+})
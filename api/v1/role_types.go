@@ -0,0 +1,119 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RoleReclaimPolicy describes a policy for end-of-life maintenance of roles.
+// +enum
+type RoleReclaimPolicy string
+
+const (
+	// RoleReclaimDelete means the role will be dropped from PostgreSQL when
+	// the Role object is deleted from Kubernetes.
+	RoleReclaimDelete RoleReclaimPolicy = "delete"
+
+	// RoleReclaimRetain means the role will be left in PostgreSQL for manual
+	// reclamation by the administrator. The default policy is Retain.
+	RoleReclaimRetain RoleReclaimPolicy = "retain"
+)
+
+// RoleSpec is the specification of a PostgreSQL role, built around the
+// same fields used by the managed roles of a Cluster.
+// +kubebuilder:validation:XValidation:rule="self.name == oldSelf.name",message="name is immutable"
+// +kubebuilder:validation:XValidation:rule="self.name != 'postgres'",message="the name postgres is reserved"
+// +kubebuilder:validation:XValidation:rule="self.name != 'streaming_replica'",message="the name streaming_replica is reserved"
+// +kubebuilder:validation:XValidation:rule="!(has(self.passwordSecret) && has(self.disablePassword) && self.disablePassword)",message="passwordSecret and disablePassword are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!has(self.passwordRotation)",message="passwordRotation is only supported by the managed roles of a Cluster"
+// +kubebuilder:validation:XValidation:rule="!has(self.clientCertificate)",message="clientCertificate is only supported by the managed roles of a Cluster"
+type RoleSpec struct {
+	// The name of the PostgreSQL cluster hosting the role.
+	ClusterRef corev1.LocalObjectReference `json:"cluster"`
+
+	// The configuration of the role inside PostgreSQL
+	RoleConfiguration `json:",inline"`
+
+	// The policy for end-of-life maintenance of this role.
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=retain
+	// +optional
+	ReclaimPolicy RoleReclaimPolicy `json:"roleReclaimPolicy,omitempty"`
+}
+
+// RoleResourceStatus defines the observed state of a Role
+type RoleResourceStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Applied is true if the role was reconciled correctly
+	// +optional
+	Applied *bool `json:"applied,omitempty"`
+
+	// Message is the reconciliation output message
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// PasswordState is the last transaction ID and password secret
+	// version applied to the role
+	// +optional
+	PasswordState *PasswordState `json:"passwordState,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Applied",type="boolean",JSONPath=".status.applied"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message",description="Latest reconciliation message"
+
+// Role is the Schema for the roles API
+type Role struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Role.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec RoleSpec `json:"spec"`
+	// Most recently observed status of the Role. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status RoleResourceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RoleList contains a list of Role
+type RoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Role `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Role{}, &RoleList{})
+}
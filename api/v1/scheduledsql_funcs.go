@@ -0,0 +1,78 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"slices"
+)
+
+// SetAsFailed records why the scheduled SQL job cannot be scheduled
+func (s *ScheduledSQL) SetAsFailed(err error) {
+	s.Status.Message = err.Error()
+}
+
+// IsSuspended checks if the scheduled SQL job is suspended or not
+func (s *ScheduledSQL) IsSuspended() bool {
+	return s.Spec.Suspend != nil && *s.Spec.Suspend
+}
+
+// GetTarget returns the instance where the job is executed,
+// defaulting to the primary
+func (s *ScheduledSQL) GetTarget() ScheduledSQLTarget {
+	if s.Spec.Target == "" {
+		return ScheduledSQLTargetPrimary
+	}
+	return s.Spec.Target
+}
+
+// GetConcurrencyPolicy returns how concurrent runs are handled,
+// defaulting to Forbid
+func (s *ScheduledSQL) GetConcurrencyPolicy() ScheduledSQLConcurrencyPolicy {
+	if s.Spec.ConcurrencyPolicy == "" {
+		return ScheduledSQLConcurrencyForbid
+	}
+	return s.Spec.ConcurrencyPolicy
+}
+
+// GetExecutingInstance returns the name of the instance of the passed
+// cluster that is in charge of executing the job, or an empty string if
+// no instance is eligible. When the target is a replica, the first healthy
+// replica in alphabetical order is chosen, so that a single instance
+// executes the job
+func (s *ScheduledSQL) GetExecutingInstance(cluster *Cluster) string {
+	primary := cluster.Status.CurrentPrimary
+	if primary == "" || primary != cluster.Status.TargetPrimary {
+		return ""
+	}
+
+	if s.GetTarget() == ScheduledSQLTargetPrimary {
+		return primary
+	}
+
+	replicas := slices.DeleteFunc(
+		slices.Clone(cluster.Status.InstancesStatus[PodHealthy]),
+		func(name string) bool { return name == primary },
+	)
+	if len(replicas) == 0 {
+		return ""
+	}
+	slices.Sort(replicas)
+	return replicas[0]
+}
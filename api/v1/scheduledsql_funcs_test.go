@@ -0,0 +1,82 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"k8s.io/utils/ptr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduledSQL", func() {
+	var (
+		cluster      *Cluster
+		scheduledSQL *ScheduledSQL
+	)
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Status: ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				InstancesStatus: map[PodStatus][]string{
+					PodHealthy: {"cluster-example-3", "cluster-example-2", "cluster-example-1"},
+				},
+			},
+		}
+		scheduledSQL = &ScheduledSQL{}
+	})
+
+	It("applies the defaults", func() {
+		Expect(scheduledSQL.IsSuspended()).To(BeFalse())
+		Expect(scheduledSQL.GetTarget()).To(Equal(ScheduledSQLTargetPrimary))
+		Expect(scheduledSQL.GetConcurrencyPolicy()).To(Equal(ScheduledSQLConcurrencyForbid))
+
+		scheduledSQL.Spec.Suspend = ptr.To(true)
+		scheduledSQL.Spec.Target = ScheduledSQLTargetReplica
+		scheduledSQL.Spec.ConcurrencyPolicy = ScheduledSQLConcurrencyAllow
+		Expect(scheduledSQL.IsSuspended()).To(BeTrue())
+		Expect(scheduledSQL.GetTarget()).To(Equal(ScheduledSQLTargetReplica))
+		Expect(scheduledSQL.GetConcurrencyPolicy()).To(Equal(ScheduledSQLConcurrencyAllow))
+	})
+
+	It("is executed by the primary by default", func() {
+		Expect(scheduledSQL.GetExecutingInstance(cluster)).To(Equal("cluster-example-2"))
+	})
+
+	It("is executed by the first healthy replica when targeting replicas", func() {
+		scheduledSQL.Spec.Target = ScheduledSQLTargetReplica
+		Expect(scheduledSQL.GetExecutingInstance(cluster)).To(Equal("cluster-example-1"))
+		Expect(cluster.Status.InstancesStatus[PodHealthy]).To(
+			Equal([]string{"cluster-example-3", "cluster-example-2", "cluster-example-1"}))
+	})
+
+	It("is not executed when there are no healthy replicas", func() {
+		scheduledSQL.Spec.Target = ScheduledSQLTargetReplica
+		cluster.Status.InstancesStatus[PodHealthy] = []string{"cluster-example-2"}
+		Expect(scheduledSQL.GetExecutingInstance(cluster)).To(BeEmpty())
+	})
+
+	It("is not executed during a switchover", func() {
+		cluster.Status.TargetPrimary = "cluster-example-1"
+		Expect(scheduledSQL.GetExecutingInstance(cluster)).To(BeEmpty())
+	})
+})
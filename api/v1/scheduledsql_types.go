@@ -0,0 +1,178 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduledSQLTarget is the instance where a scheduled SQL job is executed
+// +enum
+type ScheduledSQLTarget string
+
+const (
+	// ScheduledSQLTargetPrimary means the job is executed on the primary instance
+	ScheduledSQLTargetPrimary ScheduledSQLTarget = "primary"
+
+	// ScheduledSQLTargetReplica means the job is executed on one of the
+	// healthy replicas, chosen deterministically among them
+	ScheduledSQLTargetReplica ScheduledSQLTarget = "replica"
+)
+
+// ScheduledSQLConcurrencyPolicy describes how a new run of a scheduled SQL
+// job is handled when the previous one is still running
+// +enum
+type ScheduledSQLConcurrencyPolicy string
+
+const (
+	// ScheduledSQLConcurrencyAllow allows the runs to be executed concurrently
+	ScheduledSQLConcurrencyAllow ScheduledSQLConcurrencyPolicy = "Allow"
+
+	// ScheduledSQLConcurrencyForbid skips the new run if the previous one
+	// is still running
+	ScheduledSQLConcurrencyForbid ScheduledSQLConcurrencyPolicy = "Forbid"
+
+	// ScheduledSQLConcurrencyReplace cancels the running run and replaces it
+	// with the new one
+	ScheduledSQLConcurrencyReplace ScheduledSQLConcurrencyPolicy = "Replace"
+)
+
+// ScheduledSQLSpec defines the desired state of ScheduledSQL
+type ScheduledSQLSpec struct {
+	// The name of the PostgreSQL cluster where the SQL is executed
+	ClusterRef corev1.LocalObjectReference `json:"cluster"`
+
+	// The name of the database where the SQL is executed
+	DBName string `json:"dbname"`
+
+	// The schedule does not follow the same format used in Kubernetes CronJobs
+	// as it includes an additional seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	Schedule string `json:"schedule"`
+
+	// The list of SQL statements to be executed, in order, at every run.
+	// The statements are executed in autocommit mode, on the same
+	// connection, and the run stops at the first failing one
+	// +kubebuilder:validation:MinItems=1
+	SQL []string `json:"sql"`
+
+	// The instance where the SQL is executed, either `primary` (default)
+	// or `replica`
+	// +kubebuilder:validation:Enum=primary;replica
+	// +kubebuilder:default:=primary
+	// +optional
+	Target ScheduledSQLTarget `json:"target,omitempty"`
+
+	// The maximum duration of a run, e.g. `10m`. When exceeded, the
+	// running statement is canceled and the run is marked as failed.
+	// No timeout is applied when not set
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// How to handle a run when the previous one is still running. Valid
+	// values are `Forbid` (default), which skips the new run, `Allow`,
+	// which executes it concurrently, and `Replace`, which cancels the
+	// running one
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +kubebuilder:default:=Forbid
+	// +optional
+	ConcurrencyPolicy ScheduledSQLConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// If this job is suspended or not
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+}
+
+// ScheduledSQLRunStatus is the outcome of a run of a scheduled SQL job
+type ScheduledSQLRunStatus struct {
+	// The name of the instance where the run has been executed
+	Instance string `json:"instance"`
+
+	// The time when the run started
+	StartTime metav1.Time `json:"startTime"`
+
+	// The time when the run completed, empty while the run is in progress
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// The duration of the run
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The error raised by the run, empty if it succeeded
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ScheduledSQLStatus defines the observed state of ScheduledSQL
+type ScheduledSQLStatus struct {
+	// The latest time the job has been scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// The next time the job will be scheduled
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// The latest time a run completed successfully
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// The outcome of the latest run
+	// +optional
+	LastRun *ScheduledSQLRunStatus `json:"lastRun,omitempty"`
+
+	// Message is the reconciliation output message, reporting why the job
+	// cannot be scheduled or why the latest run has been skipped
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last Run",type="date",JSONPath=".status.lastRun.startTime"
+// +kubebuilder:printcolumn:name="Last Error",type="string",JSONPath=".status.lastRun.error"
+
+// ScheduledSQL is the Schema for the scheduledsqls API
+type ScheduledSQL struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   ScheduledSQLSpec   `json:"spec"`
+	Status ScheduledSQLStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledSQLList contains a list of ScheduledSQL
+type ScheduledSQLList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledSQL `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledSQL{}, &ScheduledSQLList{})
+}
@@ -77,6 +77,26 @@ type SubscriptionSpec struct {
 	// +kubebuilder:default:=retain
 	// +optional
 	ReclaimPolicy SubscriptionReclaimPolicy `json:"subscriptionReclaimPolicy,omitempty"`
+
+	// The configuration of the synchronization of the sequences values from
+	// the "publisher", which are not replicated by logical replication
+	// +optional
+	SequenceSync *SubscriptionSequenceSyncConfiguration `json:"sequenceSync,omitempty"`
+}
+
+// SubscriptionSequenceSyncConfiguration defines how the values of the
+// sequences are synchronized from the "publisher". Regardless of this
+// configuration, a synchronization can be requested on demand by setting
+// the `cnpg.io/syncSequences` annotation to a new value
+type SubscriptionSequenceSyncConfiguration struct {
+	// The time between two synchronizations of the sequences, e.g. `5m`.
+	// When not set, the sequences are only synchronized on demand
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// The number to add to every sequence value before being updated
+	// +optional
+	Offset int64 `json:"offset,omitempty"`
 }
 
 // SubscriptionStatus defines the observed state of Subscription
@@ -93,8 +113,145 @@ type SubscriptionStatus struct {
 	// Message is the reconciliation output message
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// Runtime is the state of the logical replication of the subscription,
+	// as observed in the "subscriber" cluster
+	// +optional
+	Runtime *SubscriptionRuntimeStatus `json:"runtime,omitempty"`
+
+	// Conditions for the subscription object
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// SequenceSync is the status of the synchronization of the sequences
+	// +optional
+	SequenceSync *SubscriptionSequenceSyncStatus `json:"sequenceSync,omitempty"`
+}
+
+// SubscriptionSequenceSyncStatus is the status of the synchronization of
+// the sequences of a subscription
+type SubscriptionSequenceSyncStatus struct {
+	// LastSyncTime is the time of the last successful synchronization
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// UpdatedSequences is the number of sequences updated by the last
+	// successful synchronization
+	// +optional
+	UpdatedSequences int `json:"updatedSequences,omitempty"`
+
+	// LastRequest is the value of the `cnpg.io/syncSequences` annotation
+	// which triggered the last successful on-demand synchronization
+	// +optional
+	LastRequest string `json:"lastRequest,omitempty"`
+
+	// Error is the error raised by the last synchronization attempt,
+	// empty if it succeeded
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// SubscriptionTableSyncState is the synchronization state of a table
+// replicated by a subscription
+// +enum
+type SubscriptionTableSyncState string
+
+const (
+	// SubscriptionTableSyncStateInit means the table synchronization is
+	// being initialized
+	SubscriptionTableSyncStateInit SubscriptionTableSyncState = "init"
+
+	// SubscriptionTableSyncStateDataCopy means the initial data of the
+	// table is being copied
+	SubscriptionTableSyncStateDataCopy SubscriptionTableSyncState = "data-copy"
+
+	// SubscriptionTableSyncStateFinishedCopy means the initial copy of the
+	// table is finished
+	SubscriptionTableSyncStateFinishedCopy SubscriptionTableSyncState = "finished-copy"
+
+	// SubscriptionTableSyncStateSynchronized means the table is
+	// synchronized, and is being handed over to the apply worker
+	SubscriptionTableSyncStateSynchronized SubscriptionTableSyncState = "synchronized"
+
+	// SubscriptionTableSyncStateReady means the changes to the table
+	// are being replicated by the apply worker
+	SubscriptionTableSyncStateReady SubscriptionTableSyncState = "ready"
+
+	// SubscriptionTableSyncStateUnknown means PostgreSQL reported an
+	// unknown state
+	SubscriptionTableSyncStateUnknown SubscriptionTableSyncState = "unknown"
+)
+
+// SubscriptionRuntimeStatus is the state of the logical replication of a
+// subscription, as reported by `pg_stat_subscription`,
+// `pg_stat_subscription_stats` and `pg_subscription_rel`
+type SubscriptionRuntimeStatus struct {
+	// Enabled is true if the subscription is enabled in PostgreSQL
+	Enabled bool `json:"enabled"`
+
+	// WorkerActive is true if the apply worker of the subscription
+	// is running
+	WorkerActive bool `json:"workerActive"`
+
+	// ReceivedLSN is the last write-ahead log location received by
+	// the apply worker
+	// +optional
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+
+	// ApplyLag is the time elapsed since the apply worker reported the
+	// last write-ahead log location to the publisher
+	// +optional
+	ApplyLag *metav1.Duration `json:"applyLag,omitempty"`
+
+	// ApplyErrorCount is the number of errors raised while applying
+	// changes. Requires PostgreSQL 15 or later
+	// +optional
+	ApplyErrorCount int64 `json:"applyErrorCount,omitempty"`
+
+	// SyncErrorCount is the number of errors raised during the initial
+	// synchronization of the tables. Requires PostgreSQL 15 or later
+	// +optional
+	SyncErrorCount int64 `json:"syncErrorCount,omitempty"`
+
+	// Tables is the synchronization state of each replicated table,
+	// indexed by its qualified name
+	// +optional
+	Tables map[string]SubscriptionTableSyncState `json:"tables,omitempty"`
 }
 
+// SubscriptionConditionType defines types of subscription conditions
+type SubscriptionConditionType string
+
+const (
+	// ConditionSubscriptionReplicating is true when the apply worker of the
+	// subscription is running and all the tables have been synchronized
+	ConditionSubscriptionReplicating SubscriptionConditionType = "Replicating"
+)
+
+// These are the reasons of the Replicating condition of a subscription
+const (
+	// SubscriptionReasonReplicating means the apply worker is running and all
+	// the tables are replicated
+	SubscriptionReasonReplicating ConditionReason = "Replicating"
+
+	// SubscriptionReasonSynchronizing means the apply worker is running, but
+	// the initial synchronization of some tables is still in progress
+	SubscriptionReasonSynchronizing ConditionReason = "Synchronizing"
+
+	// SubscriptionReasonDisabled means the subscription is disabled
+	SubscriptionReasonDisabled ConditionReason = "Disabled"
+
+	// SubscriptionReasonWorkerNotRunning means the subscription is enabled but
+	// its apply worker is not running, e.g. due to an error
+	SubscriptionReasonWorkerNotRunning ConditionReason = "WorkerNotRunning"
+
+	// SubscriptionReasonNotFound means the subscription does not exist
+	// in PostgreSQL anymore
+	SubscriptionReasonNotFound ConditionReason = "NotFound"
+)
+
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
@@ -345,6 +345,146 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenUpgrade) DeepCopyInto(out *BlueGreenUpgrade) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenUpgrade.
+func (in *BlueGreenUpgrade) DeepCopy() *BlueGreenUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlueGreenUpgrade) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenUpgradeDatabaseStatus) DeepCopyInto(out *BlueGreenUpgradeDatabaseStatus) {
+	*out = *in
+	if in.ApplyLag != nil {
+		in, out := &in.ApplyLag, &out.ApplyLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenUpgradeDatabaseStatus.
+func (in *BlueGreenUpgradeDatabaseStatus) DeepCopy() *BlueGreenUpgradeDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenUpgradeDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenUpgradeList) DeepCopyInto(out *BlueGreenUpgradeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BlueGreenUpgrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenUpgradeList.
+func (in *BlueGreenUpgradeList) DeepCopy() *BlueGreenUpgradeList {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenUpgradeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlueGreenUpgradeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenUpgradeSpec) DeepCopyInto(out *BlueGreenUpgradeSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.ImageCatalogRef != nil {
+		in, out := &in.ImageCatalogRef, &out.ImageCatalogRef
+		*out = new(ImageCatalogRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenUpgradeSpec.
+func (in *BlueGreenUpgradeSpec) DeepCopy() *BlueGreenUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenUpgradeStatus) DeepCopyInto(out *BlueGreenUpgradeStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]BlueGreenUpgradeDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CutoverStartedAt != nil {
+		in, out := &in.CutoverStartedAt, &out.CutoverStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CutoverCompletedAt != nil {
+		in, out := &in.CutoverCompletedAt, &out.CutoverCompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.SwitchedPoolers != nil {
+		in, out := &in.SwitchedPoolers, &out.SwitchedPoolers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenUpgradeStatus.
+func (in *BlueGreenUpgradeStatus) DeepCopy() *BlueGreenUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 	*out = *in
@@ -796,6 +936,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ProjectedVolumeTemplate != nil {
 		in, out := &in.ProjectedVolumeTemplate, &out.ProjectedVolumeTemplate
 		*out = new(corev1.ProjectedVolumeSource)
@@ -891,7 +1036,9 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	if in.TablespacesStatus != nil {
 		in, out := &in.TablespacesStatus, &out.TablespacesStatus
 		*out = make([]TablespaceState, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
@@ -971,6 +1118,22 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	out.SwitchReplicaClusterStatus = in.SwitchReplicaClusterStatus
+	if in.LogicalImport != nil {
+		in, out := &in.LogicalImport, &out.LogicalImport
+		*out = new(LogicalImportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OnlineImport != nil {
+		in, out := &in.OnlineImport, &out.OnlineImport
+		*out = new(OnlineImportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+
+	if in.ExtensionUpdates != nil {
+		in, out := &in.ExtensionUpdates, &out.ExtensionUpdates
+		*out = new(ExtensionUpdatesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -993,6 +1156,13 @@ func (in *ConfigMapResourceVersion) DeepCopyInto(out *ConfigMapResourceVersion)
 			(*out)[key] = val
 		}
 	}
+	if in.DeclarativeConfigMapVersions != nil {
+		in, out := &in.DeclarativeConfigMapVersions, &out.DeclarativeConfigMapVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapResourceVersion.
@@ -1092,6 +1262,44 @@ func (in *DatabaseList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseMigrations) DeepCopyInto(out *DatabaseMigrations) {
+	*out = *in
+	in.SQLRefs.DeepCopyInto(&out.SQLRefs)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseMigrations.
+func (in *DatabaseMigrations) DeepCopy() *DatabaseMigrations {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseMigrations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseMigrationsStatus) DeepCopyInto(out *DatabaseMigrationsStatus) {
+	*out = *in
+	if in.SourcesResourceVersion != nil {
+		in, out := &in.SourcesResourceVersion, &out.SourcesResourceVersion
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseMigrationsStatus.
+func (in *DatabaseMigrationsStatus) DeepCopy() *DatabaseMigrationsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseMigrationsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseObjectSpec) DeepCopyInto(out *DatabaseObjectSpec) {
 	*out = *in
@@ -1166,6 +1374,46 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 		*out = make([]ExtensionSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.FDWs != nil {
+		in, out := &in.FDWs, &out.FDWs
+		*out = make([]FDWSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]ServerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]UserMappingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]PrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultPrivileges != nil {
+		in, out := &in.DefaultPrivileges, &out.DefaultPrivileges
+		*out = make([]DefaultPrivilegeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = new(DatabaseMigrations)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -1196,6 +1444,43 @@ func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 		*out = make([]DatabaseObjectStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultPrivileges != nil {
+		in, out := &in.DefaultPrivileges, &out.DefaultPrivileges
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.FDWs != nil {
+		in, out := &in.FDWs, &out.FDWs
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]DatabaseObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretsResourceVersion != nil {
+		in, out := &in.SecretsResourceVersion, &out.SecretsResourceVersion
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = new(DatabaseMigrationsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
@@ -1208,6 +1493,26 @@ func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultPrivilegeSpec) DeepCopyInto(out *DefaultPrivilegeSpec) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultPrivilegeSpec.
+func (in *DefaultPrivilegeSpec) DeepCopy() *DefaultPrivilegeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultPrivilegeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1362,63 +1667,130 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FailoverQuorum) DeepCopyInto(out *FailoverQuorum) {
+func (in *ExtensionUpdate) DeepCopyInto(out *ExtensionUpdate) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverQuorum.
-func (in *FailoverQuorum) DeepCopy() *FailoverQuorum {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionUpdate.
+func (in *ExtensionUpdate) DeepCopy() *ExtensionUpdate {
 	if in == nil {
 		return nil
 	}
-	out := new(FailoverQuorum)
+	out := new(ExtensionUpdate)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FailoverQuorum) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FailoverQuorumList) DeepCopyInto(out *FailoverQuorumList) {
+func (in *ExtensionUpdatesStatus) DeepCopyInto(out *ExtensionUpdatesStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]FailoverQuorum, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]ExtensionUpdate, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverQuorumList.
-func (in *FailoverQuorumList) DeepCopy() *FailoverQuorumList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionUpdatesStatus.
+func (in *ExtensionUpdatesStatus) DeepCopy() *ExtensionUpdatesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FailoverQuorumList)
+	out := new(ExtensionUpdatesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FailoverQuorumList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FDWSpec) DeepCopyInto(out *FDWSpec) {
+	*out = *in
+	out.DatabaseObjectSpec = in.DatabaseObjectSpec
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FDWSpec.
+func (in *FDWSpec) DeepCopy() *FDWSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FDWSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverQuorum) DeepCopyInto(out *FailoverQuorum) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverQuorum.
+func (in *FailoverQuorum) DeepCopy() *FailoverQuorum {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverQuorum)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FailoverQuorum) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverQuorumList) DeepCopyInto(out *FailoverQuorumList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FailoverQuorum, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverQuorumList.
+func (in *FailoverQuorumList) DeepCopy() *FailoverQuorumList {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverQuorumList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FailoverQuorumList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FailoverQuorumStatus) DeepCopyInto(out *FailoverQuorumStatus) {
 	*out = *in
@@ -1439,6 +1811,26 @@ func (in *FailoverQuorumStatus) DeepCopy() *FailoverQuorumStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileLogSinkConfiguration) DeepCopyInto(out *FileLogSinkConfiguration) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileLogSinkConfiguration.
+func (in *FileLogSinkConfiguration) DeepCopy() *FileLogSinkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FileLogSinkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
 	*out = *in
@@ -1577,6 +1969,11 @@ func (in *Import) DeepCopyInto(out *Import) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DatabaseOptions != nil {
+		in, out := &in.DatabaseOptions, &out.DatabaseOptions
+		*out = make([]ImportDatabaseOptions, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
@@ -1589,6 +1986,21 @@ func (in *Import) DeepCopy() *Import {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportDatabaseOptions) DeepCopyInto(out *ImportDatabaseOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportDatabaseOptions.
+func (in *ImportDatabaseOptions) DeepCopy() *ImportDatabaseOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportDatabaseOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImportSource) DeepCopyInto(out *ImportSource) {
 	*out = *in
@@ -1735,6 +2147,156 @@ func (in *LivenessProbe) DeepCopy() *LivenessProbe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogRouteConfiguration) DeepCopyInto(out *LogRouteConfiguration) {
+	*out = *in
+	if in.Loggers != nil {
+		in, out := &in.Loggers, &out.Loggers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogRouteConfiguration.
+func (in *LogRouteConfiguration) DeepCopy() *LogRouteConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LogRouteConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogSinkConfiguration) DeepCopyInto(out *LogSinkConfiguration) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileLogSinkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(SyslogLogSinkConfiguration)
+		**out = **in
+	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(OpenTelemetryLogSinkConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSinkConfiguration.
+func (in *LogSinkConfiguration) DeepCopy() *LogSinkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSinkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingConfiguration) DeepCopyInto(out *LoggingConfiguration) {
+	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]LogSinkConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]LogRouteConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfiguration.
+func (in *LoggingConfiguration) DeepCopy() *LoggingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalImportDatabaseStatus) DeepCopyInto(out *LogicalImportDatabaseStatus) {
+	*out = *in
+	if in.DumpTime != nil {
+		in, out := &in.DumpTime, &out.DumpTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RestoreTime != nil {
+		in, out := &in.RestoreTime, &out.RestoreTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PostImportSQLTime != nil {
+		in, out := &in.PostImportSQLTime, &out.PostImportSQLTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AnalyzeTime != nil {
+		in, out := &in.AnalyzeTime, &out.AnalyzeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalImportDatabaseStatus.
+func (in *LogicalImportDatabaseStatus) DeepCopy() *LogicalImportDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalImportDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalImportStatus) DeepCopyInto(out *LogicalImportStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]LogicalImportDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalImportStatus.
+func (in *LogicalImportStatus) DeepCopy() *LogicalImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 	*out = *in
@@ -1801,7 +2363,7 @@ func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
 		in, out := &in.PasswordStatus, &out.PasswordStatus
 		*out = make(map[string]PasswordState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 }
@@ -1925,6 +2487,16 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(OpenTelemetryConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PgStatStatements != nil {
+		in, out := &in.PgStatStatements, &out.PgStatStatements
+		*out = new(PgStatStatementsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -1983,63 +2555,274 @@ func (in *OnlineConfiguration) DeepCopy() *OnlineConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PasswordState) DeepCopyInto(out *PasswordState) {
+func (in *OnlineImportDatabaseStatus) DeepCopyInto(out *OnlineImportDatabaseStatus) {
 	*out = *in
+	if in.LagBytes != nil {
+		in, out := &in.LagBytes, &out.LagBytes
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordState.
-func (in *PasswordState) DeepCopy() *PasswordState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineImportDatabaseStatus.
+func (in *OnlineImportDatabaseStatus) DeepCopy() *OnlineImportDatabaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PasswordState)
+	out := new(OnlineImportDatabaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+func (in *OnlineImportStatus) DeepCopyInto(out *OnlineImportStatus) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]OnlineImportDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
-func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineImportStatus.
+func (in *OnlineImportStatus) DeepCopy() *OnlineImportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerIntegrationStatus)
+	out := new(OnlineImportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+func (in *OpenTelemetryConfiguration) DeepCopyInto(out *OpenTelemetryConfiguration) {
 	*out = *in
-	out.AuthQuery = in.AuthQuery
+	if in.ExportInterval != nil {
+		in, out := &in.ExportInterval, &out.ExportInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Traces != nil {
+		in, out := &in.Traces, &out.Traces
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResourceAttributes != nil {
+		in, out := &in.ResourceAttributes, &out.ResourceAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
-func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetryConfiguration.
+func (in *OpenTelemetryConfiguration) DeepCopy() *OpenTelemetryConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PgBouncerSecrets)
+	out := new(OpenTelemetryConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+func (in *OpenTelemetryLogSinkConfiguration) DeepCopyInto(out *OpenTelemetryLogSinkConfiguration) {
 	*out = *in
-	if in.AuthQuerySecret != nil {
-		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
-		*out = new(LocalObjectReference)
-		**out = **in
+	if in.ResourceAttributes != nil {
+		in, out := &in.ResourceAttributes, &out.ResourceAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetryLogSinkConfiguration.
+func (in *OpenTelemetryLogSinkConfiguration) DeepCopy() *OpenTelemetryLogSinkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenTelemetryLogSinkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationPolicy) DeepCopyInto(out *PasswordRotationPolicy) {
+	*out = *in
+	out.Interval = in.Interval
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationPolicy.
+func (in *PasswordRotationPolicy) DeepCopy() *PasswordRotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordState) DeepCopyInto(out *PasswordState) {
+	*out = *in
+	if in.RotatedAt != nil {
+		in, out := &in.RotatedAt, &out.RotatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordState.
+func (in *PasswordState) DeepCopy() *PasswordState {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerCertificateAuthentication) DeepCopyInto(out *PgBouncerCertificateAuthentication) {
+	*out = *in
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]PgBouncerUserMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerCertificateAuthentication.
+func (in *PgBouncerCertificateAuthentication) DeepCopy() *PgBouncerCertificateAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerCertificateAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerCertificateAuthenticationStatus) DeepCopyInto(out *PgBouncerCertificateAuthenticationStatus) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerCertificateAuthenticationStatus.
+func (in *PgBouncerCertificateAuthenticationStatus) DeepCopy() *PgBouncerCertificateAuthenticationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerCertificateAuthenticationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerDatabase) DeepCopyInto(out *PgBouncerDatabase) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReservePoolSize != nil {
+		in, out := &in.ReservePoolSize, &out.ReservePoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDBConnections != nil {
+		in, out := &in.MaxDBConnections, &out.MaxDBConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDatabase.
+func (in *PgBouncerDatabase) DeepCopy() *PgBouncerDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateAuthentication != nil {
+		in, out := &in.CertificateAuthentication, &out.CertificateAuthentication
+		*out = make([]PgBouncerCertificateAuthenticationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerIntegrationStatus.
+func (in *PgBouncerIntegrationStatus) DeepCopy() *PgBouncerIntegrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerIntegrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSecrets) DeepCopyInto(out *PgBouncerSecrets) {
+	*out = *in
+	out.AuthQuery = in.AuthQuery
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSecrets.
+func (in *PgBouncerSecrets) DeepCopy() *PgBouncerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
+	*out = *in
+	if in.AuthQuerySecret != nil {
+		in, out := &in.AuthQuerySecret, &out.AuthQuerySecret
+		*out = new(LocalObjectReference)
+		**out = **in
 	}
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
@@ -2058,6 +2841,25 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgBouncerDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PgBouncerUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CertificateAuthentication != nil {
+		in, out := &in.CertificateAuthentication, &out.CertificateAuthentication
+		*out = new(PgBouncerCertificateAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
@@ -2070,6 +2872,61 @@ func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerUser) DeepCopyInto(out *PgBouncerUser) {
+	*out = *in
+	if in.MaxUserConnections != nil {
+		in, out := &in.MaxUserConnections, &out.MaxUserConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerUser.
+func (in *PgBouncerUser) DeepCopy() *PgBouncerUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerUserMapping) DeepCopyInto(out *PgBouncerUserMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerUserMapping.
+func (in *PgBouncerUserMapping) DeepCopy() *PgBouncerUserMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerUserMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgStatStatementsConfiguration) DeepCopyInto(out *PgStatStatementsConfiguration) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgStatStatementsConfiguration.
+func (in *PgStatStatementsConfiguration) DeepCopy() *PgStatStatementsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgStatStatementsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
 	*out = *in
@@ -2207,6 +3064,87 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingConfiguration) DeepCopyInto(out *PoolerAutoscalingConfiguration) {
+	*out = *in
+	if in.TargetClientsWaiting != nil {
+		in, out := &in.TargetClientsWaiting, &out.TargetClientsWaiting
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetAverageWaitTime != nil {
+		in, out := &in.TargetAverageWaitTime, &out.TargetAverageWaitTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Tolerance != nil {
+		in, out := &in.Tolerance, &out.Tolerance
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingConfiguration.
+func (in *PoolerAutoscalingConfiguration) DeepCopy() *PoolerAutoscalingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAutoscalingStatus) DeepCopyInto(out *PoolerAutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]PoolerScalingDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAutoscalingStatus.
+func (in *PoolerAutoscalingStatus) DeepCopy() *PoolerAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerConnectionsStatus) DeepCopyInto(out *PoolerConnectionsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerConnectionsStatus.
+func (in *PoolerConnectionsStatus) DeepCopy() *PoolerConnectionsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerConnectionsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
 	*out = *in
@@ -2223,6 +3161,30 @@ func (in *PoolerIntegrations) DeepCopy() *PoolerIntegrations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerInstanceStatus) DeepCopyInto(out *PoolerInstanceStatus) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(PoolerConnectionsStatus)
+		**out = **in
+	}
+	if in.LastReloadTime != nil {
+		in, out := &in.LastReloadTime, &out.LastReloadTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerInstanceStatus.
+func (in *PoolerInstanceStatus) DeepCopy() *PoolerInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerList) DeepCopyInto(out *PoolerList) {
 	*out = *in
@@ -2272,6 +3234,11 @@ func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.OpenTelemetry != nil {
+		in, out := &in.OpenTelemetry, &out.OpenTelemetry
+		*out = new(OpenTelemetryConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
@@ -2279,7 +3246,64 @@ func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfigurati
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerMonitoringConfiguration)
+	out := new(PoolerMonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerReplicaRoutingConfiguration) DeepCopyInto(out *PoolerReplicaRoutingConfiguration) {
+	*out = *in
+	out.MaximumLag = in.MaximumLag.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerReplicaRoutingConfiguration.
+func (in *PoolerReplicaRoutingConfiguration) DeepCopy() *PoolerReplicaRoutingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerReplicaRoutingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerReplicaRoutingStatus) DeepCopyInto(out *PoolerReplicaRoutingStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerReplicaRoutingStatus.
+func (in *PoolerReplicaRoutingStatus) DeepCopy() *PoolerReplicaRoutingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerReplicaRoutingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerScalingDecision) DeepCopyInto(out *PoolerScalingDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerScalingDecision.
+func (in *PoolerScalingDecision) DeepCopy() *PoolerScalingDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerScalingDecision)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2341,6 +3365,16 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(ServiceTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaRouting != nil {
+		in, out := &in.ReplicaRouting, &out.ReplicaRouting
+		*out = new(PoolerReplicaRoutingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
@@ -2361,6 +3395,33 @@ func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
 		*out = new(PoolerSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TargetInstances != nil {
+		in, out := &in.TargetInstances, &out.TargetInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(PoolerConnectionsStatus)
+		**out = **in
+	}
+	if in.InstancesStatus != nil {
+		in, out := &in.InstancesStatus, &out.InstancesStatus
+		*out = make([]PoolerInstanceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PoolerAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaRouting != nil {
+		in, out := &in.ReplicaRouting, &out.ReplicaRouting
+		*out = new(PoolerReplicaRoutingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
@@ -2428,6 +3489,31 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivilegeSpec) DeepCopyInto(out *PrivilegeSpec) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivilegeSpec.
+func (in *PrivilegeSpec) DeepCopy() *PrivilegeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivilegeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Probe) DeepCopyInto(out *Probe) {
 	*out = *in
@@ -2759,6 +3845,48 @@ func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Role) DeepCopyInto(out *Role) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Role.
+func (in *Role) DeepCopy() *Role {
+	if in == nil {
+		return nil
+	}
+	out := new(Role)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Role) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleClientCertificate) DeepCopyInto(out *RoleClientCertificate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleClientCertificate.
+func (in *RoleClientCertificate) DeepCopy() *RoleClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 	*out = *in
@@ -2771,6 +3899,16 @@ func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 		in, out := &in.ValidUntil, &out.ValidUntil
 		*out = (*in).DeepCopy()
 	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(RoleClientCertificate)
+		**out = **in
+	}
 	if in.InRoles != nil {
 		in, out := &in.InRoles, &out.InRoles
 		*out = make([]string, len(*in))
@@ -2788,38 +3926,239 @@ func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(RoleConfiguration)
+	out := new(RoleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleList) DeepCopyInto(out *RoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Role, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleList.
+func (in *RoleList) DeepCopy() *RoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleResourceStatus) DeepCopyInto(out *RoleResourceStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PasswordState != nil {
+		in, out := &in.PasswordState, &out.PasswordState
+		*out = new(PasswordState)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleResourceStatus.
+func (in *RoleResourceStatus) DeepCopy() *RoleResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleSpec) DeepCopyInto(out *RoleSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	in.RoleConfiguration.DeepCopyInto(&out.RoleConfiguration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleSpec.
+func (in *RoleSpec) DeepCopy() *RoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLRefs) DeepCopyInto(out *SQLRefs) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLRefs.
+func (in *SQLRefs) DeepCopy() *SQLRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackup.
+func (in *ScheduledBackup) DeepCopy() *ScheduledBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackupList) DeepCopyInto(out *ScheduledBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupList.
+func (in *ScheduledBackupList) DeepCopy() *ScheduledBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Immediate != nil {
+		in, out := &in.Immediate, &out.Immediate
+		*out = new(bool)
+		**out = **in
+	}
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(BackupPluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Online != nil {
+		in, out := &in.Online, &out.Online
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OnlineConfiguration != nil {
+		in, out := &in.OnlineConfiguration, &out.OnlineConfiguration
+		*out = new(OnlineConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
+func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SQLRefs) DeepCopyInto(out *SQLRefs) {
+func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
 	*out = *in
-	if in.SecretRefs != nil {
-		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]SecretKeySelector, len(*in))
-		copy(*out, *in)
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
-	if in.ConfigMapRefs != nil {
-		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]ConfigMapKeySelector, len(*in))
-		copy(*out, *in)
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLRefs.
-func (in *SQLRefs) DeepCopy() *SQLRefs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
+func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SQLRefs)
+	out := new(ScheduledBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
+func (in *ScheduledSQL) DeepCopyInto(out *ScheduledSQL) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -2827,18 +4166,18 @@ func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackup.
-func (in *ScheduledBackup) DeepCopy() *ScheduledBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSQL.
+func (in *ScheduledSQL) DeepCopy() *ScheduledSQL {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackup)
+	out := new(ScheduledSQL)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
+func (in *ScheduledSQL) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2846,31 +4185,31 @@ func (in *ScheduledBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupList) DeepCopyInto(out *ScheduledBackupList) {
+func (in *ScheduledSQLList) DeepCopyInto(out *ScheduledSQLList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScheduledBackup, len(*in))
+		*out = make([]ScheduledSQL, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupList.
-func (in *ScheduledBackupList) DeepCopy() *ScheduledBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSQLList.
+func (in *ScheduledSQLList) DeepCopy() *ScheduledSQLList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupList)
+	out := new(ScheduledSQLList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
+func (in *ScheduledSQLList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2878,53 +4217,64 @@ func (in *ScheduledBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
+func (in *ScheduledSQLRunStatus) DeepCopyInto(out *ScheduledSQLRunStatus) {
 	*out = *in
-	if in.Suspend != nil {
-		in, out := &in.Suspend, &out.Suspend
-		*out = new(bool)
-		**out = **in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Immediate != nil {
-		in, out := &in.Immediate, &out.Immediate
-		*out = new(bool)
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	in.Cluster.DeepCopyInto(&out.Cluster)
-	if in.PluginConfiguration != nil {
-		in, out := &in.PluginConfiguration, &out.PluginConfiguration
-		*out = new(BackupPluginConfiguration)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSQLRunStatus.
+func (in *ScheduledSQLRunStatus) DeepCopy() *ScheduledSQLRunStatus {
+	if in == nil {
+		return nil
 	}
-	if in.Online != nil {
-		in, out := &in.Online, &out.Online
-		*out = new(bool)
+	out := new(ScheduledSQLRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledSQLSpec) DeepCopyInto(out *ScheduledSQLSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.OnlineConfiguration != nil {
-		in, out := &in.OnlineConfiguration, &out.OnlineConfiguration
-		*out = new(OnlineConfiguration)
-		(*in).DeepCopyInto(*out)
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
-func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSQLSpec.
+func (in *ScheduledSQLSpec) DeepCopy() *ScheduledSQLSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupSpec)
+	out := new(ScheduledSQLSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
+func (in *ScheduledSQLStatus) DeepCopyInto(out *ScheduledSQLStatus) {
 	*out = *in
-	if in.LastCheckTime != nil {
-		in, out := &in.LastCheckTime, &out.LastCheckTime
-		*out = (*in).DeepCopy()
-	}
 	if in.LastScheduleTime != nil {
 		in, out := &in.LastScheduleTime, &out.LastScheduleTime
 		*out = (*in).DeepCopy()
@@ -2933,14 +4283,23 @@ func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
 		in, out := &in.NextScheduleTime, &out.NextScheduleTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = new(ScheduledSQLRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
-func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSQLStatus.
+func (in *ScheduledSQLStatus) DeepCopy() *ScheduledSQLStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupStatus)
+	out := new(ScheduledSQLStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3000,6 +4359,13 @@ func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
 			(*out)[key] = val
 		}
 	}
+	if in.DeclarativeSecretVersions != nil {
+		in, out := &in.DeclarativeSecretVersions, &out.DeclarativeSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
@@ -3012,6 +4378,29 @@ func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
+	*out = *in
+	out.DatabaseObjectSpec = in.DatabaseObjectSpec
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
+func (in *ServerSpec) DeepCopy() *ServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
 	*out = *in
@@ -3134,6 +4523,72 @@ func (in *SubscriptionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionRuntimeStatus) DeepCopyInto(out *SubscriptionRuntimeStatus) {
+	*out = *in
+	if in.ApplyLag != nil {
+		in, out := &in.ApplyLag, &out.ApplyLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make(map[string]SubscriptionTableSyncState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionRuntimeStatus.
+func (in *SubscriptionRuntimeStatus) DeepCopy() *SubscriptionRuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionRuntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionSequenceSyncConfiguration) DeepCopyInto(out *SubscriptionSequenceSyncConfiguration) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSequenceSyncConfiguration.
+func (in *SubscriptionSequenceSyncConfiguration) DeepCopy() *SubscriptionSequenceSyncConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionSequenceSyncConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionSequenceSyncStatus) DeepCopyInto(out *SubscriptionSequenceSyncStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSequenceSyncStatus.
+func (in *SubscriptionSequenceSyncStatus) DeepCopy() *SubscriptionSequenceSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionSequenceSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 	*out = *in
@@ -3145,6 +4600,11 @@ func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.SequenceSync != nil {
+		in, out := &in.SequenceSync, &out.SequenceSync
+		*out = new(SubscriptionSequenceSyncConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
@@ -3165,6 +4625,23 @@ func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(SubscriptionRuntimeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SequenceSync != nil {
+		in, out := &in.SequenceSync, &out.SequenceSync
+		*out = new(SubscriptionSequenceSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
@@ -3267,11 +4744,36 @@ func (in *SynchronousReplicaConfiguration) DeepCopy() *SynchronousReplicaConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyslogLogSinkConfiguration) DeepCopyInto(out *SyslogLogSinkConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyslogLogSinkConfiguration.
+func (in *SyslogLogSinkConfiguration) DeepCopy() *SyslogLogSinkConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SyslogLogSinkConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
 	*out = *in
 	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(TablespaceEphemeralStorage)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Owner = in.Owner
+	if in.Relations != nil {
+		in, out := &in.Relations, &out.Relations
+		*out = make([]TablespaceRelation, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceConfiguration.
@@ -3284,9 +4786,69 @@ func (in *TablespaceConfiguration) DeepCopy() *TablespaceConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TablespaceEphemeralStorage) DeepCopyInto(out *TablespaceEphemeralStorage) {
+	*out = *in
+	if in.VolumeSource != nil {
+		in, out := &in.VolumeSource, &out.VolumeSource
+		*out = new(corev1.EphemeralVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceEphemeralStorage.
+func (in *TablespaceEphemeralStorage) DeepCopy() *TablespaceEphemeralStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(TablespaceEphemeralStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TablespaceRelation) DeepCopyInto(out *TablespaceRelation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceRelation.
+func (in *TablespaceRelation) DeepCopy() *TablespaceRelation {
+	if in == nil {
+		return nil
+	}
+	out := new(TablespaceRelation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TablespaceRelationState) DeepCopyInto(out *TablespaceRelationState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceRelationState.
+func (in *TablespaceRelationState) DeepCopy() *TablespaceRelationState {
+	if in == nil {
+		return nil
+	}
+	out := new(TablespaceRelationState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TablespaceState) DeepCopyInto(out *TablespaceState) {
 	*out = *in
+	if in.Relations != nil {
+		in, out := &in.Relations, &out.Relations
+		*out = make([]TablespaceRelationState, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceState.
@@ -3332,6 +4894,33 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserMappingSpec) DeepCopyInto(out *UserMappingSpec) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserMappingSpec.
+func (in *UserMappingSpec) DeepCopy() *UserMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
 	*out = *in
@@ -153,11 +153,19 @@ func NewCmd() *cobra.Command {
 
 func initSubCommand(ctx context.Context, info postgres.InitInfo) error {
 	contextLogger := log.FromContext(ctx)
-	err := info.EnsureTargetDirectoriesDoNotExist(ctx)
+
+	resume, err := info.CanResumeLogicalImport(ctx)
 	if err != nil {
 		return err
 	}
 
+	if resume {
+		contextLogger.Info("Resuming the interrupted logical import")
+		info.ResumeLogicalImport = true
+	} else if err := info.EnsureTargetDirectoriesDoNotExist(ctx); err != nil {
+		return err
+	}
+
 	err = info.Bootstrap(ctx)
 	if err != nil {
 		contextLogger.Error(err, "Error while bootstrapping data directory")
@@ -59,6 +59,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/telemetry"
 	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	instancestorage "github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/instance/storage"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -190,6 +191,16 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error { //n
 						instance.GetNamespaceName(): {},
 					},
 				},
+				&apiv1.Role{}: {
+					Namespaces: map[string]cache.Config{
+						instance.GetNamespaceName(): {},
+					},
+				},
+				&apiv1.ScheduledSQL{}: {
+					Namespaces: map[string]cache.Config{
+						instance.GetNamespaceName(): {},
+					},
+				},
 			},
 		},
 		// We don't need a cache for secrets and configmap, as all reloads
@@ -234,7 +245,38 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error { //n
 	defer pluginRepository.Close()
 
 	metricsExporter := metricserver.NewExporter(instance, metrics.NewPluginCollector(pluginRepository))
-	reconciler := controller.NewInstanceReconciler(instance, mgr.GetClient(), metricsExporter, pluginRepository)
+	metricsServer, err := metricserver.New(instance, metricsExporter)
+	if err != nil {
+		return err
+	}
+
+	telemetryExporter := telemetry.NewExporter("cnpg-instance-manager", metricsServer.GetGatherer(),
+		map[string]string{
+			"k8s.namespace.name": instance.GetNamespaceName(),
+			"k8s.pod.name":       instance.GetPodName(),
+			"cnpg.cluster.name":  instance.GetClusterName(),
+		})
+	defer func() {
+		if err := telemetryExporter.Shutdown(context.Background()); err != nil {
+			contextLogger.Error(err, "Error while stopping the OpenTelemetry exporter")
+		}
+	}()
+
+	logRouter := logpipe.NewRouter(telemetryExporter)
+	defer func() {
+		if err := logRouter.Shutdown(context.Background()); err != nil {
+			contextLogger.Error(err, "Error while closing the log sinks")
+		}
+	}()
+
+	reconciler := controller.NewInstanceReconciler(
+		instance,
+		mgr.GetClient(),
+		metricsExporter,
+		telemetryExporter,
+		logRouter,
+		pluginRepository,
+	)
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Cluster{}).
 		Named("instance-cluster").
@@ -266,8 +308,23 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error { //n
 		return err
 	}
 
+	// role reconciler
+	roleReconciler := controller.NewRoleReconciler(mgr, instance)
+	if err := roleReconciler.SetupWithManager(mgr); err != nil {
+		contextLogger.Error(err, "unable to create role controller")
+		return err
+	}
+
+	// scheduled SQL reconciler
+	scheduledSQLReconciler := controller.NewScheduledSQLReconciler(mgr, instance)
+	if err := scheduledSQLReconciler.SetupWithManager(mgr); err != nil {
+		contextLogger.Error(err, "unable to create scheduled SQL controller")
+		return err
+	}
+
 	// postgres CSV logs handler (PGAudit too)
 	postgresLogPipe := logpipe.NewLogPipe()
+	postgresLogPipe.SetRouter(logRouter)
 	if err := mgr.Add(postgresLogPipe); err != nil {
 		return err
 	}
@@ -277,6 +334,7 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error { //n
 	// raw logs handler
 	rawPipe := logpipe.NewRawLineLogPipe(filepath.Join(pg.LogPath, pg.LogFileName),
 		logpipe.LoggingCollectorRecordName)
+	rawPipe.SetRouter(logRouter)
 	if err := mgr.Add(rawPipe); err != nil {
 		return err
 	}
@@ -348,10 +406,6 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error { //n
 		return err
 	}
 
-	metricsServer, err := metricserver.New(instance, metricsExporter)
-	if err != nil {
-		return err
-	}
 	if err = mgr.Add(metricsServer); err != nil {
 		contextLogger.Error(err, "unable to add local webserver runnable")
 		return err
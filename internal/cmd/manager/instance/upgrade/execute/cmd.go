@@ -428,7 +428,7 @@ func prepareConfigurationFiles(ctx context.Context, cluster apiv1.Cluster, destD
 		return fmt.Errorf("error while creating the configuration files for new datadir %q: %w", destDir, err)
 	}
 
-	if _, err := newInstance.RefreshPGIdent(ctx, nil); err != nil {
+	if _, err := newInstance.RefreshPGIdent(ctx, nil, nil); err != nil {
 		return fmt.Errorf("error while creating the pg_ident.conf file for new datadir %q: %w", destDir, err)
 	}
 
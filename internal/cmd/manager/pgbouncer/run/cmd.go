@@ -27,8 +27,8 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
-	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/execlog"
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -36,8 +36,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/pgbouncer/management/controller"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/telemetry"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
@@ -106,11 +106,33 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 		"version", versions.Version,
 		"build", versions.Info)
 
-	if err = startWebServer(ctx); err != nil {
+	stdoutWriter := &execlog.LogWriter{
+		Logger: contextLogger.WithValues(execlog.PipeKey, execlog.StdOut),
+	}
+	stderrWriter := &pgBouncerLogWriter{
+		Logger: contextLogger.WithValues(execlog.PipeKey, execlog.StdErr),
+	}
+	process := controller.NewPgBouncerProcess(stdoutWriter, stderrWriter)
+
+	if err = startWebServer(ctx, process); err != nil {
 		return fmt.Errorf("while starting the web server: %w", err)
 	}
 
-	reconciler, err := controller.NewPgBouncerReconciler(poolerNamespacedName)
+	// the hostname of a Pod is its name
+	podName, _ := os.Hostname()
+	telemetryExporter := telemetry.NewExporter("cnpg-pgbouncer-manager", metricsserver.GetGatherer(),
+		map[string]string{
+			"k8s.namespace.name": poolerNamespacedName.Namespace,
+			"k8s.pod.name":       podName,
+			"cnpg.pooler.name":   poolerNamespacedName.Name,
+		})
+	defer func() {
+		if err := telemetryExporter.Shutdown(context.Background()); err != nil {
+			contextLogger.Error(err, "Error while stopping the OpenTelemetry exporter")
+		}
+	}()
+
+	reconciler, err := controller.NewPgBouncerReconciler(poolerNamespacedName, process, telemetryExporter)
 	if err != nil {
 		return fmt.Errorf("while initializing the new reconciler: %w", err)
 	}
@@ -121,25 +143,15 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 	}
 
 	// Start PgBouncer with the generated configuration
-	const pgBouncerCommandName = "/usr/bin/pgbouncer"
-	pgBouncerIni := filepath.Join(config.ConfigsDir, config.PgBouncerIniFileName)
-	pgBouncerCmd := exec.Command(pgBouncerCommandName, pgBouncerIni) //nolint:gosec
-	stdoutWriter := &execlog.LogWriter{
-		Logger: contextLogger.WithValues(execlog.PipeKey, execlog.StdOut),
-	}
-	stderrWriter := &pgBouncerLogWriter{
-		Logger: contextLogger.WithValues(execlog.PipeKey, execlog.StdErr),
-	}
-	streamingCmd, err := execlog.RunStreamingNoWaitWithWriter(
-		pgBouncerCmd, pgBouncerCommandName, stdoutWriter, stderrWriter)
-	if err != nil {
+	if err = process.Start(ctx); err != nil {
 		return fmt.Errorf("running pgbouncer: %w", err)
 	}
+	metricsserver.SetLastReloadTime(time.Now())
 
 	startReconciler(ctx, reconciler)
-	registerSignalHandler(ctx, reconciler, pgBouncerCmd)
+	registerSignalHandler(ctx, reconciler, process)
 
-	if err = streamingCmd.Wait(); err != nil {
+	if err = process.Wait(); err != nil {
 		var exitError *exec.ExitError
 		if !errors.As(err, &exitError) {
 			contextLogger.Error(err, "Error waiting on pgbouncer process")
@@ -154,7 +166,11 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 
 // registerSignalHandler handles signals from k8s, notifying postgres as
 // needed
-func registerSignalHandler(ctx context.Context, reconciler *controller.PgBouncerReconciler, command *exec.Cmd) {
+func registerSignalHandler(
+	ctx context.Context,
+	reconciler *controller.PgBouncerReconciler,
+	process controller.PgBouncerProcessInterface,
+) {
 	contextLogger := log.FromContext(ctx)
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
@@ -173,21 +189,21 @@ func registerSignalHandler(ctx context.Context, reconciler *controller.PgBouncer
 
 		reconciler.Stop()
 
-		if command != nil {
-			contextLogger.Info("Shutting down pgbouncer instance")
-			err := command.Process.Signal(syscall.SIGINT)
-			if err != nil {
-				contextLogger.Error(err, "Unable to send SIGINT to pgbouncer instance")
-			}
+		if err := process.Shutdown(ctx); err != nil {
+			contextLogger.Error(err, "Unable to send SIGINT to pgbouncer instance")
 		}
 	}()
 }
 
 // startWebServer start the web server for handling probes given
 // a certain PostgreSQL instance
-func startWebServer(ctx context.Context) error {
+func startWebServer(ctx context.Context, process controller.PgBouncerProcessInterface) error {
 	contextLogger := log.FromContext(ctx)
-	if err := metricsserver.Setup(ctx); err != nil {
+	drainingSocketDirs := func() []string {
+		_, draining := process.SocketDirs()
+		return draining
+	}
+	if err := metricsserver.Setup(ctx, drainingSocketDirs); err != nil {
 		return err
 	}
 
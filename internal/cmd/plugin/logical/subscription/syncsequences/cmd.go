@@ -29,6 +29,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logical"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/sequences"
 )
 
 // NewCmd initializes the subscription create command
@@ -36,7 +37,7 @@ func NewCmd() *cobra.Command {
 	var subscriptionName string
 	var dbName string
 	var dryRun bool
-	var offset int
+	var offset int64
 
 	syncSequencesCmd := &cobra.Command{
 		Use:   "sync-sequences CLUSTER",
@@ -92,7 +93,7 @@ func NewCmd() *cobra.Command {
 				return fmt.Errorf("while getting sequences status from the destination database: %w", err)
 			}
 
-			script := CreateSyncScript(sourceStatus, destinationStatus, offset)
+			script := sequences.CreateSyncScript(sourceStatus, destinationStatus, offset)
 			fmt.Println(script)
 			if dryRun {
 				return nil
@@ -123,7 +124,7 @@ func NewCmd() *cobra.Command {
 		false,
 		"If specified, the subscription is not created",
 	)
-	syncSequencesCmd.Flags().IntVar(
+	syncSequencesCmd.Flags().Int64Var(
 		&offset,
 		"offset",
 		0,
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -68,6 +69,9 @@ type PostgresqlStatus struct {
 	// with the label selector
 	PodDisruptionBudgetList policyv1.PodDisruptionBudgetList
 
+	// Poolers contains the Poolers pointing to the cluster
+	Poolers []apiv1.Pooler `json:"poolers,omitempty"`
+
 	// ErrorList store the possible errors while getting the PostgreSQL status
 	ErrorList []error
 
@@ -150,6 +154,7 @@ func Status(
 			status.printPodDisruptionBudgetStatus()
 		}
 		status.printInstancesStatus()
+		status.printPoolersStatus()
 	}
 	status.printPluginStatus(verbosity)
 
@@ -195,12 +200,25 @@ func extractPostgresqlStatus(ctx context.Context, cluster apiv1.Cluster) *Postgr
 	); err != nil {
 		errs = append(errs, err)
 	}
+
+	var poolerList apiv1.PoolerList
+	if err := plugin.Client.List(ctx, &poolerList, client.InNamespace(plugin.Namespace)); err != nil {
+		errs = append(errs, err)
+	}
+	var poolers []apiv1.Pooler
+	for _, pooler := range poolerList.Items {
+		if pooler.Spec.Cluster.Name == cluster.Name {
+			poolers = append(poolers, pooler)
+		}
+	}
+
 	// Extract the status from the instances
 	status := PostgresqlStatus{
 		Cluster:                 &cluster,
 		InstanceStatus:          &instancesStatus,
 		PrimaryPod:              primaryPod,
 		PodDisruptionBudgetList: pdbl,
+		Poolers:                 poolers,
 		ErrorList:               errs,
 	}
 	return &status
@@ -787,6 +805,104 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 	fmt.Println()
 }
 
+func (fullStatus *PostgresqlStatus) printPoolersStatus() {
+	if len(fullStatus.Poolers) == 0 {
+		return
+	}
+
+	status := tabby.New()
+	fmt.Println(aurora.Green("Poolers status"))
+	status.AddHeader(
+		"Name",
+		"Type",
+		"Ready",
+		"Target",
+		"Paused",
+		"Clients (active/waiting)",
+		"Servers (active/idle)",
+		"Last Reload")
+
+	poolers := slices.Clone(fullStatus.Poolers)
+	sort.Slice(poolers, func(i, j int) bool {
+		return poolers[i].Name < poolers[j].Name
+	})
+	for _, pooler := range poolers {
+		clients, servers := "-", "-"
+		if connections := pooler.Status.Connections; connections != nil {
+			clients = fmt.Sprintf("%d/%d", connections.ClientsActive, connections.ClientsWaiting)
+			servers = fmt.Sprintf("%d/%d", connections.ServersActive, connections.ServersIdle)
+		}
+
+		status.AddLine(
+			pooler.Name,
+			pooler.Spec.Type,
+			fmt.Sprintf("%d/%d", pooler.Status.ReadyInstances, pooler.Status.Instances),
+			getPoolerTarget(pooler),
+			getPoolerPausedInstances(pooler),
+			clients,
+			servers,
+			getPoolerLastReloadTime(pooler),
+		)
+	}
+	status.Print()
+
+	for _, pooler := range poolers {
+		for _, instance := range pooler.Status.InstancesStatus {
+			if instance.Error != "" {
+				fmt.Printf("%s: %s\n", aurora.Red(instance.PodName), instance.Error)
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// getPoolerTarget returns the PostgreSQL instances receiving
+// the traffic of the passed Pooler
+func getPoolerTarget(pooler apiv1.Pooler) string {
+	if pooler.Status.TargetPrimary != "" {
+		return pooler.Status.TargetPrimary
+	}
+	if len(pooler.Status.TargetInstances) > 0 {
+		return strings.Join(pooler.Status.TargetInstances, ", ")
+	}
+	return "-"
+}
+
+// getPoolerPausedInstances returns the number of the PgBouncer
+// instances of the passed Pooler that are paused
+func getPoolerPausedInstances(pooler apiv1.Pooler) string {
+	if len(pooler.Status.InstancesStatus) == 0 {
+		return "-"
+	}
+
+	paused := 0
+	for _, instance := range pooler.Status.InstancesStatus {
+		if instance.Paused {
+			paused++
+		}
+	}
+	return fmt.Sprintf("%d/%d", paused, len(pooler.Status.InstancesStatus))
+}
+
+// getPoolerLastReloadTime returns the most recent time when a PgBouncer
+// instance of the passed Pooler loaded its configuration
+func getPoolerLastReloadTime(pooler apiv1.Pooler) string {
+	var lastReloadTime *metav1.Time
+	for _, instance := range pooler.Status.InstancesStatus {
+		if instance.LastReloadTime == nil {
+			continue
+		}
+		if lastReloadTime == nil || lastReloadTime.Before(instance.LastReloadTime) {
+			lastReloadTime = instance.LastReloadTime
+		}
+	}
+
+	if lastReloadTime == nil {
+		return "-"
+	}
+	return lastReloadTime.Format(time.RFC3339)
+}
+
 func (fullStatus *PostgresqlStatus) printCertificatesStatus() {
 	status := tabby.New()
 	status.AddHeader("Certificate Name", "Expiration Date", "Days Left Until Expiration")
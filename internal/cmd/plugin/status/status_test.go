@@ -79,3 +79,43 @@ var _ = Describe("getPrimaryStartTime", func() {
 		})
 	})
 })
+
+var _ = Describe("pooler status helpers", func() {
+	earlier := metav1.NewTime(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC))
+
+	It("reports the target of the pooler", func() {
+		Expect(getPoolerTarget(apiv1.Pooler{})).To(Equal("-"))
+		Expect(getPoolerTarget(apiv1.Pooler{
+			Status: apiv1.PoolerStatus{TargetPrimary: "cluster-example-1"},
+		})).To(Equal("cluster-example-1"))
+		Expect(getPoolerTarget(apiv1.Pooler{
+			Status: apiv1.PoolerStatus{TargetInstances: []string{"cluster-example-2", "cluster-example-3"}},
+		})).To(Equal("cluster-example-2, cluster-example-3"))
+	})
+
+	It("counts the paused instances", func() {
+		Expect(getPoolerPausedInstances(apiv1.Pooler{})).To(Equal("-"))
+		Expect(getPoolerPausedInstances(apiv1.Pooler{
+			Status: apiv1.PoolerStatus{
+				InstancesStatus: []apiv1.PoolerInstanceStatus{
+					{PodName: "pooler-1", Paused: true},
+					{PodName: "pooler-2"},
+				},
+			},
+		})).To(Equal("1/2"))
+	})
+
+	It("reports the most recent configuration reload", func() {
+		Expect(getPoolerLastReloadTime(apiv1.Pooler{})).To(Equal("-"))
+		Expect(getPoolerLastReloadTime(apiv1.Pooler{
+			Status: apiv1.PoolerStatus{
+				InstancesStatus: []apiv1.PoolerInstanceStatus{
+					{PodName: "pooler-1", LastReloadTime: &later},
+					{PodName: "pooler-2", LastReloadTime: &earlier},
+					{PodName: "pooler-3"},
+				},
+			},
+		})).To(Equal("2026-01-01T11:00:00Z"))
+	})
+})
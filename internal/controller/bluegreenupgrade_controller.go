@@ -0,0 +1,190 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
+)
+
+const (
+	// blueGreenUpgradeRequeueDelay is the time after which the progress
+	// of a blue-green upgrade is checked again
+	blueGreenUpgradeRequeueDelay = 10 * time.Second
+
+	// blueGreenUpgradeFenceCheckDelay is the time after which the fence of
+	// the client connections to the source cluster is checked again, as
+	// the applications cannot write during the cutover
+	blueGreenUpgradeFenceCheckDelay = 2 * time.Second
+)
+
+// BlueGreenUpgradeReconciler reconciles a BlueGreenUpgrade object
+type BlueGreenUpgradeReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
+	InstanceClient remote.InstanceClient
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=bluegreenupgrades,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=bluegreenupgrades/status,verbs=get;update;patch
+
+// Reconcile implements the main reconciliation loop for blue-green upgrades
+func (r *BlueGreenUpgradeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	var upgrade apiv1.BlueGreenUpgrade
+	if err := r.Get(ctx, req.NamespacedName, &upgrade); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("cannot get the blue-green upgrade resource: %w", err)
+	}
+
+	if upgrade.IsDone() {
+		return ctrl.Result{}, nil
+	}
+
+	origUpgrade := upgrade.DeepCopy()
+	result, err := r.reconcilePhase(ctx, &upgrade)
+	if err != nil {
+		contextLogger.Error(err, "while reconciling the blue-green upgrade", "phase", upgrade.Status.Phase)
+		upgrade.Status.Message = err.Error()
+	}
+
+	if !reflect.DeepEqual(origUpgrade.Status, upgrade.Status) {
+		if patchErr := r.Status().Patch(ctx, &upgrade, client.MergeFrom(origUpgrade)); patchErr != nil {
+			return ctrl.Result{}, patchErr
+		}
+	}
+
+	if origUpgrade.Status.Phase != upgrade.Status.Phase {
+		contextLogger.Info("Blue-green upgrade phase changed",
+			"from", origUpgrade.Status.Phase, "to", upgrade.Status.Phase)
+		r.Recorder.Eventf(&upgrade, "Normal", "PhaseChanged",
+			"Blue-green upgrade phase changed to %q", upgrade.Status.Phase)
+	}
+
+	return result, err
+}
+
+// reconcilePhase executes the step of the upgrade matching its current phase
+func (r *BlueGreenUpgradeReconciler) reconcilePhase(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+) (ctrl.Result, error) {
+	// Once the cutover is completed the source cluster is only needed
+	// to roll back, and can be deleted
+	if upgrade.Status.Phase == apiv1.BlueGreenUpgradePhaseCompleted && !upgrade.Spec.Rollback {
+		return ctrl.Result{}, r.ensureServices(ctx, upgrade, upgrade.Spec.TargetClusterName)
+	}
+
+	source, err := getClusterOrNil(ctx, r.Client, client.ObjectKey{
+		Namespace: upgrade.Namespace,
+		Name:      upgrade.Spec.ClusterRef.Name,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if source == nil {
+		upgrade.Status.Message = fmt.Sprintf("cluster %q not found", upgrade.Spec.ClusterRef.Name)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	if upgrade.Status.Phase == "" {
+		return ctrl.Result{}, r.createTargetCluster(ctx, upgrade, source)
+	}
+
+	if err := r.ensureServerAltDNSNames(ctx, upgrade, source); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.ensureServices(ctx, upgrade, upgrade.GetServingClusterName()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if upgrade.IsRollbackRequested() {
+		upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseRollingBack
+		upgrade.Status.Message = fmt.Sprintf("switching the applications back to cluster %q", source.Name)
+	}
+	if upgrade.Status.Phase == apiv1.BlueGreenUpgradePhaseRollingBack {
+		return r.rollBack(ctx, upgrade, source)
+	}
+
+	target, err := getClusterOrNil(ctx, r.Client, client.ObjectKey{
+		Namespace: upgrade.Namespace,
+		Name:      upgrade.Spec.TargetClusterName,
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if target == nil {
+		upgrade.SetAsFailed(fmt.Errorf("target cluster %q not found", upgrade.Spec.TargetClusterName))
+		return ctrl.Result{}, nil
+	}
+
+	switch upgrade.Status.Phase {
+	case apiv1.BlueGreenUpgradePhaseCreatingCluster:
+		return r.waitForTargetCluster(ctx, upgrade, source, target)
+	case apiv1.BlueGreenUpgradePhaseReplicating:
+		return r.reconcileReplication(ctx, upgrade, source, target)
+	case apiv1.BlueGreenUpgradePhaseFencingWrites:
+		return r.fenceWrites(ctx, upgrade, source)
+	case apiv1.BlueGreenUpgradePhaseCatchingUp:
+		return r.waitForCatchUp(ctx, upgrade)
+	case apiv1.BlueGreenUpgradePhaseSynchronizingSequences:
+		return r.synchronizeSequences(ctx, upgrade)
+	case apiv1.BlueGreenUpgradePhaseDetaching:
+		return r.detachSubscriptions(ctx, upgrade)
+	case apiv1.BlueGreenUpgradePhaseSwitchingServices:
+		return ctrl.Result{}, r.switchServices(ctx, upgrade, source, target)
+	case apiv1.BlueGreenUpgradePhaseCompleted:
+		return ctrl.Result{}, nil
+	default:
+		upgrade.SetAsFailed(fmt.Errorf("unknown phase %q", upgrade.Status.Phase))
+		return ctrl.Result{}, nil
+	}
+}
+
+// SetupWithManager sets up this controller given a controller manager
+func (r *BlueGreenUpgradeReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&apiv1.BlueGreenUpgrade{}).
+		Named("bluegreenupgrade").
+		Owns(&apiv1.Publication{}).
+		Owns(&apiv1.Subscription{}).
+		Owns(&corev1.Service{}).
+		Complete(r)
+}
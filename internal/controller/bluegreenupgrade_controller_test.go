@@ -0,0 +1,456 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeBlueGreenInstanceClient struct {
+	remote.InstanceClient
+	fenceLSN types.LSN
+}
+
+func (f fakeBlueGreenInstanceClient) GetStatusFromInstances(
+	_ context.Context,
+	pods corev1.PodList,
+) postgres.PostgresqlStatusList {
+	return postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{Pod: &pods.Items[0], IsPrimary: true, ClientConnectionsFenceLSN: f.fenceLSN},
+		},
+	}
+}
+
+var _ = Describe("BlueGreenUpgrade reconciler", func() {
+	const namespace = "default"
+
+	var (
+		source   *apiv1.Cluster
+		upgrade  *apiv1.BlueGreenUpgrade
+		database *apiv1.Database
+		pooler   *apiv1.Pooler
+		primary  *corev1.Pod
+		r        *BlueGreenUpgradeReconciler
+	)
+
+	reconcile := func(ctx context.Context) ctrl.Result {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(upgrade)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(upgrade), upgrade)).To(Succeed())
+		return result
+	}
+
+	getCluster := func(ctx context.Context, name string) *apiv1.Cluster {
+		var cluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &cluster)).To(Succeed())
+		return &cluster
+	}
+
+	getSubscription := func(ctx context.Context, name string) *apiv1.Subscription {
+		var subscription apiv1.Subscription
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &subscription)).To(Succeed())
+		return &subscription
+	}
+
+	setPhase := func(ctx context.Context, phase apiv1.BlueGreenUpgradePhase) {
+		upgrade.Status.Phase = phase
+		Expect(r.Status().Update(ctx, upgrade)).To(Succeed())
+	}
+
+	markTargetHealthy := func(ctx context.Context) {
+		target := getCluster(ctx, "blue-18")
+		target.Status.Phase = apiv1.PhaseHealthy
+		Expect(r.Status().Update(ctx, target)).To(Succeed())
+	}
+
+	setSubscriptionStatus := func(ctx context.Context, name string, status apiv1.SubscriptionStatus) {
+		subscription := getSubscription(ctx, name)
+		subscription.Status = status
+		Expect(r.Status().Update(ctx, subscription)).To(Succeed())
+	}
+
+	getServiceSelectedCluster := func(ctx context.Context, name string) string {
+		var service corev1.Service
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &service)).To(Succeed())
+		Expect(metav1.IsControlledBy(&service, upgrade)).To(BeTrue())
+		return service.Spec.Selector[utils.ClusterLabelName]
+	}
+
+	BeforeEach(func() {
+		source = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "blue", Namespace: namespace},
+			Spec: apiv1.ClusterSpec{
+				ImageName:             "ghcr.io/cloudnative-pg/postgresql:17.5",
+				Instances:             3,
+				EnableSuperuserAccess: ptr.To(true),
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				Backup: &apiv1.BackupConfiguration{},
+				Plugins: []apiv1.PluginConfiguration{
+					{Name: "archiver", IsWALArchiver: ptr.To(true)},
+					{Name: "sidecar"},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "blue-1",
+			},
+		}
+		upgrade = &apiv1.BlueGreenUpgrade{
+			ObjectMeta: metav1.ObjectMeta{Name: "to-18", Namespace: namespace},
+			Spec: apiv1.BlueGreenUpgradeSpec{
+				ClusterRef:        corev1.LocalObjectReference{Name: "blue"},
+				TargetClusterName: "blue-18",
+				ImageName:         "ghcr.io/cloudnative-pg/postgresql:18.0",
+			},
+		}
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "reporting", Namespace: namespace},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "blue"},
+				Name:       "reporting",
+				Owner:      "app",
+			},
+		}
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "blue-pooler-rw", Namespace: namespace},
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "blue"},
+			},
+		}
+		primary = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "blue-1", Namespace: namespace},
+		}
+
+		scheme := schemeBuilder.BuildWithAllKnownScheme()
+		r = &BlueGreenUpgradeReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(source, upgrade, database, pooler, primary).
+				WithStatusSubresource(&apiv1.Cluster{}, &apiv1.BlueGreenUpgrade{}, &apiv1.Subscription{}).
+				Build(),
+			Scheme:         scheme,
+			Recorder:       record.NewFakeRecorder(120),
+			InstanceClient: fakeBlueGreenInstanceClient{},
+		}
+	})
+
+	It("creates the target cluster importing the source databases", func(ctx SpecContext) {
+		reconcile(ctx)
+
+		Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseCreatingCluster))
+		Expect(upgrade.Status.Databases).To(HaveLen(2))
+		Expect(upgrade.Status.Databases[0].Name).To(Equal("app"))
+		Expect(upgrade.Status.Databases[0].SubscriptionName).To(Equal("to-18-app"))
+		Expect(upgrade.Status.Databases[1].Name).To(Equal("reporting"))
+
+		target := getCluster(ctx, "blue-18")
+		Expect(target.Spec.ImageName).To(Equal("ghcr.io/cloudnative-pg/postgresql:18.0"))
+		Expect(target.Spec.Instances).To(Equal(3))
+		Expect(target.Spec.Backup).To(BeNil())
+		Expect(target.Spec.Plugins).To(HaveLen(1))
+		Expect(target.Spec.Plugins[0].Name).To(Equal("sidecar"))
+		Expect(target.Spec.Certificates.ServerAltDNSNames).To(ContainElement("to-18-rw"))
+		Expect(target.Spec.Certificates.ServerAltDNSNames).ToNot(ContainElement("blue-rw"))
+
+		initDB := target.Spec.Bootstrap.InitDB
+		Expect(initDB.Database).To(Equal("app"))
+		Expect(initDB.Secret.Name).To(Equal("blue-app"))
+		Expect(initDB.Import.Type).To(Equal(apiv1.MonolithSnapshotType))
+		Expect(initDB.Import.SchemaOnly).To(BeTrue())
+		Expect(initDB.Import.Databases).To(Equal([]string{"app", "reporting"}))
+		Expect(initDB.Import.Source.ExternalCluster).To(Equal("blue"))
+
+		Expect(target.Spec.ExternalClusters).To(HaveLen(1))
+		Expect(target.Spec.ExternalClusters[0].ConnectionParameters).To(HaveKeyWithValue("host", "blue-rw"))
+		Expect(target.Spec.ExternalClusters[0].Password.Name).To(Equal("blue-superuser"))
+	})
+
+	It("fails when the target cluster already exists", func(ctx SpecContext) {
+		Expect(r.Create(ctx, &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "blue-18", Namespace: namespace},
+		})).To(Succeed())
+
+		reconcile(ctx)
+		Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseFailed))
+		Expect(upgrade.Status.Message).To(ContainSubstring("already exists"))
+	})
+
+	It("fails when the major version is not increased", func(ctx SpecContext) {
+		upgrade.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.9"
+		Expect(r.Update(ctx, upgrade)).To(Succeed())
+
+		reconcile(ctx)
+		Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseFailed))
+		Expect(upgrade.Status.Message).To(ContainSubstring("must be greater"))
+	})
+
+	It("performs the upgrade up to the cutover", func(ctx SpecContext) {
+		reconcile(ctx)
+
+		By("waiting for the target cluster to be ready", func() {
+			result := reconcile(ctx)
+			Expect(result.RequeueAfter).To(Equal(blueGreenUpgradeRequeueDelay))
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseCreatingCluster))
+		})
+
+		By("creating the publications and the subscriptions", func() {
+			markTargetHealthy(ctx)
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseReplicating))
+
+			Expect(getServiceSelectedCluster(ctx, "to-18-rw")).To(Equal("blue"))
+			Expect(getServiceSelectedCluster(ctx, "to-18-r")).To(Equal("blue"))
+			Expect(getServiceSelectedCluster(ctx, "to-18-ro")).To(Equal("blue"))
+			Expect(getCluster(ctx, "blue").Spec.Certificates.ServerAltDNSNames).
+				To(ContainElements("to-18-rw", "to-18-ro.default.svc"))
+
+			var publication apiv1.Publication
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "to-18-reporting"}, &publication)).
+				To(Succeed())
+			Expect(publication.Spec.ClusterRef.Name).To(Equal("blue"))
+			Expect(publication.Spec.Name).To(Equal("to_18_1"))
+			Expect(publication.Spec.Target.AllTables).To(BeTrue())
+			Expect(metav1.IsControlledBy(&publication, upgrade)).To(BeTrue())
+
+			subscription := getSubscription(ctx, "to-18-reporting")
+			Expect(subscription.Spec.ClusterRef.Name).To(Equal("blue-18"))
+			Expect(subscription.Spec.PublicationName).To(Equal("to_18_1"))
+			Expect(subscription.Spec.ExternalClusterName).To(Equal("blue"))
+			Expect(metav1.IsControlledBy(subscription, upgrade)).To(BeTrue())
+		})
+
+		By("tracking the replication without starting the cutover", func() {
+			replicating := apiv1.SubscriptionStatus{
+				Conditions: []metav1.Condition{{
+					Type:   string(apiv1.ConditionSubscriptionReplicating),
+					Status: metav1.ConditionTrue,
+					Reason: string(apiv1.SubscriptionReasonReplicating),
+				}},
+				Runtime: &apiv1.SubscriptionRuntimeStatus{ReceivedLSN: "0/3000000"},
+			}
+			setSubscriptionStatus(ctx, "to-18-app", replicating)
+			reconcile(ctx)
+			Expect(upgrade.Status.Message).To(Equal("1/2 databases replicating"))
+
+			setSubscriptionStatus(ctx, "to-18-reporting", replicating)
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseReplicating))
+			Expect(upgrade.Status.Databases[1].Replicating).To(BeTrue())
+			Expect(upgrade.Status.Databases[1].ReceivedLSN).To(Equal("0/3000000"))
+		})
+	})
+
+	It("performs the cutover", func(ctx SpecContext) {
+		reconcile(ctx)
+		markTargetHealthy(ctx)
+		reconcile(ctx)
+
+		By("fencing the client connections to the source cluster", func() {
+			upgrade.Status.CutoverStartedAt = ptr.To(metav1.NewTime(time.Now().Add(-time.Minute)))
+			setPhase(ctx, apiv1.BlueGreenUpgradePhaseFencingWrites)
+
+			result := reconcile(ctx)
+			Expect(result.RequeueAfter).To(Equal(blueGreenUpgradeFenceCheckDelay))
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseFencingWrites))
+			Expect(upgrade.Status.Message).To(ContainSubstring("to fence the client connections"))
+			Expect(getCluster(ctx, "blue").Annotations).
+				To(HaveKeyWithValue(utils.FenceClientConnectionsAnnotationName, "true"))
+
+			r.InstanceClient = fakeBlueGreenInstanceClient{fenceLSN: "0/3000060"}
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseCatchingUp))
+			Expect(upgrade.Status.FenceLSN).To(Equal("0/3000060"))
+		})
+
+		By("waiting for the subscriptions to catch up", func() {
+			setSubscriptionStatus(ctx, "to-18-app", apiv1.SubscriptionStatus{
+				Runtime: &apiv1.SubscriptionRuntimeStatus{ReceivedLSN: "0/3000060"},
+			})
+			setSubscriptionStatus(ctx, "to-18-reporting", apiv1.SubscriptionStatus{
+				Runtime: &apiv1.SubscriptionRuntimeStatus{ReceivedLSN: "0/3000000"},
+			})
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseCatchingUp))
+			Expect(upgrade.Status.Message).To(HavePrefix("1/2 databases"))
+
+			setSubscriptionStatus(ctx, "to-18-reporting", apiv1.SubscriptionStatus{
+				Runtime: &apiv1.SubscriptionRuntimeStatus{ReceivedLSN: "0/4000000"},
+			})
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseSynchronizingSequences))
+		})
+
+		By("synchronizing the sequences", func() {
+			reconcile(ctx)
+			subscription := getSubscription(ctx, "to-18-app")
+			Expect(subscription.Annotations).
+				To(HaveKeyWithValue(utils.SubscriptionSyncSequencesAnnotationName, "0/3000060"))
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseSynchronizingSequences))
+
+			for _, name := range []string{"to-18-app", "to-18-reporting"} {
+				setSubscriptionStatus(ctx, name, apiv1.SubscriptionStatus{
+					SequenceSync: &apiv1.SubscriptionSequenceSyncStatus{LastRequest: "0/3000060"},
+				})
+			}
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseDetaching))
+		})
+
+		By("dropping the subscriptions", func() {
+			reconcile(ctx)
+			var subscriptions apiv1.SubscriptionList
+			Expect(r.List(ctx, &subscriptions)).To(Succeed())
+			Expect(subscriptions.Items).To(BeEmpty())
+
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseSwitchingServices))
+		})
+
+		By("switching the services and the poolers", func() {
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseCompleted))
+			Expect(upgrade.Status.CutoverCompletedAt).ToNot(BeNil())
+			Expect(upgrade.Status.SwitchedPoolers).To(Equal([]string{"blue-pooler-rw"}))
+			Expect(getServiceSelectedCluster(ctx, "to-18-rw")).To(Equal("blue-18"))
+			Expect(getServiceSelectedCluster(ctx, "to-18-r")).To(Equal("blue-18"))
+			Expect(getServiceSelectedCluster(ctx, "to-18-ro")).To(Equal("blue-18"))
+
+			var switchedPooler apiv1.Pooler
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pooler), &switchedPooler)).To(Succeed())
+			Expect(switchedPooler.Spec.Cluster.Name).To(Equal("blue-18"))
+		})
+
+		By("keeping the services of completed upgrades pointed to the target cluster", func() {
+			Expect(r.Delete(ctx, getCluster(ctx, "blue"))).To(Succeed())
+			Expect(reconcile(ctx)).To(Equal(ctrl.Result{}))
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseCompleted))
+			Expect(getServiceSelectedCluster(ctx, "to-18-rw")).To(Equal("blue-18"))
+		})
+	})
+
+	It("rolls back a completed cutover", func(ctx SpecContext) {
+		reconcile(ctx)
+		markTargetHealthy(ctx)
+		reconcile(ctx)
+
+		cluster := getCluster(ctx, "blue")
+		cluster.Annotations = map[string]string{utils.FenceClientConnectionsAnnotationName: "true"}
+		Expect(r.Update(ctx, cluster)).To(Succeed())
+
+		switchedPooler := pooler.DeepCopy()
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(pooler), switchedPooler)).To(Succeed())
+		switchedPooler.Spec.Cluster.Name = "blue-18"
+		Expect(r.Update(ctx, switchedPooler)).To(Succeed())
+
+		upgrade.Status.SwitchedPoolers = []string{"blue-pooler-rw"}
+		setPhase(ctx, apiv1.BlueGreenUpgradePhaseCompleted)
+		reconcile(ctx)
+		Expect(getServiceSelectedCluster(ctx, "to-18-rw")).To(Equal("blue-18"))
+
+		upgrade.Spec.Cutover = true
+		upgrade.Spec.Rollback = true
+		Expect(r.Update(ctx, upgrade)).To(Succeed())
+
+		By("dropping the subscriptions", func() {
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseRollingBack))
+
+			var subscriptions apiv1.SubscriptionList
+			Expect(r.List(ctx, &subscriptions)).To(Succeed())
+			Expect(subscriptions.Items).To(BeEmpty())
+		})
+
+		By("switching the applications back to the source cluster", func() {
+			reconcile(ctx)
+			Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseRolledBack))
+			Expect(upgrade.Status.SwitchedPoolers).To(BeEmpty())
+			Expect(getServiceSelectedCluster(ctx, "to-18-rw")).To(Equal("blue"))
+			Expect(getServiceSelectedCluster(ctx, "to-18-ro")).To(Equal("blue"))
+			Expect(getCluster(ctx, "blue").Annotations).
+				ToNot(HaveKey(utils.FenceClientConnectionsAnnotationName))
+
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(pooler), switchedPooler)).To(Succeed())
+			Expect(switchedPooler.Spec.Cluster.Name).To(Equal("blue"))
+		})
+
+		By("ignoring rolled back upgrades", func() {
+			Expect(reconcile(ctx)).To(Equal(ctrl.Result{}))
+		})
+	})
+
+	It("refuses to reconcile services it doesn't own", func(ctx SpecContext) {
+		reconcile(ctx)
+		Expect(r.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "to-18-rw", Namespace: namespace},
+		})).To(Succeed())
+
+		markTargetHealthy(ctx)
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(upgrade)})
+		Expect(err).To(MatchError(ContainSubstring("not owned by the upgrade")))
+	})
+
+	It("doesn't start the cutover until every database is replicating", func(ctx SpecContext) {
+		reconcile(ctx)
+		markTargetHealthy(ctx)
+		reconcile(ctx)
+
+		upgrade.Spec.Cutover = true
+		Expect(r.Update(ctx, upgrade)).To(Succeed())
+		reconcile(ctx)
+		Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseReplicating))
+
+		for _, name := range []string{"to-18-app", "to-18-reporting"} {
+			status := apiv1.SubscriptionStatus{}
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:   string(apiv1.ConditionSubscriptionReplicating),
+				Status: metav1.ConditionTrue,
+				Reason: string(apiv1.SubscriptionReasonReplicating),
+			})
+			setSubscriptionStatus(ctx, name, status)
+		}
+		reconcile(ctx)
+		Expect(upgrade.Status.Phase).To(Equal(apiv1.BlueGreenUpgradePhaseFencingWrites))
+		Expect(upgrade.Status.CutoverStartedAt).ToNot(BeNil())
+		Expect(upgrade.IsCutoverInProgress()).To(BeTrue())
+	})
+})
@@ -0,0 +1,359 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// fenceWrites fences the client connections to the source cluster, and
+// records the write-ahead log location that the subscriptions must reach.
+// The primary instance rejects the new client connections, terminates the
+// existing ones and switches the WAL: the location is reported in its
+// status when no client connection is left
+func (r *BlueGreenUpgradeReconciler) fenceWrites(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+) (ctrl.Result, error) {
+	if upgrade.Status.CutoverStartedAt == nil {
+		upgrade.Status.CutoverStartedAt = ptr.To(metav1.Now())
+	}
+
+	if err := r.setClientConnectionsFence(ctx, source, true); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if source.Status.Phase != apiv1.PhaseHealthy || source.Status.CurrentPrimary == "" {
+		upgrade.Status.Message = fmt.Sprintf("waiting for cluster %q to be ready", source.Name)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	var primary corev1.Pod
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: source.Namespace, Name: source.Status.CurrentPrimary},
+		&primary,
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting the primary of cluster %q: %w", source.Name, err)
+	}
+
+	statusList := r.InstanceClient.GetStatusFromInstances(ctx, corev1.PodList{Items: []corev1.Pod{primary}})
+	if len(statusList.Items) != 1 {
+		return ctrl.Result{}, fmt.Errorf("cannot get the status of instance %q", primary.Name)
+	}
+	primaryStatus := statusList.Items[0]
+	if primaryStatus.Error != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting the status of instance %q: %w",
+			primary.Name, primaryStatus.Error)
+	}
+	if !primaryStatus.IsPrimary {
+		upgrade.Status.Message = fmt.Sprintf("waiting for instance %q to be promoted", primary.Name)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+	if primaryStatus.ClientConnectionsFenceLSN == "" {
+		upgrade.Status.Message = fmt.Sprintf("waiting for instance %q to fence the client connections", primary.Name)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeFenceCheckDelay}, nil
+	}
+
+	upgrade.Status.FenceLSN = string(primaryStatus.ClientConnectionsFenceLSN)
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseCatchingUp
+	upgrade.Status.Message = fmt.Sprintf("waiting for the subscriptions to reach LSN %s", upgrade.Status.FenceLSN)
+	return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+}
+
+// setClientConnectionsFence sets or removes the fence of the client
+// connections to the passed cluster
+func (r *BlueGreenUpgradeReconciler) setClientConnectionsFence(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	fenced bool,
+) error {
+	isFenced := cluster.Annotations[utils.FenceClientConnectionsAnnotationName] == "true"
+	if isFenced == fenced {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if fenced {
+		if cluster.Annotations == nil {
+			cluster.Annotations = make(map[string]string)
+		}
+		cluster.Annotations[utils.FenceClientConnectionsAnnotationName] = "true"
+	} else {
+		delete(cluster.Annotations, utils.FenceClientConnectionsAnnotationName)
+	}
+
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return fmt.Errorf("while setting the client connections fence of cluster %q: %w", cluster.Name, err)
+	}
+
+	return nil
+}
+
+// waitForCatchUp waits for every subscription to receive the changes made
+// in the source cluster before its writes were fenced
+func (r *BlueGreenUpgradeReconciler) waitForCatchUp(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+) (ctrl.Result, error) {
+	if err := r.refreshDatabasesStatus(ctx, upgrade); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	fenceLSN := types.LSN(upgrade.Status.FenceLSN)
+	caughtUp := 0
+	for _, database := range upgrade.Status.Databases {
+		if database.ReceivedLSN != "" && !types.LSN(database.ReceivedLSN).Less(fenceLSN) {
+			caughtUp++
+		}
+	}
+
+	if caughtUp < len(upgrade.Status.Databases) {
+		upgrade.Status.Message = fmt.Sprintf("%d/%d databases reached LSN %s",
+			caughtUp, len(upgrade.Status.Databases), upgrade.Status.FenceLSN)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseSynchronizingSequences
+	upgrade.Status.Message = "synchronizing the sequences"
+	return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+}
+
+// synchronizeSequences requests every subscription to synchronize its
+// sequences, and waits for the synchronization to be completed. The fence
+// LSN identifies the request
+func (r *BlueGreenUpgradeReconciler) synchronizeSequences(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+) (ctrl.Result, error) {
+	request := upgrade.Status.FenceLSN
+	synchronized := 0
+	var syncErrors []string
+
+	for _, database := range upgrade.Status.Databases {
+		var subscription apiv1.Subscription
+		if err := r.Get(
+			ctx,
+			client.ObjectKey{Namespace: upgrade.Namespace, Name: database.SubscriptionName},
+			&subscription,
+		); err != nil {
+			return ctrl.Result{}, fmt.Errorf("while getting subscription %q: %w", database.SubscriptionName, err)
+		}
+
+		if subscription.Annotations[utils.SubscriptionSyncSequencesAnnotationName] != request {
+			origSubscription := subscription.DeepCopy()
+			if subscription.Annotations == nil {
+				subscription.Annotations = make(map[string]string)
+			}
+			subscription.Annotations[utils.SubscriptionSyncSequencesAnnotationName] = request
+			if err := r.Patch(ctx, &subscription, client.MergeFrom(origSubscription)); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		sequenceSync := subscription.Status.SequenceSync
+		if sequenceSync != nil && sequenceSync.LastRequest == request {
+			synchronized++
+			continue
+		}
+		if sequenceSync != nil && sequenceSync.Error != "" {
+			syncErrors = append(syncErrors, fmt.Sprintf("%s: %s", subscription.Name, sequenceSync.Error))
+		}
+	}
+
+	if synchronized < len(upgrade.Status.Databases) {
+		upgrade.Status.Message = fmt.Sprintf("%d/%d databases with synchronized sequences",
+			synchronized, len(upgrade.Status.Databases))
+		if len(syncErrors) > 0 {
+			upgrade.Status.Message += fmt.Sprintf(" (%s)", strings.Join(syncErrors, "; "))
+		}
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseDetaching
+	upgrade.Status.Message = "dropping the subscriptions"
+	return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+}
+
+// detachSubscriptions drops the subscriptions of the target cluster, which
+// will then accept writes without conflicting with the replicated changes
+func (r *BlueGreenUpgradeReconciler) detachSubscriptions(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+) (ctrl.Result, error) {
+	remaining, err := r.deleteSubscriptions(ctx, upgrade)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if remaining > 0 {
+		upgrade.Status.Message = fmt.Sprintf("waiting for %d subscriptions to be dropped", remaining)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseSwitchingServices
+	upgrade.Status.Message = "switching the services to the target cluster"
+	return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+}
+
+// deleteSubscriptions deletes the subscriptions of the upgrade, returning
+// how many of them still exist
+func (r *BlueGreenUpgradeReconciler) deleteSubscriptions(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+) (int, error) {
+	remaining := 0
+	for _, database := range upgrade.Status.Databases {
+		var subscription apiv1.Subscription
+		err := r.Get(ctx, client.ObjectKey{Namespace: upgrade.Namespace, Name: database.SubscriptionName}, &subscription)
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		remaining++
+		if !subscription.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, &subscription); err != nil && !apierrs.IsNotFound(err) {
+			return 0, fmt.Errorf("while deleting subscription %q: %w", subscription.Name, err)
+		}
+	}
+
+	return remaining, nil
+}
+
+// switchServices switches the services of the upgrade and the poolers of
+// the source cluster to the target one, completing the cutover
+func (r *BlueGreenUpgradeReconciler) switchServices(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) error {
+	if err := r.ensureServices(ctx, upgrade, target.Name); err != nil {
+		return err
+	}
+
+	var poolers apiv1.PoolerList
+	if err := r.List(ctx, &poolers, client.InNamespace(upgrade.Namespace)); err != nil {
+		return fmt.Errorf("while listing poolers: %w", err)
+	}
+	for idx := range poolers.Items {
+		pooler := &poolers.Items[idx]
+		if pooler.Spec.Cluster.Name != source.Name {
+			continue
+		}
+
+		if err := r.switchPooler(ctx, pooler, target.Name); err != nil {
+			return err
+		}
+		if !slices.Contains(upgrade.Status.SwitchedPoolers, pooler.Name) {
+			upgrade.Status.SwitchedPoolers = append(upgrade.Status.SwitchedPoolers, pooler.Name)
+		}
+	}
+
+	upgrade.Status.CutoverCompletedAt = ptr.To(metav1.Now())
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseCompleted
+	upgrade.Status.Message = fmt.Sprintf("cluster %q is serving the applications", target.Name)
+	return nil
+}
+
+// rollBack switches the services of the upgrade and the switched poolers
+// back to the source cluster, and lifts the fence of its client connections.
+// The subscriptions are dropped first, so that the replication slots they
+// use don't retain the WAL files of the source cluster
+func (r *BlueGreenUpgradeReconciler) rollBack(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+) (ctrl.Result, error) {
+	remaining, err := r.deleteSubscriptions(ctx, upgrade)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if remaining > 0 {
+		upgrade.Status.Message = fmt.Sprintf("waiting for %d subscriptions to be dropped", remaining)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	if err := r.ensureServices(ctx, upgrade, source.Name); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, poolerName := range upgrade.Status.SwitchedPoolers {
+		var pooler apiv1.Pooler
+		err := r.Get(ctx, client.ObjectKey{Namespace: upgrade.Namespace, Name: poolerName}, &pooler)
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("while getting pooler %q: %w", poolerName, err)
+		}
+		if pooler.Spec.Cluster.Name != upgrade.Spec.TargetClusterName {
+			continue
+		}
+
+		if err := r.switchPooler(ctx, &pooler, source.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	upgrade.Status.SwitchedPoolers = nil
+
+	if err := r.setClientConnectionsFence(ctx, source, false); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseRolledBack
+	upgrade.Status.Message = fmt.Sprintf("cluster %q is serving the applications", source.Name)
+	return ctrl.Result{}, nil
+}
+
+// switchPooler points the passed pooler to the cluster with the given name
+func (r *BlueGreenUpgradeReconciler) switchPooler(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	clusterName string,
+) error {
+	origPooler := pooler.DeepCopy()
+	pooler.Spec.Cluster.Name = clusterName
+	if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+		return fmt.Errorf("while switching pooler %q: %w", pooler.Name, err)
+	}
+
+	return nil
+}
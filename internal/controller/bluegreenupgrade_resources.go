@@ -0,0 +1,366 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+)
+
+// createTargetCluster creates the cluster running the new major version,
+// importing the schema of the databases to be upgraded
+func (r *BlueGreenUpgradeReconciler) createTargetCluster(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+) error {
+	existingTarget, err := getClusterOrNil(ctx, r.Client, client.ObjectKey{
+		Namespace: upgrade.Namespace,
+		Name:      upgrade.Spec.TargetClusterName,
+	})
+	if err != nil {
+		return err
+	}
+	if existingTarget != nil {
+		upgrade.SetAsFailed(fmt.Errorf("cluster %q already exists", upgrade.Spec.TargetClusterName))
+		return nil
+	}
+
+	databases, err := r.getUpgradeDatabases(ctx, upgrade, source)
+	if err != nil {
+		return err
+	}
+	if len(databases) == 0 {
+		upgrade.SetAsFailed(fmt.Errorf("no database to be upgraded in cluster %q", source.Name))
+		return nil
+	}
+
+	target, err := buildBlueGreenTargetCluster(upgrade, source, databases)
+	if err != nil {
+		upgrade.SetAsFailed(err)
+		return nil
+	}
+
+	if err := r.Create(ctx, target); err != nil {
+		return fmt.Errorf("while creating the target cluster: %w", err)
+	}
+
+	upgrade.Status.Databases = make([]apiv1.BlueGreenUpgradeDatabaseStatus, len(databases))
+	for idx, database := range databases {
+		name := upgrade.GetReplicationObjectName(idx, database)
+		upgrade.Status.Databases[idx] = apiv1.BlueGreenUpgradeDatabaseStatus{
+			Name:             database,
+			PublicationName:  name,
+			SubscriptionName: name,
+		}
+	}
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseCreatingCluster
+	upgrade.Status.Message = fmt.Sprintf("creating cluster %q", target.Name)
+	return nil
+}
+
+// waitForTargetCluster waits for the target cluster to be ready before
+// starting the logical replication of the databases
+func (r *BlueGreenUpgradeReconciler) waitForTargetCluster(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) (ctrl.Result, error) {
+	if target.Status.Phase != apiv1.PhaseHealthy {
+		upgrade.Status.Message = fmt.Sprintf("waiting for cluster %q to be ready", target.Name)
+		return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+	}
+
+	if err := r.ensureReplicationObjects(ctx, upgrade, source, target); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseReplicating
+	upgrade.Status.Message = ""
+	return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+}
+
+// reconcileReplication tracks the progress of the logical replication,
+// starting the cutover when requested and every database is replicating
+func (r *BlueGreenUpgradeReconciler) reconcileReplication(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) (ctrl.Result, error) {
+	if err := r.ensureReplicationObjects(ctx, upgrade, source, target); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.refreshDatabasesStatus(ctx, upgrade); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	replicating := 0
+	for _, database := range upgrade.Status.Databases {
+		if database.Replicating {
+			replicating++
+		}
+	}
+	upgrade.Status.Message = fmt.Sprintf("%d/%d databases replicating", replicating, len(upgrade.Status.Databases))
+
+	if upgrade.Spec.Cutover && replicating == len(upgrade.Status.Databases) {
+		upgrade.Status.Phase = apiv1.BlueGreenUpgradePhaseFencingWrites
+		upgrade.Status.Message = fmt.Sprintf("fencing the writes of cluster %q", source.Name)
+		upgrade.Status.CutoverStartedAt = ptr.To(metav1.Now())
+	}
+
+	return ctrl.Result{RequeueAfter: blueGreenUpgradeRequeueDelay}, nil
+}
+
+// getUpgradeDatabases gets the list of the databases to be upgraded, which
+// defaults to the application database and to the databases managed by
+// Database objects
+func (r *BlueGreenUpgradeReconciler) getUpgradeDatabases(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+) ([]string, error) {
+	if len(upgrade.Spec.Databases) > 0 {
+		return upgrade.Spec.Databases, nil
+	}
+
+	var databases []string
+	if applicationDatabase := source.GetApplicationDatabaseName(); applicationDatabase != "" {
+		databases = append(databases, applicationDatabase)
+	}
+
+	var databaseList apiv1.DatabaseList
+	if err := r.List(ctx, &databaseList, client.InNamespace(upgrade.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing databases: %w", err)
+	}
+
+	for _, database := range databaseList.Items {
+		if database.Spec.ClusterRef.Name != source.Name || database.Spec.Ensure == apiv1.EnsureAbsent {
+			continue
+		}
+		if !slices.Contains(databases, database.Spec.Name) {
+			databases = append(databases, database.Spec.Name)
+		}
+	}
+
+	return databases, nil
+}
+
+// buildBlueGreenTargetCluster builds the cluster running the new major
+// version. It is a copy of the source cluster, bootstrapped by importing the
+// roles and the schema of the databases to be upgraded
+func buildBlueGreenTargetCluster(
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+	databases []string,
+) (*apiv1.Cluster, error) {
+	if !source.GetEnableSuperuserAccess() {
+		return nil, fmt.Errorf("superuser access must be enabled in cluster %q", source.Name)
+	}
+
+	target := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upgrade.Spec.TargetClusterName,
+			Namespace: upgrade.Namespace,
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	target.Spec.ImageName, target.Spec.ImageCatalogRef = upgrade.GetTargetImage()
+
+	sourceMajor, err := source.GetPostgresqlMajorVersion()
+	if err != nil {
+		return nil, err
+	}
+	targetMajor, err := target.GetPostgresqlMajorVersion()
+	if err != nil {
+		return nil, err
+	}
+	if targetMajor <= sourceMajor {
+		return nil, fmt.Errorf(
+			"the target major version (%d) must be greater than the one of cluster %q (%d)",
+			targetMajor, source.Name, sourceMajor)
+	}
+
+	target.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+		InitDB: &apiv1.BootstrapInitDB{
+			Database: source.GetApplicationDatabaseName(),
+			Owner:    source.GetApplicationDatabaseOwner(),
+			Secret:   &apiv1.LocalObjectReference{Name: source.GetApplicationSecretName()},
+			Import: &apiv1.Import{
+				Source:     apiv1.ImportSource{ExternalCluster: source.Name},
+				Type:       apiv1.MonolithSnapshotType,
+				Databases:  databases,
+				Roles:      []string{"*"},
+				SchemaOnly: true,
+			},
+		},
+	}
+	target.Spec.ExternalClusters = []apiv1.ExternalCluster{
+		{
+			Name: source.Name,
+			ConnectionParameters: map[string]string{
+				"host":    source.GetServiceReadWriteName(),
+				"user":    "postgres",
+				"dbname":  "postgres",
+				"sslmode": "verify-full",
+			},
+			SSLRootCert: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.GetServerCASecretName()},
+				Key:                  certs.CACertKey,
+			},
+			Password: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.GetSuperuserSecretName()},
+				Key:                  corev1.BasicAuthPasswordKey,
+			},
+		},
+	}
+
+	// The target cluster is a primary cluster, and must neither archive
+	// its WALs in the same place as the source cluster nor reuse its
+	// additional services
+	target.Spec.ReplicaCluster = nil
+	target.Spec.Backup = nil
+	target.Spec.Plugins = slices.DeleteFunc(target.Spec.Plugins, func(plugin apiv1.PluginConfiguration) bool {
+		return plugin.IsWALArchiver != nil && *plugin.IsWALArchiver
+	})
+	if target.Spec.Managed != nil && target.Spec.Managed.Services != nil {
+		target.Spec.Managed.Services.Additional = nil
+	}
+
+	// Applications connect to the services of the upgrade, which point
+	// to the target cluster after the cutover
+	addServerAltDNSNames(target, upgrade.GetServiceAltDNSNames())
+
+	return target, nil
+}
+
+// ensureReplicationObjects creates the publications in the source cluster
+// and the subscriptions in the target cluster
+func (r *BlueGreenUpgradeReconciler) ensureReplicationObjects(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+	target *apiv1.Cluster,
+) error {
+	for idx, database := range upgrade.Status.Databases {
+		postgresName := upgrade.GetReplicationPostgresName(idx)
+
+		publication := &apiv1.Publication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      database.PublicationName,
+				Namespace: upgrade.Namespace,
+			},
+			Spec: apiv1.PublicationSpec{
+				ClusterRef:    corev1.LocalObjectReference{Name: source.Name},
+				Name:          postgresName,
+				DBName:        database.Name,
+				Target:        apiv1.PublicationTarget{AllTables: true},
+				ReclaimPolicy: apiv1.PublicationReclaimDelete,
+			},
+		}
+		if err := r.createIfNotExists(ctx, upgrade, publication); err != nil {
+			return fmt.Errorf("while creating publication %q: %w", publication.Name, err)
+		}
+
+		subscription := &apiv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      database.SubscriptionName,
+				Namespace: upgrade.Namespace,
+			},
+			Spec: apiv1.SubscriptionSpec{
+				ClusterRef:          corev1.LocalObjectReference{Name: target.Name},
+				Name:                postgresName,
+				DBName:              database.Name,
+				PublicationName:     postgresName,
+				PublicationDBName:   database.Name,
+				ExternalClusterName: source.Name,
+				ReclaimPolicy:       apiv1.SubscriptionReclaimDelete,
+			},
+		}
+		if err := r.createIfNotExists(ctx, upgrade, subscription); err != nil {
+			return fmt.Errorf("while creating subscription %q: %w", subscription.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// createIfNotExists creates the passed object, owned by the upgrade,
+// unless it already exists
+func (r *BlueGreenUpgradeReconciler) createIfNotExists(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	obj client.Object,
+) error {
+	if err := ctrl.SetControllerReference(upgrade, obj, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, obj); err != nil && !apierrs.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// refreshDatabasesStatus updates the replication status of the databases
+// reading it from the subscriptions
+func (r *BlueGreenUpgradeReconciler) refreshDatabasesStatus(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+) error {
+	for idx := range upgrade.Status.Databases {
+		database := &upgrade.Status.Databases[idx]
+
+		var subscription apiv1.Subscription
+		err := r.Get(ctx, client.ObjectKey{Namespace: upgrade.Namespace, Name: database.SubscriptionName}, &subscription)
+		if apierrs.IsNotFound(err) {
+			database.Replicating = false
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		database.Replicating = meta.IsStatusConditionTrue(
+			subscription.Status.Conditions,
+			string(apiv1.ConditionSubscriptionReplicating))
+		if runtimeStatus := subscription.Status.Runtime; runtimeStatus != nil {
+			database.ReceivedLSN = runtimeStatus.ReceivedLSN
+			database.ApplyLag = runtimeStatus.ApplyLag
+		}
+	}
+
+	return nil
+}
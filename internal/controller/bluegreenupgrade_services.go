@@ -0,0 +1,136 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// ensureServices creates the read-write, read and read-only services of
+// the upgrade, pointing them to the cluster with the passed name. These
+// services are used by the applications in place of the ones of the source
+// cluster, which keep serving its replicas and the logical replication
+func (r *BlueGreenUpgradeReconciler) ensureServices(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	clusterName string,
+) error {
+	servingCluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: upgrade.Namespace},
+	}
+
+	readWriteService := specs.CreateClusterReadWriteService(servingCluster)
+	readWriteService.Name = upgrade.GetServiceReadWriteName()
+	readService := specs.CreateClusterReadService(servingCluster)
+	readService.Name = upgrade.GetServiceReadName()
+	readOnlyService := specs.CreateClusterReadOnlyService(servingCluster)
+	readOnlyService.Name = upgrade.GetServiceReadOnlyName()
+
+	for _, service := range []*corev1.Service{readWriteService, readService, readOnlyService} {
+		if err := r.ensureService(ctx, upgrade, service); err != nil {
+			return fmt.Errorf("while reconciling service %q: %w", service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureService creates the passed service, owned by the upgrade, or
+// updates its selector when it already exists
+func (r *BlueGreenUpgradeReconciler) ensureService(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	proposed *corev1.Service,
+) error {
+	var livingService corev1.Service
+	err := r.Get(ctx, client.ObjectKeyFromObject(proposed), &livingService)
+	if apierrs.IsNotFound(err) {
+		if err := ctrl.SetControllerReference(upgrade, proposed, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, proposed)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(&livingService, upgrade) {
+		return fmt.Errorf("refusing to reconcile service %q, not owned by the upgrade", livingService.Name)
+	}
+
+	if reflect.DeepEqual(proposed.Spec.Selector, livingService.Spec.Selector) {
+		return nil
+	}
+
+	origService := livingService.DeepCopy()
+	livingService.Spec.Selector = proposed.Spec.Selector
+	return r.Patch(ctx, &livingService, client.MergeFrom(origService))
+}
+
+// ensureServerAltDNSNames makes the server certificate of the source
+// cluster valid for the names of the services of the upgrade, which point
+// to the source cluster until the cutover is completed
+func (r *BlueGreenUpgradeReconciler) ensureServerAltDNSNames(
+	ctx context.Context,
+	upgrade *apiv1.BlueGreenUpgrade,
+	source *apiv1.Cluster,
+) error {
+	origSource := source.DeepCopy()
+	if !addServerAltDNSNames(source, upgrade.GetServiceAltDNSNames()) {
+		return nil
+	}
+
+	if err := r.Patch(ctx, source, client.MergeFrom(origSource)); err != nil {
+		return fmt.Errorf("while adding the alternative DNS names of cluster %q: %w", source.Name, err)
+	}
+
+	return nil
+}
+
+// addServerAltDNSNames adds the missing names to the alternative DNS names
+// of the server certificate of the passed cluster, returning true if any
+// name has been added
+func addServerAltDNSNames(cluster *apiv1.Cluster, names []string) bool {
+	if cluster.Spec.Certificates == nil {
+		cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{}
+	}
+
+	added := false
+	for _, name := range names {
+		if !slices.Contains(cluster.Spec.Certificates.ServerAltDNSNames, name) {
+			cluster.Spec.Certificates.ServerAltDNSNames = append(cluster.Spec.Certificates.ServerAltDNSNames, name)
+			added = true
+		}
+	}
+
+	return added
+}
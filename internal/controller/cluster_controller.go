@@ -553,6 +553,12 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return res, err
 	}
 
+	// Rotates the passwords of the managed roles having a rotation policy
+	nextPasswordRotation, err := r.reconcilePasswordRotation(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot rotate the passwords of the managed roles: %w", err)
+	}
+
 	// Calls post-reconcile hooks
 	if hookResult := postReconcilePluginHooks(ctx, cluster, cluster); hookResult.Err != nil ||
 		!hookResult.Result.IsZero() {
@@ -561,7 +567,11 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return hookResult.Result, hookResult.Err
 	}
 
-	return setStatusPluginHook(ctx, r.Client, cnpgiClient.GetPluginClientFromContext(ctx), cluster)
+	res, err = setStatusPluginHook(ctx, r.Client, cnpgiClient.GetPluginClientFromContext(ctx), cluster)
+	if err == nil && res.IsZero() && nextPasswordRotation > 0 {
+		res.RequeueAfter = nextPasswordRotation
+	}
+	return res, err
 }
 
 func (r *ClusterReconciler) ensureNoFailoverOnFullDisk(
@@ -1188,6 +1198,17 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		Watches(
 			&apiv1.Pooler{},
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&apiv1.Database{},
+			handler.EnqueueRequestsFromMapFunc(r.mapDatabasesToClusters()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&apiv1.Role{},
+			handler.EnqueueRequestsFromMapFunc(r.mapRolesToClusters()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
 		).
 		Watches(
 			&corev1.Node{},
@@ -1420,6 +1441,38 @@ func (r *ClusterReconciler) mapPoolersToClusters() handler.MapFunc {
 	}
 }
 
+// mapDatabasesToClusters returns a function mapping database events
+// to the reconcile requests of the cluster hosting them, as the
+// credentials used by a database need to be readable by the instances
+func (r *ClusterReconciler) mapDatabasesToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		database, ok := obj.(*apiv1.Database)
+		if !ok || database.Spec.ClusterRef.Name == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Namespace: database.Namespace,
+			Name:      database.Spec.ClusterRef.Name,
+		}}}
+	}
+}
+
+// mapRolesToClusters returns a function mapping role events
+// to the reconcile requests of the cluster hosting them, as the
+// password secrets of the roles need to be readable by the instances
+func (r *ClusterReconciler) mapRolesToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		role, ok := obj.(*apiv1.Role)
+		if !ok || role.Spec.ClusterRef.Name == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Namespace: role.Namespace,
+			Name:      role.Spec.ClusterRef.Name,
+		}}}
+	}
+}
+
 // mapNodeToClusters returns a function mapping cluster events watched to cluster reconcile requests
 func (r *ClusterReconciler) mapConfigMapsToClusters() handler.MapFunc {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -271,32 +271,52 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 		return nil
 	}
 
-	if len(cluster.Status.PoolerIntegrations.PgBouncerIntegration.Secrets) > 0 {
-		var clientCaSecret corev1.Secret
+	integration := cluster.Status.PoolerIntegrations.PgBouncerIntegration
+	if len(integration.Secrets) == 0 && len(integration.CertificateAuthentication) == 0 {
+		return nil
+	}
 
-		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetClientCASecretName()},
-			&clientCaSecret)
+	var clientCaSecret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetClientCASecretName()},
+		&clientCaSecret)
+	if err != nil {
+		return err
+	}
+
+	for _, secretName := range integration.Secrets {
+		replicationSecretName := client.ObjectKey{
+			Namespace: cluster.GetNamespace(),
+			Name:      secretName,
+		}
+		err = r.ensureLeafCertificate(
+			ctx,
+			cluster,
+			replicationSecretName,
+			apiv1.PGBouncerPoolerUserName,
+			&clientCaSecret,
+			certs.CertTypeClient,
+			nil,
+			map[string]string{utils.WatchedLabelName: "true"})
 		if err != nil {
 			return err
 		}
+	}
 
-		for _, secretName := range cluster.Status.PoolerIntegrations.PgBouncerIntegration.Secrets {
-			replicationSecretName := client.ObjectKey{
-				Namespace: cluster.GetNamespace(),
-				Name:      secretName,
-			}
-			err = r.ensureLeafCertificate(
-				ctx,
-				cluster,
-				replicationSecretName,
-				apiv1.PGBouncerPoolerUserName,
-				&clientCaSecret,
-				certs.CertTypeClient,
-				nil,
-				map[string]string{utils.WatchedLabelName: "true"})
-			if err != nil {
-				return err
-			}
+	// Every pooler authenticating its clients through TLS certificates
+	// has a dedicated client certificate, so that the other poolers
+	// can't connect on behalf of its clients
+	for _, certificateAuthentication := range integration.CertificateAuthentication {
+		err = r.ensureLeafCertificate(
+			ctx,
+			cluster,
+			client.ObjectKey{Namespace: cluster.GetNamespace(), Name: certificateAuthentication.SecretName},
+			certificateAuthentication.CommonName,
+			&clientCaSecret,
+			certs.CertTypeClient,
+			nil,
+			map[string]string{utils.WatchedLabelName: "true"})
+		if err != nil {
+			return fmt.Errorf("generating the client certificate %q: %w", certificateAuthentication.SecretName, err)
 		}
 	}
 
@@ -711,6 +731,11 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		return err
 	}
 
+	references, err := r.getDeclarativeReferences(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	var role rbacv1.Role
 	if err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &role); err != nil {
 		if !apierrs.IsNotFound(err) {
@@ -718,10 +743,10 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		}
 
 		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
-		return r.createRole(ctx, cluster, originBackup)
+		return r.createRole(ctx, cluster, originBackup, references)
 	}
 
-	generatedRole := specs.CreateRole(*cluster, originBackup)
+	generatedRole := specs.CreateRole(*cluster, originBackup, references)
 	if equality.Semantic.DeepEqual(generatedRole.Rules, role.Rules) {
 		// Everything fine, the two rules have the same content
 		return nil
@@ -740,6 +765,107 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 	return nil
 }
 
+// getDeclarativeReferences gets the secrets and the config maps referenced
+// by the Database and Role objects of the cluster, together with the
+// resource version of the ones allowing being referenced through the
+// DeclarativeReferenceLabelName label. The instance manager can only
+// read the latter ones
+func (r *ClusterReconciler) getDeclarativeReferences(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (specs.DeclarativeReferences, error) {
+	databases, err := r.getClusterDatabases(ctx, cluster)
+	if err != nil {
+		return specs.DeclarativeReferences{}, err
+	}
+
+	roles, err := r.getClusterRoles(ctx, cluster)
+	if err != nil {
+		return specs.DeclarativeReferences{}, err
+	}
+
+	references := specs.GetDeclarativeReferences(databases, roles)
+	for name := range references.SecretVersions {
+		var secret corev1.Secret
+		if err := r.getDeclarativeReference(ctx, cluster, name, &secret); err != nil {
+			return specs.DeclarativeReferences{}, err
+		}
+		references.SecretVersions[name] = secret.ResourceVersion
+	}
+	for name := range references.ConfigMapVersions {
+		var configMap corev1.ConfigMap
+		if err := r.getDeclarativeReference(ctx, cluster, name, &configMap); err != nil {
+			return specs.DeclarativeReferences{}, err
+		}
+		references.ConfigMapVersions[name] = configMap.ResourceVersion
+	}
+
+	return references, nil
+}
+
+// getDeclarativeReference gets a secret or a config map referenced by a
+// Database object, leaving the passed object empty when it doesn't
+// exist or doesn't allow being referenced
+func (r *ClusterReconciler) getDeclarativeReference(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	name string,
+	object client.Object,
+) error {
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, object)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting %q: %w", name, err)
+	}
+	if !utils.IsDeclarativeReferenceAllowed(object) {
+		object.SetResourceVersion("")
+	}
+
+	return nil
+}
+
+// getClusterDatabases gets the Database objects referring to the cluster
+func (r *ClusterReconciler) getClusterDatabases(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]apiv1.Database, error) {
+	var databaseList apiv1.DatabaseList
+	if err := r.List(ctx, &databaseList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing databases: %w", err)
+	}
+
+	result := make([]apiv1.Database, 0, len(databaseList.Items))
+	for _, database := range databaseList.Items {
+		if database.Spec.ClusterRef.Name == cluster.Name {
+			result = append(result, database)
+		}
+	}
+
+	return result, nil
+}
+
+// getClusterRoles gets the Role objects referring to the cluster
+func (r *ClusterReconciler) getClusterRoles(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]apiv1.Role, error) {
+	var roleList apiv1.RoleList
+	if err := r.List(ctx, &roleList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing roles: %w", err)
+	}
+
+	result := make([]apiv1.Role, 0, len(roleList.Items))
+	for _, role := range roleList.Items {
+		if role.Spec.ClusterRef.Name == cluster.Name {
+			result = append(result, role)
+		}
+	}
+
+	return result, nil
+}
+
 // createOrPatchDefaultMetricsConfigmap ensures that the required configmap containing
 // default monitoring queries exists and contains the latest queries
 func (r *ClusterReconciler) createOrPatchDefaultMetricsConfigmap(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -1011,8 +1137,13 @@ func createOrPatchPodMonitor(
 }
 
 // createRole creates the role
-func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
-	role := specs.CreateRole(*cluster, backupOrigin)
+func (r *ClusterReconciler) createRole(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	references specs.DeclarativeReferences,
+) error {
+	role := specs.CreateRole(*cluster, backupOrigin, references)
 	cluster.SetInheritedDataAndOwnership(&role.ObjectMeta)
 
 	err := r.Create(ctx, &role)
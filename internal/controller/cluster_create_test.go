@@ -1344,3 +1344,54 @@ var _ = Describe("Service Reconciling", func() {
 		})
 	})
 })
+
+var _ = Describe("getDeclarativeReferences", func() {
+	It("grants access only to the referenced objects allowing it", func(ctx SpecContext) {
+		const namespace = "default"
+		allowed := map[string]string{utils.DeclarativeReferenceLabelName: "true"}
+		cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: namespace}}
+		database := &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: cluster.Name},
+				UserMappings: []apiv1.UserMappingSpec{{
+					User:              "app",
+					Server:            "remote",
+					CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-credentials"},
+				}},
+				Migrations: &apiv1.DatabaseMigrations{
+					SQLRefs: apiv1.SQLRefs{
+						SecretRefs: []apiv1.SecretKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "cluster-example-superuser"},
+							Key:                  "password",
+						}},
+						ConfigMapRefs: []apiv1.ConfigMapKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "missing"},
+							Key:                  "V1__init.sql",
+						}},
+					},
+				},
+			},
+		}
+		r := &ClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(
+					database,
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+						Name: "remote-credentials", Namespace: namespace, Labels: allowed,
+					}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster-example-superuser", Namespace: namespace,
+					}},
+				).
+				Build(),
+		}
+
+		references, err := r.getDeclarativeReferences(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(references.SecretVersions).To(HaveLen(2))
+		Expect(references.SecretVersions["remote-credentials"]).ToNot(BeEmpty())
+		Expect(references.SecretVersions).To(HaveKeyWithValue("cluster-example-superuser", ""))
+		Expect(references.ConfigMapVersions).To(Equal(map[string]string{"missing": ""}))
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/sethvargo/go-password/password"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcilePasswordRotation rotates the passwords of the managed roles
+// having a rotation policy, and removes the previous credentials once their
+// grace period is over. The instance manager keeps applying the previous
+// password, until its secret is removed, and then applies the new one.
+// It returns the time after which the cluster needs to be reconciled
+// again, or zero if nothing is scheduled
+func (r *ClusterReconciler) reconcilePasswordRotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (time.Duration, error) {
+	if cluster.Spec.Managed == nil || cluster.IsReplica() {
+		return 0, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	origCluster := cluster.DeepCopy()
+	now := metav1.Now().Rfc3339Copy()
+
+	var requeueAfter time.Duration
+	scheduleAfter := func(duration time.Duration) {
+		if requeueAfter == 0 || duration < requeueAfter {
+			requeueAfter = duration
+		}
+	}
+
+	for _, role := range cluster.Spec.Managed.Roles {
+		if role.PasswordRotation == nil || role.PasswordSecret == nil ||
+			role.DisablePassword || role.Ensure == apiv1.EnsureAbsent {
+			continue
+		}
+
+		state := cluster.Status.ManagedRolesStatus.PasswordStatus[role.Name]
+		if state.RotatedAt == nil || !now.Time.Before(role.PasswordRotation.GetNextRotation(state.RotatedAt.Time)) {
+			rotated, err := r.rotateRolePassword(ctx, cluster, role, state.RotatedAt == nil)
+			if err != nil {
+				return 0, fmt.Errorf("while rotating the password of role %q: %w", role.Name, err)
+			}
+			if rotated {
+				contextLogger.Info("Rotated the password of a managed role",
+					"role", role.Name, "secretName", role.PasswordSecret.Name)
+			}
+
+			state.RotatedAt = now.DeepCopy()
+			if cluster.Status.ManagedRolesStatus.PasswordStatus == nil {
+				cluster.Status.ManagedRolesStatus.PasswordStatus = make(map[string]apiv1.PasswordState)
+			}
+			cluster.Status.ManagedRolesStatus.PasswordStatus[role.Name] = state
+		}
+
+		gracePeriodEnd := state.RotatedAt.Add(role.PasswordRotation.GetGracePeriod())
+		if now.Time.Before(gracePeriodEnd) {
+			scheduleAfter(gracePeriodEnd.Sub(now.Time))
+		} else if err := r.deletePreviousPasswordSecret(ctx, cluster, role); err != nil {
+			return 0, err
+		}
+
+		scheduleAfter(role.PasswordRotation.GetNextRotation(state.RotatedAt.Time).Sub(now.Time))
+	}
+
+	if equality.Semantic.DeepEqual(origCluster.Status.ManagedRolesStatus, cluster.Status.ManagedRolesStatus) {
+		return requeueAfter, nil
+	}
+
+	if err := r.Client.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return 0, err
+	}
+
+	return requeueAfter, nil
+}
+
+// rotateRolePassword generates a new password for the passed role, keeping
+// the previous credentials in a separate secret. When the rotation policy
+// has just been enabled, the existing password is kept and only a missing
+// password secret is generated. It returns true if the password has
+// been changed
+func (r *ClusterReconciler) rotateRolePassword(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	role apiv1.RoleConfiguration,
+	initial bool,
+) (bool, error) {
+	newPassword, err := password.Generate(64, 10, 0, false, true)
+	if err != nil {
+		return false, err
+	}
+
+	var secret corev1.Secret
+	err = r.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: role.PasswordSecret.Name},
+		&secret)
+	if apierrs.IsNotFound(err) {
+		passwordSecret := createPasswordSecret(role.PasswordSecret.Name, cluster.Namespace, role.Name, newPassword)
+		cluster.SetInheritedDataAndOwnership(&passwordSecret.ObjectMeta)
+		return true, r.Client.Create(ctx, passwordSecret)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if initial {
+		return false, nil
+	}
+
+	if err := r.storePreviousPasswordSecret(ctx, cluster, role, &secret); err != nil {
+		return false, err
+	}
+
+	origSecret := secret.DeepCopy()
+	secret.Data = rotatedPasswordSecretData(&secret, newPassword)
+	return true, r.Client.Patch(ctx, &secret, client.MergeFrom(origSecret))
+}
+
+// storePreviousPasswordSecret copies the current credentials of a role
+// into the secret used during the grace period of a rotation
+func (r *ClusterReconciler) storePreviousPasswordSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	role apiv1.RoleConfiguration,
+	secret *corev1.Secret,
+) error {
+	previousSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      role.GetPreviousPasswordSecretName(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.WatchedLabelName: "true",
+			},
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	cluster.SetInheritedDataAndOwnership(&previousSecret.ObjectMeta)
+
+	var currentSecret corev1.Secret
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(previousSecret), &currentSecret)
+	if apierrs.IsNotFound(err) {
+		return r.Client.Create(ctx, previousSecret)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, owned := IsOwnedByCluster(&currentSecret); !owned {
+		return fmt.Errorf("secret %q already exists and is not owned by the cluster", previousSecret.Name)
+	}
+
+	patchedSecret := currentSecret.DeepCopy()
+	patchedSecret.Data = secret.Data
+	if patchedSecret.Labels == nil {
+		patchedSecret.Labels = make(map[string]string)
+	}
+	patchedSecret.Labels[utils.WatchedLabelName] = "true"
+	return r.Client.Patch(ctx, patchedSecret, client.MergeFrom(&currentSecret))
+}
+
+// deletePreviousPasswordSecret removes the previous credentials of a role
+// once the grace period of the last rotation is over
+func (r *ClusterReconciler) deletePreviousPasswordSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	role apiv1.RoleConfiguration,
+) error {
+	var secret corev1.Secret
+	err := r.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: role.GetPreviousPasswordSecretName()},
+		&secret)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// we only delete the secrets we created
+	if _, owned := IsOwnedByCluster(&secret); !owned {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Client.Delete(ctx, &secret))
+}
+
+// createPasswordSecret creates a basic-auth secret containing the
+// credentials of a managed role
+func createPasswordSecret(name, namespace, username, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				utils.WatchedLabelName: "true",
+			},
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte(username),
+			corev1.BasicAuthPasswordKey: []byte(password),
+		},
+	}
+}
+
+// rotatedPasswordSecretData returns the content of a password secret
+// after changing its password. The connection strings generated by
+// the operator, when present, are regenerated as well
+func rotatedPasswordSecretData(secret *corev1.Secret, newPassword string) map[string][]byte {
+	data := make(map[string][]byte, len(secret.Data))
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+	data[corev1.BasicAuthPasswordKey] = []byte(newPassword)
+
+	if _, hasConnectionStrings := secret.Data["pgpass"]; !hasConnectionStrings {
+		return data
+	}
+
+	regenerated := specs.CreateSecret(
+		secret.Name,
+		secret.Namespace,
+		string(secret.Data["host"]),
+		string(secret.Data["dbname"]),
+		string(secret.Data[corev1.BasicAuthUsernameKey]),
+		newPassword,
+		utils.UserTypeApp,
+	)
+	for key, value := range regenerated.StringData {
+		if _, found := data[key]; found {
+			data[key] = []byte(value)
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed roles password rotation", func() {
+	const (
+		namespace  = "default"
+		secretName = "app-user-secret"
+	)
+
+	var (
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+		reconciler *ClusterReconciler
+	)
+
+	getSecret := func(ctx SpecContext, name string) (*corev1.Secret, error) {
+		var secret corev1.Secret
+		err := fakeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret)
+		return &secret, err
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{Kind: apiv1.ClusterKind, APIVersion: apiv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{
+							Name:           "app_user",
+							Ensure:         apiv1.EnsurePresent,
+							PasswordSecret: &apiv1.LocalObjectReference{Name: secretName},
+							PasswordRotation: &apiv1.PasswordRotationPolicy{
+								Interval:    metav1.Duration{Duration: 720 * time.Hour},
+								GracePeriod: &metav1.Duration{Duration: 10 * time.Minute},
+							},
+						},
+						{
+							Name: "not_rotated",
+						},
+					},
+				},
+			},
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		reconciler = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: record.NewFakeRecorder(120),
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+		}
+	})
+
+	It("generates the password secret when missing", func(ctx SpecContext) {
+		requeueAfter, err := reconciler.reconcilePasswordRotation(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(Equal(10 * time.Minute))
+
+		secret, err := getSecret(ctx, secretName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("username", []byte("app_user")))
+		Expect(secret.Data["password"]).To(HaveLen(64))
+
+		state := cluster.Status.ManagedRolesStatus.PasswordStatus["app_user"]
+		Expect(state.RotatedAt).ToNot(BeNil())
+		Expect(cluster.Status.ManagedRolesStatus.PasswordStatus).ToNot(HaveKey("not_rotated"))
+	})
+
+	It("keeps the existing password when the policy has just been enabled", func(ctx SpecContext) {
+		secret := createPasswordSecret(secretName, namespace, "app_user", "original")
+		Expect(fakeClient.Create(ctx, secret)).To(Succeed())
+
+		_, err := reconciler.reconcilePasswordRotation(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		secret, err = getSecret(ctx, secretName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("original")))
+		Expect(cluster.Status.ManagedRolesStatus.PasswordStatus["app_user"].RotatedAt).ToNot(BeNil())
+
+		_, err = getSecret(ctx, secretName+apiv1.PreviousPasswordSecretSuffix)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("rotates the password once the interval is elapsed", func(ctx SpecContext) {
+		secret := createPasswordSecret(secretName, namespace, "app_user", "original")
+		Expect(fakeClient.Create(ctx, secret)).To(Succeed())
+
+		rotatedAt := metav1.NewTime(time.Now().Add(-721 * time.Hour))
+		cluster.Status.ManagedRolesStatus.PasswordStatus = map[string]apiv1.PasswordState{
+			"app_user": {TransactionID: 42, RotatedAt: &rotatedAt},
+		}
+		Expect(fakeClient.Status().Update(ctx, cluster)).To(Succeed())
+
+		requeueAfter, err := reconciler.reconcilePasswordRotation(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(Equal(10 * time.Minute))
+
+		secret, err = getSecret(ctx, secretName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data["password"]).ToNot(Equal([]byte("original")))
+		Expect(secret.Data).To(HaveKeyWithValue("username", []byte("app_user")))
+
+		previousSecret, err := getSecret(ctx, secretName+apiv1.PreviousPasswordSecretSuffix)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(previousSecret.Data).To(HaveKeyWithValue("username", []byte("app_user")))
+		Expect(previousSecret.Data).To(HaveKeyWithValue("password", []byte("original")))
+		Expect(previousSecret.Labels).To(HaveKeyWithValue(utils.WatchedLabelName, "true"))
+		_, owned := IsOwnedByCluster(previousSecret)
+		Expect(owned).To(BeTrue())
+
+		state := cluster.Status.ManagedRolesStatus.PasswordStatus["app_user"]
+		Expect(state.TransactionID).To(BeEquivalentTo(42))
+		Expect(state.RotatedAt.After(rotatedAt.Time)).To(BeTrue())
+	})
+
+	It("removes the previous credentials after the grace period", func(ctx SpecContext) {
+		rotatedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+		cluster.Status.ManagedRolesStatus.PasswordStatus = map[string]apiv1.PasswordState{
+			"app_user": {RotatedAt: &rotatedAt},
+		}
+		previousSecret := createPasswordSecret(
+			secretName+apiv1.PreviousPasswordSecretSuffix, namespace, "app_user", "original")
+		cluster.SetInheritedDataAndOwnership(&previousSecret.ObjectMeta)
+		Expect(fakeClient.Create(ctx, previousSecret)).To(Succeed())
+
+		requeueAfter, err := reconciler.reconcilePasswordRotation(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(BeNumerically("~", 719*time.Hour, time.Minute))
+
+		_, err = getSecret(ctx, previousSecret.Name)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("does nothing on replica clusters", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: ptr.To(true)}
+
+		requeueAfter, err := reconciler.reconcilePasswordRotation(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+
+		_, err = getSecret(ctx, secretName)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("rotatedPasswordSecretData", func() {
+	It("only changes the password of a basic-auth secret", func() {
+		secret := createPasswordSecret("secret", "default", "app_user", "original")
+		data := rotatedPasswordSecretData(secret, "new")
+		Expect(data).To(HaveKeyWithValue("password", []byte("new")))
+		Expect(data).To(HaveKeyWithValue("username", []byte("app_user")))
+		Expect(data).To(HaveLen(2))
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("original")))
+	})
+
+	It("regenerates the connection strings generated by the operator", func() {
+		generated := specs.CreateSecret(
+			"secret", "default", "cluster-example-rw", "app", "app_user", "original", utils.UserTypeApp)
+		secret := &corev1.Secret{Data: make(map[string][]byte)}
+		for key, value := range generated.StringData {
+			secret.Data[key] = []byte(value)
+		}
+		secret.Data["custom"] = []byte("untouched")
+
+		data := rotatedPasswordSecretData(secret, "new")
+		Expect(data).To(HaveKeyWithValue("password", []byte("new")))
+		Expect(data).To(HaveKeyWithValue("custom", []byte("untouched")))
+		Expect(string(data["pgpass"])).To(Equal("cluster-example-rw:5432:app:app_user:new\n"))
+		Expect(string(data["uri"])).To(ContainSubstring("app_user:new@"))
+	})
+})
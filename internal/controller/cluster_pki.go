@@ -67,6 +67,69 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 		return fmt.Errorf("generating streaming replication client certificate: %w", err)
 	}
 
+	return r.ensureManagedRolesClientCertificates(ctx, cluster, clientCaSecret)
+}
+
+// ensureManagedRolesClientCertificates checks if we have a client certificate
+// for every managed role requiring it, and generate/renew them. Secrets that
+// are not owned by the cluster are never overwritten, and no certificate is
+// issued when the operator is not in control of the private key of the
+// client CA
+func (r *ClusterReconciler) ensureManagedRolesClientCertificates(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caSecret *v1.Secret,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	roles := cluster.GetRolesWithClientCertificate()
+	if len(roles) == 0 {
+		return nil
+	}
+
+	if _, err := certs.ParseCASecret(caSecret); err != nil {
+		contextLogger.Info("Cannot issue the client certificates of the managed roles",
+			"secret", caSecret.Name, "error", err.Error())
+		r.Recorder.Event(cluster, "Warning", "ClientCertificatesNotIssued",
+			fmt.Sprintf("Cannot sign the client certificates of the managed roles with the client CA %s: %s",
+				caSecret.Name, err.Error()))
+		return nil
+	}
+
+	for _, role := range roles {
+		secretName := client.ObjectKey{Namespace: cluster.GetNamespace(), Name: role.ClientCertificate.SecretName}
+
+		var secret v1.Secret
+		err := r.Get(ctx, secretName, &secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("getting the client certificate secret for role %q: %w", role.Name, err)
+		}
+		if err == nil {
+			if owner, owned := IsOwnedByCluster(&secret); !owned || owner != cluster.Name {
+				contextLogger.Info("Ignoring the client certificate secret not owned by the cluster",
+					"role", role.Name, "secret", secretName.Name)
+				r.Recorder.Event(cluster, "Warning", "ClientCertificateSecretNotOwned",
+					fmt.Sprintf("Secret %s, requested for the client certificate of role %s, "+
+						"is not owned by the cluster", secretName.Name, role.Name))
+				continue
+			}
+		}
+
+		err = r.ensureLeafCertificate(
+			ctx,
+			cluster,
+			secretName,
+			role.Name,
+			caSecret,
+			certs.CertTypeClient,
+			nil,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("generating client certificate for role %q: %w", role.Name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,136 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed roles client certificates", func() {
+	const (
+		namespace  = "default"
+		secretName = "cluster-example-dante-cert"
+	)
+
+	var (
+		cluster    *apiv1.Cluster
+		caSecret   *corev1.Secret
+		fakeClient client.Client
+		reconciler *ClusterReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	getSecret := func(ctx SpecContext) (*corev1.Secret, error) {
+		var secret corev1.Secret
+		err := fakeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, &secret)
+		return &secret, err
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{Kind: apiv1.ClusterKind, APIVersion: apiv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{
+							Name:              "dante",
+							Ensure:            apiv1.EnsurePresent,
+							Login:             true,
+							ClientCertificate: &apiv1.RoleClientCertificate{SecretName: secretName},
+						},
+					},
+				},
+			},
+		}
+
+		ca, err := certs.CreateRootCA("cluster-example", namespace)
+		Expect(err).ToNot(HaveOccurred())
+		caSecret = ca.GenerateCASecret(namespace, "cluster-example-ca")
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		recorder = record.NewFakeRecorder(120)
+		reconciler = &ClusterReconciler{
+			Client:   fakeClient,
+			Recorder: recorder,
+			Scheme:   schemeBuilder.BuildWithAllKnownScheme(),
+		}
+	})
+
+	It("issues a client certificate owned by the cluster", func(ctx SpecContext) {
+		Expect(reconciler.ensureManagedRolesClientCertificates(ctx, cluster, caSecret)).To(Succeed())
+
+		secret, err := getSecret(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		owner, owned := IsOwnedByCluster(secret)
+		Expect(owned).To(BeTrue())
+		Expect(owner).To(Equal(cluster.Name))
+	})
+
+	It("never overwrites a secret not owned by the cluster", func(ctx SpecContext) {
+		userSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+			Data:       map[string][]byte{"tls.crt": []byte("user certificate")},
+		}
+		Expect(fakeClient.Create(ctx, userSecret)).To(Succeed())
+
+		Expect(reconciler.ensureManagedRolesClientCertificates(ctx, cluster, caSecret)).To(Succeed())
+
+		secret, err := getSecret(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data).To(Equal(userSecret.Data))
+		Expect(recorder.Events).To(Receive(ContainSubstring("ClientCertificateSecretNotOwned")))
+	})
+
+	It("skips the certificates when the client CA has no private key", func(ctx SpecContext) {
+		delete(caSecret.Data, certs.CAPrivateKeyKey)
+
+		Expect(reconciler.ensureManagedRolesClientCertificates(ctx, cluster, caSecret)).To(Succeed())
+
+		_, err := getSecret(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("ClientCertificatesNotIssued")))
+	})
+
+	It("doesn't require the private key of the client CA when no role needs a certificate", func(ctx SpecContext) {
+		cluster.Spec.Managed.Roles[0].ClientCertificate = nil
+		delete(caSecret.Data, certs.CAPrivateKeyKey)
+
+		Expect(reconciler.ensureManagedRolesClientCertificates(ctx, cluster, caSecret)).To(Succeed())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
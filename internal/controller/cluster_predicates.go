@@ -34,14 +34,14 @@ var (
 	isUsefulConfigMap = func(object client.Object) bool {
 		return isOwnedByClusterOrSatisfiesPredicate(object, func(object client.Object) bool {
 			_, ok := object.(*corev1.ConfigMap)
-			return ok && hasReloadLabelSet(object)
+			return ok && (hasReloadLabelSet(object) || utils.IsDeclarativeReferenceAllowed(object))
 		})
 	}
 
 	isUsefulClusterSecret = func(object client.Object) bool {
 		return isOwnedByClusterOrSatisfiesPredicate(object, func(object client.Object) bool {
 			_, ok := object.(*corev1.Secret)
-			return ok && hasReloadLabelSet(object)
+			return ok && (hasReloadLabelSet(object) || utils.IsDeclarativeReferenceAllowed(object))
 		})
 	}
 
@@ -56,7 +56,9 @@ var (
 			return isUsefulConfigMap(e.Object)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return isUsefulConfigMap(e.ObjectNew)
+			// a config map no longer allowing being referenced must
+			// be removed from the permissions of the instance manager
+			return isUsefulConfigMap(e.ObjectNew) || utils.IsDeclarativeReferenceAllowed(e.ObjectOld)
 		},
 	}
 
@@ -71,7 +73,9 @@ var (
 			return isUsefulClusterSecret(e.Object)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return isUsefulClusterSecret(e.ObjectNew)
+			// a secret no longer allowing being referenced must
+			// be removed from the permissions of the instance manager
+			return isUsefulClusterSecret(e.ObjectNew) || utils.IsDeclarativeReferenceAllowed(e.ObjectOld)
 		},
 	}
 )
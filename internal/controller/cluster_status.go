@@ -360,6 +360,10 @@ func (r *ClusterReconciler) updateResourceStatus(
 		return err
 	}
 
+	if err := r.refreshDeclarativeReferenceVersions(ctx, cluster); err != nil {
+		return err
+	}
+
 	if cluster.Spec.ReplicaCluster != nil && len(cluster.Spec.ReplicaCluster.PromotionToken) == 0 {
 		cluster.Status.LastPromotionToken = ""
 	}
@@ -474,6 +478,19 @@ func (r *ClusterReconciler) getPgbouncerIntegrationStatus(
 			continue
 		}
 
+		// The integrated poolers authenticating their clients through
+		// TLS certificates need a dedicated client certificate, allowed
+		// to connect on behalf of the users their clients are mapped to
+		if pooler.Spec.PgBouncer.CertificateAuthentication != nil {
+			poolersIntegrations.CertificateAuthentication = append(
+				poolersIntegrations.CertificateAuthentication,
+				apiv1.PgBouncerCertificateAuthenticationStatus{
+					SecretName: pooler.GetCertificateAuthenticationSecretName(),
+					CommonName: pooler.GetCertificateAuthenticationCommonName(),
+					Users:      pooler.GetCertificateAuthenticationUsers(),
+				})
+		}
+
 		secretName := pooler.GetAuthQuerySecretName()
 		// there is no need to examine further, the potential secret we may add is already present.
 		// This saves us:
@@ -534,6 +551,13 @@ func (r *ClusterReconciler) refreshCertsExpirations(ctx context.Context, cluster
 		return err
 	}
 
+	for _, role := range cluster.GetRolesWithClientCertificate() {
+		err = r.setCertExpiration(ctx, cluster, role.ClientCertificate.SecretName, namespace, certs.TLSCertKey)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -588,6 +612,27 @@ func (r *ClusterReconciler) refreshConfigMapResourceVersions(ctx context.Context
 	return nil
 }
 
+// refreshDeclarativeReferenceVersions sets the resource version of the
+// secrets and the config maps referenced by the Database and Role objects
+// of the cluster, driving their reconciliation in the instance manager
+func (r *ClusterReconciler) refreshDeclarativeReferenceVersions(ctx context.Context, cluster *apiv1.Cluster) error {
+	references, err := r.getDeclarativeReferences(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions = nil
+	if len(references.SecretVersions) > 0 {
+		cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions = references.SecretVersions
+	}
+	cluster.Status.ConfigMapResourceVersion.DeclarativeConfigMapVersions = nil
+	if len(references.ConfigMapVersions) > 0 {
+		cluster.Status.ConfigMapResourceVersion.DeclarativeConfigMapVersions = references.ConfigMapVersions
+	}
+
+	return nil
+}
+
 // refreshSecretResourceVersions set the resource version of the secrets
 func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, cluster *apiv1.Cluster) error {
 	versions := apiv1.SecretsResourceVersion{}
@@ -617,6 +662,15 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 				}
 				versions.SetManagedRoleSecretVersion(role.PasswordSecret.Name, &version)
 			}
+			if role.PasswordSecret != nil && role.PasswordRotation != nil {
+				// the removal of the previous password, at the end of the
+				// grace period, needs to be applied by the instance manager
+				version, err = r.getSecretResourceVersion(ctx, cluster, role.GetPreviousPasswordSecretName())
+				if err != nil {
+					return err
+				}
+				versions.SetManagedRoleSecretVersion(role.GetPreviousPasswordSecretName(), &version)
+			}
 		}
 	}
 
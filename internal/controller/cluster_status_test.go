@@ -68,6 +68,42 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 
+	It("issues and tracks the client certificates of the managed roles", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		caSecret, _ := generateFakeCASecret(env.client, rand.String(10), namespace, "unittest.com")
+		cluster.Spec.Managed = &v1.ManagedConfiguration{
+			Roles: []v1.RoleConfiguration{
+				{
+					Name:              "dante",
+					ClientCertificate: &v1.RoleClientCertificate{SecretName: "dante-client-cert"},
+				},
+			},
+		}
+
+		By("generating the client certificate", func() {
+			err := env.clusterReconciler.ensureManagedRolesClientCertificates(ctx, cluster, caSecret)
+			Expect(err).ToNot(HaveOccurred())
+
+			var secret corev1.Secret
+			err = env.client.Get(ctx, types.NamespacedName{Name: "dante-client-cert", Namespace: namespace}, &secret)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+
+			pair, err := certs.ParseServerSecret(&secret)
+			Expect(err).ToNot(HaveOccurred())
+			certificate, err := pair.ParseCertificate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certificate.Subject.CommonName).To(Equal("dante"))
+		})
+
+		By("tracking the expiration of the client certificate", func() {
+			Expect(env.clusterReconciler.refreshCertsExpirations(ctx, cluster)).To(Succeed())
+			Expect(cluster.Status.Certificates.Expirations).To(HaveKey("dante-client-cert"))
+		})
+	})
+
 	It("makes sure that getPgbouncerIntegrationStatus returns the correct secret name without duplicates", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace(env.client)
@@ -82,6 +118,35 @@ var _ = Describe("cluster_status unit tests", func() {
 		Expect(intStatus.Secrets).To(HaveLen(1))
 	})
 
+	It("makes sure that getPgbouncerIntegrationStatus detects the poolers using certificate authentication", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := *newFakePooler(env.client, cluster)
+		poolerList := v1.PoolerList{Items: []v1.Pooler{pooler}}
+
+		intStatus, err := env.clusterReconciler.getPgbouncerIntegrationStatus(ctx, cluster, poolerList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(intStatus.CertificateAuthentication).To(BeEmpty())
+
+		poolerList.Items[0].Spec.PgBouncer.CertificateAuthentication = &v1.PgBouncerCertificateAuthentication{
+			UserMappings: []v1.PgBouncerUserMapping{
+				{CommonName: "frontend", User: "dante"},
+				{CommonName: "backend", User: "dante"},
+				{CommonName: "backend", User: "beatrice"},
+			},
+		}
+		intStatus, err = env.clusterReconciler.getPgbouncerIntegrationStatus(ctx, cluster, poolerList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(intStatus.CertificateAuthentication).To(Equal([]v1.PgBouncerCertificateAuthenticationStatus{
+			{
+				SecretName: pooler.Name + "-certificate-authentication",
+				CommonName: "cnpg_pooler_certificate_" + pooler.Name,
+				Users:      []string{"beatrice", "dante"},
+			},
+		}))
+	})
+
 	It("makes sure getObjectResourceVersion returns the correct object version", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace(env.client)
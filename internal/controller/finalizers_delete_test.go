@@ -325,6 +325,68 @@ var _ = Describe("Test cleanup of owned objects on cluster deletion", func() {
 		Expect(subscription.Status.Applied).To(BeNil())
 		Expect(subscription.Status.Message).ToNot(ContainSubstring("not reconciled"))
 	})
+
+	It("should set roles on the cluster as failed and delete their finalizers ", func(ctx SpecContext) {
+		roleList := &apiv1.RoleList{
+			Items: []apiv1.Role{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{
+							utils.RoleFinalizerName,
+						},
+						Name:      "role-1",
+						Namespace: "test",
+					},
+					Spec: apiv1.RoleSpec{
+						RoleConfiguration: apiv1.RoleConfiguration{
+							Name: "role-test",
+						},
+						ClusterRef: corev1.LocalObjectReference{
+							Name: "cluster",
+						},
+					},
+					Status: apiv1.RoleResourceStatus{
+						Applied: ptr.To(true),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{
+							utils.RoleFinalizerName,
+						},
+						Name:      "role-2",
+						Namespace: "test",
+					},
+					Spec: apiv1.RoleSpec{
+						RoleConfiguration: apiv1.RoleConfiguration{
+							Name: "role-test-2",
+						},
+						ClusterRef: corev1.LocalObjectReference{
+							Name: "another-cluster",
+						},
+					},
+				},
+			},
+		}
+
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithLists(roleList).
+			WithStatusSubresource(&roleList.Items[0], &roleList.Items[1]).Build()
+		r.Client = cli
+		err := r.notifyDeletionToOwnedResources(ctx, namespacedName)
+		Expect(err).ToNot(HaveOccurred())
+
+		role := &apiv1.Role{}
+		err = cli.Get(ctx, client.ObjectKeyFromObject(&roleList.Items[0]), role)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(role.Finalizers).To(BeZero())
+		Expect(role.Status.Applied).To(HaveValue(BeFalse()))
+		Expect(role.Status.Message).To(ContainSubstring("cluster resource has been deleted"))
+
+		err = cli.Get(ctx, client.ObjectKeyFromObject(&roleList.Items[1]), role)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(role.Finalizers).To(BeEquivalentTo([]string{utils.RoleFinalizerName}))
+		Expect(role.Status.Applied).To(BeNil())
+	})
 })
 
 type testStruct struct{ Val int }
@@ -0,0 +1,253 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// poolerAutoscalingInterval is how often the load of
+	// an autoscaled Pooler is evaluated
+	poolerAutoscalingInterval = 30 * time.Second
+
+	// maxPoolerScalingDecisions is the number of scaling
+	// decisions kept in the Pooler status
+	maxPoolerScalingDecisions = 10
+)
+
+// reconcileAutoscaling evaluates the autoscaling policy of the Pooler
+// against the load reported by its PgBouncer instances, and changes
+// the number of instances accordingly
+func (r *PoolerReconciler) reconcileAutoscaling(ctx context.Context, pooler *apiv1.Pooler) (ctrl.Result, error) {
+	policy := pooler.Spec.Autoscaling
+	if policy == nil || r.LoadClient == nil {
+		r.forgetScalingRecommendations(client.ObjectKeyFromObject(pooler))
+		return ctrl.Result{}, nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("step", "autoscaling")
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(pooler.Namespace),
+		client.MatchingLabels{utils.PgbouncerNameLabel: pooler.Name},
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while listing the pooler pods: %w", err)
+	}
+
+	loads := make([]metricsserver.Load, 0, len(podList.Items))
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		if !utils.IsPodReady(*pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		load, err := r.LoadClient.GetLoad(ctx, pod)
+		if err != nil {
+			// We don't take decisions on a partial view of the load
+			contextLogger.Warning("Cannot read the load of the pooler instance, skipping autoscaling",
+				"pod", pod.Name, "error", err.Error())
+			return ctrl.Result{RequeueAfter: poolerAutoscalingInterval}, nil
+		}
+		loads = append(loads, load)
+	}
+
+	currentInstances := ptr.Deref(pooler.Spec.Instances, 1)
+	desiredInstances, reason := evaluatePoolerAutoscaling(policy, currentInstances, loads)
+	if stabilizedInstances := r.stabilizeScaleDown(
+		client.ObjectKeyFromObject(pooler),
+		policy.GetScaleDownStabilizationWindow(),
+		currentInstances,
+		desiredInstances,
+	); stabilizedInstances != desiredInstances {
+		contextLogger.Debug("Scale down limited by the stabilization window",
+			"desiredInstances", desiredInstances, "instances", stabilizedInstances)
+		desiredInstances = stabilizedInstances
+	}
+	if desiredInstances == currentInstances {
+		return ctrl.Result{RequeueAfter: poolerAutoscalingInterval}, nil
+	}
+
+	if status := pooler.Status.Autoscaling; status != nil && status.LastScaleTime != nil {
+		if remaining := time.Until(status.LastScaleTime.Add(policy.GetCooldown())); remaining > 0 {
+			contextLogger.Debug("Scaling decision postponed by the cooldown period",
+				"desiredInstances", desiredInstances, "remaining", remaining)
+			return ctrl.Result{RequeueAfter: min(remaining, poolerAutoscalingInterval)}, nil
+		}
+	}
+
+	contextLogger.Info("Scaling the pooler",
+		"previousInstances", currentInstances,
+		"instances", desiredInstances,
+		"reason", reason)
+
+	origPooler := pooler.DeepCopy()
+	pooler.Spec.Instances = ptr.To(desiredInstances)
+	if err := r.Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while scaling the pooler: %w", err)
+	}
+
+	r.Recorder.Eventf(pooler, "Normal", "Autoscaling",
+		"Scaled from %d to %d instances: %s", currentInstances, desiredInstances, reason)
+
+	now := metav1.Now()
+	origPooler = pooler.DeepCopy()
+	if pooler.Status.Autoscaling == nil {
+		pooler.Status.Autoscaling = &apiv1.PoolerAutoscalingStatus{}
+	}
+	pooler.Status.Autoscaling.LastScaleTime = &now
+	pooler.Status.Autoscaling.Decisions = append(pooler.Status.Autoscaling.Decisions, apiv1.PoolerScalingDecision{
+		Time:              now,
+		PreviousInstances: currentInstances,
+		Instances:         desiredInstances,
+		Reason:            reason,
+	})
+	if excess := len(pooler.Status.Autoscaling.Decisions) - maxPoolerScalingDecisions; excess > 0 {
+		pooler.Status.Autoscaling.Decisions = pooler.Status.Autoscaling.Decisions[excess:]
+	}
+	if err := r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while recording the scaling decision: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: poolerAutoscalingInterval}, nil
+}
+
+// poolerScalingRecommendation is the number of instances recommended
+// by the load of a Pooler at a given time
+type poolerScalingRecommendation struct {
+	time      time.Time
+	instances int32
+}
+
+// stabilizeScaleDown records the recommended number of instances, and
+// returns the number of instances to be used: scaling up is immediate,
+// while scaling down uses the highest recommendation made during the
+// stabilization window. When no recommendation is known, i.e. after the
+// operator restarted, the current number of instances is assumed
+func (r *PoolerReconciler) stabilizeScaleDown(
+	key types.NamespacedName,
+	window time.Duration,
+	currentInstances int32,
+	desiredInstances int32,
+) int32 {
+	r.scalingRecommendationsLock.Lock()
+	defer r.scalingRecommendationsLock.Unlock()
+
+	now := time.Now()
+	if r.scalingRecommendations == nil {
+		r.scalingRecommendations = make(map[types.NamespacedName][]poolerScalingRecommendation)
+	}
+
+	recommendations := r.scalingRecommendations[key]
+	if len(recommendations) == 0 {
+		recommendations = append(recommendations, poolerScalingRecommendation{time: now, instances: currentInstances})
+	}
+	recommendations = slices.DeleteFunc(recommendations, func(item poolerScalingRecommendation) bool {
+		return now.Sub(item.time) > window
+	})
+	recommendations = append(recommendations, poolerScalingRecommendation{time: now, instances: desiredInstances})
+	r.scalingRecommendations[key] = recommendations
+
+	if desiredInstances >= currentInstances {
+		return desiredInstances
+	}
+
+	stabilizedInstances := desiredInstances
+	for _, recommendation := range recommendations {
+		stabilizedInstances = max(stabilizedInstances, recommendation.instances)
+	}
+
+	return min(stabilizedInstances, currentInstances)
+}
+
+// forgetScalingRecommendations forgets the scaling recommendations
+// of a Pooler which is not autoscaled anymore
+func (r *PoolerReconciler) forgetScalingRecommendations(key types.NamespacedName) {
+	r.scalingRecommendationsLock.Lock()
+	defer r.scalingRecommendationsLock.Unlock()
+
+	delete(r.scalingRecommendations, key)
+}
+
+// evaluatePoolerAutoscaling computes the number of instances needed to
+// bring the load reported by the PgBouncer instances to the targets of
+// the autoscaling policy, together with the reason of the decision.
+// The number of instances is proportional to the current one, as the
+// load is averaged across the ready instances, which may be fewer
+func evaluatePoolerAutoscaling(
+	policy *apiv1.PoolerAutoscalingConfiguration,
+	currentInstances int32,
+	loads []metricsserver.Load,
+) (int32, string) {
+	desiredInstances := currentInstances
+	reason := "the number of instances is outside the configured bounds"
+
+	if len(loads) > 0 {
+		var clientsWaiting float64
+		var waitTime time.Duration
+		for _, load := range loads {
+			clientsWaiting += load.ClientsWaiting
+			waitTime += load.AverageWaitTime
+		}
+		clientsWaiting /= float64(len(loads))
+		waitTime /= time.Duration(len(loads))
+
+		// The most demanding target drives the scaling
+		ratio := 0.0
+		if policy.TargetClientsWaiting != nil && *policy.TargetClientsWaiting > 0 {
+			ratio = math.Max(ratio, clientsWaiting/float64(*policy.TargetClientsWaiting))
+		}
+		if policy.TargetAverageWaitTime != nil && policy.TargetAverageWaitTime.Duration > 0 {
+			ratio = math.Max(ratio, float64(waitTime)/float64(policy.TargetAverageWaitTime.Duration))
+		}
+
+		if math.Abs(ratio-1) > policy.GetTolerance() {
+			desiredInstances = int32(math.Ceil(float64(currentInstances) * ratio))
+			reason = fmt.Sprintf("average clients waiting: %.1f, average wait time: %s",
+				clientsWaiting, waitTime.Round(time.Millisecond))
+		}
+	}
+
+	if desiredInstances < policy.MinInstances {
+		desiredInstances = policy.MinInstances
+	}
+	if desiredInstances > policy.MaxInstances {
+		desiredInstances = policy.MaxInstances
+	}
+
+	return desiredInstances, reason
+}
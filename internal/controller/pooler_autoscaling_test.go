@@ -0,0 +1,283 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeLoadClient struct {
+	loads map[string]metricsserver.Load
+	err   error
+}
+
+func (f fakeLoadClient) GetLoad(_ context.Context, pod *corev1.Pod) (metricsserver.Load, error) {
+	return f.loads[pod.Name], f.err
+}
+
+var _ = Describe("evaluatePoolerAutoscaling", func() {
+	policy := &apiv1.PoolerAutoscalingConfiguration{
+		MinInstances:          1,
+		MaxInstances:          6,
+		TargetClientsWaiting:  ptr.To(int32(10)),
+		TargetAverageWaitTime: &metav1.Duration{Duration: 100 * time.Millisecond},
+	}
+
+	It("scales out when clients are waiting more than the target", func() {
+		desired, reason := evaluatePoolerAutoscaling(policy, 2, []metricsserver.Load{
+			{ClientsWaiting: 30},
+			{ClientsWaiting: 10},
+		})
+		Expect(desired).To(BeEquivalentTo(4))
+		Expect(reason).To(ContainSubstring("average clients waiting: 20.0"))
+	})
+
+	It("uses the most demanding target", func() {
+		desired, _ := evaluatePoolerAutoscaling(policy, 2, []metricsserver.Load{
+			{ClientsWaiting: 10, AverageWaitTime: 250 * time.Millisecond},
+			{ClientsWaiting: 10, AverageWaitTime: 250 * time.Millisecond},
+		})
+		Expect(desired).To(BeEquivalentTo(5))
+	})
+
+	It("doesn't change the instances when the load is close to the target", func() {
+		desired, _ := evaluatePoolerAutoscaling(policy, 2, []metricsserver.Load{
+			{ClientsWaiting: 10},
+			{ClientsWaiting: 11},
+		})
+		Expect(desired).To(BeEquivalentTo(2))
+	})
+
+	It("scales proportionally to the current instances", func() {
+		// Only two of the three instances are ready
+		desired, _ := evaluatePoolerAutoscaling(policy, 3, []metricsserver.Load{
+			{ClientsWaiting: 20},
+			{ClientsWaiting: 20},
+		})
+		Expect(desired).To(BeEquivalentTo(6))
+	})
+
+	It("uses the configured tolerance", func() {
+		tolerantPolicy := policy.DeepCopy()
+		tolerantPolicy.Tolerance = ptr.To(int32(50))
+		desired, _ := evaluatePoolerAutoscaling(tolerantPolicy, 2, []metricsserver.Load{
+			{ClientsWaiting: 14},
+			{ClientsWaiting: 14},
+		})
+		Expect(desired).To(BeEquivalentTo(2))
+
+		desired, _ = evaluatePoolerAutoscaling(policy, 2, []metricsserver.Load{
+			{ClientsWaiting: 14},
+			{ClientsWaiting: 14},
+		})
+		Expect(desired).To(BeEquivalentTo(3))
+	})
+
+	It("respects the configured bounds", func() {
+		desired, _ := evaluatePoolerAutoscaling(policy, 3, []metricsserver.Load{
+			{ClientsWaiting: 0},
+		})
+		Expect(desired).To(BeEquivalentTo(1))
+
+		desired, _ = evaluatePoolerAutoscaling(policy, 3, []metricsserver.Load{
+			{ClientsWaiting: 100},
+			{ClientsWaiting: 100},
+			{ClientsWaiting: 100},
+		})
+		Expect(desired).To(BeEquivalentTo(6))
+
+		desired, _ = evaluatePoolerAutoscaling(policy, 8, nil)
+		Expect(desired).To(BeEquivalentTo(6))
+	})
+})
+
+var _ = Describe("pooler autoscaling reconciliation", func() {
+	var (
+		pooler *apiv1.Pooler
+		pods   []k8client.Object
+	)
+
+	newReconciler := func(loadClient metricsserver.LoadClient) *PoolerReconciler {
+		knownScheme := schemeBuilder.BuildWithAllKnownScheme()
+		return &PoolerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(knownScheme).
+				WithObjects(append(pods, pooler)...).
+				WithStatusSubresource(pooler).
+				Build(),
+			Scheme:     knownScheme,
+			Recorder:   record.NewFakeRecorder(10),
+			LoadClient: loadClient,
+		}
+	}
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pooler-rw",
+				Namespace: "default",
+			},
+			Spec: apiv1.PoolerSpec{
+				Instances: ptr.To(int32(2)),
+				Autoscaling: &apiv1.PoolerAutoscalingConfiguration{
+					MinInstances:         1,
+					MaxInstances:         5,
+					TargetClientsWaiting: ptr.To(int32(5)),
+				},
+			},
+		}
+
+		pods = nil
+		for _, name := range []string{"pooler-rw-1", "pooler-rw-2"} {
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels:    map[string]string{utils.PgbouncerNameLabel: pooler.Name},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+					},
+				},
+			})
+		}
+	})
+
+	It("scales the pooler and records the decision", func(ctx SpecContext) {
+		r := newReconciler(fakeLoadClient{loads: map[string]metricsserver.Load{
+			"pooler-rw-1": {ClientsWaiting: 10},
+			"pooler-rw-2": {ClientsWaiting: 10},
+		}})
+
+		result, err := r.reconcileAutoscaling(ctx, pooler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(poolerAutoscalingInterval))
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(*updatedPooler.Spec.Instances).To(BeEquivalentTo(4))
+		Expect(updatedPooler.Status.Autoscaling).ToNot(BeNil())
+		Expect(updatedPooler.Status.Autoscaling.LastScaleTime).ToNot(BeNil())
+		Expect(updatedPooler.Status.Autoscaling.Decisions).To(HaveLen(1))
+		Expect(updatedPooler.Status.Autoscaling.Decisions[0].PreviousInstances).To(BeEquivalentTo(2))
+		Expect(updatedPooler.Status.Autoscaling.Decisions[0].Instances).To(BeEquivalentTo(4))
+	})
+
+	It("waits for the cooldown period before scaling again", func(ctx SpecContext) {
+		pooler.Status.Autoscaling = &apiv1.PoolerAutoscalingStatus{
+			LastScaleTime: ptr.To(metav1.Now()),
+		}
+		r := newReconciler(fakeLoadClient{loads: map[string]metricsserver.Load{
+			"pooler-rw-1": {ClientsWaiting: 10},
+			"pooler-rw-2": {ClientsWaiting: 10},
+		}})
+
+		result, err := r.reconcileAutoscaling(ctx, pooler)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(*updatedPooler.Spec.Instances).To(BeEquivalentTo(2))
+	})
+
+	It("scales down only after the stabilization window", func(ctx SpecContext) {
+		pooler.Spec.Instances = ptr.To(int32(1))
+		pooler.Spec.Autoscaling.Cooldown = &metav1.Duration{}
+		r := newReconciler(nil)
+
+		reconcileWithClientsWaiting := func(clientsWaiting float64) int32 {
+			r.LoadClient = fakeLoadClient{loads: map[string]metricsserver.Load{
+				"pooler-rw-1": {ClientsWaiting: clientsWaiting},
+				"pooler-rw-2": {ClientsWaiting: clientsWaiting},
+			}}
+			_, err := r.reconcileAutoscaling(ctx, pooler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), pooler)).To(Succeed())
+			return *pooler.Spec.Instances
+		}
+
+		By("keeping the minimum instances when no client is waiting", func() {
+			Expect(reconcileWithClientsWaiting(0)).To(BeEquivalentTo(1))
+		})
+
+		By("scaling up immediately when clients are waiting", func() {
+			Expect(reconcileWithClientsWaiting(20)).To(BeEquivalentTo(4))
+		})
+
+		By("keeping the instances when no client is waiting anymore", func() {
+			Expect(reconcileWithClientsWaiting(0)).To(BeEquivalentTo(4))
+			Expect(reconcileWithClientsWaiting(0)).To(BeEquivalentTo(4))
+		})
+
+		By("scaling down once the stabilization window is elapsed", func() {
+			key := k8client.ObjectKeyFromObject(pooler)
+			for idx := range r.scalingRecommendations[key] {
+				r.scalingRecommendations[key][idx].time = time.Now().Add(-10 * time.Minute)
+			}
+			Expect(reconcileWithClientsWaiting(0)).To(BeEquivalentTo(1))
+			Expect(pooler.Status.Autoscaling.Decisions).To(HaveLen(2))
+		})
+	})
+
+	It("waits for the stabilization window after a restart before scaling down", func(ctx SpecContext) {
+		r := newReconciler(fakeLoadClient{loads: map[string]metricsserver.Load{
+			"pooler-rw-1": {ClientsWaiting: 0},
+			"pooler-rw-2": {ClientsWaiting: 0},
+		}})
+
+		_, err := r.reconcileAutoscaling(ctx, pooler)
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(*updatedPooler.Spec.Instances).To(BeEquivalentTo(2))
+	})
+
+	It("doesn't scale when the load of an instance can't be read", func(ctx SpecContext) {
+		r := newReconciler(fakeLoadClient{err: errors.New("connection refused")})
+
+		_, err := r.reconcileAutoscaling(ctx, pooler)
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(*updatedPooler.Spec.Instances).To(BeEquivalentTo(2))
+		Expect(updatedPooler.Status.Autoscaling).To(BeNil())
+	})
+})
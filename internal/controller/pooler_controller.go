@@ -23,6 +23,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -40,9 +41,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
 )
 
 // PoolerReconciler reconciles a Pooler object
@@ -51,6 +55,20 @@ type PoolerReconciler struct {
 	DiscoveryClient discovery.DiscoveryInterface
 	Scheme          *runtime.Scheme
 	Recorder        record.EventRecorder
+
+	// LoadClient retrieves the load of the PgBouncer instances,
+	// driving the automatic scaling of the Poolers and
+	// reported in their status
+	LoadClient metricsserver.LoadClient
+
+	// InstanceClient retrieves the status of the PostgreSQL instances,
+	// driving the lag-aware replica routing of the Poolers
+	InstanceClient remote.InstanceClient
+
+	// scalingRecommendations are the recent numbers of instances
+	// recommended by the load of the autoscaled Poolers
+	scalingRecommendationsLock sync.Mutex
+	scalingRecommendations     map[types.NamespacedName][]poolerScalingRecommendation
 }
 
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=poolers,verbs=get;list;watch;create;update;patch;delete
@@ -60,6 +78,7 @@ type PoolerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=secrets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;create;delete;update;patch;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
 // Reconcile implements the main reconciliation loop for pooler objects
 func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -72,6 +91,7 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		// to remove all the Pods of the cluster.
 		if apierrs.IsNotFound(err) {
 			contextLogger.Info("Resource has been deleted")
+			r.forgetScalingRecommendations(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 
@@ -125,15 +145,66 @@ func (r *PoolerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
+	// Adjust the number of instances to the load, if requested
+	autoscalingResult, err := r.reconcileAutoscaling(ctx, &pooler)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while scaling the pooler", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Select the replicas receiving the traffic, if requested
+	routingResult, err := r.reconcileReplicaRouting(ctx, &pooler, resources.Cluster)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while updating the replica routing", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Report the health of the PgBouncer instances
+	healthResult, err := r.reconcileHealth(ctx, &pooler, resources.Cluster)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict while updating the pooler health", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Take the required actions to align the spec with the collected status
-	return ctrl.Result{}, r.updateOwnedObjects(ctx, &pooler, resources)
+	if err := r.updateOwnedObjects(ctx, &pooler, resources); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return earliestRequeue(autoscalingResult, routingResult, healthResult), nil
+}
+
+// earliestRequeue returns the result requiring the earliest requeue
+func earliestRequeue(results ...ctrl.Result) ctrl.Result {
+	var earliest ctrl.Result
+	for _, result := range results {
+		if result.RequeueAfter <= 0 {
+			continue
+		}
+		if earliest.RequeueAfter <= 0 || result.RequeueAfter < earliest.RequeueAfter {
+			earliest = result
+		}
+	}
+
+	return earliest
 }
 
 // SetupWithManager setup this controller inside the controller manager
 func (r *PoolerReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
-		For(&apiv1.Pooler{}).
+		// The status of the Pooler changes at every collection of the
+		// connection counts, which is driven by the requeue interval
+		For(&apiv1.Pooler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Named("pooler").
 		Owns(&v1.Deployment{}).
 		Owns(&corev1.Service{}).
@@ -265,6 +336,34 @@ func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (req
 			)
 			continue
 		}
+
+		// The certificates used by PgBouncer are copied from the
+		// secrets of the cluster, which are recorded in the status
+		if isSecretUsedByPoolerCertificates(pooler, secret.Name) {
+			requests = append(requests,
+				types.NamespacedName{
+					Name:      pooler.Name,
+					Namespace: pooler.Namespace,
+				},
+			)
+		}
 	}
 	return requests
 }
+
+// isSecretUsedByPoolerCertificates checks whether the passed secret contains
+// a certificate or a CA used by PgBouncer, according to the pooler status
+func isSecretUsedByPoolerCertificates(pooler apiv1.Pooler, secretName string) bool {
+	secrets := pooler.Status.Secrets
+	if secrets == nil || secretName == "" {
+		return false
+	}
+
+	if secrets.PgBouncerSecrets != nil && secrets.PgBouncerSecrets.CertificateAuthentication.Name == secretName {
+		return true
+	}
+
+	return secrets.ServerTLS.Name == secretName ||
+		secrets.ServerCA.Name == secretName ||
+		secrets.ClientCA.Name == secretName
+}
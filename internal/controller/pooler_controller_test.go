@@ -115,6 +115,28 @@ var _ = Describe("pooler_controller unit tests", func() {
 		}))
 	})
 
+	It("should make sure to create a request for the poolers using the certificates of a cluster", func() {
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+
+		pooler1 := *newFakePooler(env.client, cluster)
+		pooler1.Status.Secrets = &v1.PoolerSecrets{
+			ClientCA: v1.SecretVersion{Name: cluster.GetClientCASecretName(), Version: "1"},
+		}
+		pooler2 := *newFakePooler(env.client, cluster)
+		poolerList := v1.PoolerList{Items: []v1.Pooler{pooler1, pooler2}}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetClientCASecretName(),
+				Namespace: namespace,
+			},
+		}
+
+		req := getPoolersUsingSecret(poolerList, secret)
+		Expect(req).To(Equal([]types.NamespacedName{{Name: pooler1.Name, Namespace: pooler1.Namespace}}))
+	})
+
 	It("should make sure that mapSecretToPooler produces the correct requests", func() {
 		var expectedRequests []reconcile.Request
 		var nonExpectedRequests []reconcile.Request
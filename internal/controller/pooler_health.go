@@ -0,0 +1,162 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// poolerHealthInterval is how often the health of
+// the PgBouncer instances is collected
+const poolerHealthInterval = 30 * time.Second
+
+// reconcileHealth collects the status of every PgBouncer pod from its
+// metrics endpoint, together with the PostgreSQL instances receiving the
+// traffic of the Pooler, and stores them in the Pooler status
+func (r *PoolerReconciler) reconcileHealth(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithValues("step", "health")
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(pooler.Namespace),
+		client.MatchingLabels{utils.PgbouncerNameLabel: pooler.Name},
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while listing the pooler pods: %w", err)
+	}
+
+	instancesStatus := make([]apiv1.PoolerInstanceStatus, 0, len(podList.Items))
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		instanceStatus := apiv1.PoolerInstanceStatus{
+			PodName: pod.Name,
+			Ready:   utils.IsPodReady(*pod) && pod.DeletionTimestamp == nil,
+		}
+
+		if instanceStatus.Ready && r.LoadClient != nil {
+			load, err := r.LoadClient.GetLoad(ctx, pod)
+			if err != nil {
+				contextLogger.Debug("Cannot read the status of the pooler instance",
+					"pod", pod.Name, "error", err.Error())
+				instanceStatus.Error = err.Error()
+			} else {
+				setPoolerInstanceLoad(&instanceStatus, load)
+			}
+		}
+
+		instancesStatus = append(instancesStatus, instanceStatus)
+	}
+	sort.Slice(instancesStatus, func(i, j int) bool {
+		return instancesStatus[i].PodName < instancesStatus[j].PodName
+	})
+
+	updatedStatus := pooler.Status.DeepCopy()
+	updatedStatus.InstancesStatus = instancesStatus
+	updatedStatus.ReadyInstances = 0
+	updatedStatus.Connections = nil
+	for _, instanceStatus := range instancesStatus {
+		if instanceStatus.Ready {
+			updatedStatus.ReadyInstances++
+		}
+		if instanceStatus.Connections != nil {
+			if updatedStatus.Connections == nil {
+				updatedStatus.Connections = &apiv1.PoolerConnectionsStatus{}
+			}
+			updatedStatus.Connections.ClientsActive += instanceStatus.Connections.ClientsActive
+			updatedStatus.Connections.ClientsWaiting += instanceStatus.Connections.ClientsWaiting
+			updatedStatus.Connections.ServersActive += instanceStatus.Connections.ServersActive
+			updatedStatus.Connections.ServersIdle += instanceStatus.Connections.ServersIdle
+		}
+	}
+	updatedStatus.TargetPrimary, updatedStatus.TargetInstances = getPoolerTargets(pooler, cluster)
+
+	if equality.Semantic.DeepEqual(pooler.Status, *updatedStatus) {
+		return ctrl.Result{RequeueAfter: poolerHealthInterval}, nil
+	}
+
+	origPooler := pooler.DeepCopy()
+	pooler.Status = *updatedStatus
+	if err := r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while updating the pooler health: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: poolerHealthInterval}, nil
+}
+
+// setPoolerInstanceLoad fills the status of a PgBouncer pod
+// with the data reported by its metrics endpoint
+func setPoolerInstanceLoad(instanceStatus *apiv1.PoolerInstanceStatus, load metricsserver.Load) {
+	instanceStatus.Paused = load.Paused
+	instanceStatus.Connections = &apiv1.PoolerConnectionsStatus{
+		ClientsActive:  int32(load.ClientsActive),  //nolint:gosec
+		ClientsWaiting: int32(load.ClientsWaiting), //nolint:gosec
+		ServersActive:  int32(load.ServersActive),  //nolint:gosec
+		ServersIdle:    int32(load.ServersIdle),    //nolint:gosec
+	}
+	if !load.LastReloadTime.IsZero() {
+		lastReloadTime := metav1.NewTime(load.LastReloadTime)
+		instanceStatus.LastReloadTime = &lastReloadTime
+	}
+}
+
+// getPoolerTargets returns the PostgreSQL instances receiving the traffic
+// of the Pooler: the primary for a Pooler of type rw, and the instances
+// matching the Pooler type otherwise
+func getPoolerTargets(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (string, []string) {
+	if pooler.Spec.Type == apiv1.PoolerTypeRW || pooler.Spec.Type == "" {
+		return cluster.Status.CurrentPrimary, nil
+	}
+
+	if routing := pooler.Status.ReplicaRouting; pooler.IsReplicaRoutingEnabled() && routing != nil {
+		if routing.FallbackToPrimary {
+			return "", []string{cluster.Status.CurrentPrimary}
+		}
+		return "", slices.Clone(routing.Instances)
+	}
+
+	var instances []string
+	for _, instance := range cluster.Status.InstancesStatus[apiv1.PodHealthy] {
+		if pooler.Spec.Type == apiv1.PoolerTypeRO && instance == cluster.Status.CurrentPrimary {
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	slices.Sort(instances)
+
+	return "", instances
+}
@@ -0,0 +1,195 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("getPoolerTargets", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			InstancesStatus: map[apiv1.PodStatus][]string{
+				apiv1.PodHealthy: {"cluster-example-3", "cluster-example-1", "cluster-example-2"},
+				apiv1.PodFailed:  {"cluster-example-4"},
+			},
+		},
+	}
+
+	It("targets the primary with a rw pooler", func() {
+		pooler := &apiv1.Pooler{Spec: apiv1.PoolerSpec{Type: apiv1.PoolerTypeRW}}
+		primary, instances := getPoolerTargets(pooler, cluster)
+		Expect(primary).To(Equal("cluster-example-1"))
+		Expect(instances).To(BeEmpty())
+	})
+
+	It("targets the healthy replicas with a ro pooler", func() {
+		pooler := &apiv1.Pooler{Spec: apiv1.PoolerSpec{Type: apiv1.PoolerTypeRO}}
+		primary, instances := getPoolerTargets(pooler, cluster)
+		Expect(primary).To(BeEmpty())
+		Expect(instances).To(Equal([]string{"cluster-example-2", "cluster-example-3"}))
+	})
+
+	It("targets every healthy instance with a r pooler", func() {
+		pooler := &apiv1.Pooler{Spec: apiv1.PoolerSpec{Type: apiv1.PoolerTypeR}}
+		_, instances := getPoolerTargets(pooler, cluster)
+		Expect(instances).To(Equal([]string{"cluster-example-1", "cluster-example-2", "cluster-example-3"}))
+	})
+
+	It("targets the replicas selected by the lag-aware replica routing", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Type: apiv1.PoolerTypeRO,
+				ReplicaRouting: &apiv1.PoolerReplicaRoutingConfiguration{
+					MaximumLag: resource.MustParse("16Mi"),
+				},
+			},
+			Status: apiv1.PoolerStatus{
+				ReplicaRouting: &apiv1.PoolerReplicaRoutingStatus{
+					Instances: []string{"cluster-example-3"},
+				},
+			},
+		}
+		_, instances := getPoolerTargets(pooler, cluster)
+		Expect(instances).To(Equal([]string{"cluster-example-3"}))
+
+		pooler.Status.ReplicaRouting = &apiv1.PoolerReplicaRoutingStatus{FallbackToPrimary: true}
+		_, instances = getPoolerTargets(pooler, cluster)
+		Expect(instances).To(Equal([]string{"cluster-example-1"}))
+	})
+})
+
+var _ = Describe("pooler health reconciliation", func() {
+	var (
+		pooler  *apiv1.Pooler
+		cluster *apiv1.Cluster
+		pods    []k8client.Object
+	)
+
+	newReconciler := func(loadClient metricsserver.LoadClient) *PoolerReconciler {
+		knownScheme := schemeBuilder.BuildWithAllKnownScheme()
+		return &PoolerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(knownScheme).
+				WithObjects(append(pods, pooler)...).
+				WithStatusSubresource(pooler).
+				Build(),
+			Scheme:     knownScheme,
+			Recorder:   record.NewFakeRecorder(10),
+			LoadClient: loadClient,
+		}
+	}
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pooler-rw",
+				Namespace: "default",
+			},
+			Spec: apiv1.PoolerSpec{
+				Type: apiv1.PoolerTypeRW,
+			},
+		}
+		cluster = &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+
+		pods = nil
+		for _, name := range []string{"pooler-rw-2", "pooler-rw-1", "pooler-rw-3"} {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels:    map[string]string{utils.PgbouncerNameLabel: pooler.Name},
+				},
+			}
+			if name != "pooler-rw-3" {
+				pod.Status.Conditions = []corev1.PodCondition{
+					{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+				}
+			}
+			pods = append(pods, pod)
+		}
+	})
+
+	It("reports the status of every PgBouncer pod, in aggregate too", func(ctx SpecContext) {
+		reloadTime := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+		r := newReconciler(fakeLoadClient{loads: map[string]metricsserver.Load{
+			"pooler-rw-1": {ClientsActive: 10, ClientsWaiting: 2, ServersActive: 5, ServersIdle: 1},
+			"pooler-rw-2": {ClientsActive: 5, ServersActive: 3, Paused: true, LastReloadTime: reloadTime},
+		}})
+
+		result, err := r.reconcileHealth(ctx, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(poolerHealthInterval))
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		status := updatedPooler.Status
+		Expect(status.ReadyInstances).To(BeEquivalentTo(2))
+		Expect(status.TargetPrimary).To(Equal("cluster-example-1"))
+		Expect(status.Connections).To(Equal(&apiv1.PoolerConnectionsStatus{
+			ClientsActive:  15,
+			ClientsWaiting: 2,
+			ServersActive:  8,
+			ServersIdle:    1,
+		}))
+
+		Expect(status.InstancesStatus).To(HaveLen(3))
+		Expect(status.InstancesStatus[0].PodName).To(Equal("pooler-rw-1"))
+		Expect(status.InstancesStatus[0].Paused).To(BeFalse())
+		Expect(status.InstancesStatus[0].LastReloadTime).To(BeNil())
+		Expect(status.InstancesStatus[1].PodName).To(Equal("pooler-rw-2"))
+		Expect(status.InstancesStatus[1].Paused).To(BeTrue())
+		Expect(status.InstancesStatus[1].LastReloadTime.Time.Equal(reloadTime)).To(BeTrue())
+		Expect(status.InstancesStatus[2].PodName).To(Equal("pooler-rw-3"))
+		Expect(status.InstancesStatus[2].Ready).To(BeFalse())
+		Expect(status.InstancesStatus[2].Connections).To(BeNil())
+	})
+
+	It("records the errors encountered reading the metrics", func(ctx SpecContext) {
+		r := newReconciler(fakeLoadClient{err: errors.New("connection refused")})
+
+		_, err := r.reconcileHealth(ctx, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(pooler.Status.ReadyInstances).To(BeEquivalentTo(2))
+		Expect(pooler.Status.Connections).To(BeNil())
+		Expect(pooler.Status.InstancesStatus[0].Error).To(Equal("connection refused"))
+		Expect(pooler.Status.InstancesStatus[2].Error).To(BeEmpty())
+	})
+})
@@ -0,0 +1,165 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// poolerReplicaRoutingInterval is how often the lag of the replicas
+// of a Pooler using lag-aware replica routing is evaluated
+const poolerReplicaRoutingInterval = 10 * time.Second
+
+// reconcileReplicaRouting selects the replicas receiving the traffic of
+// a read-only Pooler depending on their lag, storing them in the Pooler
+// status, from where the PgBouncer instances will pick them up
+func (r *PoolerReconciler) reconcileReplicaRouting(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	if !pooler.IsReplicaRoutingEnabled() || r.InstanceClient == nil {
+		if pooler.Status.ReplicaRouting == nil {
+			return ctrl.Result{}, nil
+		}
+
+		origPooler := pooler.DeepCopy()
+		pooler.Status.ReplicaRouting = nil
+		return ctrl.Result{}, r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler))
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("step", "replica_routing")
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName: cluster.Name,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		},
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while listing the cluster pods: %w", err)
+	}
+
+	readyPods := corev1.PodList{Items: make([]corev1.Pod, 0, len(podList.Items))}
+	for idx := range podList.Items {
+		pod := podList.Items[idx]
+		if utils.IsPodReady(pod) && pod.DeletionTimestamp == nil && pod.Status.PodIP != "" {
+			readyPods.Items = append(readyPods.Items, pod)
+		}
+	}
+
+	statusList := r.InstanceClient.GetStatusFromInstances(ctx, readyPods)
+	routing, err := selectRoutingReplicas(statusList, pooler.Spec.ReplicaRouting.MaximumLag.Value())
+	if err != nil {
+		// Without the position of the primary we can't measure the lag
+		// of the replicas: let's keep the current selection
+		contextLogger.Info("Cannot evaluate the replica lag, keeping the current routing", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: poolerReplicaRoutingInterval}, nil
+	}
+
+	if equality.Semantic.DeepEqual(routing, pooler.Status.ReplicaRouting) {
+		return ctrl.Result{RequeueAfter: poolerReplicaRoutingInterval}, nil
+	}
+
+	contextLogger.Info("Updating the replicas receiving the pooler traffic",
+		"instances", routing.Instances,
+		"fallbackToPrimary", routing.FallbackToPrimary)
+	if routing.FallbackToPrimary {
+		r.Recorder.Event(pooler, "Warning", "ReplicaRouting",
+			"No replica is within the maximum lag, routing the traffic to the primary")
+	} else {
+		r.Recorder.Eventf(pooler, "Normal", "ReplicaRouting",
+			"Routing the traffic to the replicas %v", routing.Instances)
+	}
+
+	origPooler := pooler.DeepCopy()
+	pooler.Status.ReplicaRouting = routing
+	if err := r.Status().Patch(ctx, pooler, client.MergeFrom(origPooler)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: poolerReplicaRoutingInterval}, nil
+}
+
+// selectRoutingReplicas returns the replicas whose replay position is no
+// more than maximumLag bytes behind the current position of the primary.
+// When no replica qualifies, the traffic falls back to the primary
+func selectRoutingReplicas(
+	statusList postgres.PostgresqlStatusList,
+	maximumLag int64,
+) (*apiv1.PoolerReplicaRoutingStatus, error) {
+	primaryIdx := slices.IndexFunc(statusList.Items, func(status postgres.PostgresqlStatus) bool {
+		return status.IsPrimary && status.Error == nil
+	})
+	if primaryIdx < 0 {
+		return nil, fmt.Errorf("the primary is not reporting its status")
+	}
+
+	primaryLSN, err := statusList.Items[primaryIdx].CurrentLsn.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the current LSN of the primary: %w", err)
+	}
+
+	var replicas []*corev1.Pod
+	for _, status := range statusList.Items {
+		if status.IsPrimary || status.Error != nil || status.Pod == nil || status.Pod.Status.PodIP == "" {
+			continue
+		}
+
+		replayLSN, err := status.ReplayLsn.Parse()
+		if err != nil {
+			continue
+		}
+
+		if replayLSN < primaryLSN && int64(primaryLSN-replayLSN) > maximumLag {
+			continue
+		}
+
+		replicas = append(replicas, status.Pod)
+	}
+
+	// Keep the selection stable, regardless of the order of the statuses
+	slices.SortFunc(replicas, func(a, b *corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	routing := &apiv1.PoolerReplicaRoutingStatus{FallbackToPrimary: len(replicas) == 0}
+	for _, pod := range replicas {
+		routing.Instances = append(routing.Instances, pod.Name)
+		routing.Hosts = append(routing.Hosts, pod.Status.PodIP)
+	}
+
+	return routing, nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	k8client "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/client/remote"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeReplicaRoutingInstanceClient struct {
+	remote.InstanceClient
+	statuses map[string]postgres.PostgresqlStatus
+}
+
+func (f fakeReplicaRoutingInstanceClient) GetStatusFromInstances(
+	_ context.Context,
+	pods corev1.PodList,
+) postgres.PostgresqlStatusList {
+	var result postgres.PostgresqlStatusList
+	for idx := range pods.Items {
+		status := f.statuses[pods.Items[idx].Name]
+		status.Pod = &pods.Items[idx]
+		result.Items = append(result.Items, status)
+	}
+	return result
+}
+
+var _ = Describe("selectRoutingReplicas", func() {
+	newStatus := func(name, ip string, isPrimary bool, lsn types.LSN) postgres.PostgresqlStatus {
+		status := postgres.PostgresqlStatus{
+			IsPrimary: isPrimary,
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status:     corev1.PodStatus{PodIP: ip},
+			},
+		}
+		if isPrimary {
+			status.CurrentLsn = lsn
+		} else {
+			status.ReplayLsn = lsn
+		}
+		return status
+	}
+
+	It("selects the replicas within the maximum lag", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", "10.0.0.1", true, "0/3000000"),
+			newStatus("cluster-example-3", "10.0.0.3", false, "0/2F00000"),
+			newStatus("cluster-example-2", "10.0.0.2", false, "0/1000000"),
+			newStatus("cluster-example-4", "10.0.0.4", false, "0/3000000"),
+		}}
+
+		routing, err := selectRoutingReplicas(statusList, 16*1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routing.FallbackToPrimary).To(BeFalse())
+		Expect(routing.Instances).To(Equal([]string{"cluster-example-3", "cluster-example-4"}))
+		Expect(routing.Hosts).To(Equal([]string{"10.0.0.3", "10.0.0.4"}))
+	})
+
+	It("falls back to the primary when no replica qualifies", func() {
+		unreachable := newStatus("cluster-example-3", "10.0.0.3", false, "0/3000000")
+		unreachable.Error = errors.New("connection refused")
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-1", "10.0.0.1", true, "1/0"),
+			newStatus("cluster-example-2", "10.0.0.2", false, "0/1000000"),
+			unreachable,
+		}}
+
+		routing, err := selectRoutingReplicas(statusList, 16*1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routing.FallbackToPrimary).To(BeTrue())
+		Expect(routing.Instances).To(BeEmpty())
+	})
+
+	It("fails when the primary is not reporting its status", func() {
+		statusList := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			newStatus("cluster-example-2", "10.0.0.2", false, "0/1000000"),
+		}}
+
+		_, err := selectRoutingReplicas(statusList, 16*1024*1024)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("pooler replica routing reconciliation", func() {
+	var (
+		cluster *apiv1.Cluster
+		pooler  *apiv1.Pooler
+		pods    []k8client.Object
+	)
+
+	newReconciler := func(statuses map[string]postgres.PostgresqlStatus) *PoolerReconciler {
+		knownScheme := schemeBuilder.BuildWithAllKnownScheme()
+		return &PoolerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(knownScheme).
+				WithObjects(append(pods, pooler)...).
+				WithStatusSubresource(pooler).
+				Build(),
+			Scheme:         knownScheme,
+			Recorder:       record.NewFakeRecorder(10),
+			InstanceClient: fakeReplicaRoutingInstanceClient{statuses: statuses},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pooler-ro",
+				Namespace: "default",
+			},
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+				Type:    apiv1.PoolerTypeRO,
+				ReplicaRouting: &apiv1.PoolerReplicaRoutingConfiguration{
+					MaximumLag: resource.MustParse("1Mi"),
+				},
+			},
+		}
+
+		pods = nil
+		for idx, name := range []string{"cluster-example-1", "cluster-example-2"} {
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels: map[string]string{
+						utils.ClusterLabelName: cluster.Name,
+						utils.PodRoleLabelName: string(utils.PodRoleInstance),
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: []string{"10.0.0.1", "10.0.0.2"}[idx],
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+					},
+				},
+			})
+		}
+	})
+
+	It("records the replicas receiving the traffic", func(ctx SpecContext) {
+		r := newReconciler(map[string]postgres.PostgresqlStatus{
+			"cluster-example-1": {IsPrimary: true, CurrentLsn: "0/3000000"},
+			"cluster-example-2": {ReplayLsn: "0/3000000"},
+		})
+
+		result, err := r.reconcileReplicaRouting(ctx, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(poolerReplicaRoutingInterval))
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(updatedPooler.Status.ReplicaRouting).ToNot(BeNil())
+		Expect(updatedPooler.Status.ReplicaRouting.Instances).To(Equal([]string{"cluster-example-2"}))
+		Expect(updatedPooler.GetServerHost()).To(Equal("10.0.0.2"))
+	})
+
+	It("routes the traffic to the primary when the replicas are lagging", func(ctx SpecContext) {
+		r := newReconciler(map[string]postgres.PostgresqlStatus{
+			"cluster-example-1": {IsPrimary: true, CurrentLsn: "0/3000000"},
+			"cluster-example-2": {ReplayLsn: "0/1000000"},
+		})
+
+		_, err := r.reconcileReplicaRouting(ctx, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(updatedPooler.Status.ReplicaRouting.FallbackToPrimary).To(BeTrue())
+		Expect(updatedPooler.GetServerHost()).To(Equal("cluster-example-rw"))
+	})
+
+	It("clears the routing status when the routing is disabled", func(ctx SpecContext) {
+		pooler.Spec.ReplicaRouting = nil
+		pooler.Status.ReplicaRouting = &apiv1.PoolerReplicaRoutingStatus{FallbackToPrimary: true}
+		r := newReconciler(nil)
+
+		_, err := r.reconcileReplicaRouting(ctx, pooler, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedPooler apiv1.Pooler
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(pooler), &updatedPooler)).To(Succeed())
+		Expect(updatedPooler.Status.ReplicaRouting).To(BeNil())
+	})
+})
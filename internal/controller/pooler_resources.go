@@ -38,6 +38,11 @@ type poolerManagedResources struct {
 	// the auth_query connection
 	AuthUserSecret *corev1.Secret
 
+	// This is the secret containing the client certificate used to
+	// connect on behalf of the clients authenticated through TLS
+	// certificates
+	CertificateAuthenticationSecret *corev1.Secret
+
 	// This is the pgbouncer deployment
 	Deployment *appsv1.Deployment
 
@@ -68,6 +73,17 @@ func (r *PoolerReconciler) getManagedResources(ctx context.Context,
 		return nil, err
 	}
 
+	// Get the client certificate used to connect on behalf
+	// of the clients authenticated through TLS certificates
+	if pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.CertificateAuthentication != nil {
+		result.CertificateAuthenticationSecret, err = getSecretOrNil(
+			ctx, r.Client,
+			client.ObjectKey{Name: pooler.GetCertificateAuthenticationSecretName(), Namespace: pooler.Namespace})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get the pooler deployment
 	result.Deployment, err = getDeploymentOrNil(
 		ctx, r.Client, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace})
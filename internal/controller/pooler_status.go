@@ -49,6 +49,15 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		}
 	}
 
+	if resources.CertificateAuthenticationSecret != nil {
+		updatedStatus.Secrets.PgBouncerSecrets.CertificateAuthentication = apiv1.SecretVersion{
+			Name:    resources.CertificateAuthenticationSecret.Name,
+			Version: resources.CertificateAuthenticationSecret.ResourceVersion,
+		}
+	} else {
+		updatedStatus.Secrets.PgBouncerSecrets.CertificateAuthentication = apiv1.SecretVersion{}
+	}
+
 	if cluster := resources.Cluster; cluster != nil {
 		updatedStatus.Secrets.ServerTLS = apiv1.SecretVersion{
 			Name:    cluster.GetServerTLSSecretName(),
@@ -81,6 +81,28 @@ var _ = Describe("pooler_status unit tests", func() {
 		assertAuthUserStatus(pooler, authUserSecret)
 	})
 
+	It("should correctly set the status for the certificate authentication secret", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace(env.client)
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		pooler := newFakePooler(env.client, cluster)
+		certificateAuthenticationSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            pooler.GetCertificateAuthenticationSecretName(),
+				Namespace:       pooler.Namespace,
+				ResourceVersion: "2",
+			},
+		}
+		res := &poolerManagedResources{CertificateAuthenticationSecret: certificateAuthenticationSecret, Cluster: cluster}
+
+		err := env.poolerReconciler.updatePoolerStatus(ctx, pooler, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pooler.Status.Secrets.PgBouncerSecrets.CertificateAuthentication).To(Equal(v1.SecretVersion{
+			Name:    certificateAuthenticationSecret.Name,
+			Version: certificateAuthenticationSecret.ResourceVersion,
+		}))
+	})
+
 	It("should correctly set the deployment status", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace(env.client)
@@ -36,12 +36,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // errClusterIsReplica is raised when an object
 // cannot be reconciled because it belongs to a replica cluster
 var errClusterIsReplica = fmt.Errorf("waiting for the cluster to become primary")
 
+// errDeclarativeReferenceNotAllowed is raised when a secret or a config map
+// referenced by an object doesn't allow being referenced
+var errDeclarativeReferenceNotAllowed = fmt.Errorf(
+	"the referenced object must have the %q label set to \"true\"", utils.DeclarativeReferenceLabelName)
+
 type instanceInterface interface {
 	GetSuperUserDB() (*sql.DB, error)
 	GetClusterName() string
@@ -109,6 +115,26 @@ func getClusterFromInstance(
 	return &cluster, err
 }
 
+// getDeclarativeReference gets a secret or a config map referenced by a
+// declarative object, refusing the ones which don't allow it, as the
+// authors of the object may not be allowed to read them
+func getDeclarativeReference(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	name string,
+	object client.Object,
+) error {
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, object); err != nil {
+		return err
+	}
+	if !utils.IsDeclarativeReferenceAllowed(object) {
+		return errDeclarativeReferenceNotAllowed
+	}
+
+	return nil
+}
+
 func toPostgresParameters(parameters map[string]string) string {
 	if len(parameters) == 0 {
 		return ""
@@ -23,15 +23,23 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	managementutils "github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -67,6 +75,55 @@ var extensionObjectManager = databaseObjectManager[apiv1.ExtensionSpec, extInfo]
 	drop:   dropDatabaseExtension,
 }
 
+// fdwObjectManager is the manager of the foreign data wrappers
+var fdwObjectManager = databaseObjectManager[apiv1.FDWSpec, fdwInfo]{
+	get:    getDatabaseFDWInfo,
+	create: createDatabaseFDW,
+	update: updateDatabaseFDW,
+	drop:   dropDatabaseFDW,
+}
+
+// serverObjectManager is the manager of the foreign servers
+var serverObjectManager = databaseObjectManager[apiv1.ServerSpec, serverInfo]{
+	get:    getDatabaseServerInfo,
+	create: createDatabaseServer,
+	update: updateDatabaseServer,
+	drop:   dropDatabaseServer,
+}
+
+// userMappingObjectManager is the manager of the user mappings
+var userMappingObjectManager = databaseObjectManager[apiv1.UserMappingSpec, userMappingInfo]{
+	get:    getDatabaseUserMappingInfo,
+	create: createDatabaseUserMapping,
+	update: updateDatabaseUserMapping,
+	drop:   dropDatabaseUserMapping,
+}
+
+// defaultPrivilegeObjectManager is the manager of the default privileges
+var defaultPrivilegeObjectManager = databaseObjectManager[apiv1.DefaultPrivilegeSpec, privilegeInfo]{
+	get:    getDatabaseDefaultPrivilegeInfo,
+	create: grantDatabaseDefaultPrivilege,
+	update: updateDatabaseDefaultPrivilege,
+	drop:   revokeDatabaseDefaultPrivilege,
+}
+
+// newPrivilegeObjectManager creates the manager of the privileges
+// of the passed database
+func newPrivilegeObjectManager(databaseName string) databaseObjectManager[apiv1.PrivilegeSpec, privilegeInfo] {
+	return databaseObjectManager[apiv1.PrivilegeSpec, privilegeInfo]{
+		get: getDatabasePrivilegeInfo,
+		create: func(ctx context.Context, db *sql.DB, spec apiv1.PrivilegeSpec) error {
+			return grantDatabasePrivilege(ctx, db, databaseName, spec)
+		},
+		update: func(ctx context.Context, db *sql.DB, spec apiv1.PrivilegeSpec, info *privilegeInfo) error {
+			return updateDatabasePrivilege(ctx, db, databaseName, spec, info)
+		},
+		drop: func(ctx context.Context, db *sql.DB, spec apiv1.PrivilegeSpec) error {
+			return revokeDatabasePrivilege(ctx, db, databaseName, spec)
+		},
+	}
+}
+
 // databaseReconciliationInterval is the time between the
 // database reconciliation loop failures
 const databaseReconciliationInterval = 30 * time.Second
@@ -99,17 +156,23 @@ func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
-	// If everything is reconciled, we're done here
-	if database.Generation == database.Status.ObservedGeneration {
-		return ctrl.Result{}, nil
-	}
-
 	// Fetch the Cluster from the cache
 	cluster, err := r.GetCluster(ctx)
 	if err != nil {
 		return ctrl.Result{}, markAsFailed(ctx, r.Client, &database, fmt.Errorf("while fetching the cluster: %w", err))
 	}
 
+	// If everything is reconciled, we're done here, unless the
+	// credentials used by the user mappings or the migrations
+	// have been changed. The operator records their resource
+	// versions in the status of the cluster, which is watched
+	if database.Generation == database.Status.ObservedGeneration &&
+		maps.Equal(getSecretsResourceVersion(cluster, &database), database.Status.SecretsResourceVersion) &&
+		maps.Equal(getMigrationSourcesResourceVersion(cluster, &database),
+			database.Status.Migrations.GetSourcesResourceVersion()) {
+		return ctrl.Result{}, nil
+	}
+
 	contextLogger.Info("Reconciling database")
 	defer func() {
 		contextLogger.Info("Reconciliation loop of database exited")
@@ -162,7 +225,7 @@ func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if err := markAsReady(ctx, r.Client, &database); err != nil {
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	return ctrl.Result{}, nil
 }
 
 func (r *DatabaseReconciler) evaluateDropDatabase(ctx context.Context, db *apiv1.Database) error {
@@ -207,9 +270,65 @@ func (r *DatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Database{}).
 		Named("instance-database").
+		Watches(
+			&apiv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterToDatabases),
+			builder.WithPredicates(declarativeReferencesPredicate),
+		).
 		Complete(r)
 }
 
+// declarativeReferencesPredicate filters the changes of the cluster
+// involving the resource versions of the secrets and the config maps
+// referenced by the Database objects
+var declarativeReferencesPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCluster, oldOk := e.ObjectOld.(*apiv1.Cluster)
+		newCluster, newOk := e.ObjectNew.(*apiv1.Cluster)
+		if !oldOk || !newOk {
+			return false
+		}
+
+		return !maps.Equal(
+			oldCluster.Status.SecretsResourceVersion.DeclarativeSecretVersions,
+			newCluster.Status.SecretsResourceVersion.DeclarativeSecretVersions,
+		) || !maps.Equal(
+			oldCluster.Status.ConfigMapResourceVersion.DeclarativeConfigMapVersions,
+			newCluster.Status.ConfigMapResourceVersion.DeclarativeConfigMapVersions,
+		)
+	},
+}
+
+// mapClusterToDatabases returns the reconcile requests of the
+// Database objects of the passed cluster
+func (r *DatabaseReconciler) mapClusterToDatabases(ctx context.Context, obj client.Object) []reconcile.Request {
+	var databases apiv1.DatabaseList
+	if err := r.List(ctx, &databases, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "while listing the databases of the cluster")
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, database := range databases.Items {
+		if database.Spec.ClusterRef.Name == obj.GetName() {
+			result = append(result, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&database),
+			})
+		}
+	}
+
+	return result
+}
+
 // GetCluster gets the managed cluster through the client
 func (r *DatabaseReconciler) GetCluster(ctx context.Context) (*apiv1.Cluster, error) {
 	return getClusterFromInstance(ctx, r.Client, r.instance)
@@ -233,28 +352,46 @@ func (r *DatabaseReconciler) reconcileDatabaseResource(ctx context.Context, obj
 		return err
 	}
 
-	for _, status := range obj.Status.Schemas {
-		if !status.Applied {
-			return ErrFailedDatabaseObjectReconciliation
-		}
-	}
-	for _, status := range obj.Status.Extensions {
-		if !status.Applied {
-			return ErrFailedDatabaseObjectReconciliation
+	for _, statusList := range [][]apiv1.DatabaseObjectStatus{
+		obj.Status.Schemas,
+		obj.Status.Extensions,
+		obj.Status.FDWs,
+		obj.Status.Servers,
+		obj.Status.UserMappings,
+		obj.Status.Privileges,
+		obj.Status.DefaultPrivileges,
+	} {
+		for _, status := range statusList {
+			if !status.Applied {
+				return ErrFailedDatabaseObjectReconciliation
+			}
 		}
 	}
 
-	return nil
+	// Migrations are applied last, as they may depend on the
+	// objects reconciled above
+	return r.reconcileMigrations(ctx, obj)
 }
 
 func (r *DatabaseReconciler) reconcileDatabaseObjects(
 	ctx context.Context,
 	obj *apiv1.Database,
 ) error {
-	if len(obj.Spec.Schemas) == 0 && len(obj.Spec.Extensions) == 0 {
+	if len(obj.Spec.Schemas) == 0 &&
+		len(obj.Spec.Extensions) == 0 &&
+		len(obj.Spec.FDWs) == 0 &&
+		len(obj.Spec.Servers) == 0 &&
+		len(obj.Spec.UserMappings) == 0 &&
+		len(obj.Spec.Privileges) == 0 &&
+		len(obj.Spec.DefaultPrivileges) == 0 {
 		return nil
 	}
 
+	userMappings, secretsResourceVersion, err := r.resolveUserMappingCredentials(ctx, obj)
+	if err != nil {
+		return err
+	}
+
 	db, err := r.getTargetDB(obj.Spec.Name)
 	if err != nil {
 		return fmt.Errorf("while connecting to the database %q: %v", obj.Spec.Name, err)
@@ -262,6 +399,16 @@ func (r *DatabaseReconciler) reconcileDatabaseObjects(
 
 	obj.Status.Schemas = schemaObjectManager.reconcileList(ctx, db, obj.Spec.Schemas)
 	obj.Status.Extensions = extensionObjectManager.reconcileList(ctx, db, obj.Spec.Extensions)
+	obj.Status.FDWs = fdwObjectManager.reconcileList(ctx, db, obj.Spec.FDWs)
+	obj.Status.Servers = serverObjectManager.reconcileList(ctx, db, obj.Spec.Servers)
+	obj.Status.UserMappings = userMappingObjectManager.reconcileList(ctx, db, userMappings)
+	obj.Status.SecretsResourceVersion = secretsResourceVersion
+
+	// Privileges are reconciled last, as they may refer to the objects
+	// created above
+	privilegeObjectManager := newPrivilegeObjectManager(obj.Spec.Name)
+	obj.Status.Privileges = privilegeObjectManager.reconcileList(ctx, db, obj.Spec.Privileges)
+	obj.Status.DefaultPrivileges = defaultPrivilegeObjectManager.reconcileList(ctx, db, obj.Spec.DefaultPrivileges)
 	return nil
 }
 
@@ -277,3 +424,62 @@ func (r *DatabaseReconciler) reconcilePostgresDatabase(ctx context.Context, db *
 
 	return createDatabase(ctx, db, obj)
 }
+
+// resolveUserMappingCredentials returns the user mappings of the passed
+// database, with the credentials read from their secrets added to the
+// options, together with the resource versions of the secrets
+func (r *DatabaseReconciler) resolveUserMappingCredentials(
+	ctx context.Context,
+	obj *apiv1.Database,
+) ([]apiv1.UserMappingSpec, map[string]string, error) {
+	var secretsResourceVersion map[string]string
+	result := make([]apiv1.UserMappingSpec, len(obj.Spec.UserMappings))
+	for i, mapping := range obj.Spec.UserMappings {
+		result[i] = *mapping.DeepCopy()
+		if mapping.CredentialsSecret == nil || mapping.Ensure == apiv1.EnsureAbsent {
+			continue
+		}
+
+		var secret corev1.Secret
+		if err := getDeclarativeReference(
+			ctx, r.Client, obj.Namespace, mapping.CredentialsSecret.Name, &secret,
+		); err != nil {
+			return nil, nil, fmt.Errorf("while reading the credentials of user mapping %q from secret %q: %w",
+				mapping.GetName(), mapping.CredentialsSecret.Name, err)
+		}
+
+		username, password, err := managementutils.GetUserPasswordFromSecret(&secret)
+		if err != nil {
+			return nil, nil, fmt.Errorf("while reading the credentials of user mapping %q from secret %q: %w",
+				mapping.GetName(), secret.Name, err)
+		}
+
+		if result[i].Options == nil {
+			result[i].Options = make(map[string]string, 2)
+		}
+		result[i].Options["user"] = username
+		result[i].Options["password"] = password
+
+		if secretsResourceVersion == nil {
+			secretsResourceVersion = make(map[string]string)
+		}
+		secretsResourceVersion[secret.Name] = secret.ResourceVersion
+	}
+
+	return result, secretsResourceVersion, nil
+}
+
+// getSecretsResourceVersion gets the current resource version of the
+// secrets that were used in the latest reconciliation of the database,
+// as recorded by the operator in the status of the cluster. The secrets
+// which don't exist anymore or don't allow being referenced are skipped
+func getSecretsResourceVersion(cluster *apiv1.Cluster, obj *apiv1.Database) map[string]string {
+	result := make(map[string]string, len(obj.Status.SecretsResourceVersion))
+	for secretName := range obj.Status.SecretsResourceVersion {
+		if version := cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions[secretName]; version != "" {
+			result[secretName] = version
+		}
+	}
+
+	return result
+}
@@ -24,12 +24,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
 
 type extInfo struct {
@@ -198,16 +203,117 @@ func updateDatabase(
 	}
 
 	if len(obj.Spec.Tablespace) > 0 {
-		changeTablespaceSQL := fmt.Sprintf(
-			"ALTER DATABASE %s SET TABLESPACE %s",
-			pgx.Identifier{obj.Spec.Name}.Sanitize(),
-			pgx.Identifier{obj.Spec.Tablespace}.Sanitize())
+		if err := moveDatabaseTablespace(ctx, db, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const detectDatabaseTablespaceSQL = `
+SELECT t.spcname, d.datallowconn
+FROM pg_catalog.pg_database d
+JOIN pg_catalog.pg_tablespace t ON t.oid = d.dattablespace
+WHERE d.datname = $1
+`
+
+const countDatabaseSessionsSQL = `
+SELECT count(*)
+FROM pg_catalog.pg_stat_activity
+WHERE datname = $1
+  AND pid <> pg_catalog.pg_backend_pid()
+  AND NOT (application_name = $2 AND client_addr IS NULL)
+`
+
+const terminateDatabaseSessionsSQL = `
+SELECT count(pg_catalog.pg_terminate_backend(pid))
+FROM pg_catalog.pg_stat_activity
+WHERE datname = $1
+  AND pid <> pg_catalog.pg_backend_pid()
+  AND ($3 OR (application_name = $2 AND client_addr IS NULL))
+`
+
+// moveDatabaseTablespace moves the database to the tablespace requested in
+// the spec. PostgreSQL requires no session to be connected to the database
+// while it's moved: the idle sessions opened by the instance manager are
+// always terminated, while the other ones are either waited for or
+// terminated depending on the tablespace move session policy
+func moveDatabaseTablespace(
+	ctx context.Context,
+	db *sql.DB,
+	obj *apiv1.Database,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var currentTablespace string
+	var allowConnections bool
+	if err := db.QueryRowContext(ctx, detectDatabaseTablespaceSQL, obj.Spec.Name).
+		Scan(&currentTablespace, &allowConnections); err != nil {
+		return fmt.Errorf("while detecting the tablespace of database %q: %w", obj.Spec.Name, err)
+	}
+	if currentTablespace == obj.Spec.Tablespace {
+		return nil
+	}
+
+	terminateSessions := obj.Spec.TablespaceMoveSessionPolicy == apiv1.TablespaceMoveSessionTerminate
+	if !terminateSessions {
+		var sessions int
+		if err := db.QueryRowContext(
+			ctx, countDatabaseSessionsSQL,
+			obj.Spec.Name, postgres.InstanceManagerApplicationName,
+		).Scan(&sessions); err != nil {
+			return fmt.Errorf("while counting the sessions connected to database %q: %w", obj.Spec.Name, err)
+		}
+		if sessions > 0 {
+			return fmt.Errorf(
+				"waiting for %d sessions to disconnect from database %q before moving it to tablespace %s",
+				sessions, obj.Spec.Name, obj.Spec.Tablespace)
+		}
+	}
 
-		if _, err := db.ExecContext(ctx, changeTablespaceSQL); err != nil {
-			contextLogger.Error(err, "while altering database", "query", changeTablespaceSQL)
-			return fmt.Errorf("while altering database %q tablespace to %s: %w",
-				obj.Spec.Name, obj.Spec.Tablespace, err)
+	if terminateSessions && allowConnections {
+		// Prevent new sessions from being opened while the database is moved
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(
+			"ALTER DATABASE %s WITH ALLOW_CONNECTIONS false",
+			pgx.Identifier{obj.Spec.Name}.Sanitize())); err != nil {
+			return fmt.Errorf("while preventing connections to database %q: %w", obj.Spec.Name, err)
 		}
+		defer func() {
+			restoreAllowConnectionsSQL := fmt.Sprintf(
+				"ALTER DATABASE %s WITH ALLOW_CONNECTIONS true",
+				pgx.Identifier{obj.Spec.Name}.Sanitize())
+			if _, err := db.ExecContext(ctx, restoreAllowConnectionsSQL); err != nil {
+				contextLogger.Error(err, "while restoring connections to database", "query", restoreAllowConnectionsSQL)
+			}
+		}()
+	}
+
+	var terminated int
+	if err := db.QueryRowContext(
+		ctx, terminateDatabaseSessionsSQL,
+		obj.Spec.Name, postgres.InstanceManagerApplicationName, terminateSessions,
+	).Scan(&terminated); err != nil {
+		return fmt.Errorf("while terminating the sessions connected to database %q: %w", obj.Spec.Name, err)
+	}
+	if terminated > 0 {
+		contextLogger.Info("Terminated the sessions connected to the database before moving it",
+			"database", obj.Spec.Name, "sessions", terminated)
+	}
+
+	changeTablespaceSQL := fmt.Sprintf(
+		"ALTER DATABASE %s SET TABLESPACE %s",
+		pgx.Identifier{obj.Spec.Name}.Sanitize(),
+		pgx.Identifier{obj.Spec.Tablespace}.Sanitize())
+
+	contextLogger.Info("Moving database to tablespace",
+		"database", obj.Spec.Name,
+		"fromTablespace", currentTablespace,
+		"toTablespace", obj.Spec.Tablespace)
+	if _, err := db.ExecContext(ctx, changeTablespaceSQL); err != nil {
+		contextLogger.Error(err, "while altering database", "query", changeTablespaceSQL)
+		return fmt.Errorf("while altering database %q tablespace to %s: %w",
+			obj.Spec.Name, obj.Spec.Tablespace, err)
 	}
 
 	return nil
@@ -404,3 +510,889 @@ func dropDatabaseSchema(ctx context.Context, db *sql.DB, schema apiv1.SchemaSpec
 	contextLogger.Info("dropped schema", "name", schema.Name)
 	return nil
 }
+
+// privilegeInfo is the result of the detection of a set of privileges
+type privilegeInfo struct {
+	// Granted is the number of requested privileges already held by the grantee
+	Granted int
+
+	// Missing is the number of requested privileges not held by the grantee
+	Missing int
+}
+
+const detectDatabasePrivilegesSQL = `
+SELECT o.name, a.privilege_type, COALESCE(a.is_grantable, false)
+FROM (%s) o
+LEFT JOIN LATERAL (
+	SELECT e.privilege_type, e.is_grantable
+	FROM pg_catalog.aclexplode(o.acl) e
+	LEFT JOIN pg_catalog.pg_roles r ON r.oid = e.grantee
+	WHERE CASE WHEN e.grantee = 0 THEN 'PUBLIC' ELSE r.rolname END = $1
+) a ON true
+`
+
+// privilegeObjectsSQL contains, for every object type, the query
+// listing the candidate objects together with their access privileges
+var privilegeObjectsSQL = map[apiv1.PrivilegeObjectType]string{
+	apiv1.PrivilegeObjectTypeDatabase: `
+		SELECT datname AS name, COALESCE(datacl, pg_catalog.acldefault('d', datdba)) AS acl
+		FROM pg_catalog.pg_database
+		WHERE datname = pg_catalog.current_database()`,
+	apiv1.PrivilegeObjectTypeSchema: `
+		SELECT nspname AS name, COALESCE(nspacl, pg_catalog.acldefault('n', nspowner)) AS acl
+		FROM pg_catalog.pg_namespace
+		WHERE nspname = $2`,
+	apiv1.PrivilegeObjectTypeTable: `
+		SELECT c.relname AS name, COALESCE(c.relacl, pg_catalog.acldefault('r', c.relowner)) AS acl
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $2 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')`,
+	apiv1.PrivilegeObjectTypeSequence: `
+		SELECT c.relname AS name, COALESCE(c.relacl, pg_catalog.acldefault('s', c.relowner)) AS acl
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $2 AND c.relkind = 'S'`,
+	apiv1.PrivilegeObjectTypeFunction: `
+		SELECT p.oid::pg_catalog.regprocedure::text AS name,
+			COALESCE(p.proacl, pg_catalog.acldefault('f', p.proowner)) AS acl
+		FROM pg_catalog.pg_proc p
+		JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = $2 AND p.prokind IN ('f', 'a', 'w')`,
+	apiv1.PrivilegeObjectTypeForeignDataWrapper: `
+		SELECT fdwname AS name, COALESCE(fdwacl, pg_catalog.acldefault('F', fdwowner)) AS acl
+		FROM pg_catalog.pg_foreign_data_wrapper`,
+	apiv1.PrivilegeObjectTypeForeignServer: `
+		SELECT srvname AS name, COALESCE(srvacl, pg_catalog.acldefault('S', srvowner)) AS acl
+		FROM pg_catalog.pg_foreign_server`,
+}
+
+// normalizeGrantee returns the grantee name as it is reported by the
+// privilege detection queries
+func normalizeGrantee(grantee string) string {
+	if strings.EqualFold(grantee, "public") {
+		return "PUBLIC"
+	}
+	return grantee
+}
+
+// sanitizeGrantee returns the grantee as it should be written in a
+// `GRANT` or `REVOKE` statement
+func sanitizeGrantee(grantee string) string {
+	if strings.EqualFold(grantee, "public") {
+		return "PUBLIC"
+	}
+	return pgx.Identifier{grantee}.Sanitize()
+}
+
+// expandPrivileges expands the `ALL` keyword into the list of
+// the available privileges
+func expandPrivileges(privileges []string, available []string) []string {
+	result := stringset.New()
+	for _, privilege := range privileges {
+		privilege = strings.ToUpper(privilege)
+		if privilege == "ALL" {
+			for _, availablePrivilege := range available {
+				result.Put(availablePrivilege)
+			}
+			continue
+		}
+		result.Put(privilege)
+	}
+	return result.ToSortedList()
+}
+
+func getDatabasePrivilegeInfo(
+	ctx context.Context,
+	db *sql.DB,
+	privilege apiv1.PrivilegeSpec,
+) (*privilegeInfo, error) {
+	objectsSQL, ok := privilegeObjectsSQL[privilege.ObjectType]
+	if !ok {
+		return nil, fmt.Errorf("unknown privilege object type %q", privilege.ObjectType)
+	}
+
+	args := []any{normalizeGrantee(privilege.Grantee)}
+	switch privilege.ObjectType {
+	case apiv1.PrivilegeObjectTypeDatabase,
+		apiv1.PrivilegeObjectTypeForeignDataWrapper,
+		apiv1.PrivilegeObjectTypeForeignServer:
+	default:
+		args = append(args, privilege.Schema)
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(detectDatabasePrivilegesSQL, objectsSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("while detecting privileges %q: %w", privilege.GetName(), err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	heldPrivileges := make(map[string]*stringset.Data)
+	for rows.Next() {
+		var (
+			name          string
+			privilegeType sql.NullString
+			isGrantable   bool
+		)
+		if err := rows.Scan(&name, &privilegeType, &isGrantable); err != nil {
+			return nil, fmt.Errorf("while scanning privileges %q: %w", privilege.GetName(), err)
+		}
+
+		if _, ok := heldPrivileges[name]; !ok {
+			heldPrivileges[name] = stringset.New()
+		}
+		if privilegeType.Valid && (!privilege.WithGrantOption || isGrantable) {
+			heldPrivileges[name].Put(privilegeType.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while reading privileges %q: %w", privilege.GetName(), err)
+	}
+
+	targets := privilege.Objects
+	switch {
+	case len(targets) == 0:
+		targets = stringset.FromKeys(heldPrivileges).ToSortedList()
+
+	case privilege.ObjectType == apiv1.PrivilegeObjectTypeFunction:
+		// Functions are reported by signature, as formatted by regprocedure
+		if targets, err = resolveFunctionSignatures(ctx, db, privilege.Schema, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	var result privilegeInfo
+	requestedPrivileges := expandPrivileges(privilege.Privileges, privilege.ObjectType.GetAvailablePrivileges())
+	for _, target := range targets {
+		for _, requestedPrivilege := range requestedPrivileges {
+			if held, ok := heldPrivileges[target]; ok && held.Has(requestedPrivilege) {
+				result.Granted++
+			} else {
+				result.Missing++
+			}
+		}
+	}
+
+	if result.Granted == 0 {
+		return nil, nil
+	}
+
+	return &result, nil
+}
+
+const resolveFunctionSignatureSQL = `SELECT pg_catalog.to_regprocedure($1)::text`
+
+// resolveFunctionSignatures returns the signatures of the passed functions
+// of the schema as formatted by regprocedure, which is safe to be used in
+// a SQL statement. Functions that don't exist are reported as empty strings
+func resolveFunctionSignatures(
+	ctx context.Context,
+	db *sql.DB,
+	schema string,
+	functions []string,
+) ([]string, error) {
+	result := make([]string, len(functions))
+	for i, function := range functions {
+		var signature sql.NullString
+		row := db.QueryRowContext(ctx, resolveFunctionSignatureSQL,
+			pgx.Identifier{schema}.Sanitize()+"."+function)
+		if err := row.Scan(&signature); err != nil {
+			return nil, fmt.Errorf("while resolving function %q: %w", function, err)
+		}
+		result[i] = signature.String
+	}
+
+	return result, nil
+}
+
+// privilegeTarget returns the objects a privilege refers to, as they
+// should be written in a `GRANT` or `REVOKE` statement, resolving the
+// signatures of the functions
+func privilegeTarget(
+	ctx context.Context,
+	db *sql.DB,
+	databaseName string,
+	privilege apiv1.PrivilegeSpec,
+) (string, error) {
+	if privilege.ObjectType != apiv1.PrivilegeObjectTypeFunction || len(privilege.Objects) == 0 {
+		return privilegeTargetSQL(databaseName, privilege), nil
+	}
+
+	signatures, err := resolveFunctionSignatures(ctx, db, privilege.Schema, privilege.Objects)
+	if err != nil {
+		return "", err
+	}
+	for i, signature := range signatures {
+		if signature == "" {
+			return "", fmt.Errorf("function %q does not exist in schema %q",
+				privilege.Objects[i], privilege.Schema)
+		}
+	}
+
+	return fmt.Sprintf("FUNCTION %s", strings.Join(signatures, ", ")), nil
+}
+
+// sanitizeIdentifierList returns a comma-separated list of identifiers
+func sanitizeIdentifierList(names []string) string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return strings.Join(result, ", ")
+}
+
+// privilegeTargetSQL returns the objects a privilege refers to, as they
+// should be written in a `GRANT` or `REVOKE` statement
+func privilegeTargetSQL(databaseName string, privilege apiv1.PrivilegeSpec) string {
+	objectType := strings.ToUpper(string(privilege.ObjectType))
+
+	switch {
+	case privilege.ObjectType == apiv1.PrivilegeObjectTypeDatabase:
+		return fmt.Sprintf("DATABASE %s", pgx.Identifier{databaseName}.Sanitize())
+
+	case privilege.ObjectType == apiv1.PrivilegeObjectTypeSchema:
+		return fmt.Sprintf("SCHEMA %s", pgx.Identifier{privilege.Schema}.Sanitize())
+
+	case privilege.ObjectType == apiv1.PrivilegeObjectTypeForeignDataWrapper:
+		return fmt.Sprintf("FOREIGN DATA WRAPPER %s", sanitizeIdentifierList(privilege.Objects))
+
+	case privilege.ObjectType == apiv1.PrivilegeObjectTypeForeignServer:
+		return fmt.Sprintf("FOREIGN SERVER %s", sanitizeIdentifierList(privilege.Objects))
+
+	case len(privilege.Objects) == 0:
+		return fmt.Sprintf("ALL %sS IN SCHEMA %s", objectType, pgx.Identifier{privilege.Schema}.Sanitize())
+
+	default:
+		objects := make([]string, len(privilege.Objects))
+		for i, name := range privilege.Objects {
+			objects[i] = pgx.Identifier{privilege.Schema, name}.Sanitize()
+		}
+		return fmt.Sprintf("%s %s", objectType, strings.Join(objects, ", "))
+	}
+}
+
+func grantDatabasePrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	databaseName string,
+	privilege apiv1.PrivilegeSpec,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	target, err := privilegeTarget(ctx, db, databaseName, privilege)
+	if err != nil {
+		return err
+	}
+
+	var sqlGrant strings.Builder
+	sqlGrant.WriteString(fmt.Sprintf("GRANT %s ON %s TO %s",
+		strings.ToUpper(strings.Join(privilege.Privileges, ", ")),
+		target,
+		sanitizeGrantee(privilege.Grantee)))
+	if privilege.WithGrantOption {
+		sqlGrant.WriteString(" WITH GRANT OPTION")
+	}
+
+	if _, err := db.ExecContext(ctx, sqlGrant.String()); err != nil {
+		contextLogger.Error(err, "while granting privileges", "query", sqlGrant.String())
+		return err
+	}
+	contextLogger.Info("granted privileges", "privileges", privilege.GetName())
+
+	return nil
+}
+
+func revokeDatabasePrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	databaseName string,
+	privilege apiv1.PrivilegeSpec,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	target, err := privilegeTarget(ctx, db, databaseName, privilege)
+	if err != nil {
+		return err
+	}
+
+	var sqlRevoke strings.Builder
+	sqlRevoke.WriteString("REVOKE ")
+	if privilege.WithGrantOption {
+		sqlRevoke.WriteString("GRANT OPTION FOR ")
+	}
+	sqlRevoke.WriteString(fmt.Sprintf("%s ON %s FROM %s",
+		strings.ToUpper(strings.Join(privilege.Privileges, ", ")),
+		target,
+		sanitizeGrantee(privilege.Grantee)))
+
+	if _, err := db.ExecContext(ctx, sqlRevoke.String()); err != nil {
+		contextLogger.Error(err, "while revoking privileges", "query", sqlRevoke.String())
+		return err
+	}
+	contextLogger.Info("revoked privileges", "privileges", privilege.GetName())
+
+	return nil
+}
+
+func updateDatabasePrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	databaseName string,
+	privilege apiv1.PrivilegeSpec,
+	info *privilegeInfo,
+) error {
+	if info.Missing == 0 {
+		return nil
+	}
+
+	return grantDatabasePrivilege(ctx, db, databaseName, privilege)
+}
+
+// defaultPrivilegeObjectTypes maps the default privilege object types
+// to the values of `pg_default_acl.defaclobjtype`
+var defaultPrivilegeObjectTypes = map[apiv1.DefaultPrivilegeObjectType]string{
+	apiv1.DefaultPrivilegeObjectTypeTables:    "r",
+	apiv1.DefaultPrivilegeObjectTypeSequences: "S",
+	apiv1.DefaultPrivilegeObjectTypeFunctions: "f",
+	apiv1.DefaultPrivilegeObjectTypeTypes:     "T",
+	apiv1.DefaultPrivilegeObjectTypeSchemas:   "n",
+}
+
+// detectDatabaseDefaultPrivilegesSQL lists the default privileges of the
+// grantee. When no default privileges are defined for the whole database,
+// the built-in ones returned by acldefault apply. The default privileges
+// defined for a schema are added to the ones of the database, so they
+// don't fall back to the built-in ones. The object types of
+// pg_default_acl are the ones accepted by acldefault, except for sequences
+const detectDatabaseDefaultPrivilegesSQL = `
+SELECT a.privilege_type, a.is_grantable
+FROM pg_catalog.pg_roles o
+LEFT JOIN pg_catalog.pg_namespace n ON n.nspname = $2
+LEFT JOIN pg_catalog.pg_default_acl d ON d.defaclrole = o.oid
+	AND d.defaclobjtype::text = $3
+	AND d.defaclnamespace = CASE WHEN $2 = '' THEN 0 ELSE n.oid END
+CROSS JOIN LATERAL pg_catalog.aclexplode(CASE
+	WHEN $2 = '' THEN COALESCE(d.defaclacl, pg_catalog.acldefault(
+		(CASE WHEN $3 = 'S' THEN 's' ELSE $3 END)::"char", o.oid))
+	ELSE d.defaclacl
+END) a
+LEFT JOIN pg_catalog.pg_roles r ON r.oid = a.grantee
+WHERE o.rolname = $1
+AND CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE r.rolname END = $4
+`
+
+func getDatabaseDefaultPrivilegeInfo(
+	ctx context.Context,
+	db *sql.DB,
+	privilege apiv1.DefaultPrivilegeSpec,
+) (*privilegeInfo, error) {
+	objectType, ok := defaultPrivilegeObjectTypes[privilege.ObjectType]
+	if !ok {
+		return nil, fmt.Errorf("unknown default privilege object type %q", privilege.ObjectType)
+	}
+
+	rows, err := db.QueryContext(
+		ctx, detectDatabaseDefaultPrivilegesSQL,
+		privilege.Role, privilege.Schema, objectType, normalizeGrantee(privilege.Grantee))
+	if err != nil {
+		return nil, fmt.Errorf("while detecting default privileges %q: %w", privilege.GetName(), err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	heldPrivileges := stringset.New()
+	for rows.Next() {
+		var (
+			privilegeType string
+			isGrantable   bool
+		)
+		if err := rows.Scan(&privilegeType, &isGrantable); err != nil {
+			return nil, fmt.Errorf("while scanning default privileges %q: %w", privilege.GetName(), err)
+		}
+		if !privilege.WithGrantOption || isGrantable {
+			heldPrivileges.Put(privilegeType)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while reading default privileges %q: %w", privilege.GetName(), err)
+	}
+
+	var result privilegeInfo
+	for _, requestedPrivilege := range expandPrivileges(
+		privilege.Privileges, privilege.ObjectType.GetAvailablePrivileges()) {
+		if heldPrivileges.Has(requestedPrivilege) {
+			result.Granted++
+		} else {
+			result.Missing++
+		}
+	}
+
+	if result.Granted == 0 {
+		return nil, nil
+	}
+
+	return &result, nil
+}
+
+// alterDefaultPrivilegesSQL returns the prefix of the `ALTER DEFAULT
+// PRIVILEGES` statement for the passed default privileges
+func alterDefaultPrivilegesSQL(privilege apiv1.DefaultPrivilegeSpec) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("ALTER DEFAULT PRIVILEGES FOR ROLE %s", pgx.Identifier{privilege.Role}.Sanitize()))
+	if len(privilege.Schema) > 0 {
+		result.WriteString(fmt.Sprintf(" IN SCHEMA %s", pgx.Identifier{privilege.Schema}.Sanitize()))
+	}
+	return result.String()
+}
+
+func grantDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	privilege apiv1.DefaultPrivilegeSpec,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var sqlGrant strings.Builder
+	sqlGrant.WriteString(fmt.Sprintf("%s GRANT %s ON %s TO %s",
+		alterDefaultPrivilegesSQL(privilege),
+		strings.ToUpper(strings.Join(privilege.Privileges, ", ")),
+		strings.ToUpper(string(privilege.ObjectType)),
+		sanitizeGrantee(privilege.Grantee)))
+	if privilege.WithGrantOption {
+		sqlGrant.WriteString(" WITH GRANT OPTION")
+	}
+
+	if _, err := db.ExecContext(ctx, sqlGrant.String()); err != nil {
+		contextLogger.Error(err, "while granting default privileges", "query", sqlGrant.String())
+		return err
+	}
+	contextLogger.Info("granted default privileges", "privileges", privilege.GetName())
+
+	return nil
+}
+
+func updateDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	privilege apiv1.DefaultPrivilegeSpec,
+	info *privilegeInfo,
+) error {
+	if info.Missing == 0 {
+		return nil
+	}
+
+	return grantDatabaseDefaultPrivilege(ctx, db, privilege)
+}
+
+func revokeDatabaseDefaultPrivilege(
+	ctx context.Context,
+	db *sql.DB,
+	privilege apiv1.DefaultPrivilegeSpec,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var sqlRevoke strings.Builder
+	sqlRevoke.WriteString(alterDefaultPrivilegesSQL(privilege))
+	sqlRevoke.WriteString(" REVOKE ")
+	if privilege.WithGrantOption {
+		sqlRevoke.WriteString("GRANT OPTION FOR ")
+	}
+	sqlRevoke.WriteString(fmt.Sprintf("%s ON %s FROM %s",
+		strings.ToUpper(strings.Join(privilege.Privileges, ", ")),
+		strings.ToUpper(string(privilege.ObjectType)),
+		sanitizeGrantee(privilege.Grantee)))
+
+	if _, err := db.ExecContext(ctx, sqlRevoke.String()); err != nil {
+		contextLogger.Error(err, "while revoking default privileges", "query", sqlRevoke.String())
+		return err
+	}
+	contextLogger.Info("revoked default privileges", "privileges", privilege.GetName())
+
+	return nil
+}
+
+type fdwInfo struct {
+	Name      string            `json:"name"`
+	Handler   string            `json:"handler"`
+	Validator string            `json:"validator"`
+	Owner     string            `json:"owner"`
+	Options   map[string]string `json:"options"`
+}
+
+type serverInfo struct {
+	Name    string            `json:"name"`
+	FdwName string            `json:"fdwName"`
+	Owner   string            `json:"owner"`
+	Options map[string]string `json:"options"`
+}
+
+type userMappingInfo struct {
+	Options map[string]string `json:"options"`
+}
+
+// parseOptions parses an array of options in the `name=value` format,
+// as stored in the PostgreSQL catalog
+func parseOptions(options []string) map[string]string {
+	result := make(map[string]string, len(options))
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		result[name] = value
+	}
+	return result
+}
+
+// createOptionsSQL returns the `OPTIONS` clause of a `CREATE` statement
+func createOptionsSQL(options map[string]string) string {
+	if len(options) == 0 {
+		return ""
+	}
+
+	clauses := make([]string, 0, len(options))
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		clauses = append(clauses, fmt.Sprintf("%s %s", pgx.Identifier{name}.Sanitize(), pq.QuoteLiteral(options[name])))
+	}
+	return fmt.Sprintf(" OPTIONS (%s)", strings.Join(clauses, ", "))
+}
+
+// alterOptionsSQL returns the `OPTIONS` clause of an `ALTER` statement
+// changing the current options into the desired ones. An empty string
+// is returned when there is nothing to change
+func alterOptionsSQL(current, desired map[string]string) string {
+	var clauses []string
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		currentValue, exists := current[name]
+		switch {
+		case !exists:
+			clauses = append(clauses,
+				fmt.Sprintf("ADD %s %s", pgx.Identifier{name}.Sanitize(), pq.QuoteLiteral(desired[name])))
+		case currentValue != desired[name]:
+			clauses = append(clauses,
+				fmt.Sprintf("SET %s %s", pgx.Identifier{name}.Sanitize(), pq.QuoteLiteral(desired[name])))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		if _, exists := desired[name]; !exists {
+			clauses = append(clauses, fmt.Sprintf("DROP %s", pgx.Identifier{name}.Sanitize()))
+		}
+	}
+
+	if len(clauses) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" OPTIONS (%s)", strings.Join(clauses, ", "))
+}
+
+// sanitizeFunctionName returns the name of a handler or validator
+// function as it should be written in a SQL statement
+func sanitizeFunctionName(name string) string {
+	return pgx.Identifier(strings.Split(name, ".")).Sanitize()
+}
+
+const detectDatabaseFDWSQL = `
+SELECT f.fdwname, f.fdwhandler::regproc::text, f.fdwvalidator::regproc::text, a.rolname, f.fdwoptions
+FROM pg_catalog.pg_foreign_data_wrapper f
+JOIN pg_catalog.pg_authid a ON f.fdwowner = a.oid
+WHERE f.fdwname = $1
+`
+
+func getDatabaseFDWInfo(ctx context.Context, db *sql.DB, fdw apiv1.FDWSpec) (*fdwInfo, error) {
+	row := db.QueryRowContext(ctx, detectDatabaseFDWSQL, fdw.Name)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("while checking if foreign data wrapper %q exists: %w", fdw.Name, row.Err())
+	}
+
+	var (
+		result  fdwInfo
+		options pq.StringArray
+	)
+	if err := row.Scan(&result.Name, &result.Handler, &result.Validator, &result.Owner, &options); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while scanning if foreign data wrapper %q exists: %w", fdw.Name, err)
+	}
+	result.Options = parseOptions(options)
+
+	return &result, nil
+}
+
+func createDatabaseFDW(ctx context.Context, db *sql.DB, fdw apiv1.FDWSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	var sqlCreateFDW strings.Builder
+	sqlCreateFDW.WriteString(fmt.Sprintf("CREATE FOREIGN DATA WRAPPER %s", pgx.Identifier{fdw.Name}.Sanitize()))
+	switch {
+	case fdw.Handler == "-":
+		sqlCreateFDW.WriteString(" NO HANDLER")
+	case len(fdw.Handler) > 0:
+		sqlCreateFDW.WriteString(fmt.Sprintf(" HANDLER %s", sanitizeFunctionName(fdw.Handler)))
+	}
+	switch {
+	case fdw.Validator == "-":
+		sqlCreateFDW.WriteString(" NO VALIDATOR")
+	case len(fdw.Validator) > 0:
+		sqlCreateFDW.WriteString(fmt.Sprintf(" VALIDATOR %s", sanitizeFunctionName(fdw.Validator)))
+	}
+	sqlCreateFDW.WriteString(createOptionsSQL(fdw.Options))
+
+	if _, err := db.ExecContext(ctx, sqlCreateFDW.String()); err != nil {
+		contextLogger.Error(err, "while creating foreign data wrapper", "query", sqlCreateFDW.String())
+		return err
+	}
+	contextLogger.Info("created foreign data wrapper", "name", fdw.Name)
+
+	if len(fdw.Owner) > 0 {
+		return updateDatabaseFDWOwner(ctx, db, fdw)
+	}
+
+	return nil
+}
+
+func updateDatabaseFDWOwner(ctx context.Context, db *sql.DB, fdw apiv1.FDWSpec) error {
+	changeOwnerSQL := fmt.Sprintf(
+		"ALTER FOREIGN DATA WRAPPER %s OWNER TO %s",
+		pgx.Identifier{fdw.Name}.Sanitize(),
+		pgx.Identifier{fdw.Owner}.Sanitize(),
+	)
+
+	if _, err := db.ExecContext(ctx, changeOwnerSQL); err != nil {
+		return fmt.Errorf("altering foreign data wrapper owner: %w", err)
+	}
+
+	log.FromContext(ctx).Info("altered foreign data wrapper owner", "name", fdw.Name, "owner", fdw.Owner)
+	return nil
+}
+
+func updateDatabaseFDW(ctx context.Context, db *sql.DB, fdw apiv1.FDWSpec, info *fdwInfo) error {
+	contextLogger := log.FromContext(ctx)
+
+	var clauses []string
+	switch {
+	case fdw.Handler == "-" && info.Handler != "-":
+		clauses = append(clauses, " NO HANDLER")
+	case len(fdw.Handler) > 0 && fdw.Handler != "-" && fdw.Handler != info.Handler:
+		clauses = append(clauses, fmt.Sprintf(" HANDLER %s", sanitizeFunctionName(fdw.Handler)))
+	}
+	switch {
+	case fdw.Validator == "-" && info.Validator != "-":
+		clauses = append(clauses, " NO VALIDATOR")
+	case len(fdw.Validator) > 0 && fdw.Validator != "-" && fdw.Validator != info.Validator:
+		clauses = append(clauses, fmt.Sprintf(" VALIDATOR %s", sanitizeFunctionName(fdw.Validator)))
+	}
+	if optionsSQL := alterOptionsSQL(info.Options, fdw.Options); len(optionsSQL) > 0 {
+		clauses = append(clauses, optionsSQL)
+	}
+
+	if len(clauses) > 0 {
+		alterFDWSQL := fmt.Sprintf("ALTER FOREIGN DATA WRAPPER %s%s",
+			pgx.Identifier{fdw.Name}.Sanitize(), strings.Join(clauses, ""))
+		if _, err := db.ExecContext(ctx, alterFDWSQL); err != nil {
+			return fmt.Errorf("altering foreign data wrapper: %w", err)
+		}
+		contextLogger.Info("altered foreign data wrapper", "name", fdw.Name)
+	}
+
+	if len(fdw.Owner) > 0 && fdw.Owner != info.Owner {
+		return updateDatabaseFDWOwner(ctx, db, fdw)
+	}
+
+	return nil
+}
+
+func dropDatabaseFDW(ctx context.Context, db *sql.DB, fdw apiv1.FDWSpec) error {
+	contextLogger := log.FromContext(ctx)
+	query := fmt.Sprintf("DROP FOREIGN DATA WRAPPER IF EXISTS %s", pgx.Identifier{fdw.Name}.Sanitize())
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		contextLogger.Error(err, "while dropping foreign data wrapper", "query", query)
+		return err
+	}
+	contextLogger.Info("dropped foreign data wrapper", "name", fdw.Name)
+	return nil
+}
+
+const detectDatabaseServerSQL = `
+SELECT s.srvname, f.fdwname, a.rolname, s.srvoptions
+FROM pg_catalog.pg_foreign_server s
+JOIN pg_catalog.pg_foreign_data_wrapper f ON s.srvfdw = f.oid
+JOIN pg_catalog.pg_authid a ON s.srvowner = a.oid
+WHERE s.srvname = $1
+`
+
+func getDatabaseServerInfo(ctx context.Context, db *sql.DB, server apiv1.ServerSpec) (*serverInfo, error) {
+	row := db.QueryRowContext(ctx, detectDatabaseServerSQL, server.Name)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("while checking if foreign server %q exists: %w", server.Name, row.Err())
+	}
+
+	var (
+		result  serverInfo
+		options pq.StringArray
+	)
+	if err := row.Scan(&result.Name, &result.FdwName, &result.Owner, &options); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while scanning if foreign server %q exists: %w", server.Name, err)
+	}
+	result.Options = parseOptions(options)
+
+	return &result, nil
+}
+
+func createDatabaseServer(ctx context.Context, db *sql.DB, server apiv1.ServerSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	var sqlCreateServer strings.Builder
+	sqlCreateServer.WriteString(fmt.Sprintf("CREATE SERVER %s FOREIGN DATA WRAPPER %s",
+		pgx.Identifier{server.Name}.Sanitize(), pgx.Identifier{server.FdwName}.Sanitize()))
+	sqlCreateServer.WriteString(createOptionsSQL(server.Options))
+
+	if _, err := db.ExecContext(ctx, sqlCreateServer.String()); err != nil {
+		// The query is not logged, as the options may contain sensitive data
+		contextLogger.Error(err, "while creating foreign server", "name", server.Name)
+		return err
+	}
+	contextLogger.Info("created foreign server", "name", server.Name)
+
+	if len(server.Owner) > 0 {
+		return updateDatabaseServerOwner(ctx, db, server)
+	}
+
+	return nil
+}
+
+func updateDatabaseServerOwner(ctx context.Context, db *sql.DB, server apiv1.ServerSpec) error {
+	changeOwnerSQL := fmt.Sprintf(
+		"ALTER SERVER %s OWNER TO %s",
+		pgx.Identifier{server.Name}.Sanitize(),
+		pgx.Identifier{server.Owner}.Sanitize(),
+	)
+
+	if _, err := db.ExecContext(ctx, changeOwnerSQL); err != nil {
+		return fmt.Errorf("altering foreign server owner: %w", err)
+	}
+
+	log.FromContext(ctx).Info("altered foreign server owner", "name", server.Name, "owner", server.Owner)
+	return nil
+}
+
+func updateDatabaseServer(ctx context.Context, db *sql.DB, server apiv1.ServerSpec, info *serverInfo) error {
+	contextLogger := log.FromContext(ctx)
+
+	if server.FdwName != info.FdwName {
+		return fmt.Errorf("the foreign data wrapper of server %q cannot be changed from %q to %q",
+			server.Name, info.FdwName, server.FdwName)
+	}
+
+	if optionsSQL := alterOptionsSQL(info.Options, server.Options); len(optionsSQL) > 0 {
+		alterServerSQL := fmt.Sprintf("ALTER SERVER %s%s", pgx.Identifier{server.Name}.Sanitize(), optionsSQL)
+		if _, err := db.ExecContext(ctx, alterServerSQL); err != nil {
+			return fmt.Errorf("altering foreign server options: %w", err)
+		}
+		contextLogger.Info("altered foreign server options", "name", server.Name)
+	}
+
+	if len(server.Owner) > 0 && server.Owner != info.Owner {
+		return updateDatabaseServerOwner(ctx, db, server)
+	}
+
+	return nil
+}
+
+func dropDatabaseServer(ctx context.Context, db *sql.DB, server apiv1.ServerSpec) error {
+	contextLogger := log.FromContext(ctx)
+	query := fmt.Sprintf("DROP SERVER IF EXISTS %s", pgx.Identifier{server.Name}.Sanitize())
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		contextLogger.Error(err, "while dropping foreign server", "query", query)
+		return err
+	}
+	contextLogger.Info("dropped foreign server", "name", server.Name)
+	return nil
+}
+
+const detectDatabaseUserMappingSQL = `
+SELECT umoptions
+FROM pg_catalog.pg_user_mappings
+WHERE srvname = $1 AND usename = $2
+`
+
+// userMappingTargetSQL returns the user and the server of a user mapping,
+// as they should be written in a SQL statement
+func userMappingTargetSQL(mapping apiv1.UserMappingSpec) string {
+	return fmt.Sprintf("FOR %s SERVER %s", sanitizeGrantee(mapping.User), pgx.Identifier{mapping.Server}.Sanitize())
+}
+
+func getDatabaseUserMappingInfo(
+	ctx context.Context,
+	db *sql.DB,
+	mapping apiv1.UserMappingSpec,
+) (*userMappingInfo, error) {
+	userName := mapping.User
+	if strings.EqualFold(userName, "public") {
+		// The PUBLIC pseudo-role is reported in lowercase
+		userName = "public"
+	}
+
+	row := db.QueryRowContext(ctx, detectDatabaseUserMappingSQL, mapping.Server, userName)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("while checking if user mapping %q exists: %w", mapping.GetName(), row.Err())
+	}
+
+	var options pq.StringArray
+	if err := row.Scan(&options); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while scanning if user mapping %q exists: %w", mapping.GetName(), err)
+	}
+
+	return &userMappingInfo{Options: parseOptions(options)}, nil
+}
+
+func createDatabaseUserMapping(ctx context.Context, db *sql.DB, mapping apiv1.UserMappingSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	sqlCreateUserMapping := fmt.Sprintf("CREATE USER MAPPING %s%s",
+		userMappingTargetSQL(mapping), createOptionsSQL(mapping.Options))
+	if _, err := db.ExecContext(ctx, sqlCreateUserMapping); err != nil {
+		// The query is not logged, as the options contain credentials
+		contextLogger.Error(err, "while creating user mapping", "name", mapping.GetName())
+		return err
+	}
+	contextLogger.Info("created user mapping", "name", mapping.GetName())
+
+	return nil
+}
+
+func updateDatabaseUserMapping(
+	ctx context.Context,
+	db *sql.DB,
+	mapping apiv1.UserMappingSpec,
+	info *userMappingInfo,
+) error {
+	optionsSQL := alterOptionsSQL(info.Options, mapping.Options)
+	if len(optionsSQL) == 0 {
+		return nil
+	}
+
+	alterUserMappingSQL := fmt.Sprintf("ALTER USER MAPPING %s%s", userMappingTargetSQL(mapping), optionsSQL)
+	if _, err := db.ExecContext(ctx, alterUserMappingSQL); err != nil {
+		return fmt.Errorf("altering user mapping options: %w", err)
+	}
+	log.FromContext(ctx).Info("altered user mapping options", "name", mapping.GetName())
+
+	return nil
+}
+
+func dropDatabaseUserMapping(ctx context.Context, db *sql.DB, mapping apiv1.UserMappingSpec) error {
+	contextLogger := log.FromContext(ctx)
+	query := fmt.Sprintf("DROP USER MAPPING IF EXISTS %s", userMappingTargetSQL(mapping))
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		contextLogger.Error(err, "while dropping user mapping", "query", query)
+		return err
+	}
+	contextLogger.Info("dropped user mapping", "name", mapping.GetName())
+	return nil
+}
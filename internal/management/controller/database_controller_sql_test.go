@@ -30,6 +30,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -202,6 +203,14 @@ var _ = Describe("Managed Database SQL", func() {
 			dbMock.ExpectExec(ownerExpectedQuery).WillReturnResult(expectedValue)
 
 			// Mock Tablespace DDL
+			dbMock.ExpectQuery(detectDatabaseTablespaceSQL).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows([]string{"spcname", "datallowconn"}).AddRow("pg_default", true))
+			dbMock.ExpectQuery(countDatabaseSessionsSQL).
+				WithArgs(database.Spec.Name, postgres.InstanceManagerApplicationName).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			dbMock.ExpectQuery(terminateDatabaseSessionsSQL).
+				WithArgs(database.Spec.Name, postgres.InstanceManagerApplicationName, false).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 			tablespaceExpectedQuery := fmt.Sprintf(
 				"ALTER DATABASE %s SET TABLESPACE %s",
 				pgx.Identifier{database.Spec.Name}.Sanitize(),
@@ -214,6 +223,49 @@ var _ = Describe("Managed Database SQL", func() {
 		})
 	})
 
+	Context("moveDatabaseTablespace", func() {
+		BeforeEach(func() {
+			database.Spec.Tablespace = "newTablespace"
+		})
+
+		It("should do nothing if the database is already in the tablespace", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectDatabaseTablespaceSQL).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows([]string{"spcname", "datallowconn"}).AddRow("newTablespace", true))
+
+			Expect(moveDatabaseTablespace(ctx, db, database)).To(Succeed())
+		})
+
+		It("should wait for the sessions connected to the database", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectDatabaseTablespaceSQL).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows([]string{"spcname", "datallowconn"}).AddRow("pg_default", true))
+			dbMock.ExpectQuery(countDatabaseSessionsSQL).
+				WithArgs(database.Spec.Name, postgres.InstanceManagerApplicationName).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+			err := moveDatabaseTablespace(ctx, db, database)
+			Expect(err).To(MatchError(ContainSubstring("waiting for 2 sessions")))
+		})
+
+		It("should terminate the sessions when requested", func(ctx SpecContext) {
+			database.Spec.TablespaceMoveSessionPolicy = apiv1.TablespaceMoveSessionTerminate
+			expectedValue := sqlmock.NewResult(0, 1)
+
+			dbMock.ExpectQuery(detectDatabaseTablespaceSQL).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows([]string{"spcname", "datallowconn"}).AddRow("pg_default", true))
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" WITH ALLOW_CONNECTIONS false`).
+				WillReturnResult(expectedValue)
+			dbMock.ExpectQuery(terminateDatabaseSessionsSQL).
+				WithArgs(database.Spec.Name, postgres.InstanceManagerApplicationName, true).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" SET TABLESPACE "newTablespace"`).
+				WillReturnResult(expectedValue)
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" WITH ALLOW_CONNECTIONS true`).
+				WillReturnResult(expectedValue)
+
+			Expect(moveDatabaseTablespace(ctx, db, database)).To(Succeed())
+		})
+	})
+
 	Context("dropDatabase", func() {
 		It("should drop an existing Database", func(ctx SpecContext) {
 			expectedValue := sqlmock.NewResult(0, 1)
@@ -516,3 +568,614 @@ var _ = Describe("Managed schema SQL", func() {
 		})
 	})
 })
+
+var _ = Describe("Managed privileges SQL", func() {
+	var (
+		dbMock    sqlmock.Sqlmock
+		db        *sql.DB
+		privilege apiv1.PrivilegeSpec
+		err       error
+
+		testError error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		privilege = apiv1.PrivilegeSpec{
+			Ensure:     apiv1.EnsurePresent,
+			ObjectType: apiv1.PrivilegeObjectTypeTable,
+			Schema:     "app",
+			Objects:    []string{"orders", "customers"},
+			Privileges: []string{"SELECT", "INSERT"},
+			Grantee:    "reader",
+		}
+
+		testError = fmt.Errorf("test error")
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("getDatabasePrivilegeInfo", func() {
+		detectTablePrivilegesSQL := fmt.Sprintf(
+			detectDatabasePrivilegesSQL,
+			privilegeObjectsSQL[apiv1.PrivilegeObjectTypeTable],
+		)
+
+		It("returns nil info when no privilege has been granted", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectTablePrivilegesSQL).
+				WithArgs("reader", "app").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"name", "privilege_type", "is_grantable"}).
+						AddRow("orders", nil, false).
+						AddRow("customers", nil, false),
+				)
+			info, err := getDatabasePrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+
+		It("counts the granted and the missing privileges", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectTablePrivilegesSQL).
+				WithArgs("reader", "app").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"name", "privilege_type", "is_grantable"}).
+						AddRow("orders", "SELECT", false).
+						AddRow("orders", "INSERT", false).
+						AddRow("customers", "SELECT", false),
+				)
+			info, err := getDatabasePrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{Granted: 3, Missing: 1}))
+		})
+
+		It("expands ALL and targets every object in the schema", func(ctx SpecContext) {
+			privilege.Objects = nil
+			privilege.ObjectType = apiv1.PrivilegeObjectTypeSequence
+			privilege.Privileges = []string{"ALL"}
+			privilege.Grantee = "public"
+
+			dbMock.
+				ExpectQuery(fmt.Sprintf(
+					detectDatabasePrivilegesSQL,
+					privilegeObjectsSQL[apiv1.PrivilegeObjectTypeSequence],
+				)).
+				WithArgs("PUBLIC", "app").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"name", "privilege_type", "is_grantable"}).
+						AddRow("orders_id_seq", "USAGE", false).
+						AddRow("orders_id_seq", "SELECT", false).
+						AddRow("orders_id_seq", "UPDATE", false),
+				)
+			info, err := getDatabasePrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{Granted: 3, Missing: 0}))
+		})
+
+		It("ignores privileges without grant option when it is requested", func(ctx SpecContext) {
+			privilege.WithGrantOption = true
+			dbMock.
+				ExpectQuery(detectTablePrivilegesSQL).
+				WithArgs("reader", "app").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"name", "privilege_type", "is_grantable"}).
+						AddRow("orders", "SELECT", false).
+						AddRow("orders", "INSERT", true),
+				)
+			info, err := getDatabasePrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{Granted: 1, Missing: 3}))
+		})
+
+		It("matches functions by signature", func(ctx SpecContext) {
+			privilege.ObjectType = apiv1.PrivilegeObjectTypeFunction
+			privilege.Objects = []string{"refresh_totals(integer)", "refresh_totals(text)"}
+			privilege.Privileges = []string{"EXECUTE"}
+
+			dbMock.
+				ExpectQuery(fmt.Sprintf(
+					detectDatabasePrivilegesSQL,
+					privilegeObjectsSQL[apiv1.PrivilegeObjectTypeFunction],
+				)).
+				WithArgs("reader", "app").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"name", "privilege_type", "is_grantable"}).
+						AddRow("app.refresh_totals(integer)", "EXECUTE", false).
+						AddRow("app.refresh_totals(text)", nil, false),
+				)
+			dbMock.
+				ExpectQuery(resolveFunctionSignatureSQL).
+				WithArgs(`"app".refresh_totals(integer)`).
+				WillReturnRows(sqlmock.NewRows([]string{"to_regprocedure"}).
+					AddRow("app.refresh_totals(integer)"))
+			dbMock.
+				ExpectQuery(resolveFunctionSignatureSQL).
+				WithArgs(`"app".refresh_totals(text)`).
+				WillReturnRows(sqlmock.NewRows([]string{"to_regprocedure"}).
+					AddRow("app.refresh_totals(text)"))
+			info, err := getDatabasePrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{Granted: 1, Missing: 1}))
+		})
+
+		It("fails when the detection query fails", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectTablePrivilegesSQL).
+				WithArgs("reader", "app").
+				WillReturnError(testError)
+			_, err := getDatabasePrivilegeInfo(ctx, db, privilege)
+			Expect(err).To(MatchError(testError))
+		})
+	})
+
+	Context("grantDatabasePrivilege", func() {
+		It("grants the privileges on the listed objects", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`GRANT SELECT, INSERT ON TABLE "app"."orders", "app"."customers" TO "reader"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(grantDatabasePrivilege(ctx, db, "db-one", privilege)).To(Succeed())
+		})
+
+		It("grants the privileges on the database", func(ctx SpecContext) {
+			privilege = apiv1.PrivilegeSpec{
+				ObjectType:      apiv1.PrivilegeObjectTypeDatabase,
+				Privileges:      []string{"CONNECT"},
+				Grantee:         "public",
+				WithGrantOption: true,
+			}
+			dbMock.
+				ExpectExec(`GRANT CONNECT ON DATABASE "db-one" TO PUBLIC WITH GRANT OPTION`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(grantDatabasePrivilege(ctx, db, "db-one", privilege)).To(Succeed())
+		})
+
+		It("grants the privileges on the functions using their signature", func(ctx SpecContext) {
+			privilege.ObjectType = apiv1.PrivilegeObjectTypeFunction
+			privilege.Objects = []string{"refresh_totals(integer)"}
+			privilege.Privileges = []string{"EXECUTE"}
+			dbMock.
+				ExpectQuery(resolveFunctionSignatureSQL).
+				WithArgs(`"app".refresh_totals(integer)`).
+				WillReturnRows(sqlmock.NewRows([]string{"to_regprocedure"}).
+					AddRow("app.refresh_totals(integer)"))
+			dbMock.
+				ExpectExec(`GRANT EXECUTE ON FUNCTION app.refresh_totals(integer) TO "reader"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(grantDatabasePrivilege(ctx, db, "db-one", privilege)).To(Succeed())
+		})
+
+		It("fails when a function does not exist", func(ctx SpecContext) {
+			privilege.ObjectType = apiv1.PrivilegeObjectTypeFunction
+			privilege.Objects = []string{"refresh_totals(boolean)"}
+			privilege.Privileges = []string{"EXECUTE"}
+			dbMock.
+				ExpectQuery(resolveFunctionSignatureSQL).
+				WithArgs(`"app".refresh_totals(boolean)`).
+				WillReturnRows(sqlmock.NewRows([]string{"to_regprocedure"}).AddRow(nil))
+			Expect(grantDatabasePrivilege(ctx, db, "db-one", privilege)).
+				To(MatchError(ContainSubstring("does not exist")))
+		})
+
+		It("fails when the GRANT statement failed", func(ctx SpecContext) {
+			privilege.Objects = nil
+			dbMock.
+				ExpectExec(`GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA "app" TO "reader"`).
+				WillReturnError(testError)
+			Expect(grantDatabasePrivilege(ctx, db, "db-one", privilege)).To(Equal(testError))
+		})
+	})
+
+	Context("updateDatabasePrivilege", func() {
+		It("does nothing when every privilege has been granted", func(ctx SpecContext) {
+			Expect(updateDatabasePrivilege(ctx, db, "db-one", privilege,
+				&privilegeInfo{Granted: 4})).To(Succeed())
+		})
+
+		It("grants the missing privileges", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`GRANT SELECT, INSERT ON TABLE "app"."orders", "app"."customers" TO "reader"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(updateDatabasePrivilege(ctx, db, "db-one", privilege,
+				&privilegeInfo{Granted: 3, Missing: 1})).To(Succeed())
+		})
+	})
+
+	Context("revokeDatabasePrivilege", func() {
+		It("revokes the privileges", func(ctx SpecContext) {
+			privilege = apiv1.PrivilegeSpec{
+				ObjectType: apiv1.PrivilegeObjectTypeSchema,
+				Schema:     "app",
+				Privileges: []string{"CREATE"},
+				Grantee:    "reader",
+			}
+			dbMock.
+				ExpectExec(`REVOKE CREATE ON SCHEMA "app" FROM "reader"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(revokeDatabasePrivilege(ctx, db, "db-one", privilege)).To(Succeed())
+		})
+
+		It("revokes only the grant option when requested", func(ctx SpecContext) {
+			privilege.WithGrantOption = true
+			dbMock.
+				ExpectExec(`REVOKE GRANT OPTION FOR SELECT, INSERT ON TABLE "app"."orders", "app"."customers" FROM "reader"`).
+				WillReturnError(testError)
+			Expect(revokeDatabasePrivilege(ctx, db, "db-one", privilege)).To(Equal(testError))
+		})
+	})
+})
+
+var _ = Describe("Managed default privileges SQL", func() {
+	var (
+		dbMock    sqlmock.Sqlmock
+		db        *sql.DB
+		privilege apiv1.DefaultPrivilegeSpec
+		err       error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		privilege = apiv1.DefaultPrivilegeSpec{
+			Ensure:     apiv1.EnsurePresent,
+			Role:       "owner",
+			Schema:     "app",
+			ObjectType: apiv1.DefaultPrivilegeObjectTypeTables,
+			Privileges: []string{"SELECT"},
+			Grantee:    "reader",
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("getDatabaseDefaultPrivilegeInfo", func() {
+		It("returns nil info when no default privilege is defined", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectDatabaseDefaultPrivilegesSQL).
+				WithArgs("owner", "app", "r", "reader").
+				WillReturnRows(sqlmock.NewRows([]string{"privilege_type", "is_grantable"}))
+			info, err := getDatabaseDefaultPrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+
+		It("returns info when the default privileges are defined", func(ctx SpecContext) {
+			privilege.Privileges = []string{"ALL"}
+			dbMock.
+				ExpectQuery(detectDatabaseDefaultPrivilegesSQL).
+				WithArgs("owner", "app", "r", "reader").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"privilege_type", "is_grantable"}).
+						AddRow("SELECT", false).
+						AddRow("INSERT", false),
+				)
+			info, err := getDatabaseDefaultPrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{Granted: 2, Missing: 5}))
+		})
+		It("considers the built-in default privileges", func(ctx SpecContext) {
+			privilege.Ensure = apiv1.EnsureAbsent
+			privilege.Schema = ""
+			privilege.ObjectType = apiv1.DefaultPrivilegeObjectTypeFunctions
+			privilege.Privileges = []string{"EXECUTE"}
+			privilege.Grantee = "public"
+			dbMock.
+				ExpectQuery(detectDatabaseDefaultPrivilegesSQL).
+				WithArgs("owner", "", "f", "PUBLIC").
+				WillReturnRows(
+					sqlmock.NewRows([]string{"privilege_type", "is_grantable"}).
+						AddRow("EXECUTE", false),
+				)
+			info, err := getDatabaseDefaultPrivilegeInfo(ctx, db, privilege)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&privilegeInfo{Granted: 1, Missing: 0}))
+		})
+	})
+
+	Context("grantDatabaseDefaultPrivilege", func() {
+		It("alters the default privileges in the schema", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`ALTER DEFAULT PRIVILEGES FOR ROLE "owner" IN SCHEMA "app" ` +
+					`GRANT SELECT ON TABLES TO "reader"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(grantDatabaseDefaultPrivilege(ctx, db, privilege)).To(Succeed())
+		})
+	})
+
+	Context("revokeDatabaseDefaultPrivilege", func() {
+		It("alters the default privileges in the database", func(ctx SpecContext) {
+			privilege.Schema = ""
+			privilege.ObjectType = apiv1.DefaultPrivilegeObjectTypeFunctions
+			privilege.Privileges = []string{"EXECUTE"}
+			privilege.Grantee = "PUBLIC"
+			dbMock.
+				ExpectExec(`ALTER DEFAULT PRIVILEGES FOR ROLE "owner" REVOKE EXECUTE ON FUNCTIONS FROM PUBLIC`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(revokeDatabaseDefaultPrivilege(ctx, db, privilege)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Managed foreign data wrappers SQL", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+		fdw    apiv1.FDWSpec
+		err    error
+
+		testError error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		fdw = apiv1.FDWSpec{
+			DatabaseObjectSpec: apiv1.DatabaseObjectSpec{
+				Name:   "mywrapper",
+				Ensure: apiv1.EnsurePresent,
+			},
+			Handler:   "my_handler",
+			Validator: "-",
+			Owner:     "owner",
+			Options:   map[string]string{"debug": "true"},
+		}
+
+		testError = fmt.Errorf("test error")
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("getDatabaseFDWInfo", func() {
+		It("returns info when the foreign data wrapper exists", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectDatabaseFDWSQL).
+				WithArgs(fdw.Name).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"fdwname", "fdwhandler", "fdwvalidator", "rolname", "fdwoptions"}).
+						AddRow("mywrapper", "my_handler", "-", "owner", "{debug=true,level=a=b}"),
+				)
+			info, err := getDatabaseFDWInfo(ctx, db, fdw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(Equal(&fdwInfo{
+				Name:      "mywrapper",
+				Handler:   "my_handler",
+				Validator: "-",
+				Owner:     "owner",
+				Options:   map[string]string{"debug": "true", "level": "a=b"},
+			}))
+		})
+
+		It("returns nil info when the foreign data wrapper does not exist", func(ctx SpecContext) {
+			dbMock.
+				ExpectQuery(detectDatabaseFDWSQL).
+				WithArgs(fdw.Name).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"fdwname", "fdwhandler", "fdwvalidator", "rolname", "fdwoptions"}),
+				)
+			info, err := getDatabaseFDWInfo(ctx, db, fdw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info).To(BeNil())
+		})
+	})
+
+	Context("createDatabaseFDW", func() {
+		It("creates the foreign data wrapper and sets its owner", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`CREATE FOREIGN DATA WRAPPER "mywrapper" HANDLER "my_handler" NO VALIDATOR ` +
+					`OPTIONS ("debug" 'true')`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.
+				ExpectExec(`ALTER FOREIGN DATA WRAPPER "mywrapper" OWNER TO "owner"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(createDatabaseFDW(ctx, db, fdw)).To(Succeed())
+		})
+
+		It("fails when the foreign data wrapper could not be created", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`CREATE FOREIGN DATA WRAPPER "mywrapper" HANDLER "my_handler" NO VALIDATOR ` +
+					`OPTIONS ("debug" 'true')`).
+				WillReturnError(testError)
+			Expect(createDatabaseFDW(ctx, db, fdw)).To(Equal(testError))
+		})
+	})
+
+	Context("updateDatabaseFDW", func() {
+		It("does nothing when the foreign data wrapper has been correctly reconciled", func(ctx SpecContext) {
+			Expect(updateDatabaseFDW(ctx, db, fdw, &fdwInfo{
+				Name:      "mywrapper",
+				Handler:   "my_handler",
+				Validator: "-",
+				Owner:     "owner",
+				Options:   map[string]string{"debug": "true"},
+			})).To(Succeed())
+		})
+
+		It("updates handler, validator, options and owner", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`ALTER FOREIGN DATA WRAPPER "mywrapper" HANDLER "my_handler" NO VALIDATOR ` +
+					`OPTIONS (SET "debug" 'true', DROP "level")`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.
+				ExpectExec(`ALTER FOREIGN DATA WRAPPER "mywrapper" OWNER TO "owner"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(updateDatabaseFDW(ctx, db, fdw, &fdwInfo{
+				Name:      "mywrapper",
+				Handler:   "-",
+				Validator: "my_validator",
+				Owner:     "postgres",
+				Options:   map[string]string{"debug": "false", "level": "1"},
+			})).To(Succeed())
+		})
+	})
+
+	Context("dropDatabaseFDW", func() {
+		It("drops the foreign data wrapper", func(ctx SpecContext) {
+			dbMock.
+				ExpectExec(`DROP FOREIGN DATA WRAPPER IF EXISTS "mywrapper"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			Expect(dropDatabaseFDW(ctx, db, fdw)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Managed foreign servers SQL", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+		server apiv1.ServerSpec
+		err    error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		server = apiv1.ServerSpec{
+			DatabaseObjectSpec: apiv1.DatabaseObjectSpec{
+				Name:   "remote",
+				Ensure: apiv1.EnsurePresent,
+			},
+			FdwName: "postgres_fdw",
+			Options: map[string]string{"host": "remote-rw", "dbname": "app"},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns info when the foreign server exists", func(ctx SpecContext) {
+		dbMock.
+			ExpectQuery(detectDatabaseServerSQL).
+			WithArgs(server.Name).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"srvname", "fdwname", "rolname", "srvoptions"}).
+					AddRow("remote", "postgres_fdw", "postgres", "{host=remote-rw}"),
+			)
+		info, err := getDatabaseServerInfo(ctx, db, server)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(Equal(&serverInfo{
+			Name:    "remote",
+			FdwName: "postgres_fdw",
+			Owner:   "postgres",
+			Options: map[string]string{"host": "remote-rw"},
+		}))
+	})
+
+	It("creates the foreign server", func(ctx SpecContext) {
+		dbMock.
+			ExpectExec(`CREATE SERVER "remote" FOREIGN DATA WRAPPER "postgres_fdw" ` +
+				`OPTIONS ("dbname" 'app', "host" 'remote-rw')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(createDatabaseServer(ctx, db, server)).To(Succeed())
+	})
+
+	It("adds the missing options", func(ctx SpecContext) {
+		dbMock.
+			ExpectExec(`ALTER SERVER "remote" OPTIONS (ADD "dbname" 'app')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(updateDatabaseServer(ctx, db, server, &serverInfo{
+			Name:    "remote",
+			FdwName: "postgres_fdw",
+			Owner:   "postgres",
+			Options: map[string]string{"host": "remote-rw"},
+		})).To(Succeed())
+	})
+
+	It("refuses to change the foreign data wrapper", func(ctx SpecContext) {
+		Expect(updateDatabaseServer(ctx, db, server, &serverInfo{
+			Name:    "remote",
+			FdwName: "file_fdw",
+		})).Error().To(HaveOccurred())
+	})
+
+	It("drops the foreign server", func(ctx SpecContext) {
+		dbMock.
+			ExpectExec(`DROP SERVER IF EXISTS "remote"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(dropDatabaseServer(ctx, db, server)).To(Succeed())
+	})
+})
+
+var _ = Describe("Managed user mappings SQL", func() {
+	var (
+		dbMock  sqlmock.Sqlmock
+		db      *sql.DB
+		mapping apiv1.UserMappingSpec
+		err     error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mapping = apiv1.UserMappingSpec{
+			Ensure:  apiv1.EnsurePresent,
+			User:    "app",
+			Server:  "remote",
+			Options: map[string]string{"user": "remote_app", "password": "secret"},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns info when the user mapping exists", func(ctx SpecContext) {
+		dbMock.
+			ExpectQuery(detectDatabaseUserMappingSQL).
+			WithArgs("remote", "app").
+			WillReturnRows(
+				sqlmock.NewRows([]string{"umoptions"}).AddRow("{user=remote_app,password=old}"),
+			)
+		info, err := getDatabaseUserMappingInfo(ctx, db, mapping)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Options).To(Equal(map[string]string{"user": "remote_app", "password": "old"}))
+	})
+
+	It("looks for the PUBLIC user mapping", func(ctx SpecContext) {
+		mapping.User = "PUBLIC"
+		dbMock.
+			ExpectQuery(detectDatabaseUserMappingSQL).
+			WithArgs("remote", "public").
+			WillReturnRows(sqlmock.NewRows([]string{"umoptions"}))
+		info, err := getDatabaseUserMappingInfo(ctx, db, mapping)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(BeNil())
+	})
+
+	It("creates the user mapping", func(ctx SpecContext) {
+		dbMock.
+			ExpectExec(`CREATE USER MAPPING FOR "app" SERVER "remote" ` +
+				`OPTIONS ("password" 'secret', "user" 'remote_app')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(createDatabaseUserMapping(ctx, db, mapping)).To(Succeed())
+	})
+
+	It("updates the changed credentials", func(ctx SpecContext) {
+		dbMock.
+			ExpectExec(`ALTER USER MAPPING FOR "app" SERVER "remote" OPTIONS (SET "password" 'secret')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(updateDatabaseUserMapping(ctx, db, mapping, &userMappingInfo{
+			Options: map[string]string{"user": "remote_app", "password": "old"},
+		})).To(Succeed())
+	})
+
+	It("drops the user mapping", func(ctx SpecContext) {
+		mapping.User = "public"
+		dbMock.
+			ExpectExec(`DROP USER MAPPING IF EXISTS FOR PUBLIC SERVER "remote"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(dropDatabaseUserMapping(ctx, db, mapping)).To(Succeed())
+	})
+})
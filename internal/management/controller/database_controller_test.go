@@ -23,6 +23,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jackc/pgx/v5"
@@ -34,6 +35,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
@@ -372,6 +374,104 @@ var _ = Describe("Managed Database status", func() {
 	})
 })
 
+var _ = Describe("Managed Database user mapping credentials", func() {
+	var (
+		database *apiv1.Database
+		r        *DatabaseReconciler
+	)
+
+	BeforeEach(func() {
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-one",
+				Namespace: "default",
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-example"},
+				Name:       "db-one",
+				Owner:      "app",
+				UserMappings: []apiv1.UserMappingSpec{
+					{
+						Ensure:            apiv1.EnsurePresent,
+						User:              "app",
+						Server:            "remote",
+						Options:           map[string]string{"sslmode": "require"},
+						CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-credentials"},
+					},
+					{
+						Ensure: apiv1.EnsurePresent,
+						User:   "PUBLIC",
+						Server: "remote",
+					},
+				},
+			},
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "remote-credentials",
+				Namespace: "default",
+				Labels:    map[string]string{utils.DeclarativeReferenceLabelName: "true"},
+			},
+			Data: map[string][]byte{
+				"username": []byte("remote_app"),
+				"password": []byte("secret"),
+			},
+		}
+
+		r = &DatabaseReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(database, secret).
+				Build(),
+		}
+	})
+
+	It("adds the credentials to the user mapping options", func(ctx SpecContext) {
+		userMappings, secretsResourceVersion, err := r.resolveUserMappingCredentials(ctx, database)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userMappings).To(HaveLen(2))
+		Expect(userMappings[0].Options).To(Equal(map[string]string{
+			"sslmode":  "require",
+			"user":     "remote_app",
+			"password": "secret",
+		}))
+		Expect(userMappings[1].Options).To(BeEmpty())
+		Expect(secretsResourceVersion).To(HaveKey("remote-credentials"))
+
+		// The spec of the database is not changed
+		Expect(database.Spec.UserMappings[0].Options).To(HaveLen(1))
+	})
+
+	It("fails when the secret does not exist", func(ctx SpecContext) {
+		database.Spec.UserMappings[0].CredentialsSecret.Name = "missing"
+		_, _, err := r.resolveUserMappingCredentials(ctx, database)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("fails when the secret does not allow being referenced", func(ctx SpecContext) {
+		var secret corev1.Secret
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "default", Name: "remote-credentials"}, &secret)).To(Succeed())
+		secret.Labels = nil
+		Expect(r.Update(ctx, &secret)).To(Succeed())
+
+		_, _, err := r.resolveUserMappingCredentials(ctx, database)
+		Expect(err).To(MatchError(errDeclarativeReferenceNotAllowed))
+	})
+
+	It("detects the current version of the used secrets from the cluster status", func(ctx SpecContext) {
+		_, secretsResourceVersion, err := r.resolveUserMappingCredentials(ctx, database)
+		Expect(err).ToNot(HaveOccurred())
+		database.Status.SecretsResourceVersion = secretsResourceVersion
+
+		cluster := &apiv1.Cluster{}
+		cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions = maps.Clone(secretsResourceVersion)
+		Expect(getSecretsResourceVersion(cluster, database)).To(Equal(secretsResourceVersion))
+
+		cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions["remote-credentials"] = ""
+		Expect(getSecretsResourceVersion(cluster, database)).To(BeEmpty())
+	})
+})
+
 func reconcileDatabase(
 	ctx context.Context,
 	fakeClient client.Client,
@@ -389,3 +489,25 @@ func reconcileDatabase(
 		Name:      database.GetName(),
 	}, database)
 }
+
+var _ = Describe("Declarative references predicate", func() {
+	It("triggers only when the versions of the declarative references change", func() {
+		oldCluster := &apiv1.Cluster{}
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Status.Phase = apiv1.PhaseHealthy
+		Expect(declarativeReferencesPredicate.Update(event.UpdateEvent{
+			ObjectOld: oldCluster,
+			ObjectNew: newCluster,
+		})).To(BeFalse())
+
+		newCluster.Status.SecretsResourceVersion.DeclarativeSecretVersions = map[string]string{
+			"remote-credentials": "42",
+		}
+		Expect(declarativeReferencesPredicate.Update(event.UpdateEvent{
+			ObjectOld: oldCluster,
+			ObjectNew: newCluster,
+		})).To(BeTrue())
+
+		Expect(declarativeReferencesPredicate.Create(event.CreateEvent{Object: newCluster})).To(BeFalse())
+	})
+})
@@ -0,0 +1,382 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// migrationKeyRegex matches the names of the keys containing the schema
+// migrations, in the `V<version>__<description>.sql` format
+var migrationKeyRegex = regexp.MustCompile(`^V(\d+(?:[._]\d+)*)__(.+)\.sql$`)
+
+// databaseMigration is a versioned schema migration of a database
+type databaseMigration struct {
+	// version is the normalized version, e.g. `1.2` for `V1_2_0`
+	version string
+
+	// versionParts are the numeric components of the version
+	versionParts []uint64
+
+	// description is extracted from the key name
+	description string
+
+	// source identifies the key containing the migration
+	source string
+
+	// content is the SQL code of the migration
+	content string
+
+	// checksum is the SHA256 checksum of the content
+	checksum string
+}
+
+// newDatabaseMigration parses a migration from the name of the key
+// containing it and its content
+func newDatabaseMigration(source string, key string, content string) (*databaseMigration, error) {
+	matches := migrationKeyRegex.FindStringSubmatch(key)
+	if matches == nil {
+		return nil, fmt.Errorf(
+			"%s: the key name %q does not follow the V<version>__<description>.sql format",
+			source, key)
+	}
+
+	versionParts, err := parseMigrationVersion(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid version %q: %w", source, matches[1], err)
+	}
+
+	checksum := sha256.Sum256([]byte(content))
+	return &databaseMigration{
+		version:      formatMigrationVersion(versionParts),
+		versionParts: versionParts,
+		description:  strings.ReplaceAll(matches[2], "_", " "),
+		source:       source,
+		content:      content,
+		checksum:     hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// parseMigrationVersion parses the numeric components of a version,
+// separated by dots or underscores
+func parseMigrationVersion(version string) ([]uint64, error) {
+	rawParts := strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '_' })
+	if len(rawParts) == 0 {
+		return nil, fmt.Errorf("empty version")
+	}
+
+	versionParts := make([]uint64, len(rawParts))
+	for i, rawPart := range rawParts {
+		part, err := strconv.ParseUint(rawPart, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		versionParts[i] = part
+	}
+
+	return versionParts, nil
+}
+
+// formatMigrationVersion formats the normalized version, which is the same
+// for the versions comparing as equal: the trailing zero components
+// are dropped, and so are the leading zeros of each component
+func formatMigrationVersion(versionParts []uint64) string {
+	significant := len(versionParts)
+	for significant > 1 && versionParts[significant-1] == 0 {
+		significant--
+	}
+
+	formattedParts := make([]string, significant)
+	for i, part := range versionParts[:significant] {
+		formattedParts[i] = strconv.FormatUint(part, 10)
+	}
+
+	return strings.Join(formattedParts, ".")
+}
+
+// compareMigrations compares two migrations by their version,
+// so that `1.10` comes after `1.9`, and `1.0` equals `1`
+func compareMigrations(a, b *databaseMigration) int {
+	for i := range max(len(a.versionParts), len(b.versionParts)) {
+		var partA, partB uint64
+		if i < len(a.versionParts) {
+			partA = a.versionParts[i]
+		}
+		if i < len(b.versionParts) {
+			partB = b.versionParts[i]
+		}
+		if result := cmp.Compare(partA, partB); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// getMigrations reads the schema migrations of the passed database from
+// their ConfigMaps and Secrets, sorted by version, together with the
+// resource versions of their sources
+func (r *DatabaseReconciler) getMigrations(
+	ctx context.Context,
+	obj *apiv1.Database,
+) ([]*databaseMigration, map[string]string, error) {
+	if obj.Spec.Migrations == nil {
+		return nil, nil, nil
+	}
+
+	var migrations []*databaseMigration
+	sourcesResourceVersion := make(map[string]string)
+	addMigration := func(source, key, content string) error {
+		migration, err := newDatabaseMigration(source, key, content)
+		if err != nil {
+			return err
+		}
+		migrations = append(migrations, migration)
+		return nil
+	}
+
+	for _, ref := range obj.Spec.Migrations.SecretRefs {
+		var secret corev1.Secret
+		if err := getDeclarativeReference(ctx, r.Client, obj.Namespace, ref.Name, &secret); err != nil {
+			return nil, nil, fmt.Errorf("while reading the migrations secret %q: %w", ref.Name, err)
+		}
+		content, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing key %q in the migrations secret %q", ref.Key, ref.Name)
+		}
+		sourcesResourceVersion["Secret/"+secret.Name] = secret.ResourceVersion
+		if err := addMigration(fmt.Sprintf("secret %q", ref.Name), ref.Key, string(content)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, ref := range obj.Spec.Migrations.ConfigMapRefs {
+		var configMap corev1.ConfigMap
+		if err := getDeclarativeReference(ctx, r.Client, obj.Namespace, ref.Name, &configMap); err != nil {
+			return nil, nil, fmt.Errorf("while reading the migrations config map %q: %w", ref.Name, err)
+		}
+		content, ok := configMap.Data[ref.Key]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing key %q in the migrations config map %q", ref.Key, ref.Name)
+		}
+		sourcesResourceVersion["ConfigMap/"+configMap.Name] = configMap.ResourceVersion
+		if err := addMigration(fmt.Sprintf("config map %q", ref.Name), ref.Key, content); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	slices.SortStableFunc(migrations, compareMigrations)
+	for i := 1; i < len(migrations); i++ {
+		if compareMigrations(migrations[i-1], migrations[i]) == 0 {
+			return nil, nil, fmt.Errorf("duplicate migration version %q in %s and %s",
+				migrations[i].version, migrations[i-1].source, migrations[i].source)
+		}
+	}
+
+	return migrations, sourcesResourceVersion, nil
+}
+
+// getMigrationSourcesResourceVersion gets the current resource version
+// of the ConfigMaps and Secrets containing the migrations that were used
+// in the latest reconciliation of the database, as recorded by the
+// operator in the status of the cluster. The sources which don't exist
+// anymore or don't allow being referenced are skipped
+func getMigrationSourcesResourceVersion(cluster *apiv1.Cluster, obj *apiv1.Database) map[string]string {
+	if obj.Status.Migrations == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(obj.Status.Migrations.SourcesResourceVersion))
+	for source := range obj.Status.Migrations.SourcesResourceVersion {
+		kind, name, _ := strings.Cut(source, "/")
+		var version string
+		switch kind {
+		case "Secret":
+			version = cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions[name]
+		case "ConfigMap":
+			version = cluster.Status.ConfigMapResourceVersion.DeclarativeConfigMapVersions[name]
+		}
+		if version != "" {
+			result[source] = version
+		}
+	}
+
+	return result
+}
+
+// reconcileMigrations applies the pending schema migrations of the
+// passed database, recording their status
+func (r *DatabaseReconciler) reconcileMigrations(ctx context.Context, obj *apiv1.Database) error {
+	if obj.Spec.Migrations == nil {
+		obj.Status.Migrations = nil
+		return nil
+	}
+
+	migrations, sourcesResourceVersion, err := r.getMigrations(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	db, err := r.getTargetDB(obj.Spec.Name)
+	if err != nil {
+		return fmt.Errorf("while connecting to the database %q: %w", obj.Spec.Name, err)
+	}
+
+	status, err := applyMigrations(ctx, db, obj.Spec.Migrations.GetTrackingTable(), migrations)
+	status.SourcesResourceVersion = sourcesResourceVersion
+	obj.Status.Migrations = status
+	return err
+}
+
+// applyMigrations applies, in order, the migrations that are not recorded
+// in the tracking table yet. It stops at the first failure, or if the
+// content of an applied migration has been changed
+func applyMigrations(
+	ctx context.Context,
+	db *sql.DB,
+	trackingTable string,
+	migrations []*databaseMigration,
+) (*apiv1.DatabaseMigrationsStatus, error) {
+	contextLogger := log.FromContext(ctx)
+	status := &apiv1.DatabaseMigrationsStatus{Pending: len(migrations)}
+	table := pgx.Identifier{"public", trackingTable}.Sanitize()
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ("+
+			"version text PRIMARY KEY, "+
+			"description text NOT NULL, "+
+			"checksum text NOT NULL, "+
+			"applied_at timestamptz NOT NULL DEFAULT now())",
+		table)); err != nil {
+		return status, fmt.Errorf("while creating the migrations tracking table: %w", err)
+	}
+
+	appliedChecksums, err := getAppliedMigrations(ctx, db, table)
+	if err != nil {
+		return status, err
+	}
+
+	var latestApplied *databaseMigration
+	for _, migration := range migrations {
+		checksum, applied := appliedChecksums[migration.version]
+		if !applied {
+			continue
+		}
+		if checksum != migration.checksum {
+			return status, fmt.Errorf(
+				"the content of the applied migration %q, in %s, has been changed: "+
+					"applied checksum %s, current checksum %s",
+				migration.version, migration.source, checksum, migration.checksum)
+		}
+		status.Applied++
+		status.Pending--
+		latestApplied = migration
+	}
+	if latestApplied != nil {
+		status.CurrentVersion = latestApplied.version
+	}
+
+	for _, migration := range migrations {
+		if _, applied := appliedChecksums[migration.version]; applied {
+			continue
+		}
+		if latestApplied != nil && compareMigrations(migration, latestApplied) < 0 {
+			return status, fmt.Errorf(
+				"the migration %q, in %s, is older than the latest applied one (%q)",
+				migration.version, migration.source, latestApplied.version)
+		}
+
+		contextLogger.Info("applying schema migration",
+			"version", migration.version, "description", migration.description)
+		if err := applyMigration(ctx, db, table, migration); err != nil {
+			return status, fmt.Errorf("while applying the migration %q, in %s: %w",
+				migration.version, migration.source, err)
+		}
+
+		status.Applied++
+		status.Pending--
+		status.CurrentVersion = migration.version
+		latestApplied = migration
+	}
+
+	return status, nil
+}
+
+// getAppliedMigrations returns the checksums of the applied migrations,
+// indexed by their version
+func getAppliedMigrations(ctx context.Context, db *sql.DB, table string) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum FROM %s", table))
+	if err != nil {
+		return nil, fmt.Errorf("while reading the applied migrations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var version, checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("while reading the applied migrations: %w", err)
+		}
+		result[version] = checksum
+	}
+
+	return result, rows.Err()
+}
+
+// applyMigration applies a migration and records it in the tracking
+// table, in the same transaction
+func applyMigration(ctx context.Context, db *sql.DB, table string, migration *databaseMigration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, migration.content); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (version, description, checksum) VALUES ($1, $2, $3)", table),
+		migration.version, migration.description, migration.checksum,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,272 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database schema migrations parsing", func() {
+	It("parses the version and the description from the key name", func() {
+		migration, err := newDatabaseMigration("test", "V1_2__add_customers_index.sql", "SELECT 1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migration.version).To(Equal("1.2"))
+		Expect(migration.versionParts).To(Equal([]uint64{1, 2}))
+		Expect(migration.description).To(Equal("add customers index"))
+		Expect(migration.checksum).To(HaveLen(64))
+	})
+
+	It("normalizes the version", func() {
+		for key, version := range map[string]string{
+			"V1__a.sql":      "1",
+			"V1_0__a.sql":    "1",
+			"V1.0.0__a.sql":  "1",
+			"V01_02__a.sql":  "1.2",
+			"V1_0_3__a.sql":  "1.0.3",
+			"V0__a.sql":      "0",
+			"V2.10.0__a.sql": "2.10",
+		} {
+			migration, err := newDatabaseMigration("test", key, "SELECT 1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migration.version).To(Equal(version), key)
+		}
+	})
+
+	It("rejects the keys not following the naming convention", func() {
+		for _, key := range []string{"init.sql", "V1_init.sql", "Va__init.sql", "V1__init.txt"} {
+			_, err := newDatabaseMigration("test", key, "SELECT 1")
+			Expect(err).To(HaveOccurred(), key)
+		}
+	})
+
+	It("sorts the migrations by version", func() {
+		v19, _ := newDatabaseMigration("test", "V1.9__a.sql", "")
+		v110, _ := newDatabaseMigration("test", "V1.10__b.sql", "")
+		v1, _ := newDatabaseMigration("test", "V1__c.sql", "")
+		v10, _ := newDatabaseMigration("test", "V1.0__d.sql", "")
+		Expect(compareMigrations(v19, v110)).To(Equal(-1))
+		Expect(compareMigrations(v110, v1)).To(Equal(1))
+		Expect(compareMigrations(v1, v10)).To(Equal(0))
+	})
+})
+
+var _ = Describe("Database schema migrations", func() {
+	const (
+		createTableQuery = `CREATE TABLE IF NOT EXISTS "public"."cnpg_schema_migrations"`
+		appliedQuery     = `SELECT version, checksum FROM "public"."cnpg_schema_migrations"`
+		insertQuery      = `INSERT INTO "public"."cnpg_schema_migrations" (version, description, checksum)`
+	)
+
+	var (
+		db         *sql.DB
+		dbMock     sqlmock.Sqlmock
+		migrations []*databaseMigration
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		v1, err := newDatabaseMigration("test", "V1__create_table.sql", "CREATE TABLE customers (id int)")
+		Expect(err).ToNot(HaveOccurred())
+		v2, err := newDatabaseMigration("test", "V2__add_index.sql", "CREATE INDEX ON customers (id)")
+		Expect(err).ToNot(HaveOccurred())
+		migrations = []*databaseMigration{v1, v2}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectAppliedMigrations := func(applied ...*databaseMigration) {
+		dbMock.ExpectExec(regexp.QuoteMeta(createTableQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+		rows := sqlmock.NewRows([]string{"version", "checksum"})
+		for _, migration := range applied {
+			rows.AddRow(migration.version, migration.checksum)
+		}
+		dbMock.ExpectQuery(regexp.QuoteMeta(appliedQuery)).WillReturnRows(rows)
+	}
+
+	expectMigration := func(migration *databaseMigration) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(migration.content)).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(migration.version, migration.description, migration.checksum).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+	}
+
+	It("applies the pending migrations in order", func(ctx SpecContext) {
+		expectAppliedMigrations(migrations[0])
+		expectMigration(migrations[1])
+
+		status, err := applyMigrations(ctx, db, apiv1.DefaultMigrationsTrackingTable, migrations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.CurrentVersion).To(Equal("2"))
+		Expect(status.Applied).To(Equal(2))
+		Expect(status.Pending).To(BeZero())
+	})
+
+	It("stops at the first failing migration", func(ctx SpecContext) {
+		expectAppliedMigrations()
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(migrations[0].content)).WillReturnError(errors.New("kaboom"))
+		dbMock.ExpectRollback()
+
+		status, err := applyMigrations(ctx, db, apiv1.DefaultMigrationsTrackingTable, migrations)
+		Expect(err).To(MatchError(ContainSubstring("kaboom")))
+		Expect(status.CurrentVersion).To(BeEmpty())
+		Expect(status.Pending).To(Equal(2))
+	})
+
+	It("doesn't apply again a migration whose version has been renamed", func(ctx SpecContext) {
+		original, err := newDatabaseMigration("test", "V1_0__create_table.sql", "CREATE TABLE customers (id int)")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(original.version).To(Equal("1"))
+
+		expectAppliedMigrations(original)
+		status, err := applyMigrations(ctx, db, apiv1.DefaultMigrationsTrackingTable, migrations[:1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.CurrentVersion).To(Equal("1"))
+		Expect(status.Applied).To(Equal(1))
+		Expect(status.Pending).To(BeZero())
+	})
+
+	It("refuses to continue when an applied migration has been changed", func(ctx SpecContext) {
+		changed := *migrations[0]
+		changed.checksum = "0000"
+		expectAppliedMigrations(&changed)
+
+		status, err := applyMigrations(ctx, db, apiv1.DefaultMigrationsTrackingTable, migrations)
+		Expect(err).To(MatchError(ContainSubstring("has been changed")))
+		Expect(status.Applied).To(BeZero())
+	})
+
+	It("refuses to apply migrations older than the latest applied one", func(ctx SpecContext) {
+		expectAppliedMigrations(migrations[1])
+
+		_, err := applyMigrations(ctx, db, apiv1.DefaultMigrationsTrackingTable, migrations)
+		Expect(err).To(MatchError(ContainSubstring("is older than the latest applied one")))
+	})
+
+	It("reads the migrations from config maps and secrets", func(ctx SpecContext) {
+		allowed := map[string]string{utils.DeclarativeReferenceLabelName: "true"}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: "default", Labels: allowed},
+			Data: map[string]string{
+				"V2__add_index.sql": "CREATE INDEX ON customers (id)",
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-migrations", Namespace: "default", Labels: allowed},
+			Data: map[string][]byte{
+				"V1__create_table.sql": []byte("CREATE TABLE customers (id int)"),
+			},
+		}
+		database := &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: apiv1.DatabaseSpec{
+				Migrations: &apiv1.DatabaseMigrations{
+					SQLRefs: apiv1.SQLRefs{
+						ConfigMapRefs: []apiv1.ConfigMapKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "migrations"},
+							Key:                  "V2__add_index.sql",
+						}},
+						SecretRefs: []apiv1.SecretKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "secret-migrations"},
+							Key:                  "V1__create_table.sql",
+						}},
+					},
+				},
+			},
+		}
+		r := &DatabaseReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(configMap, secret).
+				Build(),
+		}
+
+		result, sourcesResourceVersion, err := r.getMigrations(ctx, database)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].version).To(Equal("1"))
+		Expect(result[1].version).To(Equal("2"))
+		Expect(sourcesResourceVersion).To(HaveKey("ConfigMap/migrations"))
+		Expect(sourcesResourceVersion).To(HaveKey("Secret/secret-migrations"))
+
+		cluster := &apiv1.Cluster{}
+		cluster.Status.SecretsResourceVersion.DeclarativeSecretVersions = map[string]string{
+			"secret-migrations": secret.ResourceVersion,
+		}
+		database.Status.Migrations = &apiv1.DatabaseMigrationsStatus{SourcesResourceVersion: sourcesResourceVersion}
+		Expect(getMigrationSourcesResourceVersion(cluster, database)).To(Equal(map[string]string{
+			"Secret/secret-migrations": secret.ResourceVersion,
+		}))
+
+		database.Spec.Migrations.ConfigMapRefs[0].Key = "missing.sql"
+		_, _, err = r.getMigrations(ctx, database)
+		Expect(err).To(MatchError(ContainSubstring("missing key")))
+	})
+
+	It("refuses the migrations from config maps not allowing being referenced", func(ctx SpecContext) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: "default"},
+			Data: map[string]string{
+				"V1__create_table.sql": "CREATE TABLE customers (id int)",
+			},
+		}
+		database := &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: apiv1.DatabaseSpec{
+				Migrations: &apiv1.DatabaseMigrations{
+					SQLRefs: apiv1.SQLRefs{
+						ConfigMapRefs: []apiv1.ConfigMapKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "migrations"},
+							Key:                  "V1__create_table.sql",
+						}},
+					},
+				},
+			},
+		}
+		r := &DatabaseReconciler{
+			Client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(configMap).
+				Build(),
+		}
+
+		_, _, err := r.getMigrations(ctx, database)
+		Expect(err).To(MatchError(errDeclarativeReferenceNotAllowed))
+	})
+})
@@ -76,7 +76,9 @@ func (r *databaseObjectManager[Spec, Info]) reconcile(
 	if err != nil {
 		return createFailedStatus(
 			spec.GetName(),
-			fmt.Sprintf("while reading the object %#v: %v", spec, err),
+			// The spec is not included in the message, as it may contain
+			// credentials (e.g. user mapping options)
+			fmt.Sprintf("while reading the object %q: %v", spec.GetName(), err),
 		)
 	}
 
@@ -0,0 +1,113 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// terminateClientConnectionsSQL terminates the connections of every
+// non-superuser role, returning how many of them were found.
+// Replication connections are not client backends, and the poolers
+// keep their authentication connections, which cannot write
+const terminateClientConnectionsSQL = `
+SELECT pg_catalog.count(pg_catalog.pg_terminate_backend(a.pid))
+FROM pg_catalog.pg_stat_activity a
+JOIN pg_catalog.pg_roles r ON r.oid = a.usesysid
+WHERE a.backend_type = 'client backend'
+AND NOT r.rolsuper
+AND r.rolname <> '` + apiv1.PGBouncerPoolerUserName + `'
+AND a.pid <> pg_catalog.pg_backend_pid()`
+
+// switchWALSQL closes the current WAL segment, returning the location
+// the changes made before the fence are guaranteed not to exceed
+const switchWALSQL = "SELECT pg_catalog.pg_switch_wal()"
+
+// clientConnectionsFenceRetryInterval is the time after which the
+// primary checks again whether the terminated client connections are gone
+const clientConnectionsFenceRetryInterval = time.Second
+
+// reconcileClientConnectionsFence fences the client connections of the
+// primary when requested by the cluster annotation. The pg_hba.conf rules
+// rejecting the new connections are already loaded at this point, so the
+// existing ones are terminated and the reached WAL location is recorded,
+// to be reported in the instance status. It returns the time after which
+// the fence needs to be checked again, or zero if it is complete
+func (r *InstanceReconciler) reconcileClientConnectionsFence(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (time.Duration, error) {
+	if cluster.Annotations[utils.FenceClientConnectionsAnnotationName] != "true" ||
+		r.instance.GetPodName() != cluster.Status.CurrentPrimary {
+		r.instance.SetClientConnectionsFenceLSN("")
+		return 0, nil
+	}
+
+	if r.instance.GetClientConnectionsFenceLSN() != "" {
+		return 0, nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return 0, fmt.Errorf("while getting the superuser connection: %w", err)
+	}
+
+	lsn, err := fenceClientConnections(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	if lsn == "" {
+		return clientConnectionsFenceRetryInterval, nil
+	}
+
+	log.FromContext(ctx).Info("Fenced the client connections", "lsn", lsn)
+	r.instance.SetClientConnectionsFenceLSN(lsn)
+	return 0, nil
+}
+
+// fenceClientConnections terminates the existing client connections and,
+// once none of them is left, switches the WAL, returning the location
+// reached. A terminated backend may still be committing its transaction,
+// so an empty location is returned while client connections are found
+func fenceClientConnections(ctx context.Context, db *sql.DB) (types.LSN, error) {
+	var terminated int
+	if err := db.QueryRowContext(ctx, terminateClientConnectionsSQL).Scan(&terminated); err != nil {
+		return "", fmt.Errorf("while terminating the client connections: %w", err)
+	}
+	if terminated > 0 {
+		return "", nil
+	}
+
+	var lsn types.LSN
+	if err := db.QueryRowContext(ctx, switchWALSQL).Scan(&lsn); err != nil {
+		return "", fmt.Errorf("while switching the WAL: %w", err)
+	}
+
+	return lsn, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("client connections fence", func() {
+	var (
+		db     *sql.DB
+		dbMock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("switches the WAL once no client connection is left", func(ctx context.Context) {
+		dbMock.ExpectQuery(terminateClientConnectionsSQL).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		dbMock.ExpectQuery(switchWALSQL).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_switch_wal"}).AddRow("0/3000000"))
+
+		lsn, err := fenceClientConnections(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lsn).To(Equal(types.LSN("0/3000000")))
+	})
+
+	It("waits for the terminated client connections to be gone", func(ctx context.Context) {
+		dbMock.ExpectQuery(terminateClientConnectionsSQL).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		lsn, err := fenceClientConnections(ctx, db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lsn).To(BeEmpty())
+	})
+
+	It("reports the errors terminating the client connections", func(ctx context.Context) {
+		dbMock.ExpectQuery(terminateClientConnectionsSQL).WillReturnError(errors.New("boom"))
+
+		_, err := fenceClientConnections(ctx, db)
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+	})
+})
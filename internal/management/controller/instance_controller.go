@@ -34,6 +34,8 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -89,7 +91,7 @@ type shoudRequeue bool
 func (r *InstanceReconciler) Reconcile(
 	ctx context.Context,
 	_ reconcile.Request,
-) (reconcile.Result, error) {
+) (_ reconcile.Result, err error) {
 	// set up a convenient contextLog object so we don't have to type request over and over again
 	contextLogger := log.FromContext(ctx).
 		WithValues(
@@ -98,6 +100,19 @@ func (r *InstanceReconciler) Reconcile(
 			"namespace", r.instance.GetNamespaceName(),
 		)
 
+	// every reconciliation loop is traced when the OpenTelemetry
+	// export is enabled
+	ctx, span := r.telemetryExporter.StartSpan(ctx, "instance.Reconcile",
+		attribute.String("k8s.pod.name", r.instance.GetPodName()),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	// if the context has already been cancelled,
 	// trying to reconcile would just lead to misleading errors being reported
 	if err := ctx.Err(); err != nil {
@@ -153,6 +168,8 @@ func (r *InstanceReconciler) Reconcile(
 	// Reconcile monitoring section
 	r.reconcileMetrics(ctx, cluster)
 	r.reconcileMonitoringQueries(ctx, cluster)
+	r.reconcileTelemetry(ctx, cluster)
+	r.reconcileLogging(ctx, cluster)
 
 	// Verify that the promotion token is usable before changing the archive mode and triggering restarts
 	if err := r.verifyPromotionToken(cluster); err != nil {
@@ -317,6 +334,25 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	// Track the logical replication catch-up of an online import
+	onlineImportRefresh := r.reconcileOnlineImport(ctx, cluster)
+
+	// Update the extensions after the images changed, if requested
+	requeueAfter := onlineImportRefresh
+	if retryIn := r.reconcileExtensionUpdates(ctx, cluster); retryIn > 0 &&
+		(requeueAfter == 0 || retryIn < requeueAfter) {
+		requeueAfter = retryIn
+	}
+
+	// Terminate the client connections, if they have been fenced
+	retryIn, err := r.reconcileClientConnectionsFence(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot fence the client connections: %w", err)
+	}
+	if retryIn > 0 && (requeueAfter == 0 || retryIn < requeueAfter) {
+		requeueAfter = retryIn
+	}
+
 	// Reconcile postgresql.auto.conf file permissions (< PG 17)
 	// IMPORTANT: this needs a database connection to determine
 	// the PostgreSQL major version
@@ -341,7 +377,7 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *InstanceReconciler) configureSlotReplicator(cluster *apiv1.Cluster) {
@@ -391,7 +427,11 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
-	reloadIdent, err := r.instance.RefreshPGIdent(ctx, cluster.Spec.PostgresConfiguration.PgIdent)
+	reloadIdent, err := r.instance.RefreshPGIdent(
+		ctx,
+		cluster.Spec.PostgresConfiguration.PgIdent,
+		cluster.GetPoolerCertificateMappings(),
+	)
 	if err != nil {
 		return false, err
 	}
@@ -848,6 +888,40 @@ func (r *InstanceReconciler) reconcileMetrics(
 	} else {
 		exporter.Metrics.ReplicaCluster.Set(0)
 	}
+
+	var statementsConfig *apiv1.PgStatStatementsConfiguration
+	if cluster.Spec.Monitoring != nil {
+		statementsConfig = cluster.Spec.Monitoring.PgStatStatements
+	}
+	exporter.SetStatementsConfiguration(statementsConfig)
+}
+
+// reconcileTelemetry applies the OpenTelemetry configuration of the cluster
+// to the exporter pushing the metrics and the traces of this instance
+func (r *InstanceReconciler) reconcileTelemetry(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) {
+	var config *apiv1.OpenTelemetryConfiguration
+	if cluster.Spec.Monitoring != nil {
+		config = cluster.Spec.Monitoring.OpenTelemetry
+	}
+
+	if err := r.telemetryExporter.Configure(ctx, config); err != nil {
+		log.FromContext(ctx).Warning("Unable to configure the OpenTelemetry exporter",
+			"error", err.Error())
+	}
+}
+
+// reconcileLogging applies the configuration of the log sinks
+func (r *InstanceReconciler) reconcileLogging(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) {
+	if err := r.logRouter.Configure(ctx, cluster.Spec.Logging); err != nil {
+		log.FromContext(ctx).Warning("Unable to configure the log sinks",
+			"error", err.Error())
+	}
 }
 
 // reconcileMonitoringQueries applies the custom monitoring queries to the
@@ -872,6 +946,10 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 		return
 	}
 
+	if concurrency := cluster.Spec.Monitoring.CustomQueriesConcurrency; concurrency > 0 {
+		queriesCollector.SetConcurrency(int(concurrency))
+	}
+
 	for _, reference := range cluster.Spec.Monitoring.CustomQueriesConfigMap {
 		var configMap corev1.ConfigMap
 		err := r.GetClient().Get(
@@ -0,0 +1,390 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	clusterstatus "github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+)
+
+// extensionUpdateRetryInterval is the time after which a failed automatic
+// update of the extensions is retried
+const extensionUpdateRetryInterval = 5 * time.Minute
+
+// extensionUpdateCandidate is an installed extension whose version differs
+// from the default one available in the image
+type extensionUpdateCandidate struct {
+	name             string
+	installedVersion string
+	defaultVersion   string
+	requires         []string
+}
+
+// extensionUpdateRules contains the extensions managed by the Database
+// resources of the cluster, indexed by database and extension name
+type extensionUpdateRules struct {
+	defaultPolicy apiv1.ExtensionUpdatePolicy
+	extensions    map[string]map[string]apiv1.ExtensionSpec
+}
+
+// reconcileExtensionUpdates updates the extensions to the default version
+// available in the images once the cluster has been rolled out on new ones,
+// when requested by the extension update policy. It returns the time after
+// which a failed update needs to be retried, or zero if there's nothing
+// to retry
+func (r *InstanceReconciler) reconcileExtensionUpdates(ctx context.Context, cluster *apiv1.Cluster) time.Duration {
+	contextLogger := log.FromContext(ctx)
+
+	// The extensions of a replica cluster are updated in its source, and
+	// the instances must have been rolled out on the new images
+	if r.instance.GetPodName() != cluster.Status.CurrentPrimary || cluster.IsReplica() ||
+		cluster.Status.Phase != apiv1.PhaseHealthy {
+		return 0
+	}
+
+	images := cluster.GetExtensionUpdateImages()
+	previous := cluster.Status.ExtensionUpdates
+	needed, retryIn := needsExtensionUpdates(previous, images, time.Now())
+	if !needed || retryIn > 0 {
+		return retryIn
+	}
+
+	var databases apiv1.DatabaseList
+	if err := r.client.List(ctx, &databases, client.InNamespace(cluster.Namespace)); err != nil {
+		contextLogger.Error(err, "while listing the databases to update the extensions")
+		return extensionUpdateRetryInterval
+	}
+
+	rules := newExtensionUpdateRules(cluster, databases.Items)
+	if !rules.hasAutomatic() {
+		return 0
+	}
+
+	status := &apiv1.ExtensionUpdatesStatus{
+		Images:        images,
+		LastCheckTime: ptr.To(metav1.Now()),
+	}
+	if previous != nil && previous.Message != "" {
+		// Retrying a failed update: the extensions updated by the previous
+		// attempts are kept, while the failed ones are attempted again
+		for _, update := range previous.Updates {
+			if update.Error == "" {
+				status.Updates = append(status.Updates, update)
+			}
+		}
+	}
+
+	err := r.updateExtensions(ctx, rules, status)
+	if err != nil {
+		contextLogger.Error(err, "while updating the extensions")
+		status.Message = err.Error()
+	}
+
+	if err := clusterstatus.PatchWithOptimisticLock(
+		ctx,
+		r.client,
+		cluster,
+		clusterstatus.SetExtensionUpdates(status),
+	); err != nil {
+		contextLogger.Error(err, "while updating the status of the extension updates")
+	}
+
+	if status.Message != "" {
+		return extensionUpdateRetryInterval
+	}
+
+	return 0
+}
+
+// needsExtensionUpdates checks if the extensions need to be updated for
+// the given images, given the status of the previous update. A failed
+// update is needed even if the images didn't change, and the returned
+// duration is the time to wait before retrying it
+func needsExtensionUpdates(
+	previous *apiv1.ExtensionUpdatesStatus,
+	images []string,
+	now time.Time,
+) (bool, time.Duration) {
+	if previous == nil {
+		return true, 0
+	}
+
+	if previous.Message == "" {
+		return !slices.Equal(previous.Images, images), 0
+	}
+
+	if previous.LastCheckTime != nil {
+		if retryIn := previous.LastCheckTime.Add(extensionUpdateRetryInterval).Sub(now); retryIn > 0 {
+			return true, retryIn
+		}
+	}
+
+	return true, 0
+}
+
+// updateExtensions updates the extensions of every database, in
+// alphabetical order, recording the applied updates in the status
+func (r *InstanceReconciler) updateExtensions(
+	ctx context.Context,
+	rules extensionUpdateRules,
+	status *apiv1.ExtensionUpdatesStatus,
+) error {
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	databases, errs := r.getAllAccessibleDatabases(ctx, superUserDB)
+	slices.Sort(databases)
+	for _, database := range databases {
+		db, err := r.instance.ConnectionPool().Connection(database)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not connect to database %s: %w", database, err))
+			continue
+		}
+
+		updates, err := updateDatabaseExtensions(ctx, db, database, rules)
+		status.Updates = append(status.Updates, updates...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("database %s: %w", database, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// newExtensionUpdateRules builds the update rules of the extensions from
+// the policy of the cluster and the Database resources referring to it
+func newExtensionUpdateRules(cluster *apiv1.Cluster, databases []apiv1.Database) extensionUpdateRules {
+	rules := extensionUpdateRules{
+		defaultPolicy: cluster.GetExtensionUpdatePolicy(),
+		extensions:    make(map[string]map[string]apiv1.ExtensionSpec),
+	}
+
+	for _, database := range databases {
+		if database.Spec.ClusterRef.Name != cluster.Name ||
+			database.Spec.Ensure == apiv1.EnsureAbsent ||
+			!database.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		for _, extension := range database.Spec.Extensions {
+			if extension.Ensure == apiv1.EnsureAbsent {
+				continue
+			}
+			if rules.extensions[database.Spec.Name] == nil {
+				rules.extensions[database.Spec.Name] = make(map[string]apiv1.ExtensionSpec)
+			}
+			rules.extensions[database.Spec.Name][extension.Name] = extension
+		}
+	}
+
+	return rules
+}
+
+// hasAutomatic checks if any extension may be updated automatically
+func (rules extensionUpdateRules) hasAutomatic() bool {
+	if rules.defaultPolicy == apiv1.ExtensionUpdatePolicyAutomatic {
+		return true
+	}
+
+	for _, extensions := range rules.extensions {
+		for _, extension := range extensions {
+			if extension.Version == "" && extension.UpdatePolicy == apiv1.ExtensionUpdatePolicyAutomatic {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isAutomatic checks if an extension of a database needs to be updated
+// automatically. Extensions pinned to a version by a Database resource
+// are never updated automatically
+func (rules extensionUpdateRules) isAutomatic(database, extension string) bool {
+	spec, ok := rules.extensions[database][extension]
+	switch {
+	case !ok:
+		return rules.defaultPolicy == apiv1.ExtensionUpdatePolicyAutomatic
+	case spec.Version != "":
+		return false
+	case spec.UpdatePolicy != "":
+		return spec.UpdatePolicy == apiv1.ExtensionUpdatePolicyAutomatic
+	default:
+		return rules.defaultPolicy == apiv1.ExtensionUpdatePolicyAutomatic
+	}
+}
+
+// updateDatabaseExtensions updates the extensions of a database to their
+// default version, following their dependencies, and returns the list of
+// the attempted updates
+func updateDatabaseExtensions(
+	ctx context.Context,
+	db *sql.DB,
+	database string,
+	rules extensionUpdateRules,
+) ([]apiv1.ExtensionUpdate, error) {
+	contextLogger := log.FromContext(ctx)
+
+	candidates, err := getExtensionUpdateCandidates(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []apiv1.ExtensionUpdate
+	var errs []error
+	for _, candidate := range sortExtensionUpdateCandidates(candidates) {
+		if !rules.isAutomatic(database, candidate.name) {
+			continue
+		}
+
+		update := apiv1.ExtensionUpdate{
+			Database:    database,
+			Name:        candidate.name,
+			FromVersion: candidate.installedVersion,
+			ToVersion:   candidate.defaultVersion,
+		}
+		if _, err := db.ExecContext(
+			ctx,
+			fmt.Sprintf("ALTER EXTENSION %s UPDATE", pgx.Identifier{candidate.name}.Sanitize()),
+		); err != nil {
+			update.Error = err.Error()
+			errs = append(errs, fmt.Errorf("while updating extension %q: %w", candidate.name, err))
+		} else {
+			contextLogger.Info("updated extension",
+				"databaseName", database,
+				"name", candidate.name,
+				"fromVersion", candidate.installedVersion,
+				"toVersion", candidate.defaultVersion)
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, errors.Join(errs...)
+}
+
+const detectExtensionUpdateCandidatesSQL = `
+SELECT e.extname, e.extversion, a.default_version,
+  ARRAY(
+    SELECT r.extname
+    FROM pg_catalog.pg_depend d
+    JOIN pg_catalog.pg_extension r ON r.oid = d.refobjid
+    WHERE d.classid = 'pg_catalog.pg_extension'::pg_catalog.regclass
+      AND d.objid = e.oid
+      AND d.refclassid = 'pg_catalog.pg_extension'::pg_catalog.regclass
+  )
+FROM pg_catalog.pg_extension e
+JOIN pg_catalog.pg_available_extensions a ON a.name = e.extname
+WHERE a.default_version IS NOT NULL AND e.extversion <> a.default_version
+ORDER BY e.extname
+`
+
+// getExtensionUpdateCandidates gets the installed extensions of a database
+// whose version differs from the default one available in the image
+func getExtensionUpdateCandidates(ctx context.Context, db *sql.DB) ([]extensionUpdateCandidate, error) {
+	rows, err := db.QueryContext(ctx, detectExtensionUpdateCandidatesSQL)
+	if err != nil {
+		return nil, fmt.Errorf("while detecting the extensions to update: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var candidates []extensionUpdateCandidate
+	for rows.Next() {
+		var candidate extensionUpdateCandidate
+		if err := rows.Scan(
+			&candidate.name,
+			&candidate.installedVersion,
+			&candidate.defaultVersion,
+			pq.Array(&candidate.requires),
+		); err != nil {
+			return nil, fmt.Errorf("while scanning the extensions to update: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while detecting the extensions to update: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// sortExtensionUpdateCandidates sorts the extensions so that every one of
+// them is updated after the extensions it requires, keeping the
+// alphabetical order otherwise
+func sortExtensionUpdateCandidates(candidates []extensionUpdateCandidate) []extensionUpdateCandidate {
+	pending := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		pending[candidate.name] = true
+	}
+
+	isReady := func(candidate extensionUpdateCandidate) bool {
+		for _, required := range candidate.requires {
+			if pending[required] {
+				return false
+			}
+		}
+		return true
+	}
+
+	sorted := make([]extensionUpdateCandidate, 0, len(candidates))
+	for len(sorted) < len(candidates) {
+		progress := false
+		for _, candidate := range candidates {
+			if !pending[candidate.name] || !isReady(candidate) {
+				continue
+			}
+			sorted = append(sorted, candidate)
+			pending[candidate.name] = false
+			progress = true
+			break
+		}
+
+		// Extensions can't depend on each other circularly, but we never
+		// want to loop forever
+		if !progress {
+			for _, candidate := range candidates {
+				if pending[candidate.name] {
+					sorted = append(sorted, candidate)
+					pending[candidate.name] = false
+				}
+			}
+		}
+	}
+
+	return sorted
+}
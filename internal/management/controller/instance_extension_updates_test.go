@@ -0,0 +1,205 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("automatic extension updates", func() {
+	var (
+		cluster *apiv1.Cluster
+		db      *sql.DB
+		dbMock  sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	newDatabase := func(clusterName, name string, extensions ...apiv1.ExtensionSpec) apiv1.Database {
+		return apiv1.Database{
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: clusterName},
+				Name:       name,
+				Extensions: extensions,
+			},
+		}
+	}
+
+	newExtension := func(name string) apiv1.ExtensionSpec {
+		return apiv1.ExtensionSpec{DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: name}}
+	}
+
+	Context("update rules", func() {
+		It("follows the policy of the cluster by default", func() {
+			rules := newExtensionUpdateRules(cluster, nil)
+			Expect(rules.hasAutomatic()).To(BeFalse())
+			Expect(rules.isAutomatic("app", "postgis")).To(BeFalse())
+
+			cluster.Spec.PostgresConfiguration.ExtensionUpdatePolicy = apiv1.ExtensionUpdatePolicyAutomatic
+			rules = newExtensionUpdateRules(cluster, nil)
+			Expect(rules.hasAutomatic()).To(BeTrue())
+			Expect(rules.isAutomatic("app", "postgis")).To(BeTrue())
+		})
+
+		It("lets the Database resources override the policy of the cluster", func() {
+			automatic := newExtension("postgis")
+			automatic.UpdatePolicy = apiv1.ExtensionUpdatePolicyAutomatic
+			pinned := newExtension("vector")
+			pinned.Version = "0.8.0"
+
+			rules := newExtensionUpdateRules(cluster, []apiv1.Database{
+				newDatabase("cluster-example", "app", automatic, pinned, newExtension("cube")),
+				newDatabase("other-cluster", "other", newExtension("hstore")),
+			})
+			Expect(rules.hasAutomatic()).To(BeTrue())
+			Expect(rules.isAutomatic("app", "postgis")).To(BeTrue())
+			Expect(rules.isAutomatic("app", "vector")).To(BeFalse())
+			Expect(rules.isAutomatic("app", "cube")).To(BeFalse())
+			Expect(rules.isAutomatic("other", "postgis")).To(BeFalse())
+		})
+
+		It("never updates the extensions pinned to a version", func() {
+			cluster.Spec.PostgresConfiguration.ExtensionUpdatePolicy = apiv1.ExtensionUpdatePolicyAutomatic
+			manual := newExtension("postgis")
+			manual.UpdatePolicy = apiv1.ExtensionUpdatePolicyManual
+			pinned := newExtension("vector")
+			pinned.Version = "0.8.0"
+
+			rules := newExtensionUpdateRules(cluster, []apiv1.Database{
+				newDatabase("cluster-example", "app", manual, pinned),
+			})
+			Expect(rules.isAutomatic("app", "postgis")).To(BeFalse())
+			Expect(rules.isAutomatic("app", "vector")).To(BeFalse())
+			Expect(rules.isAutomatic("app", "cube")).To(BeTrue())
+		})
+	})
+
+	Context("update checks", func() {
+		images := []string{"ghcr.io/cloudnative-pg/postgresql:17.5"}
+		now := time.Now()
+
+		It("updates the extensions when no check has been done yet", func() {
+			needed, retryIn := needsExtensionUpdates(nil, images, now)
+			Expect(needed).To(BeTrue())
+			Expect(retryIn).To(BeZero())
+		})
+
+		It("doesn't update the extensions again after a successful update on the same images", func() {
+			previous := &apiv1.ExtensionUpdatesStatus{
+				Images:        images,
+				LastCheckTime: ptr.To(metav1.NewTime(now)),
+			}
+			needed, _ := needsExtensionUpdates(previous, images, now)
+			Expect(needed).To(BeFalse())
+
+			needed, retryIn := needsExtensionUpdates(previous, []string{"ghcr.io/cloudnative-pg/postgresql:17.6"}, now)
+			Expect(needed).To(BeTrue())
+			Expect(retryIn).To(BeZero())
+		})
+
+		It("retries a failed update on the same images after the retry interval", func() {
+			previous := &apiv1.ExtensionUpdatesStatus{
+				Images:        images,
+				LastCheckTime: ptr.To(metav1.NewTime(now)),
+				Message:       "update failed",
+			}
+			needed, retryIn := needsExtensionUpdates(previous, images, now.Add(time.Minute))
+			Expect(needed).To(BeTrue())
+			Expect(retryIn).To(Equal(extensionUpdateRetryInterval - time.Minute))
+
+			needed, retryIn = needsExtensionUpdates(previous, images, now.Add(extensionUpdateRetryInterval))
+			Expect(needed).To(BeTrue())
+			Expect(retryIn).To(BeZero())
+		})
+	})
+
+	It("updates the extensions after the ones they require", func() {
+		sorted := sortExtensionUpdateCandidates([]extensionUpdateCandidate{
+			{name: "earthdistance", requires: []string{"cube"}},
+			{name: "postgis"},
+			{name: "postgis_raster", requires: []string{"postgis"}},
+			{name: "postgis_topology", requires: []string{"postgis"}},
+			{name: "cube"},
+		})
+
+		names := make([]string, 0, len(sorted))
+		for _, candidate := range sorted {
+			names = append(names, candidate.name)
+		}
+		Expect(names).To(Equal([]string{"postgis", "postgis_raster", "postgis_topology", "cube", "earthdistance"}))
+	})
+
+	It("updates the extensions of a database and reports the changes", func(ctx SpecContext) {
+		cluster.Spec.PostgresConfiguration.ExtensionUpdatePolicy = apiv1.ExtensionUpdatePolicyAutomatic
+		pinned := newExtension("vector")
+		pinned.Version = "0.7.0"
+		rules := newExtensionUpdateRules(cluster, []apiv1.Database{
+			newDatabase("cluster-example", "app", pinned),
+		})
+
+		dbMock.ExpectQuery(detectExtensionUpdateCandidatesSQL).WillReturnRows(
+			sqlmock.NewRows([]string{"extname", "extversion", "default_version", "requires"}).
+				AddRow("postgis_raster", "3.4.0", "3.5.0", pq.StringArray{"postgis"}).
+				AddRow("postgis", "3.4.0", "3.5.0", pq.StringArray{}).
+				AddRow("vector", "0.7.0", "0.8.0", pq.StringArray{}))
+		dbMock.ExpectExec(`ALTER EXTENSION "postgis" UPDATE`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec(`ALTER EXTENSION "postgis_raster" UPDATE`).
+			WillReturnError(errors.New("update failed"))
+
+		updates, err := updateDatabaseExtensions(ctx, db, "app", rules)
+		Expect(err).To(HaveOccurred())
+		Expect(updates).To(Equal([]apiv1.ExtensionUpdate{
+			{Database: "app", Name: "postgis", FromVersion: "3.4.0", ToVersion: "3.5.0"},
+			{
+				Database:    "app",
+				Name:        "postgis_raster",
+				FromVersion: "3.4.0",
+				ToVersion:   "3.5.0",
+				Error:       "update failed",
+			},
+		}))
+	})
+})
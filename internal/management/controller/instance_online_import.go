@@ -0,0 +1,332 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logicalimport"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/sequences"
+	clusterstatus "github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// onlineImportRefreshInterval is the time between two refreshes of the
+// catch-up status of an online import
+const onlineImportRefreshInterval = 30 * time.Second
+
+// reconcileOnlineImport refreshes the catch-up status of an online import,
+// completing it when the cutover is requested. It returns the time after
+// which the status needs to be refreshed again, or zero if there's
+// nothing to track
+func (r *InstanceReconciler) reconcileOnlineImport(ctx context.Context, cluster *apiv1.Cluster) time.Duration {
+	contextLogger := log.FromContext(ctx)
+
+	importSpec := cluster.GetOnlineImport()
+	if importSpec == nil || r.instance.GetPodName() != cluster.Status.CurrentPrimary {
+		return 0
+	}
+
+	if cluster.Status.OnlineImport != nil &&
+		cluster.Status.OnlineImport.Phase == apiv1.OnlineImportPhaseCompleted {
+		return 0
+	}
+
+	status := &apiv1.OnlineImportStatus{Phase: apiv1.OnlineImportPhaseCatchingUp}
+	err := r.refreshOnlineImportStatus(ctx, cluster, importSpec, status)
+	if _, cutoverRequested := cluster.Annotations[utils.CompleteOnlineImportAnnotationName]; err == nil &&
+		cutoverRequested {
+		err = r.completeOnlineImport(ctx, cluster, importSpec, status)
+	}
+	if err != nil {
+		contextLogger.Error(err, "while reconciling the online import")
+		status.Message = err.Error()
+	}
+	status.LastUpdateTime = ptr.To(metav1.Now())
+
+	if err := clusterstatus.PatchWithOptimisticLock(
+		ctx,
+		r.client,
+		cluster,
+		clusterstatus.SetOnlineImport(status),
+	); err != nil {
+		contextLogger.Error(err, "while updating the online import status")
+	}
+
+	if status.Phase == apiv1.OnlineImportPhaseCompleted {
+		return 0
+	}
+
+	return onlineImportRefreshInterval
+}
+
+// refreshOnlineImportStatus reads the state of the subscriptions created by
+// the online import, enabling them the first time the cluster is running
+func (r *InstanceReconciler) refreshOnlineImportStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	importSpec *apiv1.Import,
+	status *apiv1.OnlineImportStatus,
+) error {
+	postgresDB, err := r.instance.ConnectionPool().Connection("postgres")
+	if err != nil {
+		return fmt.Errorf("while getting the postgres connection: %w", err)
+	}
+
+	version, err := r.instance.GetPgVersion()
+	if err != nil {
+		return fmt.Errorf("while getting the PostgreSQL major version: %w", err)
+	}
+
+	states, err := postgresManagement.GetSubscriptionsRuntimeState(ctx, postgresDB, int(version.Major)) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, state := range states {
+		if state.Name != logicalimport.OnlineImportSubscriptionName {
+			continue
+		}
+
+		databaseStatus := apiv1.OnlineImportDatabaseStatus{
+			Name:           state.DatabaseName,
+			SourceDatabase: importSpec.GetSourceDatabaseName(state.DatabaseName),
+			SlotName:       logicalimport.GetOnlineImportSlotName(cluster, state.DatabaseName),
+			WorkerActive:   state.WorkerActive,
+		}
+		if err := r.refreshOnlineImportDatabaseStatus(
+			ctx,
+			cluster,
+			importSpec,
+			state,
+			&databaseStatus,
+		); err != nil {
+			errs = append(errs, fmt.Errorf("database %s: %w", state.DatabaseName, err))
+		}
+		status.Databases = append(status.Databases, databaseStatus)
+	}
+
+	return errors.Join(errs...)
+}
+
+// refreshOnlineImportDatabaseStatus refreshes the catch-up status of
+// a single imported database
+func (r *InstanceReconciler) refreshOnlineImportDatabaseStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	importSpec *apiv1.Import,
+	state postgresManagement.SubscriptionRuntimeState,
+	databaseStatus *apiv1.OnlineImportDatabaseStatus,
+) error {
+	db, err := r.instance.ConnectionPool().Connection(databaseStatus.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureOnlineImportSubscriptionEnabled(ctx, db, state); err != nil {
+		return err
+	}
+
+	tables, err := getSubscriptionTablesSyncState(ctx, db, logicalimport.OnlineImportSubscriptionName)
+	if err != nil {
+		return err
+	}
+	databaseStatus.TablesNotReady = countNotReadyTables(tables)
+
+	sourceDB, err := openOnlineImportSourceDB(cluster, importSpec, databaseStatus.SourceDatabase)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = sourceDB.Close()
+	}()
+
+	databaseStatus.LagBytes, err = getOnlineImportLag(ctx, sourceDB, databaseStatus.SlotName)
+	return err
+}
+
+// completeOnlineImport executes the cutover of an online import, which
+// is expected to be requested once the writes to the source have been
+// stopped. The cutover waits until every table has been copied and
+// the lag is zero
+func (r *InstanceReconciler) completeOnlineImport(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	importSpec *apiv1.Import,
+	status *apiv1.OnlineImportStatus,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if err := checkOnlineImportCaughtUp(status); err != nil {
+		return err
+	}
+
+	for _, databaseStatus := range status.Databases {
+		db, err := r.instance.ConnectionPool().Connection(databaseStatus.Name)
+		if err != nil {
+			return err
+		}
+
+		sourceDB, err := openOnlineImportSourceDB(cluster, importSpec, databaseStatus.SourceDatabase)
+		if err != nil {
+			return err
+		}
+
+		err = completeOnlineImportDatabase(ctx, sourceDB, db, databaseStatus.SlotName)
+		_ = sourceDB.Close()
+		if err != nil {
+			return fmt.Errorf("while completing the import of database %s: %w", databaseStatus.Name, err)
+		}
+
+		contextLogger.Info("Online import completed", "databaseName", databaseStatus.Name)
+	}
+
+	status.Phase = apiv1.OnlineImportPhaseCompleted
+	status.CompletionTime = ptr.To(metav1.Now())
+	return nil
+}
+
+// checkOnlineImportCaughtUp checks if every imported database has
+// copied all of its tables and has no lag with the source, which is
+// required to complete the import without losing data
+func checkOnlineImportCaughtUp(status *apiv1.OnlineImportStatus) error {
+	for _, databaseStatus := range status.Databases {
+		if databaseStatus.TablesNotReady > 0 {
+			return fmt.Errorf(
+				"cannot complete the import of database %s: %d tables are still being copied",
+				databaseStatus.Name, databaseStatus.TablesNotReady)
+		}
+		if databaseStatus.LagBytes == nil {
+			return fmt.Errorf(
+				"cannot complete the import of database %s: the lag is unknown",
+				databaseStatus.Name)
+		}
+		if *databaseStatus.LagBytes > 0 {
+			return fmt.Errorf(
+				"cannot complete the import of database %s: the lag is %d bytes",
+				databaseStatus.Name, *databaseStatus.LagBytes)
+		}
+	}
+
+	return nil
+}
+
+// openOnlineImportSourceDB opens a connection to a database of the
+// source of an online import
+func openOnlineImportSourceDB(
+	cluster *apiv1.Cluster,
+	importSpec *apiv1.Import,
+	databaseName string,
+) (*sql.DB, error) {
+	connString, err := getSubscriptionConnectionString(cluster, importSpec.Source.ExternalCluster, databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool.NewDBConnection(connString, pool.ConnectionProfilePostgresql)
+}
+
+// ensureOnlineImportSubscriptionEnabled enables the online import
+// subscription when it is disabled. The subscriptions are created
+// disabled while bootstrapping the cluster, and they are enabled again
+// whenever they are found disabled until the import is completed
+func ensureOnlineImportSubscriptionEnabled(
+	ctx context.Context,
+	db *sql.DB,
+	state postgresManagement.SubscriptionRuntimeState,
+) error {
+	if state.Enabled {
+		return nil
+	}
+
+	return enableOnlineImportSubscription(ctx, db)
+}
+
+// enableOnlineImportSubscription enables the online import subscription,
+// starting the initial data copy
+func enableOnlineImportSubscription(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(
+		ctx,
+		fmt.Sprintf("ALTER SUBSCRIPTION %s ENABLE", pgx.Identifier{logicalimport.OnlineImportSubscriptionName}.Sanitize()),
+	); err != nil {
+		return fmt.Errorf("while enabling the online import subscription: %w", err)
+	}
+
+	return nil
+}
+
+// getOnlineImportLag returns the amount of WAL generated by the source
+// that has not been confirmed by the subscription yet, or nil if the
+// replication slot cannot be found
+func getOnlineImportLag(ctx context.Context, sourceDB *sql.DB, slotName string) (*int64, error) {
+	var lag sql.NullInt64
+	err := sourceDB.QueryRowContext(
+		ctx,
+		`SELECT pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn)::bigint
+		FROM pg_catalog.pg_replication_slots WHERE slot_name = $1`,
+		slotName,
+	).Scan(&lag)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while getting the replication slot lag: %w", err)
+	}
+	if !lag.Valid {
+		return nil, nil
+	}
+
+	return &lag.Int64, nil
+}
+
+// completeOnlineImportDatabase synchronizes the sequences of an imported
+// database with the source ones and drops the subscription, which drops
+// the replication slot too, and the publication
+func completeOnlineImportDatabase(ctx context.Context, sourceDB, db *sql.DB, slotName string) error {
+	if _, err := sequences.Synchronize(ctx, sourceDB, db, 0); err != nil {
+		return err
+	}
+
+	if err := executeDropSubscription(ctx, db, logicalimport.OnlineImportSubscriptionName); err != nil {
+		return err
+	}
+
+	if _, err := sourceDB.ExecContext(
+		ctx,
+		fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pgx.Identifier{slotName}.Sanitize()),
+	); err != nil {
+		return fmt.Errorf("while dropping the publication: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logicalimport"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/sequences"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("online import", func() {
+	const slotName = "cnpg_import_0123456789abcdef"
+
+	var (
+		sourceDB   *sql.DB
+		sourceMock sqlmock.Sqlmock
+		db         *sql.DB
+		dbMock     sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourceDB, sourceMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(sourceMock.ExpectationsWereMet()).To(Succeed())
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	lagQuery := `SELECT pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn)::bigint
+		FROM pg_catalog.pg_replication_slots WHERE slot_name = $1`
+
+	It("enables the subscription", func(ctx SpecContext) {
+		dbMock.ExpectExec(`ALTER SUBSCRIPTION "cnpg_import" ENABLE`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(enableOnlineImportSubscription(ctx, db)).To(Succeed())
+	})
+
+	It("enables the subscription whenever it is disabled", func(ctx SpecContext) {
+		Expect(ensureOnlineImportSubscriptionEnabled(ctx, db, postgresManagement.SubscriptionRuntimeState{
+			Name:    logicalimport.OnlineImportSubscriptionName,
+			Enabled: true,
+		})).To(Succeed())
+
+		dbMock.ExpectExec(`ALTER SUBSCRIPTION "cnpg_import" ENABLE`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(ensureOnlineImportSubscriptionEnabled(ctx, db, postgresManagement.SubscriptionRuntimeState{
+			Name: logicalimport.OnlineImportSubscriptionName,
+		})).To(Succeed())
+	})
+
+	It("completes the import only when every database has caught up", func() {
+		status := &apiv1.OnlineImportStatus{
+			Phase: apiv1.OnlineImportPhaseCatchingUp,
+			Databases: []apiv1.OnlineImportDatabaseStatus{
+				{Name: "app", TablesNotReady: 0, LagBytes: ptr.To[int64](0)},
+				{Name: "other", TablesNotReady: 2, LagBytes: ptr.To[int64](0)},
+			},
+		}
+		Expect(checkOnlineImportCaughtUp(status)).To(MatchError(ContainSubstring("2 tables")))
+
+		status.Databases[1].TablesNotReady = 0
+		status.Databases[1].LagBytes = ptr.To[int64](4096)
+		Expect(checkOnlineImportCaughtUp(status)).To(MatchError(ContainSubstring("4096 bytes")))
+
+		status.Databases[1].LagBytes = nil
+		Expect(checkOnlineImportCaughtUp(status)).To(MatchError(ContainSubstring("unknown")))
+
+		status.Databases[1].LagBytes = ptr.To[int64](0)
+		Expect(checkOnlineImportCaughtUp(status)).To(Succeed())
+	})
+
+	It("reads the lag of the replication slot", func(ctx SpecContext) {
+		sourceMock.ExpectQuery(lagQuery).
+			WithArgs(slotName).
+			WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(4096))
+
+		lag, err := getOnlineImportLag(ctx, sourceDB, slotName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lag).ToNot(BeNil())
+		Expect(*lag).To(BeEquivalentTo(4096))
+	})
+
+	It("reports an unknown lag when the replication slot is missing", func(ctx SpecContext) {
+		sourceMock.ExpectQuery(lagQuery).
+			WithArgs(slotName).
+			WillReturnRows(sqlmock.NewRows([]string{"lag"}))
+
+		lag, err := getOnlineImportLag(ctx, sourceDB, slotName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lag).To(BeNil())
+	})
+
+	It("synchronizes the sequences and drops the replication objects on cutover", func(ctx SpecContext) {
+		sourceMock.ExpectQuery(sequences.GetSequencesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"json_agg"}).AddRow(
+				`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":42}]`))
+		dbMock.ExpectQuery(sequences.GetSequencesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"json_agg"}).AddRow(
+				`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":1}]`))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(`SELECT pg_catalog.setval('"public"."orders_id_seq"', 42);`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+		dbMock.ExpectExec(`DROP SUBSCRIPTION IF EXISTS "cnpg_import"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		sourceMock.ExpectExec(`DROP PUBLICATION IF EXISTS "cnpg_import_0123456789abcdef"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(completeOnlineImportDatabase(ctx, sourceDB, db, slotName)).To(Succeed())
+	})
+
+	It("doesn't drop the subscription if the sequences cannot be synchronized", func(ctx SpecContext) {
+		sourceMock.ExpectQuery(sequences.GetSequencesQuery).WillReturnError(sql.ErrConnDone)
+
+		Expect(completeOnlineImportDatabase(ctx, sourceDB, db, slotName)).ToNot(Succeed())
+	})
+})
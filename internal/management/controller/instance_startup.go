@@ -34,7 +34,9 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/archiver"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // verifyPgDataCoherenceForPrimary will abort the execution if the current server is a primary
@@ -162,7 +164,7 @@ func (r *InstanceReconciler) ReconcileTablespaces(
 		return nil
 	}
 
-	for _, tbsConfig := range cluster.Spec.Tablespaces {
+	for _, tbsConfig := range cluster.GetProvisionedTablespaces() {
 		tbsName := tbsConfig.Name
 		mountPoint := specs.MountForTablespace(tbsName)
 		if tbsMount, err := fileutils.FileExists(mountPoint); err != nil {
@@ -190,6 +192,79 @@ func (r *InstanceReconciler) ReconcileTablespaces(
 				"instance", r.instance.GetPodName(), "tablespace", tbsName)
 			return fmt.Errorf("while creating data dir in tablespace %s: %w", mountPoint, err)
 		}
+
+		if tbsConfig.IsEphemeral() {
+			if err := r.ensureEphemeralTablespaceVersionDirectory(ctx, tbsName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensureEphemeralTablespaceVersionDirectory recreates the version-specific
+// directory inside the ephemeral volume of a tablespace that already exists
+// in PostgreSQL. The volume is empty every time the Pod is created, while
+// PostgreSQL expects to find that directory when creating temporary files
+// in the tablespace
+func (r *InstanceReconciler) ensureEphemeralTablespaceVersionDirectory(
+	ctx context.Context,
+	tbsName string,
+) error {
+	location := specs.LocationForTablespace(tbsName)
+	inUse, err := isTablespaceLocationInUse(r.instance.PgData, location)
+	if err != nil {
+		return fmt.Errorf("while checking if tablespace %s exists: %w", tbsName, err)
+	}
+	if !inUse {
+		// The tablespace will be created by PostgreSQL, together
+		// with its version directory
+		return nil
+	}
+
+	majorVersion, err := postgresutils.GetMajorVersionFromPgData(r.instance.PgData)
+	if err != nil {
+		return fmt.Errorf("while reading the PostgreSQL major version: %w", err)
+	}
+	controlDataOutput, err := r.instance.GetPgControldata()
+	if err != nil {
+		return err
 	}
+	catalogVersion, err := utils.ParsePgControldataOutput(controlDataOutput).GetCatalogVersionNumber()
+	if err != nil {
+		return err
+	}
+
+	versionDirectory := filepath.Join(location, fmt.Sprintf("PG_%d_%s", majorVersion, catalogVersion))
+	log.FromContext(ctx).Debug("Ensuring the version directory of the ephemeral tablespace exists",
+		"tablespace", tbsName, "directory", versionDirectory)
+	if err := fileutils.EnsureDirectoryExists(versionDirectory); err != nil {
+		return fmt.Errorf("while creating the version directory of tablespace %s: %w", tbsName, err)
+	}
+
 	return nil
 }
+
+// isTablespaceLocationInUse checks whether a tablespace in the given
+// PGDATA, as linked inside `pg_tblspc`, points to the given location
+func isTablespaceLocationInUse(pgData, location string) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(pgData, "pg_tblspc"))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(pgData, "pg_tblspc", entry.Name()))
+		if err != nil {
+			continue
+		}
+		if filepath.Clean(target) == filepath.Clean(location) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,306 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed role controller tests", func() {
+	var (
+		dbMock     sqlmock.Sqlmock
+		db         *sql.DB
+		role       *apiv1.Role
+		cluster    *apiv1.Cluster
+		r          *RoleReconciler
+		fakeClient client.Client
+		err        error
+	)
+
+	expectRolesInDB := func(roleNames ...string) {
+		rows := sqlmock.NewRows([]string{
+			"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
+			"rolcanlogin", "rolreplication", "rolconnlimit", "rolpassword", "rolvaliduntil", "rolbypassrls", "comment",
+			"xmin", "inroles",
+		})
+		for _, name := range roleNames {
+			rows.AddRow(name, false, true, false, false, true, false, -1, nil,
+				nil, false, nil, 11, []byte("{}"))
+		}
+		dbMock.ExpectQuery(regexp.QuoteMeta("SELECT rolname")).WillReturnRows(rows)
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		role = &apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "role-one",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: apiv1.RoleSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: cluster.Name,
+				},
+				RoleConfiguration: apiv1.RoleConfiguration{
+					Name:            "reader",
+					Ensure:          apiv1.EnsurePresent,
+					Login:           true,
+					ConnectionLimit: -1,
+					Inherit:         ptr.To(true),
+				},
+				ReclaimPolicy: apiv1.RoleReclaimDelete,
+			},
+		}
+		db, dbMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		pgInstance := postgres.NewInstance().
+			WithNamespace("default").
+			WithPodName("cluster-example-1").
+			WithClusterName("cluster-example")
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, role).
+			WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Role{}).
+			Build()
+
+		r = &RoleReconciler{
+			Client:           fakeClient,
+			Scheme:           schemeBuilder.BuildWithAllKnownScheme(),
+			instance:         pgInstance,
+			roleSynchronizer: roles.NewRoleSynchronizer(pgInstance, fakeClient),
+			getSuperUserDB: func() (*sql.DB, error) {
+				return db, nil
+			},
+		}
+		r.finalizerReconciler = newFinalizerReconciler(
+			fakeClient,
+			utils.RoleFinalizerName,
+			r.evaluateDropRole,
+		)
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("adds finalizer and sets status ready on success", func(ctx SpecContext) {
+		expectRolesInDB("postgres")
+		dbMock.ExpectExec(regexp.QuoteMeta(`CREATE ROLE "reader" NOBYPASSRLS NOCREATEDB NOCREATEROLE INHERIT LOGIN`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(regexp.QuoteMeta("SELECT xmin FROM pg_catalog.pg_authid WHERE rolname = $1")).
+			WithArgs("reader").
+			WillReturnRows(sqlmock.NewRows([]string{"xmin"}).AddRow("12"))
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeTrue()))
+		Expect(role.GetStatusMessage()).Should(BeEmpty())
+		Expect(role.GetFinalizers()).NotTo(BeEmpty())
+		Expect(role.Status.PasswordState).To(Equal(&apiv1.PasswordState{TransactionID: 12}))
+	})
+
+	It("doesn't change the role when it is already reconciled", func(ctx SpecContext) {
+		role.Status.PasswordState = &apiv1.PasswordState{TransactionID: 11}
+		Expect(fakeClient.Status().Update(ctx, role)).To(Succeed())
+		expectRolesInDB("postgres", "reader")
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeTrue()))
+		Expect(role.GetStatusMessage()).Should(BeEmpty())
+	})
+
+	It("drops the role on deletion when the reclaim policy is delete", func(ctx SpecContext) {
+		role.Status.PasswordState = &apiv1.PasswordState{TransactionID: 11}
+		Expect(fakeClient.Status().Update(ctx, role)).To(Succeed())
+		expectRolesInDB("postgres", "reader")
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(role.Status.Applied).Should(HaveValue(BeTrue()))
+		Expect(role.GetFinalizers()).NotTo(BeEmpty())
+
+		// The next 2 lines are a hacky bit to make sure the next reconciler
+		// call doesn't skip on account of Generation == ObservedGeneration.
+		// See fake.Client known issues with `Generation`
+		// https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/client/fake@v0.19.0#NewClientBuilder
+		role.SetGeneration(role.GetGeneration() + 1)
+		Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+		dbMock.ExpectExec(regexp.QuoteMeta(`DROP ROLE IF EXISTS "reader"`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		Expect(fakeClient.Delete(ctx, role)).To(Succeed())
+
+		err = reconcileRole(ctx, fakeClient, r, role)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("marks as failed a role which is reserved", func(ctx SpecContext) {
+		role.Spec.Name = "cnpg_pooler_pgbouncer"
+		Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(role.Status.Message).Should(Equal(errRoleIsReserved.Error()))
+	})
+
+	It("marks as failed a role whose password secret doesn't allow being referenced", func(ctx SpecContext) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-superuser", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("reader"), "password": []byte("secret")},
+		}
+		Expect(fakeClient.Create(ctx, secret)).To(Succeed())
+		role.Spec.PasswordSecret = &apiv1.LocalObjectReference{Name: secret.Name}
+		Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(role.Status.Message).Should(ContainSubstring(errDeclarativeReferenceNotAllowed.Error()))
+	})
+
+	It("marks as failed a role which is managed by the cluster", func(ctx SpecContext) {
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "reader"},
+			},
+		}
+		Expect(fakeClient.Update(ctx, cluster)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(role.Status.Message).Should(Equal(
+			fmt.Sprintf("%q is already managed by cluster %q", role.Spec.Name, cluster.Name)))
+		Expect(role.GetFinalizers()).To(BeEmpty())
+	})
+
+	It("marks as failed if the target role is already being managed", func(ctx SpecContext) {
+		// Let's force the role to have a past reconciliation
+		role.Status.ObservedGeneration = 2
+		Expect(fakeClient.Status().Update(ctx, role)).To(Succeed())
+
+		roleDuplicate := &apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "role-duplicate",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: apiv1.RoleSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: cluster.Name,
+				},
+				RoleConfiguration: apiv1.RoleConfiguration{
+					Name: "reader",
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, roleDuplicate)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, roleDuplicate)
+		Expect(err).ToNot(HaveOccurred())
+
+		expectedError := fmt.Sprintf("%q is already managed by object %q",
+			roleDuplicate.Spec.Name, role.Name)
+		Expect(roleDuplicate.Status.Applied).To(HaveValue(BeFalse()))
+		Expect(roleDuplicate.Status.Message).To(ContainSubstring(expectedError))
+	})
+
+	It("skips the roles of other clusters", func(ctx SpecContext) {
+		role.Spec.ClusterRef.Name = "cluster-other"
+		Expect(fakeClient.Update(ctx, role)).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(BeNil())
+		Expect(role.GetFinalizers()).To(BeEmpty())
+	})
+
+	It("properly signals a role is on a replica cluster", func(ctx SpecContext) {
+		initialCluster := cluster.DeepCopy()
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{
+			Enabled: ptr.To(true),
+		}
+		Expect(fakeClient.Patch(ctx, cluster, client.MergeFrom(initialCluster))).To(Succeed())
+
+		err := reconcileRole(ctx, fakeClient, r, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(role.Status.Applied).Should(BeNil())
+		Expect(role.Status.Message).Should(ContainSubstring("waiting for the cluster to become primary"))
+	})
+})
+
+func reconcileRole(
+	ctx SpecContext,
+	fakeClient client.Client,
+	r *RoleReconciler,
+	role *apiv1.Role,
+) error {
+	GinkgoT().Helper()
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+		Namespace: role.GetNamespace(),
+		Name:      role.GetName(),
+	}})
+	Expect(err).ToNot(HaveOccurred())
+	return fakeClient.Get(ctx, client.ObjectKey{
+		Namespace: role.GetNamespace(),
+		Name:      role.GetName(),
+	}, role)
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/jackc/pgx/v5/pgtype"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -126,11 +127,12 @@ func evaluateNextRoleActions(
 	contextLog := log.FromContext(ctx).WithName("roles_reconciler")
 	contextLog.Debug("evaluating role actions")
 
-	rolesInSpec := config.Roles
+	rolesInSpec := make([]apiv1.RoleConfiguration, len(config.Roles))
 	// set up a map name -> role for the spec roles
 	roleInSpecNamed := make(map[string]apiv1.RoleConfiguration)
-	for _, r := range rolesInSpec {
-		roleInSpecNamed[r.Name] = r
+	for i, r := range config.Roles {
+		rolesInSpec[i] = withPasswordExpiration(r, lastPasswordState[r.Name])
+		roleInSpecNamed[r.Name] = rolesInSpec[i]
 	}
 
 	rolesByAction := make(rolesByAction)
@@ -191,3 +193,19 @@ func evaluateNextRoleActions(
 
 	return rolesByAction
 }
+
+// withPasswordExpiration returns the role configuration to be applied in the
+// database. When the role has a password rotation policy and no explicit
+// ValidUntil, the password expiration is derived from the last rotation
+func withPasswordExpiration(
+	role apiv1.RoleConfiguration,
+	passwordState apiv1.PasswordState,
+) apiv1.RoleConfiguration {
+	if role.PasswordRotation == nil || role.ValidUntil != nil || passwordState.RotatedAt == nil {
+		return role
+	}
+
+	validUntil := metav1.NewTime(role.PasswordRotation.GetPasswordExpiration(passwordState.RotatedAt.Time))
+	role.ValidUntil = &validUntil
+	return role
+}
@@ -169,12 +169,56 @@ func (sr *RoleSynchronizer) reconcile(ctx context.Context, config *apiv1.Managed
 	}, &remoteCluster); err != nil {
 		return err
 	}
+	// the operator may have rotated some passwords in the meantime
+	for role, state := range appliedState {
+		if latestState, ok := remoteCluster.Status.ManagedRolesStatus.PasswordStatus[role]; ok {
+			state.RotatedAt = latestState.RotatedAt
+			appliedState[role] = state
+		}
+	}
 	updatedCluster := remoteCluster.DeepCopy()
 	updatedCluster.Status.ManagedRolesStatus.PasswordStatus = appliedState
 	updatedCluster.Status.ManagedRolesStatus.CannotReconcile = irreconcilableRoles
 	return sr.client.Status().Patch(ctx, updatedCluster, client.MergeFrom(&remoteCluster))
 }
 
+// ReconcileRole aligns a single role in the database with the passed
+// configuration, independently of the managed roles of the cluster.
+// This is used by the Role resources, which keep the password state
+// in their own status. It returns the password state after the
+// reconciliation, or nil if the role is not required to be present
+func (sr *RoleSynchronizer) ReconcileRole(
+	ctx context.Context,
+	db *sql.DB,
+	role apiv1.RoleConfiguration,
+	storedPasswordState *apiv1.PasswordState,
+) (*apiv1.PasswordState, error) {
+	rolePasswords := make(map[string]apiv1.PasswordState)
+	if storedPasswordState != nil {
+		rolePasswords[role.Name] = *storedPasswordState
+	}
+
+	config := &apiv1.ManagedConfiguration{Roles: []apiv1.RoleConfiguration{role}}
+	appliedState, irreconcilableRoles, err := sr.synchronizeRoles(ctx, db, config, rolePasswords)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleErrors := irreconcilableRoles[role.Name]; len(roleErrors) > 0 {
+		return nil, fmt.Errorf("cannot reconcile role %q: %s", role.Name, strings.Join(roleErrors, ", "))
+	}
+
+	if role.Ensure == apiv1.EnsureAbsent {
+		return nil, nil
+	}
+
+	passwordState, ok := appliedState[role.Name]
+	if !ok {
+		return nil, nil
+	}
+	return &passwordState, nil
+}
+
 func getRoleNames(roles []roleConfigurationAdapter) []string {
 	names := make([]string, len(roles))
 	for i, role := range roles {
@@ -214,6 +258,8 @@ func (sr *RoleSynchronizer) synchronizeRoles(
 	// Merge the status from database into spec. We should keep all the status
 	// otherwise in the next loop the user without status will be marked as need update
 	for role, stateInDatabase := range passwordStates {
+		// the rotation time is set by the operator, and must be preserved
+		stateInDatabase.RotatedAt = storedPasswordState[role].RotatedAt
 		storedPasswordState[role] = stateInDatabase
 	}
 	return storedPasswordState, irreconcilableRoles, nil
@@ -374,7 +420,9 @@ type passwordSecret struct {
 }
 
 // getPassword retrieves the password stored in the Kubernetes secret for the
-// RoleConfiguration
+// RoleConfiguration. When the role has a password rotation policy, the
+// secret keeping the previous password takes precedence, as it exists
+// only during the grace period following a rotation
 func getPassword(
 	ctx context.Context,
 	cl client.Client,
@@ -387,30 +435,40 @@ func getPassword(
 		return passwordSecret{}, nil
 	}
 
-	var secret corev1.Secret
-	err := cl.Get(ctx,
-		client.ObjectKey{Namespace: namespace, Name: secretName},
-		&secret)
-	if err != nil {
+	var secretNames []string
+	if roleInSpec.PasswordRotation != nil {
+		secretNames = append(secretNames, roleInSpec.GetPreviousPasswordSecretName())
+	}
+	secretNames = append(secretNames, secretName)
+
+	for _, name := range secretNames {
+		var secret corev1.Secret
+		err := cl.Get(ctx,
+			client.ObjectKey{Namespace: namespace, Name: name},
+			&secret)
 		if apierrs.IsNotFound(err) {
-			return passwordSecret{}, nil
+			continue
 		}
-		return passwordSecret{}, err
+		if err != nil {
+			return passwordSecret{}, err
+		}
+		usernameFromSecret, passwordFromSecret, err := utils.GetUserPasswordFromSecret(&secret)
+		if err != nil {
+			return passwordSecret{}, err
+		}
+		if strings.TrimSpace(roleInSpec.Name) != strings.TrimSpace(usernameFromSecret) {
+			err := fmt.Errorf("wrong username '%v' in secret, expected '%v'", usernameFromSecret, roleInSpec.Name)
+			return passwordSecret{}, err
+		}
+		return passwordSecret{
+				strings.TrimSpace(usernameFromSecret),
+				strings.TrimSpace(passwordFromSecret),
+				secret.GetResourceVersion(),
+			},
+			nil
 	}
-	usernameFromSecret, passwordFromSecret, err := utils.GetUserPasswordFromSecret(&secret)
-	if err != nil {
-		return passwordSecret{}, err
-	}
-	if strings.TrimSpace(roleInSpec.Name) != strings.TrimSpace(usernameFromSecret) {
-		err := fmt.Errorf("wrong username '%v' in secret, expected '%v'", usernameFromSecret, roleInSpec.Name)
-		return passwordSecret{}, err
-	}
-	return passwordSecret{
-			strings.TrimSpace(usernameFromSecret),
-			strings.TrimSpace(passwordFromSecret),
-			secret.GetResourceVersion(),
-		},
-		nil
+
+	return passwordSecret{}, nil
 }
 
 // getPasswordSecretResourceVersion returns a list of resource version of the passwords secrets for managed roles
@@ -377,6 +377,164 @@ var _ = Describe("Role synchronizer tests", func() {
 				"could not perform DELETE on role role_to_test2: owner of database edbDatabase"))
 		})
 	})
+
+	When("a single role is reconciled", func() {
+		It("creates the role and returns its password state", func(ctx context.Context) {
+			mock.ExpectExec("CREATE ROLE \"foo_bar\" NOBYPASSRLS NOCREATEDB NOCREATEROLE INHERIT " +
+				"NOLOGIN NOREPLICATION NOSUPERUSER CONNECTION LIMIT 0").
+				WillReturnResult(sqlmock.NewResult(11, 1))
+			rows := mock.NewRows([]string{"xmin"}).AddRow("12")
+			lastTransactionQuery := "SELECT xmin FROM pg_catalog.pg_authid WHERE rolname = $1"
+			mock.ExpectQuery(lastTransactionQuery).WithArgs("foo_bar").WillReturnRows(rows)
+
+			passwordState, err := roleSynchronizer.ReconcileRole(ctx, db, apiv1.RoleConfiguration{
+				Name:   "foo_bar",
+				Ensure: apiv1.EnsurePresent,
+			}, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(passwordState).To(Equal(&apiv1.PasswordState{TransactionID: 12}))
+		})
+
+		It("does nothing for an absent role which is not in the database", func(ctx context.Context) {
+			passwordState, err := roleSynchronizer.ReconcileRole(ctx, db, apiv1.RoleConfiguration{
+				Name:   "foo_bar",
+				Ensure: apiv1.EnsureAbsent,
+			}, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(passwordState).To(BeNil())
+		})
+
+		It("returns the errors preventing the reconciliation of the role", func(ctx context.Context) {
+			impossibleDeleteError := pgconn.PgError{
+				Code:   "2BP01", // 2BP01 -> dependent_objects_still_exist
+				Detail: "owner of database edbDatabase",
+			}
+			mock.ExpectExec(`DROP ROLE "role_to_test2"`).WillReturnError(&impossibleDeleteError)
+
+			passwordState, err := roleSynchronizer.ReconcileRole(ctx, db, apiv1.RoleConfiguration{
+				Name:   "role_to_test2",
+				Ensure: apiv1.EnsureAbsent,
+			}, nil)
+			Expect(err).To(MatchError(ContainSubstring("owner of database edbDatabase")))
+			Expect(passwordState).To(BeNil())
+		})
+
+		It("derives the password expiration from the last rotation", func(ctx context.Context) {
+			mock.ExpectExec("CREATE ROLE \"foo_bar\" NOBYPASSRLS NOCREATEDB NOCREATEROLE INHERIT " +
+				"NOLOGIN NOREPLICATION NOSUPERUSER CONNECTION LIMIT 0 VALID UNTIL '2100-01-31 01:00:00Z'").
+				WillReturnResult(sqlmock.NewResult(11, 1))
+			rows := mock.NewRows([]string{"xmin"}).AddRow("12")
+			lastTransactionQuery := "SELECT xmin FROM pg_catalog.pg_authid WHERE rolname = $1"
+			mock.ExpectQuery(lastTransactionQuery).WithArgs("foo_bar").WillReturnRows(rows)
+
+			rotatedAt := v1.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+			passwordState, err := roleSynchronizer.ReconcileRole(ctx, db, apiv1.RoleConfiguration{
+				Name:   "foo_bar",
+				Ensure: apiv1.EnsurePresent,
+				PasswordRotation: &apiv1.PasswordRotationPolicy{
+					Interval: v1.Duration{Duration: 720 * time.Hour},
+				},
+			}, &apiv1.PasswordState{RotatedAt: &rotatedAt})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(passwordState).To(Equal(&apiv1.PasswordState{TransactionID: 12, RotatedAt: &rotatedAt}))
+		})
+	})
+})
+
+var _ = Describe("withPasswordExpiration", func() {
+	rotatedAt := v1.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotationPolicy := &apiv1.PasswordRotationPolicy{
+		Interval:    v1.Duration{Duration: 720 * time.Hour},
+		GracePeriod: &v1.Duration{Duration: 10 * time.Minute},
+	}
+
+	It("doesn't change roles without a rotation policy", func() {
+		role := apiv1.RoleConfiguration{Name: "foo"}
+		Expect(withPasswordExpiration(role, apiv1.PasswordState{RotatedAt: &rotatedAt})).To(Equal(role))
+	})
+
+	It("doesn't change roles never rotated", func() {
+		role := apiv1.RoleConfiguration{Name: "foo", PasswordRotation: rotationPolicy}
+		Expect(withPasswordExpiration(role, apiv1.PasswordState{})).To(Equal(role))
+	})
+
+	It("keeps the ValidUntil set by the user", func() {
+		validUntil := v1.Date(2100, 6, 1, 0, 0, 0, 0, time.UTC)
+		role := apiv1.RoleConfiguration{Name: "foo", PasswordRotation: rotationPolicy, ValidUntil: &validUntil}
+		Expect(withPasswordExpiration(role, apiv1.PasswordState{RotatedAt: &rotatedAt}).ValidUntil).
+			To(Equal(&validUntil))
+	})
+
+	It("expires the password after the next rotation and its grace period", func() {
+		role := apiv1.RoleConfiguration{Name: "foo", PasswordRotation: rotationPolicy}
+		effectiveRole := withPasswordExpiration(role, apiv1.PasswordState{RotatedAt: &rotatedAt})
+		Expect(effectiveRole.ValidUntil.Time).To(Equal(time.Date(2100, 1, 31, 0, 10, 0, 0, time.UTC)))
+		Expect(role.ValidUntil).To(BeNil())
+	})
+})
+
+var _ = Describe("password rotation grace period", func() {
+	const namespace = "default"
+
+	role := apiv1.RoleConfiguration{
+		Name:           "app_user",
+		Ensure:         apiv1.EnsurePresent,
+		Login:          true,
+		PasswordSecret: &apiv1.LocalObjectReference{Name: "app-user-secret"},
+		PasswordRotation: &apiv1.PasswordRotationPolicy{
+			Interval:    v1.Duration{Duration: 720 * time.Hour},
+			GracePeriod: &v1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+
+	newSecret := func(name, username, password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte(username),
+				corev1.BasicAuthPasswordKey: []byte(password),
+			},
+		}
+	}
+
+	It("keeps the previous password during the grace period", func(ctx SpecContext) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				newSecret("app-user-secret", "app_user", "current"),
+				newSecret("app-user-secret-previous", "app_user", "previous"),
+			).
+			Build()
+
+		credentials, err := getPassword(ctx, cl, roleConfigurationAdapter{RoleConfiguration: role}, namespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials.password).To(Equal("previous"))
+	})
+
+	It("applies the new password once the grace period is over", func(ctx SpecContext) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(newSecret("app-user-secret", "app_user", "current")).
+			Build()
+
+		credentials, err := getPassword(ctx, cl, roleConfigurationAdapter{RoleConfiguration: role}, namespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials.password).To(Equal("current"))
+	})
+
+	It("ignores the previous password of the roles without a rotation policy", func(ctx SpecContext) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				newSecret("app-user-secret", "app_user", "current"),
+				newSecret("app-user-secret-previous", "app_user", "previous"),
+			).
+			Build()
+
+		notRotatedRole := role
+		notRotatedRole.PasswordRotation = nil
+		credentials, err := getPassword(
+			ctx, cl, roleConfigurationAdapter{RoleConfiguration: notRotatedRole}, namespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials.password).To(Equal("current"))
+	})
 })
 
 var _ = DescribeTable("Role status tests",
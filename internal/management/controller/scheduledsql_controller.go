@@ -0,0 +1,405 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// ScheduledSQLReconciler reconciles a ScheduledSQL object, executing
+// its statements when they are due
+type ScheduledSQLReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	instance *postgres.Instance
+	getDB    func(name string) (*sql.DB, error)
+
+	runsLock sync.Mutex
+	runs     map[types.NamespacedName][]*scheduledSQLRun
+}
+
+// scheduledSQLRun is a run of a scheduled SQL job being executed
+// by this instance
+type scheduledSQLRun struct {
+	startTime metav1.Time
+	cancel    context.CancelFunc
+}
+
+// scheduledSQLReconciliationInterval is the time between the
+// reconciliations of the scheduled SQL jobs which are not executed
+// by this instance
+const scheduledSQLReconciliationInterval = 30 * time.Second
+
+// errScheduledSQLPreviousRunActive is recorded when a run is skipped
+// because of the Forbid concurrency policy
+var errScheduledSQLPreviousRunActive = errors.New("the previous run is still in progress")
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledsqls,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledsqls/status,verbs=get;update;patch
+
+// Reconcile is the scheduled SQL reconciliation loop
+func (r *ScheduledSQLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).
+		WithName("scheduledsql_reconciler").
+		WithValues("scheduledSQLName", req.Name)
+
+	var scheduledSQL apiv1.ScheduledSQL
+	if err := r.Get(ctx, req.NamespacedName, &scheduledSQL); err != nil {
+		contextLogger.Trace("Could not fetch ScheduledSQL", "error", err)
+		if client.IgnoreNotFound(err) == nil {
+			r.cancelRuns(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This is not for me!
+	if scheduledSQL.Spec.ClusterRef.Name != r.instance.GetClusterName() {
+		contextLogger.Trace("ScheduledSQL is not for this cluster",
+			"cluster", scheduledSQL.Spec.ClusterRef.Name,
+			"expected", r.instance.GetClusterName(),
+		)
+		return ctrl.Result{}, nil
+	}
+
+	if scheduledSQL.IsSuspended() {
+		contextLogger.Debug("Skipping as the scheduled SQL job is suspended")
+		r.cancelRuns(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the Cluster from the cache
+	cluster, err := r.GetCluster(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("while fetching the cluster: %w", err)
+	}
+
+	// This is not for me, at least now. The instance in charge of the
+	// job may change after a failover or a scale operation
+	if scheduledSQL.GetExecutingInstance(cluster) != r.instance.GetPodName() {
+		return ctrl.Result{RequeueAfter: scheduledSQLReconciliationInterval}, nil
+	}
+
+	// The designated primary of a replica cluster cannot accept writes
+	if scheduledSQL.GetTarget() == apiv1.ScheduledSQLTargetPrimary && cluster.IsReplica() {
+		if err := r.recordMessage(ctx, &scheduledSQL, errClusterIsReplica); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: scheduledSQLReconciliationInterval}, nil
+	}
+
+	schedule, err := cron.Parse(scheduledSQL.Spec.Schedule)
+	if err != nil {
+		return ctrl.Result{}, r.recordMessage(ctx, &scheduledSQL,
+			fmt.Errorf("invalid schedule %q: %w", scheduledSQL.Spec.Schedule, err))
+	}
+
+	now := time.Now()
+	lastScheduleTime := scheduledSQL.CreationTimestamp.Time
+	if scheduledSQL.Status.LastScheduleTime != nil {
+		lastScheduleTime = scheduledSQL.Status.LastScheduleTime.Time
+	}
+
+	nextTime := schedule.Next(lastScheduleTime)
+	if nextTime.IsZero() {
+		return ctrl.Result{}, r.recordMessage(ctx, &scheduledSQL,
+			fmt.Errorf("no time satisfying the schedule %q has been found", scheduledSQL.Spec.Schedule))
+	}
+
+	if now.Before(nextTime) {
+		if err := r.recordNextScheduleTime(ctx, &scheduledSQL, nextTime); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	// A run is due. Missed runs are not recovered: the next one is
+	// computed starting from now
+	nextTime = schedule.Next(now)
+	if err := r.startRun(ctx, &scheduledSQL, now, nextTime); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+}
+
+// startRun records the scheduling of a new run in the status and, unless
+// the concurrency policy forbids it, starts executing it in background
+func (r *ScheduledSQLReconciler) startRun(
+	ctx context.Context,
+	scheduledSQL *apiv1.ScheduledSQL,
+	now time.Time,
+	nextTime time.Time,
+) error {
+	contextLogger := log.FromContext(ctx)
+	key := client.ObjectKeyFromObject(scheduledSQL)
+
+	r.runsLock.Lock()
+	defer r.runsLock.Unlock()
+
+	activeRuns := r.runs[key]
+	skip := len(activeRuns) > 0 && scheduledSQL.GetConcurrencyPolicy() == apiv1.ScheduledSQLConcurrencyForbid
+
+	origScheduledSQL := scheduledSQL.DeepCopy()
+	scheduledSQL.Status.LastScheduleTime = &metav1.Time{Time: now}
+	scheduledSQL.Status.NextScheduleTime = &metav1.Time{Time: nextTime}
+	scheduledSQL.Status.Message = ""
+
+	run := &scheduledSQLRun{startTime: metav1.NewTime(now).Rfc3339Copy()}
+	if skip {
+		contextLogger.Info("Skipping the scheduled SQL run, the previous one is still in progress")
+		scheduledSQL.Status.Message = fmt.Sprintf("skipped the run scheduled at %s: %s",
+			run.startTime.Format(time.RFC3339), errScheduledSQLPreviousRunActive.Error())
+	} else {
+		scheduledSQL.Status.LastRun = &apiv1.ScheduledSQLRunStatus{
+			Instance:  r.instance.GetPodName(),
+			StartTime: run.startTime,
+		}
+	}
+
+	if err := r.Status().Patch(ctx, scheduledSQL, client.MergeFrom(origScheduledSQL)); err != nil {
+		return err
+	}
+
+	if skip {
+		return nil
+	}
+
+	if scheduledSQL.GetConcurrencyPolicy() == apiv1.ScheduledSQLConcurrencyReplace {
+		for _, activeRun := range activeRuns {
+			contextLogger.Info("Canceling the previous scheduled SQL run",
+				"startTime", activeRun.startTime)
+			activeRun.cancel()
+		}
+	}
+
+	var runCtx context.Context
+	if scheduledSQL.Spec.Timeout != nil {
+		runCtx, run.cancel = context.WithTimeout(ctx, scheduledSQL.Spec.Timeout.Duration)
+	} else {
+		runCtx, run.cancel = context.WithCancel(ctx)
+	}
+
+	if r.runs == nil {
+		r.runs = make(map[types.NamespacedName][]*scheduledSQLRun)
+	}
+	r.runs[key] = append(r.runs[key], run)
+
+	contextLogger.Info("Starting the scheduled SQL run", "nextScheduleTime", nextTime)
+	go r.executeRun(ctx, runCtx, key, run, scheduledSQL.Spec.DeepCopy())
+
+	return nil
+}
+
+// executeRun executes the statements of a run and records its outcome
+func (r *ScheduledSQLReconciler) executeRun(
+	ctx context.Context,
+	runCtx context.Context,
+	key types.NamespacedName,
+	run *scheduledSQLRun,
+	spec *apiv1.ScheduledSQLSpec,
+) {
+	contextLogger := log.FromContext(ctx)
+	defer r.removeRun(key, run)
+
+	err := r.executeStatements(runCtx, spec.DBName, spec.SQL)
+	if err != nil {
+		switch {
+		case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+			err = fmt.Errorf("the run exceeded the timeout of %s: %w", spec.Timeout.Duration, err)
+		case errors.Is(runCtx.Err(), context.Canceled):
+			err = fmt.Errorf("the run has been canceled: %w", err)
+		}
+	}
+	completionTime := metav1.Now()
+
+	if err != nil {
+		contextLogger.Error(err, "Scheduled SQL run failed")
+	} else {
+		contextLogger.Info("Scheduled SQL run completed")
+	}
+
+	if recordErr := r.recordRunOutcome(ctx, key, run.startTime, completionTime, err); recordErr != nil {
+		contextLogger.Error(recordErr, "while recording the outcome of the scheduled SQL run")
+	}
+}
+
+// executeStatements executes the passed statements in order on a
+// dedicated connection to the database, stopping at the first failure
+func (r *ScheduledSQLReconciler) executeStatements(ctx context.Context, dbname string, statements []string) error {
+	db, err := r.getDB(dbname)
+	if err != nil {
+		return fmt.Errorf("while getting DB connection: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("while getting a connection to %q: %w", dbname, err)
+	}
+	defer func() {
+		// The statements may have changed the session state, i.e. with
+		// SET commands, so the connection is not given back to the pool
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		_ = conn.Close()
+	}()
+
+	for idx, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("while executing statement #%d: %w", idx+1, err)
+		}
+	}
+
+	return nil
+}
+
+// recordRunOutcome records the outcome of a run in the status, unless
+// the run has been superseded by a newer one
+func (r *ScheduledSQLReconciler) recordRunOutcome(
+	ctx context.Context,
+	key types.NamespacedName,
+	startTime metav1.Time,
+	completionTime metav1.Time,
+	runErr error,
+) error {
+	var scheduledSQL apiv1.ScheduledSQL
+	if err := r.Get(ctx, key, &scheduledSQL); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	lastRun := scheduledSQL.Status.LastRun
+	if lastRun == nil || lastRun.Instance != r.instance.GetPodName() || !lastRun.StartTime.Equal(&startTime) {
+		return nil
+	}
+
+	origScheduledSQL := scheduledSQL.DeepCopy()
+	lastRun.CompletionTime = &completionTime
+	lastRun.Duration = &metav1.Duration{Duration: completionTime.Sub(startTime.Time)}
+	lastRun.Error = ""
+	if runErr != nil {
+		lastRun.Error = runErr.Error()
+	} else {
+		scheduledSQL.Status.LastSuccessfulTime = &completionTime
+	}
+
+	return r.Status().Patch(ctx, &scheduledSQL, client.MergeFrom(origScheduledSQL))
+}
+
+// recordMessage records why the job cannot be scheduled
+func (r *ScheduledSQLReconciler) recordMessage(
+	ctx context.Context,
+	scheduledSQL *apiv1.ScheduledSQL,
+	err error,
+) error {
+	if scheduledSQL.Status.Message == err.Error() {
+		return nil
+	}
+
+	origScheduledSQL := scheduledSQL.DeepCopy()
+	scheduledSQL.SetAsFailed(err)
+	return r.Status().Patch(ctx, scheduledSQL, client.MergeFrom(origScheduledSQL))
+}
+
+// recordNextScheduleTime records the next time the job will be scheduled
+func (r *ScheduledSQLReconciler) recordNextScheduleTime(
+	ctx context.Context,
+	scheduledSQL *apiv1.ScheduledSQL,
+	nextTime time.Time,
+) error {
+	next := metav1.NewTime(nextTime).Rfc3339Copy()
+	if scheduledSQL.Status.NextScheduleTime.Equal(&next) && scheduledSQL.Status.Message == "" {
+		return nil
+	}
+
+	origScheduledSQL := scheduledSQL.DeepCopy()
+	scheduledSQL.Status.NextScheduleTime = &next
+	scheduledSQL.Status.Message = ""
+	return r.Status().Patch(ctx, scheduledSQL, client.MergeFrom(origScheduledSQL))
+}
+
+// removeRun forgets a completed run
+func (r *ScheduledSQLReconciler) removeRun(key types.NamespacedName, run *scheduledSQLRun) {
+	r.runsLock.Lock()
+	defer r.runsLock.Unlock()
+
+	run.cancel()
+	r.runs[key] = slices.DeleteFunc(r.runs[key], func(item *scheduledSQLRun) bool {
+		return item == run
+	})
+	if len(r.runs[key]) == 0 {
+		delete(r.runs, key)
+	}
+}
+
+// cancelRuns cancels every active run of a scheduled SQL job
+func (r *ScheduledSQLReconciler) cancelRuns(key types.NamespacedName) {
+	r.runsLock.Lock()
+	defer r.runsLock.Unlock()
+
+	for _, run := range r.runs[key] {
+		run.cancel()
+	}
+}
+
+// NewScheduledSQLReconciler creates a new scheduled SQL reconciler
+func NewScheduledSQLReconciler(
+	mgr manager.Manager,
+	instance *postgres.Instance,
+) *ScheduledSQLReconciler {
+	return &ScheduledSQLReconciler{
+		Client:   mgr.GetClient(),
+		instance: instance,
+		getDB: func(name string) (*sql.DB, error) {
+			return instance.ConnectionPool().Connection(name)
+		},
+		runs: make(map[types.NamespacedName][]*scheduledSQLRun),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ScheduledSQLReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.ScheduledSQL{}).
+		Named("instance-scheduledsql").
+		Complete(r)
+}
+
+// GetCluster gets the managed cluster through the client
+func (r *ScheduledSQLReconciler) GetCluster(ctx context.Context) (*apiv1.Cluster, error) {
+	return getClusterFromInstance(ctx, r.Client, r.instance)
+}
@@ -0,0 +1,290 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduledSQL controller tests", func() {
+	var (
+		dbMock       sqlmock.Sqlmock
+		db           *sql.DB
+		scheduledSQL *apiv1.ScheduledSQL
+		cluster      *apiv1.Cluster
+		r            *ScheduledSQLReconciler
+		fakeClient   client.Client
+		err          error
+	)
+
+	buildReconciler := func(podName string) {
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, scheduledSQL).
+			WithStatusSubresource(&apiv1.Cluster{}, &apiv1.ScheduledSQL{}).
+			Build()
+
+		r = &ScheduledSQLReconciler{
+			Client: fakeClient,
+			Scheme: schemeBuilder.BuildWithAllKnownScheme(),
+			instance: postgres.NewInstance().
+				WithNamespace("default").
+				WithPodName(podName).
+				WithClusterName("cluster-example"),
+			getDB: func(_ string) (*sql.DB, error) {
+				return db, nil
+			},
+			runs: make(map[types.NamespacedName][]*scheduledSQLRun),
+		}
+	}
+
+	reconcile := func(ctx SpecContext) ctrl.Result {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: scheduledSQL.Namespace,
+			Name:      scheduledSQL.Name,
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	getScheduledSQL := func(ctx SpecContext) *apiv1.ScheduledSQL {
+		var updated apiv1.ScheduledSQL
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(scheduledSQL), &updated)).To(Succeed())
+		return &updated
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstancesStatus: map[apiv1.PodStatus][]string{
+					apiv1.PodHealthy: {"cluster-example-1", "cluster-example-2"},
+				},
+			},
+		}
+		scheduledSQL = &apiv1.ScheduledSQL{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "partitions",
+				Namespace:         "default",
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: apiv1.ScheduledSQLSpec{
+				ClusterRef: corev1.LocalObjectReference{
+					Name: cluster.Name,
+				},
+				DBName:   "app",
+				Schedule: "0 0 * * * *",
+				SQL:      []string{"SELECT create_partitions()", "ANALYZE"},
+			},
+		}
+		db, dbMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("skips the jobs of other clusters", func(ctx SpecContext) {
+		scheduledSQL.Spec.ClusterRef.Name = "another-cluster"
+		buildReconciler("cluster-example-1")
+
+		Expect(reconcile(ctx)).To(BeZero())
+		Expect(getScheduledSQL(ctx).Status).To(BeZero())
+	})
+
+	It("skips the jobs executed by another instance", func(ctx SpecContext) {
+		buildReconciler("cluster-example-2")
+
+		Expect(reconcile(ctx).RequeueAfter).To(Equal(scheduledSQLReconciliationInterval))
+		Expect(getScheduledSQL(ctx).Status).To(BeZero())
+	})
+
+	It("waits for the next schedule time", func(ctx SpecContext) {
+		buildReconciler("cluster-example-1")
+
+		result := reconcile(ctx)
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+
+		status := getScheduledSQL(ctx).Status
+		Expect(status.NextScheduleTime).ToNot(BeNil())
+		Expect(status.LastRun).To(BeNil())
+	})
+
+	It("reports an invalid schedule", func(ctx SpecContext) {
+		scheduledSQL.Spec.Schedule = "not a schedule"
+		buildReconciler("cluster-example-1")
+
+		Expect(reconcile(ctx)).To(BeZero())
+		Expect(getScheduledSQL(ctx).Status.Message).To(ContainSubstring("invalid schedule"))
+	})
+
+	It("executes the statements when they are due", func(ctx SpecContext) {
+		scheduledSQL.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		buildReconciler("cluster-example-1")
+
+		dbMock.ExpectExec("SELECT create_partitions()").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ANALYZE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		result := reconcile(ctx)
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		Eventually(func(g Gomega) {
+			status := getScheduledSQL(ctx).Status
+			g.Expect(status.LastRun).ToNot(BeNil())
+			g.Expect(status.LastRun.CompletionTime).ToNot(BeNil())
+			g.Expect(status.LastRun.Instance).To(Equal("cluster-example-1"))
+			g.Expect(status.LastRun.Error).To(BeEmpty())
+			g.Expect(status.LastSuccessfulTime).ToNot(BeNil())
+			g.Expect(status.NextScheduleTime.After(status.LastScheduleTime.Time)).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	It("executes the statements on the replica", func(ctx SpecContext) {
+		scheduledSQL.Spec.Target = apiv1.ScheduledSQLTargetReplica
+		scheduledSQL.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		buildReconciler("cluster-example-2")
+
+		dbMock.ExpectExec("SELECT create_partitions()").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ANALYZE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		reconcile(ctx)
+
+		Eventually(func(g Gomega) {
+			status := getScheduledSQL(ctx).Status
+			g.Expect(status.LastRun).ToNot(BeNil())
+			g.Expect(status.LastRun.CompletionTime).ToNot(BeNil())
+			g.Expect(status.LastRun.Instance).To(Equal("cluster-example-2"))
+		}).Should(Succeed())
+	})
+
+	It("records the error of a failed run", func(ctx SpecContext) {
+		scheduledSQL.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		buildReconciler("cluster-example-1")
+
+		dbMock.ExpectExec("SELECT create_partitions()").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ANALYZE").WillReturnError(errors.New("kaboom"))
+
+		reconcile(ctx)
+
+		Eventually(func(g Gomega) {
+			status := getScheduledSQL(ctx).Status
+			g.Expect(status.LastRun).ToNot(BeNil())
+			g.Expect(status.LastRun.CompletionTime).ToNot(BeNil())
+			g.Expect(status.LastRun.Error).To(ContainSubstring("statement #2"))
+			g.Expect(status.LastRun.Error).To(ContainSubstring("kaboom"))
+			g.Expect(status.LastSuccessfulTime).To(BeNil())
+		}).Should(Succeed())
+	})
+
+	It("skips the run when the previous one is still in progress", func(ctx SpecContext) {
+		scheduledSQL.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		buildReconciler("cluster-example-1")
+		r.runs[client.ObjectKeyFromObject(scheduledSQL)] = []*scheduledSQLRun{
+			{cancel: func() {}},
+		}
+
+		reconcile(ctx)
+
+		status := getScheduledSQL(ctx).Status
+		Expect(status.LastRun).To(BeNil())
+		Expect(status.Message).To(ContainSubstring(errScheduledSQLPreviousRunActive.Error()))
+	})
+
+	It("cancels the run in progress when suspended", func(ctx SpecContext) {
+		scheduledSQL.Spec.Suspend = ptr.To(true)
+		buildReconciler("cluster-example-1")
+		canceled := false
+		r.runs[client.ObjectKeyFromObject(scheduledSQL)] = []*scheduledSQLRun{
+			{cancel: func() { canceled = true }},
+		}
+
+		Expect(reconcile(ctx)).To(BeZero())
+		Expect(canceled).To(BeTrue())
+	})
+
+	It("records the cancellation of a run", func(ctx SpecContext) {
+		startTime := metav1.NewTime(time.Now()).Rfc3339Copy()
+		scheduledSQL.Status.LastRun = &apiv1.ScheduledSQLRunStatus{
+			Instance:  "cluster-example-1",
+			StartTime: startTime,
+		}
+		buildReconciler("cluster-example-1")
+
+		runCtx, cancel := context.WithCancel(ctx)
+		run := &scheduledSQLRun{startTime: startTime, cancel: cancel}
+		key := client.ObjectKeyFromObject(scheduledSQL)
+		r.runs[key] = []*scheduledSQLRun{run}
+		cancel()
+
+		r.executeRun(ctx, runCtx, key, run, scheduledSQL.Spec.DeepCopy())
+
+		status := getScheduledSQL(ctx).Status
+		Expect(status.LastRun.CompletionTime).ToNot(BeNil())
+		Expect(status.LastRun.Error).To(ContainSubstring("the run has been canceled"))
+		Expect(r.runs).To(BeEmpty())
+	})
+
+	It("cancels the previous run when replacing it", func(ctx SpecContext) {
+		scheduledSQL.Spec.ConcurrencyPolicy = apiv1.ScheduledSQLConcurrencyReplace
+		scheduledSQL.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		buildReconciler("cluster-example-1")
+		canceled := false
+		r.runs[client.ObjectKeyFromObject(scheduledSQL)] = []*scheduledSQLRun{
+			{cancel: func() { canceled = true }},
+		}
+
+		dbMock.ExpectExec("SELECT create_partitions()").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("ANALYZE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		reconcile(ctx)
+		Expect(canceled).To(BeTrue())
+
+		Eventually(func(g Gomega) {
+			status := getScheduledSQL(ctx).Status
+			g.Expect(status.LastRun).ToNot(BeNil())
+			g.Expect(status.LastRun.CompletionTime).ToNot(BeNil())
+		}).Should(Succeed())
+	})
+})
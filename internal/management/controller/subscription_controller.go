@@ -28,12 +28,15 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -45,6 +48,7 @@ type SubscriptionReconciler struct {
 	instance                *postgres.Instance
 	finalizerReconciler     *finalizerReconciler[*apiv1.Subscription]
 	getDB                   func(name string) (*sql.DB, error)
+	openExternalDB          func(connString string) (*sql.DB, error)
 	getPostgresMajorVersion func() (int, error)
 }
 
@@ -80,11 +84,6 @@ func (r *SubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
-	// If everything is reconciled, we're done here
-	if subscription.Generation == subscription.Status.ObservedGeneration {
-		return ctrl.Result{}, nil
-	}
-
 	// Fetch the Cluster from the cache
 	cluster, err := r.GetCluster(ctx)
 	if err != nil {
@@ -101,6 +100,25 @@ func (r *SubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
 	}
 
+	// If everything is reconciled, we only need to refresh the
+	// runtime status of the subscription
+	if subscription.Generation == subscription.Status.ObservedGeneration {
+		if cluster.IsReplica() || !subscription.GetDeletionTimestamp().IsZero() {
+			return ctrl.Result{}, nil
+		}
+		if err := r.refreshRuntimeStatus(ctx, &subscription); err != nil {
+			contextLogger.Error(err, "while refreshing the subscription runtime status")
+		}
+		nextSequenceSync, err := r.reconcileSequenceSync(ctx, cluster, &subscription)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if nextSequenceSync > 0 && nextSequenceSync < subscriptionReconciliationInterval {
+			return ctrl.Result{RequeueAfter: nextSequenceSync}, nil
+		}
+		return ctrl.Result{RequeueAfter: subscriptionReconciliationInterval}, nil
+	}
+
 	contextLogger.Info("Reconciling subscription")
 	defer func() {
 		contextLogger.Info("Reconciliation loop of subscription exited")
@@ -191,6 +209,9 @@ func NewSubscriptionReconciler(
 		getDB: func(name string) (*sql.DB, error) {
 			return instance.ConnectionPool().Connection(name)
 		},
+		openExternalDB: func(connString string) (*sql.DB, error) {
+			return pool.NewDBConnection(connString, pool.ConnectionProfilePostgresql)
+		},
 		getPostgresMajorVersion: func() (int, error) {
 			version, err := instance.GetPgVersion()
 			return int(version.Major), err //nolint:gosec
@@ -208,7 +229,13 @@ func NewSubscriptionReconciler(
 // SetupWithManager sets up the controller with the Manager
 func (r *SubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&apiv1.Subscription{}).
+		// The runtime status of an active subscription changes at every
+		// refresh, which is driven by the requeue interval. The annotations
+		// are watched to synchronize the sequences on demand
+		For(&apiv1.Subscription{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+		))).
 		Named("instance-subscription").
 		Complete(r)
 }
@@ -0,0 +1,135 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/sequences"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileSequenceSync synchronizes the sequences of a subscription with
+// the ones of the "publisher" when requested via the sync sequences
+// annotation or when the configured interval has elapsed.
+// It returns the time after which the next scheduled synchronization is
+// due, or zero if none is scheduled
+func (r *SubscriptionReconciler) reconcileSequenceSync(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	obj *apiv1.Subscription,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+
+	request, ok := obj.Annotations[utils.SubscriptionSyncSequencesAnnotationName]
+	requested := ok && (obj.Status.SequenceSync == nil || obj.Status.SequenceSync.LastRequest != request)
+	if nextSync, scheduled := getNextSequenceSync(obj, time.Now()); !requested && (!scheduled || nextSync > 0) {
+		return nextSync, nil
+	}
+
+	origSubscription := obj.DeepCopy()
+	if obj.Status.SequenceSync == nil {
+		obj.Status.SequenceSync = &apiv1.SubscriptionSequenceSyncStatus{}
+	}
+
+	updatedSequences, err := r.synchronizeSequences(ctx, cluster, obj)
+	if err != nil {
+		contextLogger.Error(err, "while synchronizing the sequences of the subscription")
+		obj.Status.SequenceSync.Error = err.Error()
+	} else {
+		contextLogger.Info("Sequences synchronized", "updatedSequences", updatedSequences)
+		obj.Status.SequenceSync.Error = ""
+		obj.Status.SequenceSync.LastSyncTime = ptr.To(metav1.Now())
+		obj.Status.SequenceSync.UpdatedSequences = updatedSequences
+		if requested {
+			obj.Status.SequenceSync.LastRequest = request
+		}
+	}
+
+	if err := r.Client.Status().Patch(ctx, obj, client.MergeFrom(origSubscription)); err != nil {
+		return 0, err
+	}
+
+	nextSync, _ := getNextSequenceSync(obj, time.Now())
+	return nextSync, nil
+}
+
+// synchronizeSequences updates the sequences of the subscription database
+// with the values they have in the "publisher", returning the number of
+// updated sequences
+func (r *SubscriptionReconciler) synchronizeSequences(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	obj *apiv1.Subscription,
+) (int, error) {
+	connString, err := getSubscriptionConnectionString(
+		cluster,
+		obj.Spec.ExternalClusterName,
+		obj.Spec.PublicationDBName,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	sourceDB, err := r.openExternalDB(connString)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = sourceDB.Close()
+	}()
+
+	db, err := r.getDB(obj.Spec.DBName)
+	if err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	if obj.Spec.SequenceSync != nil {
+		offset = obj.Spec.SequenceSync.Offset
+	}
+
+	return sequences.Synchronize(ctx, sourceDB, db, offset)
+}
+
+// getNextSequenceSync returns the time remaining before the next scheduled
+// synchronization of the sequences of a subscription, which is zero if the
+// synchronization is already due. The boolean is false when the
+// subscription has no scheduled synchronization
+func getNextSequenceSync(obj *apiv1.Subscription, now time.Time) (time.Duration, bool) {
+	if obj.Spec.SequenceSync == nil || obj.Spec.SequenceSync.Interval == nil ||
+		obj.Spec.SequenceSync.Interval.Duration <= 0 {
+		return 0, false
+	}
+
+	if obj.Status.SequenceSync == nil || obj.Status.SequenceSync.LastSyncTime == nil {
+		return 0, true
+	}
+
+	nextSyncTime := obj.Status.SequenceSync.LastSyncTime.Add(obj.Spec.SequenceSync.Interval.Duration)
+	return max(nextSyncTime.Sub(now), 0), true
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("subscription sequences synchronization schedule", func() {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newSubscription := func(interval *metav1.Duration, lastSyncTime *metav1.Time) *apiv1.Subscription {
+		subscription := &apiv1.Subscription{}
+		if interval != nil {
+			subscription.Spec.SequenceSync = &apiv1.SubscriptionSequenceSyncConfiguration{
+				Interval: interval,
+			}
+		}
+		if lastSyncTime != nil {
+			subscription.Status.SequenceSync = &apiv1.SubscriptionSequenceSyncStatus{
+				LastSyncTime: lastSyncTime,
+			}
+		}
+		return subscription
+	}
+
+	It("doesn't schedule any synchronization without an interval", func() {
+		_, scheduled := getNextSequenceSync(newSubscription(nil, nil), now)
+		Expect(scheduled).To(BeFalse())
+
+		_, scheduled = getNextSequenceSync(newSubscription(&metav1.Duration{}, nil), now)
+		Expect(scheduled).To(BeFalse())
+	})
+
+	It("requires a synchronization if it never happened", func() {
+		nextSync, scheduled := getNextSequenceSync(
+			newSubscription(&metav1.Duration{Duration: time.Hour}, nil), now)
+		Expect(scheduled).To(BeTrue())
+		Expect(nextSync).To(BeZero())
+	})
+
+	It("schedules the next synchronization after the interval", func() {
+		nextSync, scheduled := getNextSequenceSync(newSubscription(
+			&metav1.Duration{Duration: time.Hour},
+			&metav1.Time{Time: now.Add(-20 * time.Minute)},
+		), now)
+		Expect(scheduled).To(BeTrue())
+		Expect(nextSync).To(Equal(40 * time.Minute))
+	})
+
+	It("requires a synchronization when the interval has elapsed", func() {
+		nextSync, scheduled := getNextSequenceSync(newSubscription(
+			&metav1.Duration{Duration: time.Hour},
+			&metav1.Time{Time: now.Add(-2 * time.Hour)},
+		), now)
+		Expect(scheduled).To(BeTrue())
+		Expect(nextSync).To(BeZero())
+	})
+})
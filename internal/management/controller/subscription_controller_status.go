@@ -0,0 +1,216 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// refreshRuntimeStatus updates the runtime status and the Replicating
+// condition of a subscription, reading the state of the logical
+// replication from PostgreSQL
+func (r *SubscriptionReconciler) refreshRuntimeStatus(
+	ctx context.Context,
+	obj *apiv1.Subscription,
+) error {
+	db, err := r.getDB(obj.Spec.DBName)
+	if err != nil {
+		return fmt.Errorf("while getting DB connection: %w", err)
+	}
+
+	version, err := r.getPostgresMajorVersion()
+	if err != nil {
+		return fmt.Errorf("while getting the PostgreSQL major version: %w", err)
+	}
+
+	states, err := postgres.GetSubscriptionsRuntimeState(ctx, db, version)
+	if err != nil {
+		return err
+	}
+
+	var runtimeStatus *apiv1.SubscriptionRuntimeStatus
+	for _, state := range states {
+		if state.Name != obj.Spec.Name || state.DatabaseName != obj.Spec.DBName {
+			continue
+		}
+
+		tables, err := getSubscriptionTablesSyncState(ctx, db, obj.Spec.Name)
+		if err != nil {
+			return err
+		}
+		runtimeStatus = toSubscriptionRuntimeStatus(state, tables)
+		break
+	}
+
+	origSubscription := obj.DeepCopy()
+	obj.Status.Runtime = runtimeStatus
+	meta.SetStatusCondition(&obj.Status.Conditions, getSubscriptionReplicatingCondition(obj))
+	if equality.Semantic.DeepEqual(origSubscription.Status, obj.Status) {
+		return nil
+	}
+
+	return r.Client.Status().Patch(ctx, obj, client.MergeFrom(origSubscription))
+}
+
+// getSubscriptionTablesSyncState returns the synchronization state of the
+// tables replicated by a subscription, indexed by their qualified name.
+// The passed connection must be open on the database of the subscription
+func getSubscriptionTablesSyncState(
+	ctx context.Context,
+	db *sql.DB,
+	subscriptionName string,
+) (map[string]apiv1.SubscriptionTableSyncState, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT n.nspname, c.relname, sr.srsubstate
+		FROM pg_catalog.pg_subscription_rel sr
+		JOIN pg_catalog.pg_subscription s ON s.oid = sr.srsubid
+		JOIN pg_catalog.pg_class c ON c.oid = sr.srrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_catalog.pg_database d ON d.oid = s.subdbid
+		WHERE s.subname = $1 AND d.datname = pg_catalog.current_database()`,
+		subscriptionName)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the subscription tables state: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result map[string]apiv1.SubscriptionTableSyncState
+	for rows.Next() {
+		var schemaName, tableName, state string
+		if err := rows.Scan(&schemaName, &tableName, &state); err != nil {
+			return nil, fmt.Errorf("while scanning the subscription tables state: %w", err)
+		}
+		if result == nil {
+			result = make(map[string]apiv1.SubscriptionTableSyncState)
+		}
+		result[schemaName+"."+tableName] = toSubscriptionTableSyncState(state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while getting the subscription tables state: %w", err)
+	}
+
+	return result, nil
+}
+
+// toSubscriptionTableSyncState converts the state code stored in
+// `pg_subscription_rel` to its API representation
+func toSubscriptionTableSyncState(code string) apiv1.SubscriptionTableSyncState {
+	switch code {
+	case "i":
+		return apiv1.SubscriptionTableSyncStateInit
+	case "d":
+		return apiv1.SubscriptionTableSyncStateDataCopy
+	case "f":
+		return apiv1.SubscriptionTableSyncStateFinishedCopy
+	case "s":
+		return apiv1.SubscriptionTableSyncStateSynchronized
+	case "r":
+		return apiv1.SubscriptionTableSyncStateReady
+	default:
+		return apiv1.SubscriptionTableSyncStateUnknown
+	}
+}
+
+func toSubscriptionRuntimeStatus(
+	state postgres.SubscriptionRuntimeState,
+	tables map[string]apiv1.SubscriptionTableSyncState,
+) *apiv1.SubscriptionRuntimeStatus {
+	result := &apiv1.SubscriptionRuntimeStatus{
+		Enabled:         state.Enabled,
+		WorkerActive:    state.WorkerActive,
+		ReceivedLSN:     state.ReceivedLSN,
+		ApplyErrorCount: state.ApplyErrorCount,
+		SyncErrorCount:  state.SyncErrorCount,
+		Tables:          tables,
+	}
+	if state.ApplyLag != nil {
+		// we don't need sub-second precision, and we avoid
+		// updating the status at every reconciliation loop
+		// when the subscription is idle
+		result.ApplyLag = &metav1.Duration{Duration: state.ApplyLag.Round(time.Second)}
+	}
+
+	return result
+}
+
+// getSubscriptionReplicatingCondition computes the Replicating condition
+// of a subscription from its runtime status
+func getSubscriptionReplicatingCondition(obj *apiv1.Subscription) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               string(apiv1.ConditionSubscriptionReplicating),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: obj.Generation,
+	}
+
+	runtimeStatus := obj.Status.Runtime
+	switch {
+	case runtimeStatus == nil:
+		condition.Reason = string(apiv1.SubscriptionReasonNotFound)
+		condition.Message = fmt.Sprintf("Subscription %q not found in database %q",
+			obj.Spec.Name, obj.Spec.DBName)
+
+	case !runtimeStatus.Enabled:
+		condition.Reason = string(apiv1.SubscriptionReasonDisabled)
+		condition.Message = "The subscription is disabled"
+
+	case !runtimeStatus.WorkerActive:
+		condition.Reason = string(apiv1.SubscriptionReasonWorkerNotRunning)
+		condition.Message = fmt.Sprintf(
+			"The apply worker is not running (apply errors: %d, sync errors: %d)",
+			runtimeStatus.ApplyErrorCount, runtimeStatus.SyncErrorCount)
+
+	case countNotReadyTables(runtimeStatus.Tables) > 0:
+		condition.Reason = string(apiv1.SubscriptionReasonSynchronizing)
+		condition.Message = fmt.Sprintf("%d of %d tables are being synchronized",
+			countNotReadyTables(runtimeStatus.Tables), len(runtimeStatus.Tables))
+
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.SubscriptionReasonReplicating)
+		condition.Message = "The apply worker is running"
+	}
+
+	return condition
+}
+
+func countNotReadyTables(tables map[string]apiv1.SubscriptionTableSyncState) int {
+	result := 0
+	for _, state := range tables {
+		if state != apiv1.SubscriptionTableSyncStateReady {
+			result++
+		}
+	}
+	return result
+}
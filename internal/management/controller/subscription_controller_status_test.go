@@ -0,0 +1,154 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("subscription runtime status", func() {
+	var (
+		db     *sql.DB
+		dbMock sqlmock.Sqlmock
+		err    error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reads the synchronization state of the tables", func(ctx SpecContext) {
+		dbMock.ExpectQuery("FROM pg_catalog.pg_subscription_rel").WithArgs("sub").
+			WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname", "srsubstate"}).
+				AddRow("public", "orders", "r").
+				AddRow("public", "customers", "d").
+				AddRow("sales", "items", "x"))
+
+		tables, err := getSubscriptionTablesSyncState(ctx, db, "sub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tables).To(Equal(map[string]apiv1.SubscriptionTableSyncState{
+			"public.orders":    apiv1.SubscriptionTableSyncStateReady,
+			"public.customers": apiv1.SubscriptionTableSyncStateDataCopy,
+			"sales.items":      apiv1.SubscriptionTableSyncStateUnknown,
+		}))
+	})
+
+	It("returns no tables when the subscription has none", func(ctx SpecContext) {
+		dbMock.ExpectQuery("FROM pg_catalog.pg_subscription_rel").WithArgs("sub").
+			WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname", "srsubstate"}))
+
+		tables, err := getSubscriptionTablesSyncState(ctx, db, "sub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tables).To(BeNil())
+	})
+
+	It("rounds the apply lag to the second", func() {
+		status := toSubscriptionRuntimeStatus(postgres.SubscriptionRuntimeState{
+			Enabled:      true,
+			WorkerActive: true,
+			ReceivedLSN:  "0/3000060",
+			ApplyLag:     ptr.To(1600 * time.Millisecond),
+		}, nil)
+		Expect(status.ApplyLag).To(HaveValue(Equal(metav1.Duration{Duration: 2 * time.Second})))
+		Expect(status.ReceivedLSN).To(Equal("0/3000060"))
+	})
+})
+
+var _ = Describe("subscription Replicating condition", func() {
+	var subscription *apiv1.Subscription
+
+	BeforeEach(func() {
+		subscription = &apiv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Spec:       apiv1.SubscriptionSpec{Name: "sub", DBName: "app"},
+		}
+	})
+
+	DescribeTable("computes the condition from the runtime status",
+		func(runtimeStatus *apiv1.SubscriptionRuntimeStatus, status metav1.ConditionStatus, reason apiv1.ConditionReason) {
+			subscription.Status.Runtime = runtimeStatus
+			condition := getSubscriptionReplicatingCondition(subscription)
+			Expect(condition.Type).To(Equal(string(apiv1.ConditionSubscriptionReplicating)))
+			Expect(condition.Status).To(Equal(status))
+			Expect(condition.Reason).To(Equal(string(reason)))
+			Expect(condition.ObservedGeneration).To(BeEquivalentTo(3))
+		},
+		Entry("subscription not found",
+			nil, metav1.ConditionFalse, apiv1.SubscriptionReasonNotFound),
+		Entry("disabled subscription",
+			&apiv1.SubscriptionRuntimeStatus{}, metav1.ConditionFalse, apiv1.SubscriptionReasonDisabled),
+		Entry("apply worker not running",
+			&apiv1.SubscriptionRuntimeStatus{Enabled: true, ApplyErrorCount: 2},
+			metav1.ConditionFalse, apiv1.SubscriptionReasonWorkerNotRunning),
+		Entry("tables being synchronized",
+			&apiv1.SubscriptionRuntimeStatus{
+				Enabled:      true,
+				WorkerActive: true,
+				Tables: map[string]apiv1.SubscriptionTableSyncState{
+					"public.orders":    apiv1.SubscriptionTableSyncStateReady,
+					"public.customers": apiv1.SubscriptionTableSyncStateDataCopy,
+				},
+			},
+			metav1.ConditionFalse, apiv1.SubscriptionReasonSynchronizing),
+		Entry("replicating",
+			&apiv1.SubscriptionRuntimeStatus{
+				Enabled:      true,
+				WorkerActive: true,
+				Tables: map[string]apiv1.SubscriptionTableSyncState{
+					"public.orders": apiv1.SubscriptionTableSyncStateReady,
+				},
+			},
+			metav1.ConditionTrue, apiv1.SubscriptionReasonReplicating),
+	)
+
+	It("reports the number of tables being synchronized", func() {
+		subscription.Status.Runtime = &apiv1.SubscriptionRuntimeStatus{
+			Enabled:      true,
+			WorkerActive: true,
+			Tables: map[string]apiv1.SubscriptionTableSyncState{
+				"public.a": apiv1.SubscriptionTableSyncStateReady,
+				"public.b": apiv1.SubscriptionTableSyncStateInit,
+				"public.c": apiv1.SubscriptionTableSyncStateSynchronized,
+			},
+		}
+		meta.SetStatusCondition(&subscription.Status.Conditions, getSubscriptionReplicatingCondition(subscription))
+		condition := meta.FindStatusCondition(subscription.Status.Conditions,
+			string(apiv1.ConditionSubscriptionReplicating))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Message).To(Equal("2 of 3 tables are being synchronized"))
+	})
+})
@@ -371,6 +371,96 @@ var _ = Describe("Managed subscription controller tests", func() {
 		Expect(subDuplicate.Status.Message).Should(ContainSubstring(expectedError))
 	})
 
+	It("refreshes the runtime status of an already reconciled subscription", func(ctx SpecContext) {
+		subscription.Status.ObservedGeneration = subscription.Generation
+		subscription.Status.Applied = ptr.To(true)
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		// the runtime status queries depend on the PostgreSQL version,
+		// we only match their relevant part
+		db, dbMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		dbMock.ExpectQuery("FROM pg_catalog.pg_subscription s").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"subname", "datname", "subenabled", "worker_active", "received_lsn",
+				"apply_lag", "apply_error_count", "sync_error_count",
+			}).AddRow("sub-one", "app", true, true, "0/3000060", 0.2, 1, 0))
+		dbMock.ExpectQuery("FROM pg_catalog.pg_subscription_rel").WithArgs(subscription.Spec.Name).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname", "srsubstate"}).
+				AddRow("public", "orders", "r"))
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: subscription.GetNamespace(),
+			Name:      subscription.GetName(),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(subscriptionReconciliationInterval))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(subscription), subscription)).To(Succeed())
+		Expect(subscription.Status.Applied).To(HaveValue(BeTrue()))
+		Expect(subscription.Status.Runtime).ToNot(BeNil())
+		Expect(subscription.Status.Runtime.WorkerActive).To(BeTrue())
+		Expect(subscription.Status.Runtime.ReceivedLSN).To(Equal("0/3000060"))
+		Expect(subscription.Status.Runtime.ApplyErrorCount).To(BeEquivalentTo(1))
+		Expect(subscription.Status.Runtime.Tables).To(HaveKeyWithValue(
+			"public.orders", apiv1.SubscriptionTableSyncStateReady))
+		Expect(subscription.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", string(apiv1.ConditionSubscriptionReplicating)),
+			HaveField("Status", metav1.ConditionTrue),
+		)))
+	})
+
+	It("synchronizes the sequences when requested via annotation", func(ctx SpecContext) {
+		subscription.Annotations = map[string]string{
+			utils.SubscriptionSyncSequencesAnnotationName: "2026-01-01T00:00:00Z",
+		}
+		Expect(fakeClient.Update(ctx, subscription)).To(Succeed())
+		subscription.Status.ObservedGeneration = subscription.Generation
+		subscription.Status.Applied = ptr.To(true)
+		Expect(fakeClient.Status().Update(ctx, subscription)).To(Succeed())
+
+		db, dbMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		sourceDB, sourceDBMock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		r.openExternalDB = func(externalConnString string) (*sql.DB, error) {
+			Expect(externalConnString).To(Equal(connString))
+			return sourceDB, nil
+		}
+
+		dbMock.ExpectQuery("FROM pg_catalog.pg_subscription s").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"subname", "datname", "subenabled", "worker_active", "received_lsn",
+				"apply_lag", "apply_error_count", "sync_error_count",
+			}))
+		sourceDBMock.ExpectQuery("FROM pg_catalog.pg_sequences").
+			WillReturnRows(sqlmock.NewRows([]string{"json_agg"}).AddRow(
+				`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":42}]`))
+		dbMock.ExpectQuery("FROM pg_catalog.pg_sequences").
+			WillReturnRows(sqlmock.NewRows([]string{"json_agg"}).AddRow(
+				`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":1}]`))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec("SELECT pg_catalog.setval").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		sourceDBMock.ExpectClose()
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: subscription.GetNamespace(),
+			Name:      subscription.GetName(),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(subscriptionReconciliationInterval))
+		Expect(sourceDBMock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(subscription), subscription)).To(Succeed())
+		Expect(subscription.Status.SequenceSync).ToNot(BeNil())
+		Expect(subscription.Status.SequenceSync.LastSyncTime).ToNot(BeNil())
+		Expect(subscription.Status.SequenceSync.UpdatedSequences).To(Equal(1))
+		Expect(subscription.Status.SequenceSync.LastRequest).To(Equal("2026-01-01T00:00:00Z"))
+		Expect(subscription.Status.SequenceSync.Error).To(BeEmpty())
+	})
+
 	It("properly signals a subscription is on a replica cluster", func(ctx SpecContext) {
 		initialCluster := cluster.DeepCopy()
 		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{
@@ -132,3 +132,54 @@ func (r *noopTablespaceAction) execute(
 		State: apiv1.TablespaceStatusReconciled,
 	}
 }
+
+type dropTablespaceAction struct {
+	tablespace apiv1.TablespaceConfiguration
+}
+
+func (r *dropTablespaceAction) execute(
+	ctx context.Context,
+	db *sql.DB,
+	_ tablespaceStorageManager,
+) apiv1.TablespaceState {
+	contextLog := log.FromContext(ctx).WithName("tbs_drop_reconciler")
+
+	contextLog.Trace("dropping tablespace ", "tablespace", r.tablespace.Name)
+	tablespace := infrastructure.Tablespace{
+		Name:  r.tablespace.Name,
+		Owner: r.tablespace.Owner.Name,
+	}
+	if err := infrastructure.Drop(ctx, db, tablespace); err != nil {
+		contextLog.Error(
+			err, "while performing action",
+			"tablespace", r.tablespace.Name)
+		return apiv1.TablespaceState{
+			Name:  r.tablespace.Name,
+			Owner: r.tablespace.Owner.Name,
+			State: apiv1.TablespaceStatusPendingReconciliation,
+			Error: err.Error(),
+		}
+	}
+
+	return apiv1.TablespaceState{
+		Name:  r.tablespace.Name,
+		Owner: r.tablespace.Owner.Name,
+		State: apiv1.TablespaceStatusDropped,
+	}
+}
+
+type noopDroppedTablespaceAction struct {
+	tablespace apiv1.TablespaceConfiguration
+}
+
+func (r *noopDroppedTablespaceAction) execute(
+	_ context.Context,
+	_ *sql.DB,
+	_ tablespaceStorageManager,
+) apiv1.TablespaceState {
+	return apiv1.TablespaceState{
+		Name:  r.tablespace.Name,
+		Owner: r.tablespace.Owner.Name,
+		State: apiv1.TablespaceStatusDropped,
+	}
+}
@@ -35,6 +35,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -57,6 +58,21 @@ func (mst mockTablespaceStorageManager) getStorageLocation(tablespaceName string
 	return fmt.Sprintf("/%s", tablespaceName)
 }
 
+type fakePooler struct {
+	db *sql.DB
+}
+
+func (f fakePooler) Connection(_ string) (*sql.DB, error) {
+	return f.db, nil
+}
+
+func (f fakePooler) GetDsn(dbName string) string {
+	return dbName
+}
+
+func (f fakePooler) ShutdownConnections() {
+}
+
 type fakeInstance struct {
 	*postgres.Instance
 	db *sql.DB
@@ -66,6 +82,10 @@ func (f fakeInstance) GetSuperUserDB() (*sql.DB, error) {
 	return f.db, nil
 }
 
+func (f fakeInstance) ConnectionPool() pool.Pooler {
+	return fakePooler{db: f.db}
+}
+
 func (f fakeInstance) CanCheckReadiness() bool {
 	return true
 }
@@ -91,6 +111,16 @@ const (
 		"LOCATION '%s'"
 
 	expectedUpdateStmt = "ALTER TABLESPACE \"%s\" OWNER TO \"%s\""
+
+	expectedDropStmt = "DROP TABLESPACE \"%s\""
+
+	expectedRelationStmt = `
+		SELECT c.relkind, COALESCE(t.spcname, '')
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_tablespace t ON t.oid = c.reltablespace
+		WHERE n.nspname = $1 AND c.relname = $2
+		`
 )
 
 func getCluster(ctx context.Context, c client.Client, cluster *apiv1.Cluster) (*apiv1.Cluster, error) {
@@ -366,5 +396,160 @@ var _ = Describe("Tablespace synchronizer tests", func() {
 				},
 			})
 		})
+
+		It("will drop a tablespace marked as absent", func(ctx context.Context) {
+			assertTablespaceReconciled(ctx, tablespaceTest{
+				tablespacesInSpec: []apiv1.TablespaceConfiguration{
+					{
+						Name: "foo",
+						Storage: apiv1.StorageConfiguration{
+							Size: "1Gi",
+						},
+						Owner: apiv1.DatabaseRoleRef{
+							Name: "app",
+						},
+						Ensure: apiv1.EnsureAbsent,
+					},
+				},
+				postgresExpectations: func(mock sqlmock.Sqlmock) {
+					rows := sqlmock.NewRows(
+						[]string{"spcname", "rolname"}).
+						AddRow("foo", "app")
+					mock.ExpectQuery(expectedListStmt).WithArgs("pg_").WillReturnRows(rows)
+					stmt := fmt.Sprintf(expectedDropStmt, "foo")
+					mock.ExpectExec(stmt).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				},
+				shouldRequeue: false,
+				expectedTablespaceStatus: []apiv1.TablespaceState{
+					{
+						Name:  "foo",
+						Owner: "app",
+						State: "dropped",
+					},
+				},
+			})
+		})
+
+		It("will report an absent tablespace missing from the DB as dropped", func(ctx context.Context) {
+			assertTablespaceReconciled(ctx, tablespaceTest{
+				tablespacesInSpec: []apiv1.TablespaceConfiguration{
+					{
+						Name: "foo",
+						Storage: apiv1.StorageConfiguration{
+							Size: "1Gi",
+						},
+						Ensure: apiv1.EnsureAbsent,
+					},
+				},
+				postgresExpectations: func(mock sqlmock.Sqlmock) {
+					rows := sqlmock.NewRows(
+						[]string{"spcname", "rolname"})
+					mock.ExpectQuery(expectedListStmt).WithArgs("pg_").WillReturnRows(rows)
+				},
+				shouldRequeue: false,
+				expectedTablespaceStatus: []apiv1.TablespaceState{
+					{
+						Name:  "foo",
+						State: "dropped",
+					},
+				},
+			})
+		})
+
+		It("will requeue the drop of a tablespace that is not empty", func(ctx context.Context) {
+			assertTablespaceReconciled(ctx, tablespaceTest{
+				tablespacesInSpec: []apiv1.TablespaceConfiguration{
+					{
+						Name: "foo",
+						Storage: apiv1.StorageConfiguration{
+							Size: "1Gi",
+						},
+						Ensure: apiv1.EnsureAbsent,
+					},
+				},
+				postgresExpectations: func(mock sqlmock.Sqlmock) {
+					rows := sqlmock.NewRows(
+						[]string{"spcname", "rolname"}).
+						AddRow("foo", "")
+					mock.ExpectQuery(expectedListStmt).WithArgs("pg_").WillReturnRows(rows)
+					stmt := fmt.Sprintf(expectedDropStmt, "foo")
+					mock.ExpectExec(stmt).
+						WillReturnError(errors.New(`tablespace "foo" is not empty`))
+				},
+				shouldRequeue: true,
+				expectedTablespaceStatus: []apiv1.TablespaceState{
+					{
+						Name:  "foo",
+						State: "pending",
+						Error: `while dropping tablespace foo: tablespace "foo" is not empty`,
+					},
+				},
+			})
+		})
+
+		It("will move the declared relations into the tablespace", func(ctx context.Context) {
+			assertTablespaceReconciled(ctx, tablespaceTest{
+				tablespacesInSpec: []apiv1.TablespaceConfiguration{
+					{
+						Name: "foo",
+						Storage: apiv1.StorageConfiguration{
+							Size: "1Gi",
+						},
+						Owner: apiv1.DatabaseRoleRef{
+							Name: "app",
+						},
+						Relations: []apiv1.TablespaceRelation{
+							{Database: "app", Name: "orders"},
+							{Database: "app", Schema: "sales", Name: "customers_idx"},
+						},
+					},
+				},
+				postgresExpectations: func(mock sqlmock.Sqlmock) {
+					rows := sqlmock.NewRows(
+						[]string{"spcname", "rolname"}).
+						AddRow("foo", "app")
+					mock.ExpectQuery(expectedListStmt).WithArgs("pg_").WillReturnRows(rows)
+
+					// the table is already in the tablespace
+					mock.ExpectQuery(expectedRelationStmt).WithArgs("public", "orders").
+						WillReturnRows(sqlmock.NewRows([]string{"relkind", "spcname"}).AddRow("r", "foo"))
+
+					// the index is moved, but the lock can't be acquired
+					mock.ExpectQuery(expectedRelationStmt).WithArgs("sales", "customers_idx").
+						WillReturnRows(sqlmock.NewRows([]string{"relkind", "spcname"}).AddRow("i", ""))
+					mock.ExpectBegin()
+					mock.ExpectExec("SET LOCAL lock_timeout TO '5000ms'").
+						WillReturnResult(sqlmock.NewResult(0, 0))
+					mock.ExpectExec(`ALTER INDEX "sales"."customers_idx" SET TABLESPACE "foo"`).
+						WillReturnError(errors.New("canceling statement due to lock timeout"))
+					mock.ExpectRollback()
+				},
+				shouldRequeue: true,
+				expectedTablespaceStatus: []apiv1.TablespaceState{
+					{
+						Name:  "foo",
+						Owner: "app",
+						State: "pending",
+						Error: "some relations have not been moved yet",
+						Relations: []apiv1.TablespaceRelationState{
+							{
+								Database: "app",
+								Schema:   "public",
+								Name:     "orders",
+								Moved:    true,
+							},
+							{
+								Database: "app",
+								Schema:   "sales",
+								Name:     "customers_idx",
+								Error: "while moving relation sales.customers_idx into tablespace foo: " +
+									"canceling statement due to lock timeout",
+							},
+						},
+					},
+				},
+			})
+		})
 	})
 })
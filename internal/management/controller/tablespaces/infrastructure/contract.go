@@ -27,3 +27,12 @@ type Tablespace struct {
 	// Owner is the owner of this tablespace
 	Owner string `json:"owner"`
 }
+
+// Relation identifies a relation to be stored in a tablespace
+type Relation struct {
+	// Schema is the schema containing the relation
+	Schema string `json:"schema"`
+
+	// Name is the name of the relation
+	Name string `json:"name"`
+}
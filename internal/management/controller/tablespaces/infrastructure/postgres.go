@@ -22,7 +22,9 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/jackc/pgx/v5"
@@ -128,3 +130,110 @@ func Update(ctx context.Context, db *sql.DB, tbs Tablespace) error {
 	}
 	return nil
 }
+
+// Drop the tablespace from the database. PostgreSQL refuses to drop
+// a tablespace that still contains objects
+func Drop(ctx context.Context, db *sql.DB, tbs Tablespace) error {
+	contextLog := log.FromContext(ctx).WithName("tbs_reconciler_drop")
+
+	contextLog.Info("Dropping tablespace", "tablespace", tbs)
+	if _, err := db.ExecContext(
+		ctx,
+		fmt.Sprintf("DROP TABLESPACE %s", pgx.Identifier{tbs.Name}.Sanitize()),
+	); err != nil {
+		return fmt.Errorf("while dropping tablespace %s: %w", tbs.Name, err)
+	}
+	return nil
+}
+
+// MoveRelation moves a table, an index or a materialized view into the
+// tablespace, unless it's already stored there. The lock timeout prevents
+// the move from blocking the applications for too long when the relation
+// is in use, and it returns true if the relation is stored in the tablespace
+func MoveRelation(
+	ctx context.Context,
+	db *sql.DB,
+	tablespace string,
+	relation Relation,
+	lockTimeout time.Duration,
+) (bool, error) {
+	contextLog := log.FromContext(ctx).WithName("tbs_reconciler_move")
+	wrapErr := func(err error) error {
+		return fmt.Errorf("while moving relation %s.%s into tablespace %s: %w",
+			relation.Schema, relation.Name, tablespace, err)
+	}
+
+	var relKind, currentTablespace string
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT c.relkind, COALESCE(t.spcname, '')
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_tablespace t ON t.oid = c.reltablespace
+		WHERE n.nspname = $1 AND c.relname = $2
+		`,
+		relation.Schema,
+		relation.Name,
+	)
+	if err := row.Scan(&relKind, &currentTablespace); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, wrapErr(errors.New("relation not found"))
+		}
+		return false, wrapErr(err)
+	}
+
+	if currentTablespace == tablespace {
+		return true, nil
+	}
+
+	var objectType string
+	switch relKind {
+	case "r", "p":
+		objectType = "TABLE"
+	case "i", "I":
+		objectType = "INDEX"
+	case "m":
+		objectType = "MATERIALIZED VIEW"
+	default:
+		return false, wrapErr(fmt.Errorf("unsupported relation kind %q", relKind))
+	}
+
+	contextLog.Info("Moving relation into tablespace",
+		"schema", relation.Schema,
+		"relation", relation.Name,
+		"tablespace", tablespace)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, wrapErr(err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf("SET LOCAL lock_timeout TO '%dms'", lockTimeout.Milliseconds()),
+	); err != nil {
+		return false, wrapErr(err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			"ALTER %s %s SET TABLESPACE %s",
+			objectType,
+			pgx.Identifier{relation.Schema, relation.Name}.Sanitize(),
+			pgx.Identifier{tablespace}.Sanitize(),
+		),
+	); err != nil {
+		return false, wrapErr(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, wrapErr(err)
+	}
+
+	return true, nil
+}
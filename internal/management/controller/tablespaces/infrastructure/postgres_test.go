@@ -109,4 +109,13 @@ var _ = Describe("Postgres tablespaces functions test", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(mock.ExpectationsWereMet()).To(Succeed())
 	})
+	It("should issue the expected command to drop a tablespace", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectExec(`DROP TABLESPACE "atablespace"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = Drop(ctx, db, Tablespace{Name: "atablespace"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
 })
@@ -29,6 +29,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 )
 
 // instanceInterface represents the behavior required for the reconciler for
@@ -40,6 +41,7 @@ type instanceInterface interface {
 	IsPrimary() (bool, error)
 	IsReady() error
 	CanCheckReadiness() bool
+	ConnectionPool() pool.Pooler
 }
 
 // TablespaceReconciler is a Kubernetes controller that ensures Tablespaces
@@ -112,6 +112,22 @@ func (r *TablespaceReconciler) reconcile(
 		steps,
 	)
 
+	// move the declared relations into the tablespaces that are ready
+	for idx, tbsConfig := range cluster.Spec.Tablespaces {
+		if len(tbsConfig.Relations) == 0 || result[idx].State != apiv1.TablespaceStatusReconciled {
+			continue
+		}
+
+		result[idx].Relations = r.moveRelations(ctx, tbsConfig)
+		for _, relation := range result[idx].Relations {
+			if !relation.Moved {
+				result[idx].State = apiv1.TablespaceStatusPendingReconciliation
+				result[idx].Error = "some relations have not been moved yet"
+				break
+			}
+		}
+	}
+
 	// update the cluster status
 	updatedCluster := cluster.DeepCopy()
 	updatedCluster.Status.TablespacesStatus = result
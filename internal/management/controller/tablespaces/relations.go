@@ -0,0 +1,83 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tablespaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/tablespaces/infrastructure"
+)
+
+// relationMoveLockTimeout is the maximum time a relation move waits for
+// the locks it needs, so that it doesn't queue the application queries
+// touching the relation for too long
+const relationMoveLockTimeout = 5 * time.Second
+
+// moveRelations moves the relations declared in the tablespace configuration
+// into the tablespace, returning the state of each relation
+func (r *TablespaceReconciler) moveRelations(
+	ctx context.Context,
+	tbsConfig apiv1.TablespaceConfiguration,
+) []apiv1.TablespaceRelationState {
+	contextLog := log.FromContext(ctx).WithName("tbs_reconciler_relations")
+
+	result := make([]apiv1.TablespaceRelationState, len(tbsConfig.Relations))
+	for idx, relation := range tbsConfig.Relations {
+		schema := relation.Schema
+		if schema == "" {
+			schema = "public"
+		}
+		result[idx] = apiv1.TablespaceRelationState{
+			Database: relation.Database,
+			Schema:   schema,
+			Name:     relation.Name,
+		}
+
+		db, err := r.instance.ConnectionPool().Connection(relation.Database)
+		if err != nil {
+			result[idx].Error = err.Error()
+			continue
+		}
+
+		moved, err := infrastructure.MoveRelation(
+			ctx,
+			db,
+			tbsConfig.Name,
+			infrastructure.Relation{Schema: schema, Name: relation.Name},
+			relationMoveLockTimeout,
+		)
+		if err != nil {
+			contextLog.Warning("could not move relation into tablespace, will retry",
+				"tablespace", tbsConfig.Name,
+				"database", relation.Database,
+				"schema", schema,
+				"relation", relation.Name,
+				"err", err)
+			result[idx].Error = err.Error()
+		}
+		result[idx].Moved = moved
+	}
+
+	return result
+}
@@ -44,12 +44,22 @@ func evaluateNextSteps(
 		tbsInDBNamed[tbs.Name] = tablespaceInDBSlice[idx]
 	}
 
-	// we go through all the tablespaces in spec and create them if missing in DB
-	// NOTE: we do not at the moment support Dropping tablespaces
+	// we go through all the tablespaces in spec and create them if missing in DB,
+	// or drop them if they are marked as absent
 	for idx, tbsInSpec := range tablespaceInSpecSlice {
 		dbTablespace, isTbsInDB := tbsInDBNamed[tbsInSpec.Name]
 
 		switch {
+		case tbsInSpec.Ensure == apiv1.EnsureAbsent && isTbsInDB:
+			result[idx] = &dropTablespaceAction{
+				tablespace: tbsInSpec,
+			}
+
+		case tbsInSpec.Ensure == apiv1.EnsureAbsent:
+			result[idx] = &noopDroppedTablespaceAction{
+				tablespace: tbsInSpec,
+			}
+
 		case !isTbsInDB:
 			result[idx] = &createTablespaceAction{
 				tablespace: tbsInSpec,
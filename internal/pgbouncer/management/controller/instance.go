@@ -20,11 +20,11 @@ SPDX-License-Identifier: Apache-2.0
 package controller
 
 import (
-	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"sync"
 
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	"k8s.io/client-go/util/retry"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
@@ -40,31 +40,51 @@ type PgBouncerInstanceInterface interface {
 	Reload() error
 }
 
-// NewPgBouncerInstance initializes a new pgBouncerInstance
-func NewPgBouncerInstance() PgBouncerInstanceInterface {
+// NewPgBouncerInstance initializes a new pgBouncerInstance, sending the
+// administrative commands to every PgBouncer process whose Unix socket
+// directory is returned by socketDirs
+func NewPgBouncerInstance(socketDirs func() (string, []string)) PgBouncerInstanceInterface {
+	return &pgBouncerInstance{
+		mu:               &sync.RWMutex{},
+		paused:           false,
+		pausedSocketDirs: stringset.New(),
+		socketDirs:       socketDirs,
+		pools:            make(map[string]pool.Pooler),
+		newPool:          newPgBouncerConnectionPool,
+	}
+}
+
+// newPgBouncerConnectionPool creates the connection pool used to connect
+// to the PgBouncer process listening in the passed Unix socket directory
+func newPgBouncerConnectionPool(socketDir string) pool.Pooler {
 	dsn := fmt.Sprintf(
 		"host=%s port=%v user=%s sslmode=disable",
-		config.PgBouncerSocketDir,
+		socketDir,
 		config.PgBouncerPort,
 		config.PgBouncerAdminUser,
 	)
 
-	return &pgBouncerInstance{
-		mu:     &sync.RWMutex{},
-		paused: false,
-		pool:   pool.NewPgbouncerConnectionPool(dsn),
-	}
+	return pool.NewPgbouncerConnectionPool(dsn)
 }
 
 type pgBouncerInstance struct {
-	// The following two fields are used to keep track of
-	// pgbouncer being paused or not
-	mu     *sync.RWMutex
-	paused bool
-
-	// This is the connection pool used to connect to pgbouncer
-	// using the administrative user and the administrative database
-	pool pool.Pooler
+	// The following fields are used to keep track of pgbouncer
+	// being paused or not, and of the PgBouncer processes
+	// that have been paused
+	mu               *sync.RWMutex
+	paused           bool
+	pausedSocketDirs *stringset.Data
+
+	// socketDirs returns the Unix socket directories of the
+	// running PgBouncer processes, starting from the one
+	// accepting new connections
+	socketDirs func() (string, []string)
+
+	// These are the connection pools used to connect to every
+	// PgBouncer process using the administrative user and the
+	// administrative database, indexed by Unix socket directory
+	pools   map[string]pool.Pooler
+	newPool func(socketDir string) pool.Pooler
 }
 
 // Paused returns whether the pgbouncerInstance is paused or not, thread safe
@@ -74,75 +94,123 @@ func (p *pgBouncerInstance) Paused() bool {
 	return p.paused
 }
 
-// Pause the instance, thread safe
+// Pause pauses every running PgBouncer process, thread safe
 func (p *pgBouncerInstance) Pause() error {
-	// First step: connect to the pgbouncer administrative database
-	db, err := p.pool.Connection("pgbouncer")
-	if err != nil {
-		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
-	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// Second step: pause pgbouncer
-	//
-	// We are retrying the PAUSE query since we need to wait for
-	// pgbouncer to be really up and the user could have created
-	// a pooler which is paused from the start.
-	err = retry.OnError(retry.DefaultBackoff, func(err error) bool {
-		if errors.Is(err, os.ErrNotExist) {
+	socketDirs := p.runningSocketDirs()
+	for _, socketDir := range socketDirs {
+		if p.pausedSocketDirs.Has(socketDir) {
+			continue
+		}
+
+		// We are retrying the PAUSE query since we need to wait for
+		// pgbouncer to be really up and the user could have created
+		// a pooler which is paused from the start.
+		err := retry.OnError(retry.DefaultBackoff, func(error) bool {
 			return true
+		}, func() error {
+			return p.exec(socketDir, "PAUSE")
+		})
+		if err != nil && p.isRunning(socketDir) {
+			return err
 		}
-		return true
-	}, func() error {
-		_, err = db.Exec("PAUSE")
-		return err
-	})
-	if err != nil {
-		return err
+
+		p.pausedSocketDirs.Put(socketDir)
 	}
 
-	// Third step: keep track of pgbouncer being paused
-	p.mu.Lock()
-	defer p.mu.Unlock()
 	p.paused = true
-
 	return nil
 }
 
-// Resume the instance, thread safe
+// Resume resumes every paused PgBouncer process, thread safe
 func (p *pgBouncerInstance) Resume() error {
-	// First step: connect to the pgbouncer administrative database
-	db, err := p.pool.Connection("pgbouncer")
-	if err != nil {
-		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
-	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// Second step: resume pgbouncer
-	_, err = db.Exec("RESUME")
-	if err != nil {
-		return fmt.Errorf("while resuming instance: %w", err)
+	for _, socketDir := range p.runningSocketDirs() {
+		if !p.pausedSocketDirs.Has(socketDir) {
+			continue
+		}
+
+		if err := p.exec(socketDir, "RESUME"); err != nil && p.isRunning(socketDir) {
+			return fmt.Errorf("while resuming instance: %w", err)
+		}
+
+		p.pausedSocketDirs.Delete(socketDir)
 	}
 
-	// Third step: keep track of pgbouncer being resumed
+	p.paused = false
+	return nil
+}
+
+// Reload issues a RELOAD command to every running PgBouncer process,
+// returning any error, thread safe
+func (p *pgBouncerInstance) Reload() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.paused = false
+
+	for _, socketDir := range p.runningSocketDirs() {
+		if err := p.exec(socketDir, "RELOAD"); err != nil && p.isRunning(socketDir) {
+			return fmt.Errorf("while reloading configuration: %w", err)
+		}
+	}
 
 	return nil
 }
 
-// Reload issues a RELOAD command to the PgBouncer instance, returning any error
-func (p *pgBouncerInstance) Reload() error {
-	// First step: connect to the pgbouncer administrative database
-	db, err := p.pool.Connection("pgbouncer")
-	if err != nil {
-		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
+// runningSocketDirs returns the Unix socket directories of the running
+// PgBouncer processes, dropping the connection pools and the pause state
+// of the terminated ones. It must be called while holding the lock.
+func (p *pgBouncerInstance) runningSocketDirs() []string {
+	current, draining := p.socketDirs()
+
+	socketDirs := make([]string, 0, len(draining)+1)
+	if current != "" {
+		socketDirs = append(socketDirs, current)
 	}
+	socketDirs = append(socketDirs, draining...)
 
-	// Second step: resume pgbouncer
-	_, err = db.Exec("RELOAD")
+	running := stringset.From(socketDirs)
+	for socketDir, socketPool := range p.pools {
+		if !running.Has(socketDir) {
+			socketPool.ShutdownConnections()
+			delete(p.pools, socketDir)
+		}
+	}
+	for _, socketDir := range p.pausedSocketDirs.ToList() {
+		if !running.Has(socketDir) {
+			p.pausedSocketDirs.Delete(socketDir)
+		}
+	}
+
+	return socketDirs
+}
+
+// isRunning checks whether the PgBouncer process listening in the passed
+// Unix socket directory is still running. The errors of a replaced process
+// terminating while receiving a command are not relevant.
+func (p *pgBouncerInstance) isRunning(socketDir string) bool {
+	current, draining := p.socketDirs()
+	return socketDir == current || slices.Contains(draining, socketDir)
+}
+
+// exec executes an administrative command in the PgBouncer process
+// listening in the passed Unix socket directory. It must be called
+// while holding the lock.
+func (p *pgBouncerInstance) exec(socketDir, command string) error {
+	socketPool, ok := p.pools[socketDir]
+	if !ok {
+		socketPool = p.newPool(socketDir)
+		p.pools[socketDir] = socketPool
+	}
+
+	db, err := socketPool.Connection("pgbouncer")
 	if err != nil {
-		return fmt.Errorf("while reloading configuration: %w", err)
+		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
 	}
 
-	return nil
+	_, err = db.Exec(command)
+	return err
 }
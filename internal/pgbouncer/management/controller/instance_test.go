@@ -21,74 +21,119 @@ package controller
 
 import (
 	"database/sql"
-	"sync"
+	"errors"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("PgBouncerInstance", func() {
 	var (
-		db   *sql.DB
-		mock sqlmock.Sqlmock
-		err  error
+		currentDB    *sql.DB
+		currentMock  sqlmock.Sqlmock
+		drainingDB   *sql.DB
+		drainingMock sqlmock.Sqlmock
+		draining     []string
+		instance     *pgBouncerInstance
+		err          error
 	)
 
 	BeforeEach(func() {
-		db, mock, err = sqlmock.New()
+		currentDB, currentMock, err = sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+		drainingDB, drainingMock, err = sqlmock.New()
 		Expect(err).NotTo(HaveOccurred())
+
+		draining = []string{"/run/pgbouncer-1"}
+		instance = NewPgBouncerInstance(func() (string, []string) {
+			return "/run/pgbouncer-2", draining
+		}).(*pgBouncerInstance)
+		instance.newPool = func(socketDir string) pool.Pooler {
+			if socketDir == "/run/pgbouncer-1" {
+				return &fakePooler{DB: drainingDB}
+			}
+			return &fakePooler{DB: currentDB}
+		}
 	})
 
 	AfterEach(func() {
-		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(currentMock.ExpectationsWereMet()).To(Succeed())
+		Expect(drainingMock.ExpectationsWereMet()).To(Succeed())
 	})
 
 	Context("when the instance is paused", func() {
-		It("should not return an error", func() {
-			mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
+		It("pauses every running process", func() {
+			currentMock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
+			drainingMock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
 
-			pgBouncerInstance := &pgBouncerInstance{
-				mu:     &sync.RWMutex{},
-				paused: false,
-				pool:   &fakePooler{DB: db},
-			}
+			Expect(instance.Pause()).To(Succeed())
+			Expect(instance.Paused()).To(BeTrue())
+		})
+
+		It("pauses only the processes that are not paused yet", func() {
+			instance.paused = true
+			instance.pausedSocketDirs.Put("/run/pgbouncer-1")
+			currentMock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
 
-			err := pgBouncerInstance.Pause()
-			Expect(err).NotTo(HaveOccurred())
-			Expect(pgBouncerInstance.Paused()).To(BeTrue())
+			Expect(instance.Pause()).To(Succeed())
+			Expect(instance.pausedSocketDirs.ToSortedList()).To(
+				Equal([]string{"/run/pgbouncer-1", "/run/pgbouncer-2"}))
 		})
 	})
 
 	Context("when the instance is resumed", func() {
-		It("should not return an error", func() {
-			mock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
+		It("resumes every paused process", func() {
+			instance.paused = true
+			instance.pausedSocketDirs.Put("/run/pgbouncer-1")
+			instance.pausedSocketDirs.Put("/run/pgbouncer-2")
+			currentMock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
+			drainingMock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
+
+			Expect(instance.Resume()).To(Succeed())
+			Expect(instance.Paused()).To(BeFalse())
+			Expect(instance.pausedSocketDirs.Len()).To(BeZero())
+		})
 
-			pgBouncerInstance := &pgBouncerInstance{
-				mu:     &sync.RWMutex{},
-				paused: true,
-				pool:   &fakePooler{DB: db},
-			}
+		It("forgets the processes that terminated", func() {
+			instance.paused = true
+			instance.pausedSocketDirs.Put("/run/pgbouncer-0")
+			instance.pausedSocketDirs.Put("/run/pgbouncer-2")
+			currentMock.ExpectExec("RESUME").WillReturnResult(sqlmock.NewResult(1, 1))
 
-			err := pgBouncerInstance.Resume()
-			Expect(err).NotTo(HaveOccurred())
-			Expect(pgBouncerInstance.Paused()).To(BeFalse())
+			Expect(instance.Resume()).To(Succeed())
+			Expect(instance.pausedSocketDirs.Len()).To(BeZero())
 		})
 	})
 
 	Context("when the instance configuration is reloaded", func() {
-		It("should not return an error", func() {
-			mock.ExpectExec("RELOAD").WillReturnResult(sqlmock.NewResult(1, 1))
+		It("reloads every running process", func() {
+			currentMock.ExpectExec("RELOAD").WillReturnResult(sqlmock.NewResult(1, 1))
+			drainingMock.ExpectExec("RELOAD").WillReturnResult(sqlmock.NewResult(1, 1))
+
+			Expect(instance.Reload()).To(Succeed())
+		})
 
-			pgBouncerInstance := &pgBouncerInstance{
-				mu:     &sync.RWMutex{},
-				paused: false,
-				pool:   &fakePooler{DB: db},
+		It("ignores the errors of a process terminating in the meantime", func() {
+			currentMock.ExpectExec("RELOAD").WillReturnResult(sqlmock.NewResult(1, 1))
+			drainingMock.ExpectExec("RELOAD").WillReturnError(errors.New("connection closed"))
+			newPool := instance.newPool
+			instance.newPool = func(socketDir string) pool.Pooler {
+				// The replaced process terminates while being reloaded
+				draining = nil
+				return newPool(socketDir)
 			}
 
-			err := pgBouncerInstance.Reload()
-			Expect(err).NotTo(HaveOccurred())
+			Expect(instance.Reload()).To(Succeed())
+		})
+
+		It("reports the errors of a running process", func() {
+			currentMock.ExpectExec("RELOAD").WillReturnError(errors.New("connection closed"))
+
+			Expect(instance.Reload()).To(MatchError(ContainSubstring("connection closed")))
 		})
 	})
 })
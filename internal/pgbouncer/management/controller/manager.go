@@ -24,10 +24,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
@@ -39,6 +42,8 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/metricsserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/telemetry"
 )
 
 // PgBouncerReconciler reconciles the status of the Pooler resource with
@@ -47,11 +52,24 @@ type PgBouncerReconciler struct {
 	client               ctrl.WithWatch
 	poolerWatch          watch.Interface
 	instance             PgBouncerInstanceInterface
+	process              PgBouncerProcessInterface
 	poolerNamespacedName types.NamespacedName
+
+	// The values of the parameters requiring a restart
+	// that are used by the running PgBouncer process
+	restartParameters map[string]string
+
+	// The exporter pushing metrics and traces to an
+	// OpenTelemetry collector, when configured
+	telemetryExporter *telemetry.Exporter
 }
 
 // NewPgBouncerReconciler creates a new pgbouncer reconciler
-func NewPgBouncerReconciler(poolerNamespacedName types.NamespacedName) (*PgBouncerReconciler, error) {
+func NewPgBouncerReconciler(
+	poolerNamespacedName types.NamespacedName,
+	process PgBouncerProcessInterface,
+	telemetryExporter *telemetry.Exporter,
+) (*PgBouncerReconciler, error) {
 	client, err := management.NewControllerRuntimeClient()
 	if err != nil {
 		return nil, err
@@ -59,8 +77,10 @@ func NewPgBouncerReconciler(poolerNamespacedName types.NamespacedName) (*PgBounc
 
 	return &PgBouncerReconciler{
 		client:               client,
-		instance:             NewPgBouncerInstance(),
+		instance:             NewPgBouncerInstance(process.SocketDirs),
+		process:              process,
 		poolerNamespacedName: poolerNamespacedName,
+		telemetryExporter:    telemetryExporter,
 	}, nil
 }
 
@@ -124,7 +144,7 @@ func (r *PgBouncerReconciler) GetClient() ctrl.Client {
 }
 
 // Reconcile is the main reconciliation loop for the pgbouncer instance
-func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event) error {
+func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event) (err error) {
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Debug(
 		"Reconciliation loop",
@@ -136,14 +156,40 @@ func (r *PgBouncerReconciler) Reconcile(ctx context.Context, event *watch.Event)
 		return fmt.Errorf("error decoding pooler resource")
 	}
 
-	err := r.synchronizeConfig(ctx, pooler)
-	if err != nil {
+	r.synchronizeTelemetry(ctx, pooler)
+
+	ctx, span := r.telemetryExporter.StartSpan(ctx, "pgbouncer.Reconcile",
+		attribute.String("event.type", string(event.Type)),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if err = r.synchronizeConfig(ctx, pooler); err != nil {
 		return fmt.Errorf("while reconciling configuration: %w", err)
 	}
 
 	return r.synchronizePause(pooler)
 }
 
+// synchronizeTelemetry applies the OpenTelemetry configuration of the
+// Pooler to the exporter pushing the metrics and the traces
+func (r *PgBouncerReconciler) synchronizeTelemetry(ctx context.Context, pooler *apiv1.Pooler) {
+	var telemetryConfig *apiv1.OpenTelemetryConfiguration
+	if pooler.Spec.Monitoring != nil {
+		telemetryConfig = pooler.Spec.Monitoring.OpenTelemetry
+	}
+
+	if err := r.telemetryExporter.Configure(ctx, telemetryConfig); err != nil {
+		log.FromContext(ctx).Warning("Unable to configure the OpenTelemetry exporter",
+			"error", err.Error())
+	}
+}
+
 // synchronizePause ensure that the pause flag inside the Pooler
 // specification matches the PgBouncer status
 func (r *PgBouncerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
@@ -153,11 +199,13 @@ func (r *PgBouncerReconciler) synchronizePause(pooler *apiv1.Pooler) error {
 		if err := r.instance.Pause(); err != nil {
 			return fmt.Errorf("while pausing instance: %w", err)
 		}
+		metricsserver.SetPaused(true)
 	}
 	if !shouldBePaused && isPaused {
 		if err := r.instance.Resume(); err != nil {
 			return fmt.Errorf("while resuming instance: %w", err)
 		}
+		metricsserver.SetPaused(false)
 	}
 	return nil
 }
@@ -174,13 +222,47 @@ func (r *PgBouncerReconciler) synchronizeConfig(ctx context.Context, pooler *api
 		return fmt.Errorf("while writing PgBouncer configuration: %w", err)
 	}
 
+	return r.applyConfiguration(ctx, pooler, configurationChanged)
+}
+
+// applyConfiguration makes PgBouncer use the configuration that has been
+// written. PgBouncer is restarted when a parameter that can't be changed
+// by reloading the configuration is changed, otherwise it is reloaded.
+func (r *PgBouncerReconciler) applyConfiguration(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	configurationChanged bool,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	restartParameters := config.GetRestartRequiredParameters(pooler.Spec.PgBouncer.Parameters)
+	if !maps.Equal(restartParameters, r.restartParameters) {
+		contextLogger.Info("Restarting PgBouncer to apply the configuration",
+			"parameters", restartParameters,
+			"previousParameters", r.restartParameters)
+		if err := r.process.Restart(ctx); err != nil {
+			return fmt.Errorf("while restarting PgBouncer due to configuration change: %w", err)
+		}
+		r.restartParameters = restartParameters
+		metricsserver.SetLastReloadTime(time.Now())
+
+		// The new process is not paused, even if the previous one was
+		if r.instance.Paused() {
+			if err := r.instance.Pause(); err != nil {
+				return fmt.Errorf("while pausing the restarted instance: %w", err)
+			}
+		}
+		return nil
+	}
+
 	if !configurationChanged {
 		return nil
 	}
 
-	if err = r.instance.Reload(); err != nil {
+	if err := r.instance.Reload(); err != nil {
 		return fmt.Errorf("while reloading configuration due to change: %w", err)
 	}
+	metricsserver.SetLastReloadTime(time.Now())
 
 	return nil
 }
@@ -234,6 +316,7 @@ func (r *PgBouncerReconciler) Init(ctx context.Context) error {
 	if _, err := r.writePgBouncerConfig(ctx, &pooler); err != nil {
 		return err
 	}
+	r.restartParameters = config.GetRestartRequiredParameters(pooler.Spec.PgBouncer.Parameters)
 
 	// Ensure we have the directory to store the controlling socket
 	if err := fileutils.EnsureDirectoryExists(config.PgBouncerSocketDir); err != nil {
@@ -0,0 +1,147 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakePgBouncerInstance struct {
+	paused  bool
+	pauses  int
+	reloads int
+}
+
+func (f *fakePgBouncerInstance) Paused() bool {
+	return f.paused
+}
+
+func (f *fakePgBouncerInstance) Pause() error {
+	f.paused = true
+	f.pauses++
+	return nil
+}
+
+func (f *fakePgBouncerInstance) Resume() error {
+	f.paused = false
+	return nil
+}
+
+func (f *fakePgBouncerInstance) Reload() error {
+	f.reloads++
+	return nil
+}
+
+type fakePgBouncerProcess struct {
+	restarts int
+}
+
+func (f *fakePgBouncerProcess) Start(context.Context) error {
+	return nil
+}
+
+func (f *fakePgBouncerProcess) Restart(context.Context) error {
+	f.restarts++
+	return nil
+}
+
+func (f *fakePgBouncerProcess) Shutdown(context.Context) error {
+	return nil
+}
+
+func (f *fakePgBouncerProcess) Wait() error {
+	return nil
+}
+
+func (f *fakePgBouncerProcess) SocketDirs() (string, []string) {
+	return "", nil
+}
+
+var _ = Describe("PgBouncer configuration application", func() {
+	var (
+		instance   *fakePgBouncerInstance
+		process    *fakePgBouncerProcess
+		reconciler *PgBouncerReconciler
+		pooler     *apiv1.Pooler
+	)
+
+	BeforeEach(func() {
+		instance = &fakePgBouncerInstance{}
+		process = &fakePgBouncerProcess{}
+		reconciler = &PgBouncerReconciler{
+			instance:          instance,
+			process:           process,
+			restartParameters: map[string]string{"pkt_buf": "4096"},
+		}
+		pooler = &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Parameters: map[string]string{
+						"pkt_buf":         "4096",
+						"max_client_conn": "100",
+					},
+				},
+			},
+		}
+	})
+
+	It("does nothing when the configuration is unchanged", func(ctx SpecContext) {
+		Expect(reconciler.applyConfiguration(ctx, pooler, false)).To(Succeed())
+		Expect(instance.reloads).To(BeZero())
+		Expect(process.restarts).To(BeZero())
+	})
+
+	It("reloads PgBouncer when only reloadable parameters changed", func(ctx SpecContext) {
+		pooler.Spec.PgBouncer.Parameters["max_client_conn"] = "200"
+		Expect(reconciler.applyConfiguration(ctx, pooler, true)).To(Succeed())
+		Expect(instance.reloads).To(Equal(1))
+		Expect(process.restarts).To(BeZero())
+	})
+
+	It("restarts PgBouncer when a parameter requiring a restart changed", func(ctx SpecContext) {
+		pooler.Spec.PgBouncer.Parameters["pkt_buf"] = "8192"
+		Expect(reconciler.applyConfiguration(ctx, pooler, true)).To(Succeed())
+		Expect(process.restarts).To(Equal(1))
+		Expect(instance.reloads).To(BeZero())
+		Expect(reconciler.restartParameters).To(Equal(map[string]string{"pkt_buf": "8192"}))
+
+		// The restart is not repeated once applied
+		Expect(reconciler.applyConfiguration(ctx, pooler, false)).To(Succeed())
+		Expect(process.restarts).To(Equal(1))
+	})
+
+	It("restarts PgBouncer when a parameter requiring a restart is removed", func(ctx SpecContext) {
+		delete(pooler.Spec.PgBouncer.Parameters, "pkt_buf")
+		Expect(reconciler.applyConfiguration(ctx, pooler, true)).To(Succeed())
+		Expect(process.restarts).To(Equal(1))
+	})
+
+	It("pauses the new process when the instance was paused", func(ctx SpecContext) {
+		instance.paused = true
+		pooler.Spec.PgBouncer.Parameters["pkt_buf"] = "8192"
+		Expect(reconciler.applyConfiguration(ctx, pooler, true)).To(Succeed())
+		Expect(instance.pauses).To(Equal(1))
+	})
+})
@@ -0,0 +1,460 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+)
+
+const (
+	// pgBouncerCommandName is the PgBouncer executable
+	pgBouncerCommandName = "/usr/bin/pgbouncer"
+
+	// pgBouncerStartTimeout is the time a new PgBouncer process has
+	// to start accepting connections
+	pgBouncerStartTimeout = 30 * time.Second
+
+	// pgBouncerStartPollInterval is the interval between two checks
+	// of the Unix socket of a starting PgBouncer process
+	pgBouncerStartPollInterval = 100 * time.Millisecond
+)
+
+var errPgBouncerNotRunning = errors.New("PgBouncer is not running")
+
+// pgBouncerVersionRegex matches the version in the output of "pgbouncer --version"
+var pgBouncerVersionRegex = regexp.MustCompile(`PgBouncer (\d+)\.(\d+)`)
+
+// PgBouncerProcessInterface the public interface of the component running
+// the PgBouncer processes of this instance, implementations should be
+// thread safe
+type PgBouncerProcessInterface interface {
+	// Start starts PgBouncer
+	Start(ctx context.Context) error
+	// Restart replaces the running PgBouncer process with a new one,
+	// without closing the connections of the existing clients
+	Restart(ctx context.Context) error
+	// Shutdown asks PgBouncer to shut down
+	Shutdown(ctx context.Context) error
+	// Wait waits for PgBouncer to terminate, returning its exit status
+	Wait() error
+	// SocketDirs returns the Unix socket directory of the PgBouncer process
+	// accepting the new connections, empty when PgBouncer is not running,
+	// and the ones of the processes replaced by a restart that are still
+	// serving their clients
+	SocketDirs() (string, []string)
+}
+
+// pgBouncerProcess runs PgBouncer inside this Pod.
+//
+// Every PgBouncer process has its own configuration file and Unix socket
+// directory, and shares the listening TCP port with the other processes
+// via SO_REUSEPORT. A stable symbolic link points to the Unix socket of the
+// latest process. This allows a restart to start a new process before
+// shutting down the previous one, which is left running until its clients
+// disconnect or, before PgBouncer 1.23, complete their transactions.
+type pgBouncerProcess struct {
+	commandName string
+	configsDir  string
+	socketDir   string
+
+	stdout io.Writer
+	stderr io.Writer
+
+	// drainSignal is the signal asking a replaced PgBouncer
+	// process to shut down without interrupting its clients
+	drainSignal syscall.Signal
+
+	mu           sync.Mutex
+	generation   int
+	current      *pgBouncerProcessGeneration
+	draining     map[int]*pgBouncerProcessGeneration
+	shuttingDown bool
+
+	// exited receives the exit status of the current process
+	exited chan error
+}
+
+// pgBouncerProcessGeneration is a PgBouncer process started
+// by pgBouncerProcess
+type pgBouncerProcessGeneration struct {
+	generation int
+	cmd        *exec.Cmd
+	socketDir  string
+	iniFile    string
+
+	// done is closed when the process exits, and err is its exit status
+	done chan struct{}
+	err  error
+}
+
+// NewPgBouncerProcess initializes a new pgBouncerProcess, streaming the
+// output of PgBouncer to the passed writers
+func NewPgBouncerProcess(stdout, stderr io.Writer) PgBouncerProcessInterface {
+	return &pgBouncerProcess{
+		commandName: pgBouncerCommandName,
+		configsDir:  config.ConfigsDir,
+		socketDir:   config.PgBouncerSocketDir,
+		stdout:      stdout,
+		stderr:      stderr,
+		draining:    make(map[int]*pgBouncerProcessGeneration),
+		exited:      make(chan error, 1),
+	}
+}
+
+// Start starts the first PgBouncer process, thread safe
+func (p *pgBouncerProcess) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current != nil || p.generation > 0 {
+		return fmt.Errorf("PgBouncer has already been started")
+	}
+
+	if err := p.removeLeftovers(); err != nil {
+		return fmt.Errorf("while removing the files of the previous PgBouncer processes: %w", err)
+	}
+
+	p.drainSignal = p.detectDrainSignal(ctx)
+	return p.spawn(ctx)
+}
+
+// Restart starts a new PgBouncer process, that will be accepting the new
+// connections, and asks the previous one to shut down without interrupting
+// its clients, thread safe
+func (p *pgBouncerProcess) Restart(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current == nil || p.shuttingDown {
+		return errPgBouncerNotRunning
+	}
+
+	previous := p.current
+	if err := p.spawn(ctx); err != nil {
+		return fmt.Errorf("while starting the new PgBouncer process: %w", err)
+	}
+
+	contextLogger.Info("Waiting for the clients of the previous PgBouncer process to complete",
+		"generation", previous.generation, "signal", p.drainSignal.String())
+	p.draining[previous.generation] = previous
+	if err := previous.cmd.Process.Signal(p.drainSignal); err != nil {
+		return fmt.Errorf("while shutting down the previous PgBouncer process: %w", err)
+	}
+
+	return nil
+}
+
+// Shutdown asks the current PgBouncer process to shut down, waiting for
+// the running transactions to complete, thread safe. The processes
+// replaced by a restart are left waiting for their clients to disconnect.
+func (p *pgBouncerProcess) Shutdown(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.shuttingDown = true
+	if p.current == nil {
+		return nil
+	}
+
+	contextLogger.Info("Shutting down pgbouncer instance", "generation", p.current.generation)
+	return p.current.cmd.Process.Signal(syscall.SIGINT)
+}
+
+// Wait waits for the current PgBouncer process to terminate, returning its
+// exit status. When PgBouncer is shutting down, this also waits for the
+// processes replaced by a restart.
+func (p *pgBouncerProcess) Wait() error {
+	err := <-p.exited
+
+	p.mu.Lock()
+	shuttingDown := p.shuttingDown
+	draining := make([]*pgBouncerProcessGeneration, 0, len(p.draining))
+	for _, process := range p.draining {
+		draining = append(draining, process)
+	}
+	p.mu.Unlock()
+
+	if shuttingDown {
+		for _, process := range draining {
+			<-process.done
+		}
+	}
+
+	return err
+}
+
+// SocketDirs returns the Unix socket directories of the running
+// PgBouncer processes, thread safe. The ones of the processes replaced
+// by a restart are sorted from the newest to the oldest.
+func (p *pgBouncerProcess) SocketDirs() (string, []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var current string
+	if p.current != nil {
+		current = p.current.socketDir
+	}
+
+	generations := make([]int, 0, len(p.draining))
+	for generation := range p.draining {
+		generations = append(generations, generation)
+	}
+	slices.Sort(generations)
+	slices.Reverse(generations)
+
+	draining := make([]string, 0, len(generations))
+	for _, generation := range generations {
+		draining = append(draining, p.draining[generation].socketDir)
+	}
+
+	return current, draining
+}
+
+// detectDrainSignal chooses the signal asking a replaced PgBouncer process
+// to shut down given the installed PgBouncer version. Starting from
+// PgBouncer 1.23, SIGTERM makes PgBouncer wait for every client to
+// disconnect. The previous versions interpret SIGTERM as an immediate
+// shutdown, and SIGINT is used to wait for the running transactions
+// to complete instead.
+func (p *pgBouncerProcess) detectDrainSignal(ctx context.Context) syscall.Signal {
+	contextLogger := log.FromContext(ctx)
+
+	var major, minor int
+	output, err := exec.CommandContext(ctx, p.commandName, "--version").Output() //nolint:gosec
+	if err == nil {
+		major, minor, err = parsePgBouncerVersion(string(output))
+	}
+	if err != nil {
+		contextLogger.Warning("Unable to detect the PgBouncer version, "+
+			"replaced processes will only wait for the running transactions",
+			"error", err.Error())
+		return syscall.SIGINT
+	}
+
+	if major > 1 || (major == 1 && minor >= 23) {
+		return syscall.SIGTERM
+	}
+	return syscall.SIGINT
+}
+
+// parsePgBouncerVersion extracts the major and the minor
+// version from the output of "pgbouncer --version"
+func parsePgBouncerVersion(output string) (int, int, error) {
+	matches := pgBouncerVersionRegex.FindStringSubmatch(output)
+	if matches == nil {
+		return 0, 0, fmt.Errorf("unexpected PgBouncer version output: %q", output)
+	}
+
+	major, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	minor, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return major, minor, nil
+}
+
+// spawn starts a new PgBouncer process and, once it is accepting
+// connections, makes it the current one. It must be called while
+// holding the lock.
+func (p *pgBouncerProcess) spawn(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	generation := p.generation + 1
+	process := &pgBouncerProcessGeneration{
+		generation: generation,
+		socketDir:  filepath.Join(p.socketDir, fmt.Sprintf("pgbouncer-%d", generation)),
+		iniFile:    filepath.Join(p.configsDir, fmt.Sprintf("pgbouncer-%d.ini", generation)),
+		done:       make(chan struct{}),
+	}
+
+	if err := fileutils.EnsureDirectoryExists(process.socketDir); err != nil {
+		return fmt.Errorf("while creating the socket directory: %w", err)
+	}
+
+	content, err := config.BuildProcessConfiguration(
+		filepath.Join(p.configsDir, config.PgBouncerIniFileName),
+		process.socketDir)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(process.iniFile, content, 0o600); err != nil {
+		return fmt.Errorf("while writing the process configuration: %w", err)
+	}
+
+	process.cmd = exec.Command(p.commandName, process.iniFile) //nolint:gosec
+	streamingCmd, err := execlog.RunStreamingNoWaitWithWriter(
+		process.cmd, p.commandName, p.stdout, p.stderr)
+	if err != nil {
+		return fmt.Errorf("running pgbouncer: %w", err)
+	}
+	p.generation = generation
+
+	go p.waitProcess(ctx, process, streamingCmd)
+
+	contextLogger.Info("Started PgBouncer process", "generation", generation)
+	if err := waitForSocket(ctx, process); err != nil {
+		_ = process.cmd.Process.Kill()
+		return err
+	}
+
+	if err := p.linkSocket(process); err != nil {
+		_ = process.cmd.Process.Kill()
+		return err
+	}
+
+	p.current = process
+	return nil
+}
+
+// waitProcess waits for a PgBouncer process to exit, reporting the exit
+// status of the current one and cleaning up after the replaced ones
+func (p *pgBouncerProcess) waitProcess(
+	ctx context.Context,
+	process *pgBouncerProcessGeneration,
+	streamingCmd *execlog.StreamingCmd,
+) {
+	contextLogger := log.FromContext(ctx).WithValues("generation", process.generation)
+
+	process.err = streamingCmd.Wait()
+	close(process.done)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current == process {
+		p.current = nil
+		p.exited <- process.err
+		return
+	}
+
+	delete(p.draining, process.generation)
+	contextLogger.Info("PgBouncer process terminated", "err", process.err)
+	if err := os.RemoveAll(process.socketDir); err != nil {
+		contextLogger.Error(err, "while removing the socket directory of a terminated PgBouncer process")
+	}
+	if err := os.Remove(process.iniFile); err != nil {
+		contextLogger.Error(err, "while removing the configuration of a terminated PgBouncer process")
+	}
+}
+
+// linkSocket atomically points the stable Unix socket path
+// to the socket of the passed process
+func (p *pgBouncerProcess) linkSocket(process *pgBouncerProcessGeneration) error {
+	link := filepath.Join(p.socketDir, pgBouncerSocketName())
+	target := filepath.Join(filepath.Base(process.socketDir), pgBouncerSocketName())
+
+	temporaryLink := link + ".new"
+	if err := os.Remove(temporaryLink); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("while removing the temporary socket link: %w", err)
+	}
+	if err := os.Symlink(target, temporaryLink); err != nil {
+		return fmt.Errorf("while creating the socket link: %w", err)
+	}
+	if err := os.Rename(temporaryLink, link); err != nil {
+		return fmt.Errorf("while replacing the socket link: %w", err)
+	}
+
+	return nil
+}
+
+// removeLeftovers removes the files of the PgBouncer processes started
+// before a restart of this container
+func (p *pgBouncerProcess) removeLeftovers() error {
+	patterns := []string{
+		filepath.Join(p.socketDir, "pgbouncer-*"),
+		filepath.Join(p.socketDir, pgBouncerSocketName()+"*"),
+		filepath.Join(p.configsDir, "pgbouncer-*.ini"),
+	}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			if err := os.RemoveAll(match); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitForSocket waits for a PgBouncer process to create its Unix
+// socket, that is to start accepting connections
+func waitForSocket(ctx context.Context, process *pgBouncerProcessGeneration) error {
+	socketPath := filepath.Join(process.socketDir, pgBouncerSocketName())
+
+	ticker := time.NewTicker(pgBouncerStartPollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(pgBouncerStartTimeout)
+	defer timeout.Stop()
+
+	for {
+		if _, err := os.Stat(socketPath); err == nil {
+			return nil
+		}
+
+		select {
+		case <-process.done:
+			if process.err != nil {
+				return fmt.Errorf("PgBouncer exited before accepting connections: %w", process.err)
+			}
+			return fmt.Errorf("PgBouncer exited before accepting connections")
+		case <-timeout.C:
+			return fmt.Errorf("timeout waiting for PgBouncer to accept connections")
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pgBouncerSocketName is the name of the Unix socket created by PgBouncer
+func pgBouncerSocketName() string {
+	return fmt.Sprintf(".s.PGSQL.%d", config.PgBouncerPort)
+}
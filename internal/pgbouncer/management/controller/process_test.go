@@ -0,0 +1,184 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controller
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakePgBouncerScript simulates PgBouncer by creating the Unix socket
+// file in the configured directory and waiting to be terminated,
+// recording the received signal in the socket directory. It reports
+// the passed PgBouncer version when invoked with "--version".
+func fakePgBouncerScript(version string) string {
+	return fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "--version" ]; then
+  echo "PgBouncer %s"
+  echo "libevent 2.1.12-stable"
+  exit 0
+fi
+dir=$(sed -n 's/^unix_socket_dir = //p' "$1")
+trap 'touch "$dir/sigterm"; exit 0' TERM
+trap 'touch "$dir/sigint"; exit 0' INT
+touch "$dir/.s.PGSQL.5432"
+while true; do sleep 0.1; done
+`, version)
+}
+
+var _ = Describe("PgBouncer process", func() {
+	var (
+		tmpDir  string
+		process *pgBouncerProcess
+	)
+
+	writeScript := func(version string) {
+		Expect(os.WriteFile(process.commandName, []byte(fakePgBouncerScript(version)), 0o700)). //nolint:gosec
+													To(Succeed())
+	}
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+		command := filepath.Join(tmpDir, "pgbouncer")
+		Expect(os.Mkdir(filepath.Join(tmpDir, "configs"), 0o700)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(tmpDir, "run"), 0o700)).To(Succeed())
+
+		process = &pgBouncerProcess{
+			commandName: command,
+			configsDir:  filepath.Join(tmpDir, "configs"),
+			socketDir:   filepath.Join(tmpDir, "run"),
+			stdout:      io.Discard,
+			stderr:      io.Discard,
+			draining:    make(map[int]*pgBouncerProcessGeneration),
+			exited:      make(chan error, 1),
+		}
+		writeScript("1.24.1")
+	})
+
+	socketLink := func() string {
+		target, err := os.Readlink(filepath.Join(tmpDir, "run", ".s.PGSQL.5432"))
+		Expect(err).ToNot(HaveOccurred())
+		return target
+	}
+
+	It("links the socket of the started process", func(ctx SpecContext) {
+		Expect(process.Start(ctx)).To(Succeed())
+		Expect(socketLink()).To(Equal(filepath.Join("pgbouncer-1", ".s.PGSQL.5432")))
+
+		ini, err := os.ReadFile(filepath.Join(tmpDir, "configs", "pgbouncer-1.ini"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(ini)).To(ContainSubstring("%include " + filepath.Join(tmpDir, "configs", "pgbouncer.ini")))
+
+		Expect(process.Shutdown(ctx)).To(Succeed())
+		Expect(process.Wait()).To(Succeed())
+		Expect(filepath.Join(tmpDir, "run", "pgbouncer-1", "sigint")).To(BeAnExistingFile())
+	})
+
+	It("removes the files left by a previous container", func(ctx SpecContext) {
+		Expect(os.Mkdir(filepath.Join(tmpDir, "run", "pgbouncer-7"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tmpDir, "configs", "pgbouncer-7.ini"), nil, 0o600)).To(Succeed())
+
+		Expect(process.Start(ctx)).To(Succeed())
+		Expect(filepath.Join(tmpDir, "run", "pgbouncer-7")).ToNot(BeAnExistingFile())
+		Expect(filepath.Join(tmpDir, "configs", "pgbouncer-7.ini")).ToNot(BeAnExistingFile())
+
+		Expect(process.Shutdown(ctx)).To(Succeed())
+		Expect(process.Wait()).To(Succeed())
+	})
+
+	It("hands the socket over to the new process when restarting", func(ctx SpecContext) {
+		Expect(process.Start(ctx)).To(Succeed())
+		Expect(process.Restart(ctx)).To(Succeed())
+		Expect(socketLink()).To(Equal(filepath.Join("pgbouncer-2", ".s.PGSQL.5432")))
+
+		// The previous process is asked to wait for its clients, and its
+		// files are removed once it terminates
+		Eventually(filepath.Join(tmpDir, "run", "pgbouncer-1")).
+			WithTimeout(5 * time.Second).ShouldNot(BeAnExistingFile())
+		Expect(filepath.Join(tmpDir, "configs", "pgbouncer-1.ini")).ToNot(BeAnExistingFile())
+
+		current, draining := process.SocketDirs()
+		Expect(current).To(Equal(filepath.Join(tmpDir, "run", "pgbouncer-2")))
+		Expect(draining).To(BeEmpty())
+
+		Expect(process.Shutdown(ctx)).To(Succeed())
+		Expect(process.Wait()).To(Succeed())
+		Expect(filepath.Join(tmpDir, "run", "pgbouncer-2", "sigint")).To(BeAnExistingFile())
+	})
+
+	It("reports the socket directories of the replaced processes", func(ctx SpecContext) {
+		Expect(process.Start(ctx)).To(Succeed())
+		// Keep the replaced processes running, as it happens
+		// to PgBouncer processes waiting for their clients
+		process.drainSignal = syscall.SIGWINCH
+		Expect(process.Restart(ctx)).To(Succeed())
+		Expect(process.Restart(ctx)).To(Succeed())
+
+		current, draining := process.SocketDirs()
+		Expect(current).To(Equal(filepath.Join(tmpDir, "run", "pgbouncer-3")))
+		Expect(draining).To(Equal([]string{
+			filepath.Join(tmpDir, "run", "pgbouncer-2"),
+			filepath.Join(tmpDir, "run", "pgbouncer-1"),
+		}))
+
+		Expect(process.Shutdown(ctx)).To(Succeed())
+		process.mu.Lock()
+		for _, generation := range process.draining {
+			Expect(generation.cmd.Process.Signal(syscall.SIGINT)).To(Succeed())
+		}
+		process.mu.Unlock()
+		Expect(process.Wait()).To(Succeed())
+	})
+
+	DescribeTable("asks the replaced process to shut down with the signal supported by PgBouncer",
+		func(ctx SpecContext, version string, signal syscall.Signal) {
+			writeScript(version)
+			Expect(process.Start(ctx)).To(Succeed())
+			Expect(process.drainSignal).To(Equal(signal))
+
+			Expect(process.Restart(ctx)).To(Succeed())
+			Eventually(filepath.Join(tmpDir, "run", "pgbouncer-1")).
+				WithTimeout(5 * time.Second).ShouldNot(BeAnExistingFile())
+
+			Expect(process.Shutdown(ctx)).To(Succeed())
+			Expect(process.Wait()).To(Succeed())
+		},
+		Entry("waiting for the clients since PgBouncer 1.23", "1.23.1", syscall.SIGTERM),
+		Entry("waiting for the clients in PgBouncer 2", "2.0.0", syscall.SIGTERM),
+		Entry("waiting for the transactions before PgBouncer 1.23", "1.22.1", syscall.SIGINT),
+		Entry("waiting for the transactions when the version is unknown", "unknown", syscall.SIGINT),
+	)
+
+	It("refuses to restart when PgBouncer is not running", func(ctx SpecContext) {
+		Expect(process.Restart(ctx)).To(MatchError(errPgBouncerNotRunning))
+	})
+
+	It("fails when the process exits before accepting connections", func(ctx SpecContext) {
+		Expect(os.WriteFile(process.commandName, []byte("#!/bin/sh\nexit 1\n"), 0o700)).To(Succeed()) //nolint:gosec
+		Expect(process.Start(ctx)).To(MatchError(ContainSubstring("exited before accepting connections")))
+	})
+})
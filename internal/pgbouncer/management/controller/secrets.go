@@ -73,10 +73,23 @@ func getSecrets(ctx context.Context, client ctrl.Client, pooler *apiv1.Pooler) (
 		return nil, fmt.Errorf("while getting client CA secret: %w", err)
 	}
 
-	return &config.Secrets{
+	result := &config.Secrets{
 		AuthQuery: &authQuerySecret,
 		ServerCA:  &serverCASecret,
 		Client:    &serverCertSecret,
 		ClientCA:  &clientCASecret,
-	}, nil
+	}
+
+	if pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.CertificateAuthentication != nil {
+		var certificateAuthenticationSecret corev1.Secret
+		secretName := pooler.GetCertificateAuthenticationSecretName()
+		if err := client.Get(ctx,
+			types.NamespacedName{Name: secretName, Namespace: pooler.Namespace},
+			&certificateAuthenticationSecret); err != nil {
+			return nil, fmt.Errorf("while getting certificate authentication secret %s: %w", secretName, err)
+		}
+		result.CertificateAuthentication = &certificateAuthenticationSecret
+	}
+
+	return result, nil
 }
@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"slices"
 	"strconv"
 	"strings"
@@ -197,6 +198,8 @@ func (v *ClusterCustomValidator) validate(r *apiv1.Cluster) (allErrs field.Error
 		v.validateTablespaceNames,
 		v.validateBootstrapPgBaseBackupSource,
 		v.validateTablespaceBackupSnapshot,
+		v.validateTablespaceRelations,
+		v.validateTablespaceEphemeralStorage,
 		v.validateBootstrapRecoverySource,
 		v.validateBootstrapRecoveryDataSource,
 		v.validateExternalClusters,
@@ -222,6 +225,7 @@ func (v *ClusterCustomValidator) validate(r *apiv1.Cluster) (allErrs field.Error
 		v.validatePluginConfiguration,
 		v.validateLivenessPingerProbe,
 		v.validateExtensions,
+		v.validateLogging,
 	}
 
 	for _, validate := range validations {
@@ -399,18 +403,30 @@ func (v *ClusterCustomValidator) validateImport(r *apiv1.Cluster) field.ErrorLis
 		return nil
 	}
 
+	var result field.ErrorList
+	if importSpec.Online && importSpec.SchemaOnly {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "online"),
+				importSpec.Online,
+				"An online import cannot be a schema-only one, as the data is copied by logical replication"),
+		)
+	}
+
 	switch importSpec.Type {
 	case apiv1.MicroserviceSnapshotType:
-		return v.validateMicroservice(importSpec)
+		return append(result, v.validateMicroservice(importSpec)...)
 	case apiv1.MonolithSnapshotType:
-		return v.validateMonolith(importSpec)
+		return append(result, v.validateMonolith(importSpec)...)
 	default:
-		return field.ErrorList{
+		return append(
+			result,
 			field.Invalid(
 				field.NewPath("spec", "bootstrap", "initdb", "import", "type"),
 				importSpec.Type,
 				"Unrecognized import type"),
-		}
+		)
 	}
 }
 
@@ -1608,6 +1624,9 @@ func (v *ClusterCustomValidator) validateTablespaceStorageSize(r *apiv1.Cluster)
 	var result field.ErrorList
 
 	for idx, tablespaceConf := range r.Spec.Tablespaces {
+		if tablespaceConf.IsEphemeral() {
+			continue
+		}
 		result = append(result,
 			validateStorageConfigurationSize(
 				*field.NewPath("spec", "tablespaces").Index(idx),
@@ -1674,37 +1693,40 @@ func (v *ClusterCustomValidator) validateWalStorageChange(r, old *apiv1.Cluster)
 	)
 }
 
-// validateTablespacesChange checks that no tablespaces have been deleted, and that
-// no tablespaces have an invalid storage update
+// validateTablespacesChange checks that only retired tablespaces have been deleted,
+// and that no tablespaces have an invalid storage update
 func (v *ClusterCustomValidator) validateTablespacesChange(r, old *apiv1.Cluster) field.ErrorList {
 	if old.Spec.Tablespaces == nil {
 		return nil
 	}
 
-	if old.Spec.Tablespaces != nil && r.Spec.Tablespaces == nil {
-		return field.ErrorList{
-			field.Invalid(
-				field.NewPath("spec", "tablespaces"),
-				r.Spec.Tablespaces,
-				"tablespaces section cannot be deleted once created"),
-		}
-	}
-
 	var errs field.ErrorList
 	for idx, oldConf := range old.Spec.Tablespaces {
 		name := oldConf.Name
-		if newConf := r.GetTablespaceConfiguration(name); newConf != nil {
+		newConf := r.GetTablespaceConfiguration(name)
+		switch {
+		case newConf == nil:
+			if !old.IsTablespaceRetired(name) {
+				errs = append(errs,
+					field.Invalid(
+						field.NewPath("spec", "tablespaces").Index(idx),
+						r.Spec.Tablespaces,
+						"a tablespace can be deleted only once it has been marked as absent and dropped"))
+			}
+
+		case newConf.IsEphemeral() != oldConf.IsEphemeral():
+			errs = append(errs,
+				field.Invalid(
+					field.NewPath("spec", "tablespaces").Index(idx).Child("ephemeral"),
+					newConf.Ephemeral,
+					"a tablespace cannot be moved between an ephemeral volume and a persistent volume claim"))
+
+		case !newConf.IsEphemeral():
 			errs = append(errs, validateStorageConfigurationChange(
 				field.NewPath("spec", "tablespaces").Index(idx),
 				oldConf.Storage,
 				newConf.Storage,
 			)...)
-		} else {
-			errs = append(errs,
-				field.Invalid(
-					field.NewPath("spec", "tablespaces").Index(idx),
-					r.Spec.Tablespaces,
-					"no tablespace can be deleted once created"))
 		}
 	}
 	return errs
@@ -1816,6 +1838,47 @@ func (v *ClusterCustomValidator) validateTablespaceBackupSnapshot(r *apiv1.Clust
 	return result
 }
 
+// validateTablespaceEphemeralStorage checks that only temporary tablespaces
+// are hosted on ephemeral volumes, and that their configuration is coherent
+func (v *ClusterCustomValidator) validateTablespaceEphemeralStorage(r *apiv1.Cluster) field.ErrorList {
+	var result field.ErrorList
+	for idx, tbsConfig := range r.Spec.Tablespaces {
+		if !tbsConfig.IsEphemeral() {
+			continue
+		}
+
+		path := field.NewPath("spec", "tablespaces").Index(idx)
+		if !tbsConfig.Temporary {
+			result = append(result, field.Invalid(
+				path.Child("ephemeral"),
+				tbsConfig.Ephemeral,
+				"only temporary tablespaces can be hosted on ephemeral volumes"))
+		}
+
+		if tbsConfig.Ephemeral.VolumeSource != nil && tbsConfig.Ephemeral.SizeLimit != nil {
+			result = append(result, field.Duplicate(
+				path.Child("ephemeral"),
+				"Conflicting settings: provide either volumeSource or sizeLimit, not both."))
+		}
+	}
+	return result
+}
+
+// validateTablespaceRelations checks that no relation is moved into a
+// tablespace that is going to be dropped
+func (v *ClusterCustomValidator) validateTablespaceRelations(r *apiv1.Cluster) field.ErrorList {
+	var result field.ErrorList
+	for idx, tbsConfig := range r.Spec.Tablespaces {
+		if tbsConfig.Ensure == apiv1.EnsureAbsent && len(tbsConfig.Relations) > 0 {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "tablespaces").Index(idx).Child("relations"),
+				tbsConfig.Relations,
+				"relations cannot be moved into a tablespace marked as absent"))
+		}
+	}
+	return result
+}
+
 // Check if the external clusters list contains two servers with the same name
 func (v *ClusterCustomValidator) validateExternalClusters(r *apiv1.Cluster) field.ErrorList {
 	var result field.ErrorList
@@ -2406,6 +2469,14 @@ func (v *ClusterCustomValidator) validateManagedRoles(r *apiv1.Cluster) field.Er
 	}
 
 	managedRoles := make(map[string]interface{})
+	clientCertificateSecrets := map[string]interface{}{
+		r.GetServerCASecretName():    nil,
+		r.GetServerTLSSecretName():   nil,
+		r.GetClientCASecretName():    nil,
+		r.GetReplicationSecretName(): nil,
+		r.GetSuperuserSecretName():   nil,
+		r.GetApplicationSecretName(): nil,
+	}
 	for _, role := range r.Spec.Managed.Roles {
 		_, found := managedRoles[role.Name]
 		if found {
@@ -2441,6 +2512,54 @@ func (v *ClusterCustomValidator) validateManagedRoles(r *apiv1.Cluster) field.Er
 					role.Name,
 					"This role both sets and disables a password"))
 		}
+		result = append(result, validatePasswordRotation(role)...)
+		if role.ClientCertificate != nil {
+			if _, found := clientCertificateSecrets[role.ClientCertificate.SecretName]; found {
+				result = append(
+					result,
+					field.Invalid(
+						field.NewPath("spec", "managed", "roles"),
+						role.ClientCertificate.SecretName,
+						"Client certificate secret name is already used by the cluster or by another role"))
+			}
+			clientCertificateSecrets[role.ClientCertificate.SecretName] = nil
+		}
+	}
+
+	return result
+}
+
+// validatePasswordRotation validates the password rotation policy of a managed role
+func validatePasswordRotation(role apiv1.RoleConfiguration) field.ErrorList {
+	if role.PasswordRotation == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "managed", "roles")
+	if role.PasswordSecret == nil || role.DisablePassword {
+		result = append(
+			result,
+			field.Invalid(
+				path,
+				role.Name,
+				"Password rotation requires a password secret"))
+	}
+	if role.PasswordRotation.Interval.Duration <= 0 {
+		result = append(
+			result,
+			field.Invalid(
+				path,
+				role.PasswordRotation.Interval.String(),
+				"Password rotation interval should be positive"))
+	}
+	if role.PasswordRotation.GetGracePeriod() < 0 {
+		result = append(
+			result,
+			field.Invalid(
+				path,
+				role.PasswordRotation.GracePeriod.String(),
+				"Password rotation grace period cannot be negative"))
 	}
 
 	return result
@@ -2501,9 +2620,26 @@ func (v *ClusterCustomValidator) getAdmissionWarnings(r *apiv1.Cluster) admissio
 	list = append(list, getInTreeBarmanWarnings(r)...)
 	list = append(list, getRetentionPolicyWarnings(r)...)
 	list = append(list, getStorageWarnings(r)...)
+	list = append(list, getPgStatStatementsWarnings(r)...)
 	return append(list, getSharedBuffersWarnings(r)...)
 }
 
+func getPgStatStatementsWarnings(r *apiv1.Cluster) admission.Warnings {
+	if r.Spec.Monitoring == nil || r.Spec.Monitoring.PgStatStatements == nil {
+		return nil
+	}
+
+	if postgres.IsManagedExtensionUsed("pg_stat_statements", r.Spec.PostgresConfiguration.Parameters) {
+		return nil
+	}
+
+	return admission.Warnings{
+		"`.spec.monitoring.pgStatStatements` requires the pg_stat_statements extension: " +
+			"set at least one `pg_stat_statements.*` parameter, such as `pg_stat_statements.max`, " +
+			"in `.spec.postgresql.parameters` to enable it",
+	}
+}
+
 func getStorageWarnings(r *apiv1.Cluster) admission.Warnings {
 	generateWarningsFunc := func(path field.Path, configuration *apiv1.StorageConfiguration) admission.Warnings {
 		if configuration == nil {
@@ -2799,3 +2935,74 @@ func (v *ClusterCustomValidator) validateExtensions(r *apiv1.Cluster) field.Erro
 
 	return result
 }
+
+// validateLogging validates the log sinks and the routes choosing them
+func (v *ClusterCustomValidator) validateLogging(r *apiv1.Cluster) field.ErrorList {
+	if r.Spec.Logging == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+
+	sinkNames := stringset.From([]string{apiv1.LogSinkStdout})
+	for i, sink := range r.Spec.Logging.Sinks {
+		basePath := field.NewPath("spec", "logging", "sinks").Index(i)
+
+		switch {
+		case sink.Name == apiv1.LogSinkStdout:
+			result = append(result, field.Invalid(
+				basePath.Child("name"),
+				sink.Name,
+				"this name is reserved for the built-in sink"))
+		case sinkNames.Has(sink.Name):
+			result = append(result, field.Duplicate(basePath.Child("name"), sink.Name))
+		}
+		sinkNames.Put(sink.Name)
+
+		kinds := 0
+		for _, defined := range []bool{sink.File != nil, sink.Syslog != nil, sink.OpenTelemetry != nil} {
+			if defined {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			result = append(result, field.Invalid(
+				basePath,
+				sink.Name,
+				"exactly one of file, syslog and openTelemetry is required"))
+		}
+
+		if sink.Syslog != nil {
+			if _, _, err := net.SplitHostPort(sink.Syslog.Address); err != nil {
+				result = append(result, field.Invalid(
+					basePath.Child("syslog", "address"),
+					sink.Syslog.Address,
+					fmt.Sprintf("the address must be in the host:port format: %v", err)))
+			}
+		}
+	}
+
+	if limit := r.Spec.Logging.VolumeSizeLimit; limit != nil {
+		if usage := r.GetLogSinksVolumeUsage(); usage > limit.Value() {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "logging", "volumeSizeLimit"),
+				limit.String(),
+				fmt.Sprintf("the file sinks can use up to %s, counting the current "+
+					"and the rotated files, which doesn't fit the volume",
+					resource.NewQuantity(usage, resource.BinarySI).String())))
+		}
+	}
+
+	for i, route := range r.Spec.Logging.Routes {
+		for j, sinkName := range route.Sinks {
+			if !sinkNames.Has(sinkName) {
+				result = append(result, field.Invalid(
+					field.NewPath("spec", "logging", "routes").Index(i).Child("sinks").Index(j),
+					sinkName,
+					"unknown log sink"))
+			}
+		}
+	}
+
+	return result
+}
@@ -3384,6 +3384,31 @@ var _ = Describe("validation of imports", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("rejects online schema-only imports", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &apiv1.Import{
+							Type:       apiv1.MicroserviceSnapshotType,
+							Databases:  []string{"foo"},
+							Online:     true,
+							SchemaOnly: true,
+						},
+					},
+				},
+			},
+		}
+
+		result := v.validateImport(cluster)
+		Expect(result).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.InitDB.Import.SchemaOnly = false
+		Expect(v.validateImport(cluster)).To(BeEmpty())
+	})
+
 	It("rejects monolith import with no databases", func() {
 		cluster := &apiv1.Cluster{
 			Spec: apiv1.ClusterSpec{
@@ -4083,6 +4108,99 @@ var _ = Describe("Role management validation", func() {
 		}
 		Expect(v.validateManagedRoles(cluster)).To(HaveLen(1))
 	})
+
+	It("should accept a password rotation policy on a role with a password secret", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{
+							Name: "my_test",
+							PasswordSecret: &apiv1.LocalObjectReference{
+								Name: "myPassword",
+							},
+							PasswordRotation: &apiv1.PasswordRotationPolicy{
+								Interval: metav1.Duration{Duration: 720 * time.Hour},
+							},
+							ConnectionLimit: -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validateManagedRoles(cluster)).To(BeEmpty())
+	})
+
+	It("should produce an error if a password rotation policy is set without a password secret", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{
+							Name: "my_test",
+							PasswordRotation: &apiv1.PasswordRotationPolicy{
+								Interval: metav1.Duration{Duration: 720 * time.Hour},
+							},
+							ConnectionLimit: -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validateManagedRoles(cluster)).To(HaveLen(1))
+	})
+
+	It("should produce an error if the password rotation interval or grace period are invalid", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{
+							Name: "my_test",
+							PasswordSecret: &apiv1.LocalObjectReference{
+								Name: "myPassword",
+							},
+							PasswordRotation: &apiv1.PasswordRotationPolicy{
+								GracePeriod: &metav1.Duration{Duration: -time.Hour},
+							},
+							ConnectionLimit: -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validateManagedRoles(cluster)).To(HaveLen(2))
+	})
+
+	It("should produce an error if a client certificate secret name is already in use", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Roles: []apiv1.RoleConfiguration{
+						{
+							Name:              "dante",
+							ClientCertificate: &apiv1.RoleClientCertificate{SecretName: "dante-cert"},
+							ConnectionLimit:   -1,
+						},
+						{
+							Name:              "petrarca",
+							ClientCertificate: &apiv1.RoleClientCertificate{SecretName: "dante-cert"},
+							ConnectionLimit:   -1,
+						},
+						{
+							Name:              "boccaccio",
+							ClientCertificate: &apiv1.RoleClientCertificate{SecretName: "cluster-example-ca"},
+							ConnectionLimit:   -1,
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validateManagedRoles(cluster)).To(HaveLen(2))
+	})
 })
 
 var _ = Describe("Managed Extensions validation", func() {
@@ -4804,6 +4922,148 @@ var _ = Describe("Tablespaces validation", func() {
 		Expect(v.validateClusterChanges(cluster, oldCluster)).To(HaveLen(1))
 	})
 
+	It("should allow deleting a tablespace that has been dropped", func() {
+		retiredTbsConf := createFakeTemporaryTbsConf("my-tablespace2")
+		retiredTbsConf.Ensure = apiv1.EnsureAbsent
+		oldCluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+				},
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					createFakeTemporaryTbsConf("my-tablespace1"),
+					retiredTbsConf,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				TablespacesStatus: []apiv1.TablespaceState{
+					{Name: "my-tablespace1", State: apiv1.TablespaceStatusReconciled},
+					{Name: "my-tablespace2", State: apiv1.TablespaceStatusDropped},
+				},
+			},
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+				},
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					createFakeTemporaryTbsConf("my-tablespace1"),
+				},
+			},
+		}
+		Expect(v.validateClusterChanges(cluster, oldCluster)).To(BeEmpty())
+	})
+
+	It("should produce an error if an absent tablespace is deleted before being dropped", func() {
+		absentTbsConf := createFakeTemporaryTbsConf("my-tablespace1")
+		absentTbsConf.Ensure = apiv1.EnsureAbsent
+		oldCluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "10Gi",
+				},
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					absentTbsConf,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				TablespacesStatus: []apiv1.TablespaceState{
+					{Name: "my-tablespace1", State: apiv1.TablespaceStatusPendingReconciliation},
+				},
+			},
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+			},
+		}
+		Expect(v.validateClusterChanges(cluster, oldCluster)).To(HaveLen(1))
+	})
+
+	It("should produce an error if relations are moved into an absent tablespace", func() {
+		absentTbsConf := createFakeTemporaryTbsConf("my-tablespace1")
+		absentTbsConf.Ensure = apiv1.EnsureAbsent
+		absentTbsConf.Relations = []apiv1.TablespaceRelation{
+			{Database: "app", Schema: "public", Name: "orders"},
+		}
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					absentTbsConf,
+				},
+			},
+		}
+		Expect(v.validateTablespaceRelations(cluster)).To(HaveLen(1))
+	})
+
+	It("should only allow temporary tablespaces on ephemeral volumes", func() {
+		sizeLimit := resource.MustParse("1Gi")
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					{
+						Name:      "scratch",
+						Temporary: true,
+						Ephemeral: &apiv1.TablespaceEphemeralStorage{SizeLimit: &sizeLimit},
+					},
+				},
+			},
+		}
+		Expect(v.validateTablespaceEphemeralStorage(cluster)).To(BeEmpty())
+		Expect(v.validateTablespaceStorageSize(cluster)).To(BeEmpty())
+
+		cluster.Spec.Tablespaces[0].Temporary = false
+		Expect(v.validateTablespaceEphemeralStorage(cluster)).To(HaveLen(1))
+	})
+
+	It("should produce an error if both a volume source and a size limit are set", func() {
+		sizeLimit := resource.MustParse("1Gi")
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					{
+						Name:      "scratch",
+						Temporary: true,
+						Ephemeral: &apiv1.TablespaceEphemeralStorage{
+							SizeLimit:    &sizeLimit,
+							VolumeSource: &corev1.EphemeralVolumeSource{},
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validateTablespaceEphemeralStorage(cluster)).To(HaveLen(1))
+	})
+
+	It("should produce an error if a tablespace is moved to an ephemeral volume", func() {
+		oldCluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					createFakeTemporaryTbsConf("my-tablespace1"),
+				},
+			},
+		}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.Tablespaces[0].Ephemeral = &apiv1.TablespaceEphemeralStorage{}
+		Expect(v.validateTablespacesChange(cluster, oldCluster)).To(HaveLen(1))
+	})
+
 	It("should produce an error if a tablespace is reduced in size", func() {
 		oldCluster := &apiv1.Cluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -5608,6 +5868,37 @@ var _ = Describe("getRetentionPolicyWarnings", func() {
 	})
 })
 
+var _ = Describe("getPgStatStatementsWarnings", func() {
+	It("returns no warnings when the pg_stat_statements exporter is not configured", func() {
+		Expect(getPgStatStatementsWarnings(&apiv1.Cluster{})).To(BeEmpty())
+	})
+
+	It("returns no warnings when the pg_stat_statements extension is enabled", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"pg_stat_statements.max": "10000"},
+				},
+				Monitoring: &apiv1.MonitoringConfiguration{
+					PgStatStatements: &apiv1.PgStatStatementsConfiguration{},
+				},
+			},
+		}
+		Expect(getPgStatStatementsWarnings(cluster)).To(BeEmpty())
+	})
+
+	It("returns a warning when the pg_stat_statements extension is not enabled", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					PgStatStatements: &apiv1.PgStatStatementsConfiguration{},
+				},
+			},
+		}
+		Expect(getPgStatStatementsWarnings(cluster)).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("getStorageWarnings", func() {
 	It("returns no warnings when storage is properly configured", func() {
 		cluster := &apiv1.Cluster{
@@ -5872,3 +6163,113 @@ var _ = Describe("failoverQuorum validation", func() {
 		Expect(errList).To(HaveLen(1))
 	})
 })
+
+var _ = Describe("logging validation", func() {
+	var v *ClusterCustomValidator
+	BeforeEach(func() {
+		v = &ClusterCustomValidator{}
+	})
+
+	It("accepts clusters without a logging configuration", func() {
+		Expect(v.validateLogging(&apiv1.Cluster{})).To(BeEmpty())
+	})
+
+	It("accepts routes referencing the defined sinks and stdout", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{Name: "audit", File: &apiv1.FileLogSinkConfiguration{}},
+						{Name: "remote", Syslog: &apiv1.SyslogLogSinkConfiguration{Address: "syslog:514"}},
+					},
+					Routes: []apiv1.LogRouteConfiguration{
+						{Loggers: []string{"pgaudit"}, Sinks: []string{"audit"}},
+						{MinSeverity: "ERROR", Sinks: []string{"remote", apiv1.LogSinkStdout}},
+					},
+				},
+			},
+		}
+		Expect(v.validateLogging(cluster)).To(BeEmpty())
+	})
+
+	It("rejects reserved, duplicated and ambiguous sinks", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{Name: apiv1.LogSinkStdout, File: &apiv1.FileLogSinkConfiguration{}},
+						{Name: "audit", File: &apiv1.FileLogSinkConfiguration{}},
+						{Name: "audit", File: &apiv1.FileLogSinkConfiguration{}},
+						{
+							Name:   "both",
+							File:   &apiv1.FileLogSinkConfiguration{},
+							Syslog: &apiv1.SyslogLogSinkConfiguration{Address: "syslog:514"},
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validateLogging(cluster)).To(HaveLen(3))
+	})
+
+	It("rejects syslog addresses without a port", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{Name: "remote", Syslog: &apiv1.SyslogLogSinkConfiguration{Address: "syslog"}},
+					},
+				},
+			},
+		}
+		Expect(v.validateLogging(cluster)).To(HaveLen(1))
+	})
+
+	It("accepts a volume size limit fitting the file sinks", func() {
+		limit := resource.MustParse("600Mi")
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{Name: "audit", File: &apiv1.FileLogSinkConfiguration{}},
+					},
+					VolumeSizeLimit: &limit,
+				},
+			},
+		}
+		Expect(v.validateLogging(cluster)).To(BeEmpty())
+	})
+
+	It("rejects a volume size limit smaller than the space used by the file sinks", func() {
+		limit := resource.MustParse("1Gi")
+		maxSize := resource.MustParse("200Mi")
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{Name: "audit", File: &apiv1.FileLogSinkConfiguration{MaxSize: &maxSize}},
+					},
+					VolumeSizeLimit: &limit,
+				},
+			},
+		}
+		errList := v.validateLogging(cluster)
+		Expect(errList).To(HaveLen(1))
+		Expect(errList[0].Field).To(Equal("spec.logging.volumeSizeLimit"))
+	})
+
+	It("rejects routes referencing unknown sinks", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Routes: []apiv1.LogRouteConfiguration{
+						{Sinks: []string{"missing"}},
+					},
+				},
+			},
+		}
+		errList := v.validateLogging(cluster)
+		Expect(errList).To(HaveLen(1))
+		Expect(errList[0].Field).To(Equal("spec.logging.routes[0].sinks[0]"))
+	})
+})
@@ -23,7 +23,9 @@ import (
 	"context"
 	"fmt"
 
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -131,3 +133,36 @@ func validate(obj runtime.Object, validator func() (admission.Warnings, error))
 	warnings = append(warnings, validationWarnings...)
 	return warnings, err
 }
+
+// validateDeclarativeReference checks that the referenced secret or config
+// map, when existing, allows being referenced by the declarative objects.
+// Missing objects are accepted, since the instance manager will refuse them
+// when they are created without the required label
+func validateDeclarativeReference(
+	ctx context.Context,
+	cli client.Client,
+	path *field.Path,
+	namespace, name string,
+	object client.Object,
+) *field.Error {
+	if cli == nil || name == "" {
+		return nil
+	}
+
+	err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, object)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return field.InternalError(path, err)
+	}
+
+	if !utils.IsDeclarativeReferenceAllowed(object) {
+		return field.Forbidden(
+			path,
+			fmt.Sprintf("%q must have the %q label set to \"true\" to be referenced",
+				name, utils.DeclarativeReferenceLabelName))
+	}
+
+	return nil
+}
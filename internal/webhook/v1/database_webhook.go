@@ -22,14 +22,17 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -41,7 +44,7 @@ var databaseLog = log.WithName("database-resource").WithValues("version", "v1")
 // SetupDatabaseWebhookWithManager registers the webhook for Database in the manager.
 func SetupDatabaseWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.Database{}).
-		WithValidator(newBypassableValidator(&DatabaseCustomValidator{})).
+		WithValidator(newBypassableValidator(&DatabaseCustomValidator{client: mgr.GetClient()})).
 		WithDefaulter(&DatabaseCustomDefaulter{}).
 		Complete()
 }
@@ -71,10 +74,14 @@ func (d *DatabaseCustomDefaulter) Default(_ context.Context, obj runtime.Object)
 
 // DatabaseCustomValidator is responsible for validating the Database
 // resource when it is created, updated, or deleted.
-type DatabaseCustomValidator struct{}
+type DatabaseCustomValidator struct {
+	// client is used to check the secrets and the config maps
+	// referenced by the Database
+	client client.Client
+}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Database .
-func (v *DatabaseCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *DatabaseCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	database, ok := obj.(*apiv1.Database)
 	if !ok {
 		return nil, fmt.Errorf("expected a Database object but got %T", obj)
@@ -83,7 +90,10 @@ func (v *DatabaseCustomValidator) ValidateCreate(_ context.Context, obj runtime.
 		"Validation for Database upon creation",
 		"name", database.GetName(), "namespace", database.GetNamespace())
 
-	allErrs := v.validate(database)
+	allErrs := append(
+		v.validate(database),
+		v.validateReferences(ctx, database)...,
+	)
 	allWarnings := v.getAdmissionWarnings(database)
 
 	if len(allErrs) == 0 {
@@ -97,7 +107,7 @@ func (v *DatabaseCustomValidator) ValidateCreate(_ context.Context, obj runtime.
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Database .
 func (v *DatabaseCustomValidator) ValidateUpdate(
-	_ context.Context,
+	ctx context.Context,
 	oldObj, newObj runtime.Object,
 ) (admission.Warnings, error) {
 	database, ok := newObj.(*apiv1.Database)
@@ -118,6 +128,7 @@ func (v *DatabaseCustomValidator) ValidateUpdate(
 		v.validate(database),
 		v.validateDatabaseChanges(database, oldDatabase)...,
 	)
+	allErrs = append(allErrs, v.validateReferences(ctx, database)...)
 	allWarnings := v.getAdmissionWarnings(database)
 
 	if len(allErrs) == 0 {
@@ -154,6 +165,11 @@ func (v *DatabaseCustomValidator) validate(d *apiv1.Database) (allErrs field.Err
 	validations := []validationFunc{
 		v.validateExtensions,
 		v.validateSchemas,
+		v.validateFDWs,
+		v.validateServers,
+		v.validateUserMappings,
+		v.validatePrivileges,
+		v.validateDefaultPrivileges,
 	}
 
 	for _, validate := range validations {
@@ -163,6 +179,50 @@ func (v *DatabaseCustomValidator) validate(d *apiv1.Database) (allErrs field.Err
 	return allErrs
 }
 
+// validateReferences checks that the secrets and the config maps
+// referenced by the database allow it
+func (v *DatabaseCustomValidator) validateReferences(ctx context.Context, d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+	appendError := func(err *field.Error) {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+
+	for i, mapping := range d.Spec.UserMappings {
+		if mapping.CredentialsSecret == nil {
+			continue
+		}
+		appendError(validateDeclarativeReference(
+			ctx, v.client,
+			field.NewPath("spec", "userMappings").Index(i).Child("credentialsSecret", "name"),
+			d.Namespace, mapping.CredentialsSecret.Name, &corev1.Secret{},
+		))
+	}
+
+	if d.Spec.Migrations == nil {
+		return result
+	}
+
+	migrationsPath := field.NewPath("spec", "migrations")
+	for i, ref := range d.Spec.Migrations.SecretRefs {
+		appendError(validateDeclarativeReference(
+			ctx, v.client,
+			migrationsPath.Child("secretRefs").Index(i).Child("name"),
+			d.Namespace, ref.Name, &corev1.Secret{},
+		))
+	}
+	for i, ref := range d.Spec.Migrations.ConfigMapRefs {
+		appendError(validateDeclarativeReference(
+			ctx, v.client,
+			migrationsPath.Child("configMapRefs").Index(i).Child("name"),
+			d.Namespace, ref.Name, &corev1.ConfigMap{},
+		))
+	}
+
+	return result
+}
+
 func (v *DatabaseCustomValidator) getAdmissionWarnings(_ *apiv1.Database) admission.Warnings {
 	return nil
 }
@@ -185,6 +245,17 @@ func (v *DatabaseCustomValidator) validateExtensions(d *apiv1.Database) field.Er
 		}
 
 		extensionNames.Put(name)
+
+		if ext.Version != "" && ext.UpdatePolicy == apiv1.ExtensionUpdatePolicyAutomatic {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "extensions").Index(i).Child("updatePolicy"),
+					ext.UpdatePolicy,
+					"an extension pinned to a version can't be updated automatically",
+				),
+			)
+		}
 	}
 
 	return result
@@ -212,3 +283,168 @@ func (v *DatabaseCustomValidator) validateSchemas(d *apiv1.Database) field.Error
 
 	return result
 }
+
+// validateFDWs validates the database foreign data wrappers
+func (v *DatabaseCustomValidator) validateFDWs(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	fdwNames := stringset.New()
+	for i, fdw := range d.Spec.FDWs {
+		name := fdw.Name
+		if fdwNames.Has(name) {
+			result = append(
+				result,
+				field.Duplicate(
+					field.NewPath("spec", "fdws").Index(i).Child("name"),
+					name,
+				),
+			)
+		}
+
+		fdwNames.Put(name)
+	}
+
+	return result
+}
+
+// validateServers validates the database foreign servers
+func (v *DatabaseCustomValidator) validateServers(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	serverNames := stringset.New()
+	for i, server := range d.Spec.Servers {
+		name := server.Name
+		if serverNames.Has(name) {
+			result = append(
+				result,
+				field.Duplicate(
+					field.NewPath("spec", "servers").Index(i).Child("name"),
+					name,
+				),
+			)
+		}
+
+		serverNames.Put(name)
+	}
+
+	return result
+}
+
+// validateUserMappings validates the database user mappings
+func (v *DatabaseCustomValidator) validateUserMappings(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	userMappingNames := stringset.New()
+	for i, mapping := range d.Spec.UserMappings {
+		path := field.NewPath("spec", "userMappings").Index(i)
+		name := mapping.GetName()
+		if userMappingNames.Has(name) {
+			result = append(result, field.Duplicate(path, name))
+		}
+		userMappingNames.Put(name)
+
+		if mapping.CredentialsSecret == nil {
+			continue
+		}
+		for _, option := range []string{"user", "password"} {
+			if _, ok := mapping.Options[option]; ok {
+				result = append(
+					result,
+					field.Invalid(
+						path.Child("options").Key(option),
+						mapping.Options[option],
+						"cannot be set when credentialsSecret is specified",
+					),
+				)
+			}
+		}
+	}
+
+	return result
+}
+
+// functionSignatureRegex matches a function name followed by the
+// list of its argument types
+var functionSignatureRegex = regexp.MustCompile(`^[^()]+\(.*\)$`)
+
+// validatePrivileges validates the database privileges
+func (v *DatabaseCustomValidator) validatePrivileges(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	privilegeNames := stringset.New()
+	for i, privilege := range d.Spec.Privileges {
+		path := field.NewPath("spec", "privileges").Index(i)
+		name := privilege.GetName()
+		if privilegeNames.Has(name) {
+			result = append(result, field.Duplicate(path, name))
+		}
+		privilegeNames.Put(name)
+
+		result = append(
+			result,
+			validatePrivilegeList(
+				path.Child("privileges"),
+				privilege.Privileges,
+				privilege.ObjectType.GetAvailablePrivileges(),
+			)...,
+		)
+
+		if privilege.ObjectType == apiv1.PrivilegeObjectTypeFunction {
+			for j, object := range privilege.Objects {
+				if !functionSignatureRegex.MatchString(object) {
+					result = append(result, field.Invalid(
+						path.Child("objects").Index(j),
+						object,
+						"functions must be referred to by signature, like `add(integer, integer)`"))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// validateDefaultPrivileges validates the database default privileges
+func (v *DatabaseCustomValidator) validateDefaultPrivileges(d *apiv1.Database) field.ErrorList {
+	var result field.ErrorList
+
+	privilegeNames := stringset.New()
+	for i, privilege := range d.Spec.DefaultPrivileges {
+		path := field.NewPath("spec", "defaultPrivileges").Index(i)
+		name := privilege.GetName()
+		if privilegeNames.Has(name) {
+			result = append(result, field.Duplicate(path, name))
+		}
+		privilegeNames.Put(name)
+
+		result = append(
+			result,
+			validatePrivilegeList(
+				path.Child("privileges"),
+				privilege.Privileges,
+				privilege.ObjectType.GetAvailablePrivileges(),
+			)...,
+		)
+	}
+
+	return result
+}
+
+// validatePrivilegeList checks that every requested privilege can be
+// applied to the object type
+func validatePrivilegeList(path *field.Path, privileges []string, available []string) field.ErrorList {
+	var result field.ErrorList
+
+	availablePrivileges := stringset.From(available)
+	availablePrivileges.Put("ALL")
+	for i, privilege := range privileges {
+		if !availablePrivileges.Has(privilege) {
+			result = append(
+				result,
+				field.NotSupported(path.Index(i), privilege, availablePrivileges.ToSortedList()),
+			)
+		}
+	}
+
+	return result
+}
@@ -20,7 +20,14 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -46,6 +53,25 @@ var _ = Describe("Database validation", func() {
 		}
 	}
 
+	createPrivilegeSpec := func(privileges ...string) apiv1.PrivilegeSpec {
+		return apiv1.PrivilegeSpec{
+			Ensure:     apiv1.EnsurePresent,
+			ObjectType: apiv1.PrivilegeObjectTypeTable,
+			Schema:     "public",
+			Privileges: privileges,
+			Grantee:    "app",
+		}
+	}
+	createDefaultPrivilegeSpec := func(privileges ...string) apiv1.DefaultPrivilegeSpec {
+		return apiv1.DefaultPrivilegeSpec{
+			Ensure:     apiv1.EnsurePresent,
+			Role:       "owner",
+			ObjectType: apiv1.DefaultPrivilegeObjectTypeSequences,
+			Privileges: privileges,
+			Grantee:    "app",
+		}
+	}
+
 	BeforeEach(func() {
 		v = &DatabaseCustomValidator{}
 	})
@@ -91,6 +117,25 @@ var _ = Describe("Database validation", func() {
 			1,
 		),
 
+		Entry(
+			"complain if an extension pinned to a version is updated automatically",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					Extensions: []apiv1.ExtensionSpec{
+						{
+							DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "postgis"},
+							Version:            "3.5.0",
+							UpdatePolicy:       apiv1.ExtensionUpdatePolicyAutomatic,
+						},
+						{
+							DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "cube"},
+							UpdatePolicy:       apiv1.ExtensionUpdatePolicyAutomatic,
+						},
+					},
+				},
+			},
+			1,
+		),
 		Entry(
 			"complain if there are duplicate schemas",
 			&apiv1.Database{
@@ -104,5 +149,161 @@ var _ = Describe("Database validation", func() {
 			},
 			1,
 		),
+		Entry(
+			"doesn't complain if privileges are valid for their object type",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					Privileges: []apiv1.PrivilegeSpec{
+						createPrivilegeSpec("SELECT", "INSERT"),
+						createPrivilegeSpec("ALL"),
+					},
+					DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{
+						createDefaultPrivilegeSpec("USAGE"),
+					},
+				},
+			},
+			0,
+		),
+		Entry(
+			"complain if there are duplicate privileges",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					Privileges: []apiv1.PrivilegeSpec{
+						createPrivilegeSpec("SELECT"),
+						createPrivilegeSpec("SELECT"),
+					},
+					DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{
+						createDefaultPrivilegeSpec("USAGE"),
+						createDefaultPrivilegeSpec("USAGE"),
+					},
+				},
+			},
+			2,
+		),
+		Entry(
+			"complain if privileges don't apply to their object type",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					Privileges: []apiv1.PrivilegeSpec{
+						createPrivilegeSpec("SELECT", "EXECUTE"),
+					},
+					DefaultPrivileges: []apiv1.DefaultPrivilegeSpec{
+						createDefaultPrivilegeSpec("TRUNCATE"),
+					},
+				},
+			},
+			2,
+		),
+		Entry(
+			"complain if functions are not referred to by signature",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					Privileges: []apiv1.PrivilegeSpec{
+						{
+							ObjectType: apiv1.PrivilegeObjectTypeFunction,
+							Schema:     "public",
+							Objects:    []string{"refresh_totals", "refresh_totals(integer)", "add(integer, integer)"},
+							Privileges: []string{"EXECUTE"},
+							Grantee:    "app",
+						},
+					},
+				},
+			},
+			1,
+		),
+		Entry(
+			"complain if there are duplicate foreign data wrappers, servers and user mappings",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					FDWs: []apiv1.FDWSpec{
+						{DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "postgres_fdw"}},
+						{DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "postgres_fdw"}},
+					},
+					Servers: []apiv1.ServerSpec{
+						{DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "remote"}, FdwName: "postgres_fdw"},
+						{DatabaseObjectSpec: apiv1.DatabaseObjectSpec{Name: "remote"}, FdwName: "postgres_fdw"},
+					},
+					UserMappings: []apiv1.UserMappingSpec{
+						{User: "app", Server: "remote"},
+						{User: "app", Server: "remote"},
+						{User: "PUBLIC", Server: "remote"},
+					},
+				},
+			},
+			3,
+		),
+		Entry(
+			"complain if user mapping credentials are set both in options and in a secret",
+			&apiv1.Database{
+				Spec: apiv1.DatabaseSpec{
+					UserMappings: []apiv1.UserMappingSpec{
+						{
+							User:              "app",
+							Server:            "remote",
+							Options:           map[string]string{"user": "remote_app", "sslmode": "require"},
+							CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-credentials"},
+						},
+					},
+				},
+			},
+			1,
+		),
 	)
 })
+
+var _ = Describe("Database references validation", func() {
+	allowed := map[string]string{utils.DeclarativeReferenceLabelName: "true"}
+
+	newDatabase := func() *apiv1.Database {
+		return &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: apiv1.DatabaseSpec{
+				UserMappings: []apiv1.UserMappingSpec{
+					{
+						User:              "app",
+						Server:            "remote",
+						CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-credentials"},
+					},
+				},
+				Migrations: &apiv1.DatabaseMigrations{
+					SQLRefs: apiv1.SQLRefs{
+						SecretRefs: []apiv1.SecretKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "superuser"},
+							Key:                  "V1__init.sql",
+						}},
+						ConfigMapRefs: []apiv1.ConfigMapKeySelector{{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "migrations"},
+							Key:                  "V2__add_table.sql",
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	It("refuses the existing objects not allowing being referenced", func(ctx SpecContext) {
+		v := &DatabaseCustomValidator{
+			client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+						Name: "remote-credentials", Namespace: "default", Labels: allowed,
+					}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "superuser", Namespace: "default"}},
+				).
+				Build(),
+		}
+
+		result := v.validateReferences(ctx, newDatabase())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(result[0].Field).To(Equal("spec.migrations.secretRefs[0].name"))
+	})
+
+	It("accepts the objects which don't exist yet", func(ctx SpecContext) {
+		v := &DatabaseCustomValidator{
+			client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).Build(),
+		}
+
+		Expect(v.validateReferences(ctx, newDatabase())).To(BeEmpty())
+	})
+})
@@ -22,6 +22,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
@@ -213,6 +214,12 @@ func (v *PoolerCustomValidator) validatePgBouncer(r *apiv1.Pooler) field.ErrorLi
 		result = append(result, v.validatePgbouncerGenericParameters(r)...)
 	}
 
+	if r.Spec.PgBouncer != nil {
+		result = append(result, v.validatePgBouncerDatabases(r)...)
+		result = append(result, v.validatePgBouncerUsers(r)...)
+		result = append(result, v.validatePgBouncerCertificateAuthentication(r)...)
+	}
+
 	return result
 }
 
@@ -233,11 +240,77 @@ func (v *PoolerCustomValidator) validateCluster(r *apiv1.Pooler) field.ErrorList
 	return result
 }
 
+func (v *PoolerCustomValidator) validateAutoscaling(r *apiv1.Pooler) field.ErrorList {
+	policy := r.Spec.Autoscaling
+	if policy == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "autoscaling")
+	if policy.MinInstances > policy.MaxInstances {
+		result = append(result,
+			field.Invalid(
+				path.Child("minInstances"),
+				policy.MinInstances, "must not be greater than maxInstances"))
+	}
+
+	if policy.TargetClientsWaiting == nil && policy.TargetAverageWaitTime == nil {
+		result = append(result,
+			field.Required(
+				path,
+				"must specify at least one of targetClientsWaiting and targetAverageWaitTime"))
+	}
+
+	if policy.TargetAverageWaitTime != nil && policy.TargetAverageWaitTime.Duration <= 0 {
+		result = append(result,
+			field.Invalid(
+				path.Child("targetAverageWaitTime"),
+				policy.TargetAverageWaitTime.String(), "must be a positive duration"))
+	}
+
+	if policy.Cooldown != nil && policy.Cooldown.Duration < 0 {
+		result = append(result,
+			field.Invalid(
+				path.Child("cooldown"),
+				policy.Cooldown.String(), "must not be negative"))
+	}
+
+	return result
+}
+
+func (v *PoolerCustomValidator) validateReplicaRouting(r *apiv1.Pooler) field.ErrorList {
+	routing := r.Spec.ReplicaRouting
+	if routing == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "replicaRouting")
+	if r.Spec.Type != apiv1.PoolerTypeRO {
+		result = append(result,
+			field.Invalid(
+				path,
+				routing, "replica routing is only supported by poolers of type ro"))
+	}
+
+	if routing.MaximumLag.Sign() < 0 {
+		result = append(result,
+			field.Invalid(
+				path.Child("maximumLag"),
+				routing.MaximumLag.String(), "must not be negative"))
+	}
+
+	return result
+}
+
 // validate validates the configuration of a Pooler, returning
 // a list of errors
 func (v *PoolerCustomValidator) validate(r *apiv1.Pooler) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, v.validatePgBouncer(r)...)
 	allErrs = append(allErrs, v.validateCluster(r)...)
+	allErrs = append(allErrs, v.validateAutoscaling(r)...)
+	allErrs = append(allErrs, v.validateReplicaRouting(r)...)
 	return allErrs
 }
 
@@ -255,3 +328,106 @@ func (v *PoolerCustomValidator) validatePgbouncerGenericParameters(r *apiv1.Pool
 	}
 	return result
 }
+
+// validatePgBouncerDatabases validates the per-database PgBouncer settings
+func (v *PoolerCustomValidator) validatePgBouncerDatabases(r *apiv1.Pooler) field.ErrorList {
+	var result field.ErrorList
+
+	seen := stringset.New()
+	for idx, database := range r.Spec.PgBouncer.Databases {
+		path := field.NewPath("spec", "pgbouncer", "databases").Index(idx)
+		switch {
+		case database.Name == "":
+			result = append(result, field.Required(path.Child("name"), "must specify the database name"))
+		case database.Name == "*" || database.Name == "pgbouncer":
+			result = append(result,
+				field.Invalid(
+					path.Child("name"),
+					database.Name, "reserved database name"))
+		case seen.Has(database.Name):
+			result = append(result, field.Duplicate(path.Child("name"), database.Name))
+		}
+		seen.Put(database.Name)
+
+		// When dbname is not specified, the name is used as the
+		// connection string value and is subject to the same rules
+		dbnamePath, dbname := path.Child("dbname"), database.DBName
+		if dbname == "" {
+			dbnamePath, dbname = path.Child("name"), database.Name
+		}
+		if strings.ContainsAny(dbname, " \t\r\n'\"=\\") {
+			result = append(result,
+				field.Invalid(
+					dbnamePath,
+					dbname, "must not contain whitespaces, quotes, backslashes or equal signs"))
+		}
+	}
+
+	return result
+}
+
+// validatePgBouncerUsers validates the per-user PgBouncer settings
+func (v *PoolerCustomValidator) validatePgBouncerUsers(r *apiv1.Pooler) field.ErrorList {
+	var result field.ErrorList
+
+	seen := stringset.New()
+	for idx, user := range r.Spec.PgBouncer.Users {
+		path := field.NewPath("spec", "pgbouncer", "users").Index(idx)
+		switch {
+		case user.Name == "":
+			result = append(result, field.Required(path.Child("name"), "must specify the user name"))
+		case seen.Has(user.Name):
+			result = append(result, field.Duplicate(path.Child("name"), user.Name))
+		}
+		seen.Put(user.Name)
+
+		if user.PoolMode == "" && user.MaxUserConnections == nil {
+			result = append(result,
+				field.Required(
+					path,
+					"must specify at least one of poolMode and maxUserConnections"))
+		}
+	}
+
+	return result
+}
+
+// validatePgBouncerCertificateAuthentication validates the mappings
+// between the client certificates and the database users
+func (v *PoolerCustomValidator) validatePgBouncerCertificateAuthentication(r *apiv1.Pooler) field.ErrorList {
+	certificateAuthentication := r.Spec.PgBouncer.CertificateAuthentication
+	if certificateAuthentication == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+
+	// The client certificate of the pooler is only issued
+	// by the operator for the automated integration
+	if !r.IsAutomatedIntegration() {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "certificateAuthentication"),
+				"",
+				"certificate authentication can't be used with a custom authQuery or authQuerySecret"))
+	}
+
+	seen := stringset.New()
+	for idx, mapping := range certificateAuthentication.UserMappings {
+		path := field.NewPath("spec", "pgbouncer", "certificateAuthentication", "userMappings").Index(idx)
+		if mapping.CommonName == "" {
+			result = append(result, field.Required(path.Child("commonName"), "must specify the common name"))
+		}
+		if mapping.User == "" {
+			result = append(result, field.Required(path.Child("user"), "must specify the user"))
+		}
+
+		key := mapping.CommonName + "/" + mapping.User
+		if seen.Has(key) {
+			result = append(result, field.Duplicate(path, mapping))
+		}
+		seen.Put(key)
+	}
+
+	return result
+}
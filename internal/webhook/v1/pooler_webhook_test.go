@@ -20,7 +20,11 @@ SPDX-License-Identifier: Apache-2.0
 package v1
 
 import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -138,4 +142,165 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(v.validatePgbouncerGenericParameters(pooler)).To(BeEmpty())
 	})
+
+	It("allows a valid autoscaling policy", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Autoscaling: &apiv1.PoolerAutoscalingConfiguration{
+					MinInstances:         1,
+					MaxInstances:         5,
+					TargetClientsWaiting: ptr.To(int32(10)),
+				},
+			},
+		}
+		Expect(v.validateAutoscaling(pooler)).To(BeEmpty())
+	})
+
+	It("complains when the autoscaling bounds are inverted", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Autoscaling: &apiv1.PoolerAutoscalingConfiguration{
+					MinInstances:          5,
+					MaxInstances:          1,
+					TargetAverageWaitTime: &metav1.Duration{Duration: 100 * time.Millisecond},
+				},
+			},
+		}
+		Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+	})
+
+	It("complains when the autoscaling policy has no target", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Autoscaling: &apiv1.PoolerAutoscalingConfiguration{
+					MinInstances: 1,
+					MaxInstances: 3,
+				},
+			},
+		}
+		Expect(v.validateAutoscaling(pooler)).To(HaveLen(1))
+	})
+
+	It("allows replica routing on read-only poolers", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Type: apiv1.PoolerTypeRO,
+				ReplicaRouting: &apiv1.PoolerReplicaRoutingConfiguration{
+					MaximumLag: resource.MustParse("16Mi"),
+				},
+			},
+		}
+		Expect(v.validateReplicaRouting(pooler)).To(BeEmpty())
+	})
+
+	It("complains about replica routing on read-write poolers", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				Type: apiv1.PoolerTypeRW,
+				ReplicaRouting: &apiv1.PoolerReplicaRoutingConfiguration{
+					MaximumLag: resource.MustParse("-1"),
+				},
+			},
+		}
+		Expect(v.validateReplicaRouting(pooler)).To(HaveLen(2))
+	})
+
+	It("complains about invalid and duplicated certificate user mappings", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					CertificateAuthentication: &apiv1.PgBouncerCertificateAuthentication{
+						UserMappings: []apiv1.PgBouncerUserMapping{
+							{CommonName: "frontend", User: "app"},
+							{CommonName: "backend", User: "app"},
+							{CommonName: "frontend", User: "app"},
+							{CommonName: "", User: "app"},
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validatePgBouncerCertificateAuthentication(pooler)).To(HaveLen(2))
+	})
+
+	It("requires the automated integration for the certificate authentication", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					AuthQuerySecret: &apiv1.LocalObjectReference{Name: "custom"},
+					AuthQuery:       "SELECT usename, passwd FROM pg_catalog.pg_shadow WHERE usename=$1",
+					CertificateAuthentication: &apiv1.PgBouncerCertificateAuthentication{
+						UserMappings: []apiv1.PgBouncerUserMapping{
+							{CommonName: "frontend", User: "app"},
+						},
+					},
+				},
+			},
+		}
+		Expect(v.validatePgBouncerCertificateAuthentication(pooler)).To(HaveLen(1))
+	})
+
+	It("allows valid per-database and per-user settings", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Databases: []apiv1.PgBouncerDatabase{
+						{Name: "app", PoolSize: ptr.To(int32(10))},
+						{Name: "reporting", DBName: "app", PoolMode: apiv1.PgBouncerPoolModeSession},
+					},
+					Users: []apiv1.PgBouncerUser{
+						{Name: "batch", MaxUserConnections: ptr.To(int32(5))},
+					},
+				},
+			},
+		}
+		Expect(v.validatePgBouncer(pooler)).To(BeEmpty())
+	})
+
+	It("complains about reserved and duplicated database names", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Databases: []apiv1.PgBouncerDatabase{
+						{Name: "pgbouncer"},
+						{Name: "app"},
+						{Name: "app"},
+						{Name: "other", DBName: "app dbname=postgres"},
+					},
+				},
+			},
+		}
+		Expect(v.validatePgBouncerDatabases(pooler)).To(HaveLen(3))
+	})
+
+	It("complains about invalid characters in the name used as dbname", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Databases: []apiv1.PgBouncerDatabase{
+						{Name: "app dbname=postgres"},
+						{Name: "app'"},
+						{Name: "other host=evil", DBName: "app"},
+					},
+				},
+			},
+		}
+		result := v.validatePgBouncerDatabases(pooler)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.pgbouncer.databases[0].name"))
+		Expect(result[1].Field).To(Equal("spec.pgbouncer.databases[1].name"))
+	})
+
+	It("complains about users without settings", func() {
+		pooler := &apiv1.Pooler{
+			Spec: apiv1.PoolerSpec{
+				PgBouncer: &apiv1.PgBouncerSpec{
+					Users: []apiv1.PgBouncerUser{
+						{Name: "app"},
+					},
+				},
+			},
+		}
+		Expect(v.validatePgBouncerUsers(pooler)).To(HaveLen(1))
+	})
 })
@@ -0,0 +1,130 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// roleLog is for logging in this package.
+var roleLog = log.WithName("role-resource").WithValues("version", "v1")
+
+// SetupRoleWebhookWithManager registers the webhook for Role in the manager.
+func SetupRoleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.Role{}).
+		WithValidator(newBypassableValidator(&RoleCustomValidator{client: mgr.GetClient()})).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-role,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=roles,versions=v1,name=vrole.cnpg.io,sideEffects=None
+
+// RoleCustomValidator is responsible for validating the Role
+// resource when it is created or updated.
+type RoleCustomValidator struct {
+	// client is used to check the password secret
+	// referenced by the Role
+	client client.Client
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Role.
+func (v *RoleCustomValidator) ValidateCreate(
+	ctx context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	role, ok := obj.(*apiv1.Role)
+	if !ok {
+		return nil, fmt.Errorf("expected a Role object but got %T", obj)
+	}
+	roleLog.Info("Validation for Role upon creation",
+		"name", role.GetName(), "namespace", role.GetNamespace())
+
+	allErrs := v.validate(ctx, role)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Role"},
+		role.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Role.
+func (v *RoleCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	role, ok := newObj.(*apiv1.Role)
+	if !ok {
+		return nil, fmt.Errorf("expected a Role object for the newObj but got %T", newObj)
+	}
+	roleLog.Info("Validation for Role upon update",
+		"name", role.GetName(), "namespace", role.GetNamespace())
+
+	allErrs := v.validate(ctx, role)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Role"},
+		role.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Role.
+func (v *RoleCustomValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks that the password secret of the role, if any,
+// allows being referenced
+func (v *RoleCustomValidator) validate(ctx context.Context, r *apiv1.Role) field.ErrorList {
+	if r.Spec.PasswordSecret == nil || r.Spec.DisablePassword {
+		return nil
+	}
+
+	if err := validateDeclarativeReference(
+		ctx, v.client,
+		field.NewPath("spec", "passwordSecret", "name"),
+		r.Namespace, r.Spec.PasswordSecret.Name, &corev1.Secret{},
+	); err != nil {
+		return field.ErrorList{err}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role validation", func() {
+	var v *RoleCustomValidator
+	BeforeEach(func() {
+		v = &RoleCustomValidator{
+			client: fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+						Name:      "reader-password",
+						Namespace: "default",
+						Labels:    map[string]string{utils.DeclarativeReferenceLabelName: "true"},
+					}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster-example-superuser",
+						Namespace: "default",
+					}},
+				).
+				Build(),
+		}
+	})
+
+	newRole := func(secretName string) *apiv1.Role {
+		return &apiv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "default"},
+			Spec: apiv1.RoleSpec{
+				RoleConfiguration: apiv1.RoleConfiguration{
+					Name:           "reader",
+					PasswordSecret: &apiv1.LocalObjectReference{Name: secretName},
+				},
+			},
+		}
+	}
+
+	It("accepts the password secrets allowing being referenced", func(ctx SpecContext) {
+		Expect(v.validate(ctx, newRole("reader-password"))).To(BeEmpty())
+	})
+
+	It("accepts the password secrets which don't exist yet", func(ctx SpecContext) {
+		Expect(v.validate(ctx, newRole("missing"))).To(BeEmpty())
+	})
+
+	It("rejects the password secrets not allowing being referenced", func(ctx SpecContext) {
+		result := v.validate(ctx, newRole("cluster-example-superuser"))
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(result[0].Field).To(Equal("spec.passwordSecret.name"))
+	})
+})
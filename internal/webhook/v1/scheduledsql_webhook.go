@@ -0,0 +1,126 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/robfig/cron"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// scheduledSQLLog is for logging in this package.
+var scheduledSQLLog = log.WithName("scheduledsql-resource").WithValues("version", "v1")
+
+// SetupScheduledSQLWebhookWithManager registers the webhook for ScheduledSQL in the manager.
+func SetupScheduledSQLWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.ScheduledSQL{}).
+		WithValidator(newBypassableValidator(&ScheduledSQLCustomValidator{})).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-scheduledsql,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=scheduledsqls,versions=v1,name=vscheduledsql.cnpg.io,sideEffects=None
+
+// ScheduledSQLCustomValidator is responsible for validating the ScheduledSQL
+// resource when it is created or updated.
+type ScheduledSQLCustomValidator struct{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ScheduledSQL.
+func (v *ScheduledSQLCustomValidator) ValidateCreate(
+	_ context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	scheduledSQL, ok := obj.(*apiv1.ScheduledSQL)
+	if !ok {
+		return nil, fmt.Errorf("expected a ScheduledSQL object but got %T", obj)
+	}
+	scheduledSQLLog.Info("Validation for ScheduledSQL upon creation",
+		"name", scheduledSQL.GetName(), "namespace", scheduledSQL.GetNamespace())
+
+	allErrs := v.validate(scheduledSQL)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "ScheduledSQL"},
+		scheduledSQL.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ScheduledSQL.
+func (v *ScheduledSQLCustomValidator) ValidateUpdate(
+	_ context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	scheduledSQL, ok := newObj.(*apiv1.ScheduledSQL)
+	if !ok {
+		return nil, fmt.Errorf("expected a ScheduledSQL object for the newObj but got %T", newObj)
+	}
+	scheduledSQLLog.Info("Validation for ScheduledSQL upon update",
+		"name", scheduledSQL.GetName(), "namespace", scheduledSQL.GetNamespace())
+
+	allErrs := v.validate(scheduledSQL)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "ScheduledSQL"},
+		scheduledSQL.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ScheduledSQL.
+func (v *ScheduledSQLCustomValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the schedule, which must be a valid cron expression
+// made of six fields, including the seconds
+func (v *ScheduledSQLCustomValidator) validate(r *apiv1.ScheduledSQL) field.ErrorList {
+	var result field.ErrorList
+
+	schedulePath := field.NewPath("spec", "schedule")
+	if fields := len(strings.Fields(r.Spec.Schedule)); fields != 6 {
+		result = append(result, field.Invalid(
+			schedulePath,
+			r.Spec.Schedule,
+			fmt.Sprintf("the schedule must have six fields, including the seconds, but it has %d", fields)))
+	} else if _, err := cron.Parse(r.Spec.Schedule); err != nil {
+		result = append(result, field.Invalid(schedulePath, r.Spec.Schedule, err.Error()))
+	}
+
+	return result
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package v1
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScheduledSQL validation", func() {
+	var v *ScheduledSQLCustomValidator
+	BeforeEach(func() {
+		v = &ScheduledSQLCustomValidator{}
+	})
+
+	newScheduledSQL := func(schedule string) *apiv1.ScheduledSQL {
+		return &apiv1.ScheduledSQL{
+			Spec: apiv1.ScheduledSQLSpec{
+				Schedule: schedule,
+				SQL:      []string{"ANALYZE"},
+			},
+		}
+	}
+
+	It("accepts a valid schedule", func() {
+		Expect(v.validate(newScheduledSQL("0 0 * * * *"))).To(BeEmpty())
+		Expect(v.validate(newScheduledSQL("0 */15 8-18 * * MON-FRI"))).To(BeEmpty())
+	})
+
+	It("rejects the schedules without the seconds", func() {
+		result := v.validate(newScheduledSQL("0 * * * *"))
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.schedule"))
+		Expect(result[0].Detail).To(ContainSubstring("six fields"))
+	})
+
+	It("rejects the schedules with too many fields", func() {
+		Expect(v.validate(newScheduledSQL("0 0 0 * * * 1996"))).To(HaveLen(1))
+	})
+
+	It("rejects the invalid schedules", func() {
+		result := v.validate(newScheduledSQL("0 0 25 * * *"))
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.schedule"))
+
+		Expect(v.validate(newScheduledSQL("not a valid cron schedule"))).To(HaveLen(1))
+	})
+})
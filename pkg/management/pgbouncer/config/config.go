@@ -56,6 +56,17 @@ const (
 	authUserCrtPath            = ConfigsDir + "/authUser/tls.crt"
 	authUserKeyPath            = ConfigsDir + "/authUser/tls.key"
 	authFilePath               = ConfigsDir + "/userlist.txt"
+	identFilePath              = ConfigsDir + "/pg_ident.conf"
+
+	// certificateAuthenticationCrtPath and certificateAuthenticationKeyPath
+	// are the paths of the client certificate used to connect on behalf
+	// of the clients authenticated through TLS certificates
+	certificateAuthenticationCrtPath = ConfigsDir + "/certificateAuthentication/tls.crt"
+	certificateAuthenticationKeyPath = ConfigsDir + "/certificateAuthentication/tls.key"
+
+	// pgBouncerCertificateMap is the name of the user map
+	// used to authenticate the clients through certificates
+	pgBouncerCertificateMap = "cnpg_certificate"
 
 	// PgBouncerIniFileName is the name of PgBouncer configuration file
 	PgBouncerIniFileName = "pgbouncer.ini"
@@ -63,6 +74,8 @@ const (
 	PgBouncerHBAConfFileName = "pg_hba.conf"
 	// PgBouncerUserListFileName is the name of PgBouncer user list file
 	PgBouncerUserListFileName = "userlist.txt"
+	// PgBouncerIdentFileName is the name of PgBouncer user name maps file
+	PgBouncerIdentFileName = "pg_ident.conf"
 	// PgBouncerAdminUser is the default admin user for pgbouncer
 	PgBouncerAdminUser = "pgbouncer"
 	// PgBouncerSocketDir is the directory in which pgbouncer socket is
@@ -74,8 +87,12 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
-
+{{ .Databases -}}
+* = host={{ .Host }}
+{{ if .Users }}
+[users]
+{{ .Users -}}
+{{ end }}
 [pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
 auth_user = {{ .AuthQueryUser }}
@@ -89,9 +106,19 @@ local pgbouncer pgbouncer peer
 {{ range $rule := .PgHba }}
 {{ $rule -}}
 {{ end }}
-
+{{ range $user := .CertificateMappedUsers }}
+hostssl all {{ $user }} 0.0.0.0/0 cert map=` + pgBouncerCertificateMap + `
+hostssl all {{ $user }} ::/0 cert map=` + pgBouncerCertificateMap + `
+{{- end }}
 host all all 0.0.0.0/0 md5
 host all all ::/0 md5
+`
+
+	pgBouncerProcessIniTemplateString = `
+%include {{ .BaseIni }}
+
+[pgbouncer]
+unix_socket_dir = {{ .SocketDir }}
 `
 
 	pgBouncerUserListTemplateString = `
@@ -106,6 +133,15 @@ var (
 		template.New(PgBouncerUserListFileName).Parse(pgBouncerUserListTemplateString))
 	pgBouncerHBATemplate = template.Must(
 		template.New(PgBouncerHBAConfFileName).Parse(pgbouncerHBAFileTemplateString))
+	pgBouncerProcessIniTemplate = template.Must(
+		template.New("process.ini").Parse(pgBouncerProcessIniTemplateString))
+
+	// The PgBouncer parameters that are not applied on RELOAD, and
+	// require PgBouncer to be restarted to be changed
+	restartRequiredParameters = []string{
+		"listen_backlog",
+		"pkt_buf",
+	}
 
 	// the PgBouncer parameters we want to have a default different from the default one
 	defaultPgBouncerParameters = map[string]string{
@@ -121,6 +157,7 @@ var (
 		"unix_socket_dir":      PgBouncerSocketDir,
 		"listen_port":          "5432",
 		"listen_addr":          "*",
+		"so_reuseport":         "1",
 		"admin_users":          PgBouncerAdminUser,
 		"auth_type":            "hba",
 		"auth_hba_file":        ConfigsDir + "/pg_hba.conf",
@@ -133,6 +170,39 @@ var (
 	}
 )
 
+// GetRestartRequiredParameters returns the subset of the passed PgBouncer
+// parameters that can only be applied by restarting PgBouncer
+func GetRestartRequiredParameters(parameters map[string]string) map[string]string {
+	result := make(map[string]string)
+	for _, name := range restartRequiredParameters {
+		if value, ok := parameters[name]; ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// BuildProcessConfiguration creates the configuration file of a single
+// PgBouncer process. It includes the main PgBouncer configuration file,
+// overriding the directory of the Unix socket, which must not be shared
+// among the PgBouncer processes listening on the same port
+func BuildProcessConfiguration(baseIni, socketDir string) ([]byte, error) {
+	var processIni bytes.Buffer
+
+	err := pgBouncerProcessIniTemplate.Execute(&processIni, struct {
+		BaseIni   string
+		SocketDir string
+	}{
+		BaseIni:   baseIni,
+		SocketDir: socketDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("while executing the process configuration template: %w", err)
+	}
+
+	return processIni.Bytes(), nil
+}
+
 // BuildConfigurationFiles create the config files containing the pgbouncer configuration and
 // the users file
 func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (ConfigurationFiles, error) {
@@ -185,15 +255,46 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		parameters["auth_file"] = authFilePath
 	}
 
+	// When the clients are authenticated through certificates, PgBouncer
+	// connects to PostgreSQL on their behalf with the client certificate
+	// dedicated to the pooler, which is also used by the auth query user
+	certificateAuthentication := pooler.Spec.PgBouncer.CertificateAuthentication
+	if certificateAuthentication != nil {
+		if secrets.CertificateAuthentication == nil {
+			return nil, fmt.Errorf("certificate authentication requires the client certificate of the pooler")
+		}
+		if _, err := certs.ParseServerSecret(secrets.CertificateAuthentication); err != nil {
+			return nil, fmt.Errorf("while parsing the client certificate of the pooler: %w", err)
+		}
+
+		delete(files, authUserCrtPath)
+		delete(files, authUserKeyPath)
+		parameters["server_tls_cert_file"] = certificateAuthenticationCrtPath
+		parameters["server_tls_key_file"] = certificateAuthenticationKeyPath
+		parameters["auth_ident_file"] = identFilePath
+		files[certificateAuthenticationCrtPath] = secrets.CertificateAuthentication.Data[certs.TLSCertKey]
+		files[certificateAuthenticationKeyPath] = secrets.CertificateAuthentication.Data[certs.TLSPrivateKeyKey]
+		files[identFilePath] = []byte(stringifyPgBouncerUserMappings(certificateAuthentication.UserMappings))
+	}
+
+	host := pooler.GetServerHost()
 	templateData := struct {
 		Pooler            *apiv1.Pooler
+		Host              string
+		Databases         string
+		Users             string
 		AuthQuery         string
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
 		PgHba             []string
+
+		CertificateMappedUsers []string
 	}{
 		Pooler:            pooler,
+		Host:              host,
+		Databases:         stringifyPgBouncerDatabases(pooler.Spec.PgBouncer.Databases, host),
+		Users:             stringifyPgBouncerUsers(pooler.Spec.PgBouncer.Users),
 		AuthQuery:         pooler.GetAuthQuery(),
 		AuthQueryUser:     authQueryUser,
 		AuthQueryPassword: authQueryPassword,
@@ -207,6 +308,9 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		Parameters: stringifyPgBouncerParameters(parameters),
 		PgHba:      pooler.Spec.PgBouncer.PgHBA,
 	}
+	if certificateAuthentication != nil {
+		templateData.CertificateMappedUsers = getPgBouncerMappedUsers(certificateAuthentication.UserMappings)
+	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
 	if err != nil {
@@ -0,0 +1,135 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgBouncer restart required parameters", func() {
+	It("only returns the parameters that require a restart", func() {
+		Expect(GetRestartRequiredParameters(map[string]string{
+			"listen_backlog":  "256",
+			"max_client_conn": "1000",
+			"pkt_buf":         "8192",
+		})).To(Equal(map[string]string{
+			"listen_backlog": "256",
+			"pkt_buf":        "8192",
+		}))
+	})
+
+	It("returns an empty map when no such parameter is set", func() {
+		Expect(GetRestartRequiredParameters(map[string]string{
+			"max_client_conn": "1000",
+		})).To(BeEmpty())
+		Expect(GetRestartRequiredParameters(nil)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("PgBouncer process configuration", func() {
+	It("includes the main configuration file overriding the socket directory", func() {
+		content, err := BuildProcessConfiguration("/controller/configs/pgbouncer.ini", "/controller/run/pgbouncer-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal(`
+%include /controller/configs/pgbouncer.ini
+
+[pgbouncer]
+unix_socket_dir = /controller/run/pgbouncer-2
+`))
+	})
+})
+
+var _ = Describe("PgBouncer certificate authentication", func() {
+	var secrets *Secrets
+	pooler := &apiv1.Pooler{
+		ObjectMeta: metav1.ObjectMeta{Name: "pooler", Namespace: "default"},
+		Spec: apiv1.PoolerSpec{
+			Cluster: apiv1.LocalObjectReference{Name: "cluster"},
+			Type:    apiv1.PoolerTypeRW,
+			PgBouncer: &apiv1.PgBouncerSpec{
+				PoolMode: apiv1.PgBouncerPoolModeSession,
+				CertificateAuthentication: &apiv1.PgBouncerCertificateAuthentication{
+					UserMappings: []apiv1.PgBouncerUserMapping{
+						{CommonName: "frontend", User: "app"},
+					},
+				},
+			},
+		},
+	}
+
+	BeforeEach(func() {
+		ca, err := certs.CreateRootCA("ca", "cluster")
+		Expect(err).ToNot(HaveOccurred())
+		authQueryPair, err := ca.CreateAndSignPair(apiv1.PGBouncerPoolerUserName, certs.CertTypeClient, nil)
+		Expect(err).ToNot(HaveOccurred())
+		poolerPair, err := ca.CreateAndSignPair(pooler.GetCertificateAuthenticationCommonName(), certs.CertTypeClient, nil)
+		Expect(err).ToNot(HaveOccurred())
+		serverPair, err := ca.CreateAndSignPair("cluster-rw", certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		secrets = &Secrets{
+			AuthQuery:                 authQueryPair.GenerateCertificateSecret("default", "cluster-pooler"),
+			Client:                    serverPair.GenerateCertificateSecret("default", "cluster-server"),
+			ClientCA:                  ca.GenerateCASecret("default", "cluster-ca"),
+			ServerCA:                  ca.GenerateCASecret("default", "cluster-ca"),
+			CertificateAuthentication: poolerPair.GenerateCertificateSecret("default", "pooler-certificate-authentication"),
+		}
+	})
+
+	It("connects to PostgreSQL with the certificate of the pooler", func() {
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+
+		ini := string(files[filepath.Join(ConfigsDir, PgBouncerIniFileName)])
+		Expect(ini).To(ContainSubstring("auth_user = " + apiv1.PGBouncerPoolerUserName + "\n"))
+		Expect(ini).To(ContainSubstring("server_tls_cert_file = " + certificateAuthenticationCrtPath + "\n"))
+		Expect(ini).To(ContainSubstring("server_tls_key_file = " + certificateAuthenticationKeyPath + "\n"))
+		Expect(files[certificateAuthenticationCrtPath]).To(Equal(secrets.CertificateAuthentication.Data[certs.TLSCertKey]))
+		Expect(files).ToNot(HaveKey(authUserCrtPath))
+	})
+
+	It("keeps the password authentication for the users without a mapping", func() {
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+
+		hba := string(files[filepath.Join(ConfigsDir, PgBouncerHBAConfFileName)])
+		Expect(hba).To(ContainSubstring(
+			"\nhostssl all app 0.0.0.0/0 cert map=cnpg_certificate\n" +
+				"hostssl all app ::/0 cert map=cnpg_certificate\n" +
+				"host all all 0.0.0.0/0 md5\n" +
+				"host all all ::/0 md5\n"))
+		Expect(string(files[filepath.Join(ConfigsDir, PgBouncerIniFileName)])).ToNot(
+			ContainSubstring("client_tls_sslmode = verify-ca"))
+	})
+
+	It("requires the certificate of the pooler", func() {
+		secrets.CertificateAuthentication = nil
+		_, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).To(HaveOccurred())
+	})
+})
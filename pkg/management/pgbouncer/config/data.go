@@ -36,6 +36,11 @@ type Secrets struct {
 
 	// The CA that will be used to validate the connections to PostgreSQL
 	ServerCA *corev1.Secret
+
+	// The TLS secret containing the client certificate used to connect
+	// to PostgreSQL on behalf of the clients authenticated through TLS
+	// certificates
+	CertificateAuthentication *corev1.Secret
 }
 
 // ConfigurationFiles is a set of configuration files that are needed for
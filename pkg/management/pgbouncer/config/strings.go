@@ -22,8 +22,11 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // stringifyPgBouncerParameters will take map of PgBouncer parameters and emit
@@ -42,6 +45,102 @@ func stringifyPgBouncerParameters(parameters map[string]string) (paramsString st
 	return paramsString
 }
 
+// stringifyPgBouncerDatabases emits the entries of the `[databases]` section
+// for the databases having specific settings, all pointing to the passed host
+func stringifyPgBouncerDatabases(databases []apiv1.PgBouncerDatabase, host string) (databasesString string) {
+	for _, database := range databases {
+		dbname := database.DBName
+		if dbname == "" {
+			dbname = database.Name
+		}
+
+		options := []string{
+			fmt.Sprintf("host=%s", host),
+			fmt.Sprintf("dbname=%s", cleanupPgBouncerValue(dbname)),
+		}
+		if database.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", database.PoolMode))
+		}
+		if database.PoolSize != nil {
+			options = append(options, fmt.Sprintf("pool_size=%d", *database.PoolSize))
+		}
+		if database.ReservePoolSize != nil {
+			options = append(options, fmt.Sprintf("reserve_pool=%d", *database.ReservePoolSize))
+		}
+		if database.MaxDBConnections != nil {
+			options = append(options, fmt.Sprintf("max_db_connections=%d", *database.MaxDBConnections))
+		}
+
+		databasesString += fmt.Sprintf("%s = %s\n", quotePgBouncerName(database.Name), strings.Join(options, " "))
+	}
+	return databasesString
+}
+
+// stringifyPgBouncerUsers emits the entries of the `[users]` section,
+// skipping the users without any specific setting
+func stringifyPgBouncerUsers(users []apiv1.PgBouncerUser) (usersString string) {
+	for _, user := range users {
+		var options []string
+		if user.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", user.PoolMode))
+		}
+		if user.MaxUserConnections != nil {
+			options = append(options, fmt.Sprintf("max_user_connections=%d", *user.MaxUserConnections))
+		}
+		if len(options) == 0 {
+			continue
+		}
+
+		usersString += fmt.Sprintf("%s = %s\n", quotePgBouncerName(user.Name), strings.Join(options, " "))
+	}
+	return usersString
+}
+
+// stringifyPgBouncerUserMappings emits the content of the ident file, mapping
+// the common names of the client certificates to the database users. As the
+// mapped users are authenticated through the map, they are also mapped to
+// themselves
+func stringifyPgBouncerUserMappings(mappings []apiv1.PgBouncerUserMapping) (identString string) {
+	for _, user := range getPgBouncerMappedUsers(mappings) {
+		identString += fmt.Sprintf("%s %s %s\n", pgBouncerCertificateMap, user, user)
+	}
+	for _, mapping := range mappings {
+		identString += fmt.Sprintf("%s %s %s\n",
+			pgBouncerCertificateMap,
+			quotePgBouncerName(mapping.CommonName),
+			quotePgBouncerName(mapping.User))
+	}
+	return identString
+}
+
+// getPgBouncerMappedUsers returns the quoted names of the database
+// users having a certificate mapping, without duplicates
+func getPgBouncerMappedUsers(mappings []apiv1.PgBouncerUserMapping) []string {
+	var users []string
+	for _, mapping := range mappings {
+		user := quotePgBouncerName(mapping.User)
+		if !slices.Contains(users, user) {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// The names of databases and users not matching this regexp
+// need to be quoted in the PgBouncer configuration
+var plainNameRegexp = regexp.MustCompile(`^[_0-9A-Za-z]+$`)
+
+// quotePgBouncerName quotes the name of a database or a user, when
+// needed, using the SQL identifier quoting supported by PgBouncer
+func quotePgBouncerName(name string) string {
+	name = cleanupPgBouncerValue(name)
+	if plainNameRegexp.MatchString(name) {
+		return name
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
 // buildPgBouncerParameters will build a PgBouncer configuration applying any
 // default parameters and forcing any required parameter needed for the
 // controller to work correctly
@@ -20,6 +20,10 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 import (
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -47,4 +51,47 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pool_mode.*"))
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
+
+	It("emits the per-database settings", func() {
+		databases := stringifyPgBouncerDatabases([]apiv1.PgBouncerDatabase{
+			{
+				Name:             "app",
+				PoolMode:         apiv1.PgBouncerPoolModeTransaction,
+				PoolSize:         ptr.To(int32(20)),
+				ReservePoolSize:  ptr.To(int32(5)),
+				MaxDBConnections: ptr.To(int32(50)),
+			},
+			{
+				Name:   "reporting-app",
+				DBName: "app",
+			},
+		}, "cluster-example-rw")
+		Expect(databases).To(Equal(
+			"app = host=cluster-example-rw dbname=app pool_mode=transaction pool_size=20 " +
+				"reserve_pool=5 max_db_connections=50\n" +
+				"\"reporting-app\" = host=cluster-example-rw dbname=app\n"))
+	})
+
+	It("emits the per-user settings, skipping the users without settings", func() {
+		users := stringifyPgBouncerUsers([]apiv1.PgBouncerUser{
+			{Name: "batch", PoolMode: apiv1.PgBouncerPoolModeSession, MaxUserConnections: ptr.To(int32(10))},
+			{Name: "app"},
+		})
+		Expect(users).To(Equal("batch = pool_mode=session max_user_connections=10\n"))
+	})
+
+	It("quotes the names when needed", func() {
+		Expect(quotePgBouncerName("app_1")).To(Equal("app_1"))
+		Expect(quotePgBouncerName(`my "app"`)).To(Equal(`"my ""app"""`))
+	})
+
+	It("emits the certificate user mappings", func() {
+		ident := stringifyPgBouncerUserMappings([]apiv1.PgBouncerUserMapping{
+			{CommonName: "frontend.example.com", User: "app"},
+			{CommonName: "backend.example.com", User: "app"},
+		})
+		Expect(ident).To(Equal("cnpg_certificate app app\n" +
+			"cnpg_certificate \"frontend.example.com\" app\n" +
+			"cnpg_certificate \"backend.example.com\" app\n"))
+	})
 })
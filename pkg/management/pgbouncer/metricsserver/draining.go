@@ -0,0 +1,207 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/pgbouncer/config"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// DrainingMetrics contains the metrics of the PgBouncer processes that
+// have been replaced by a restart, and are still serving their clients.
+// They are summed over every pool of every process.
+type DrainingMetrics struct {
+	Processes prometheus.Gauge
+	ClActive  prometheus.Gauge
+	ClWaiting prometheus.Gauge
+	SvActive  prometheus.Gauge
+}
+
+// Describe produces the description for all the contained Metrics
+func (r *DrainingMetrics) Describe(ch chan<- *prometheus.Desc) {
+	r.Processes.Describe(ch)
+	r.ClActive.Describe(ch)
+	r.ClWaiting.Describe(ch)
+	r.SvActive.Describe(ch)
+}
+
+// Reset resets all the contained Metrics
+func (r *DrainingMetrics) Reset() {
+	r.Processes.Set(0)
+	r.ClActive.Set(0)
+	r.ClWaiting.Set(0)
+	r.SvActive.Set(0)
+}
+
+// NewDrainingMetrics builds the metrics of the replaced PgBouncer processes
+func NewDrainingMetrics(subsystem string) *DrainingMetrics {
+	subsystem += "_draining"
+	return &DrainingMetrics{
+		Processes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "processes",
+			Help:      "Number of PgBouncer processes replaced by a restart and still serving their clients.",
+		}),
+		ClActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "cl_active",
+			Help: "Client connections of the replaced PgBouncer processes " +
+				"that are linked to server connection and can process queries.",
+		}),
+		ClWaiting: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "cl_waiting",
+			Help: "Client connections of the replaced PgBouncer processes " +
+				"that have sent queries but have not yet got a server connection.",
+		}),
+		SvActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "sv_active",
+			Help:      "Server connections of the replaced PgBouncer processes that are linked to a client.",
+		}),
+	}
+}
+
+func (e *Exporter) collectDrainingProcesses(ch chan<- prometheus.Metric) {
+	contextLogger := log.FromContext(e.ctx)
+
+	e.Metrics.Draining.Reset()
+
+	var socketDirs []string
+	if e.drainingSocketDirs != nil {
+		socketDirs = e.drainingSocketDirs()
+	}
+	e.pruneDrainingPools(socketDirs)
+
+	for _, socketDir := range socketDirs {
+		db, err := e.drainingConnectionPool(socketDir).Connection("pgbouncer")
+		if err != nil {
+			contextLogger.Error(err, "Error opening connection to a replaced PgBouncer process",
+				"socketDir", socketDir)
+			continue
+		}
+
+		// The process may terminate while being queried, when
+		// its last client disconnects
+		if err := e.collectDrainingPools(db); err != nil {
+			contextLogger.Debug("Error while executing SHOW POOLS in a replaced PgBouncer process",
+				"socketDir", socketDir, "error", err.Error())
+			continue
+		}
+		e.Metrics.Draining.Processes.Inc()
+	}
+
+	e.Metrics.Draining.Processes.Collect(ch)
+	e.Metrics.Draining.ClActive.Collect(ch)
+	e.Metrics.Draining.ClWaiting.Collect(ch)
+	e.Metrics.Draining.SvActive.Collect(ch)
+}
+
+// collectDrainingPools adds the connections of every pool of a replaced
+// PgBouncer process to the draining metrics. The columns are looked up
+// by name, as they change with the PgBouncer version.
+func (e *Exporter) collectDrainingPools(db *sql.DB) error {
+	rows, err := db.Query("SHOW POOLS;")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	gauges := map[string]prometheus.Gauge{
+		"cl_active":  e.Metrics.Draining.ClActive,
+		"cl_waiting": e.Metrics.Draining.ClWaiting,
+		"sv_active":  e.Metrics.Draining.SvActive,
+	}
+	values := make([]sql.NullFloat64, len(cols))
+	destinations := make([]any, len(cols))
+	for i, col := range cols {
+		if _, ok := gauges[col]; ok {
+			destinations[i] = &values[i]
+		} else {
+			destinations[i] = new(sql.RawBytes)
+		}
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(destinations...); err != nil {
+			return err
+		}
+		for i, col := range cols {
+			if gauge, ok := gauges[col]; ok {
+				gauge.Add(values[i].Float64)
+			}
+		}
+	}
+
+	return rows.Err()
+}
+
+// drainingConnectionPool gets or initializes the connection pool
+// for the replaced PgBouncer process listening in the passed
+// Unix socket directory
+func (e *Exporter) drainingConnectionPool(socketDir string) pool.Pooler {
+	if e.drainingPools == nil {
+		e.drainingPools = make(map[string]pool.Pooler)
+	}
+
+	socketPool, ok := e.drainingPools[socketDir]
+	if !ok {
+		dsn := fmt.Sprintf(
+			"host=%s port=%v user=%s sslmode=disable",
+			socketDir,
+			config.PgBouncerPort,
+			config.PgBouncerAdminUser,
+		)
+		socketPool = pool.NewPgbouncerConnectionPool(dsn)
+		e.drainingPools[socketDir] = socketPool
+	}
+
+	return socketPool
+}
+
+// pruneDrainingPools drops the connection pools of
+// the replaced PgBouncer processes that terminated
+func (e *Exporter) pruneDrainingPools(socketDirs []string) {
+	running := stringset.From(socketDirs)
+	for socketDir, socketPool := range e.drainingPools {
+		if !running.Has(socketDir) {
+			socketPool.ShutdownConnections()
+			delete(e.drainingPools, socketDir)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Draining PgBouncer processes", func() {
+	var (
+		firstDB    *sql.DB
+		firstMock  sqlmock.Sqlmock
+		secondDB   *sql.DB
+		secondMock sqlmock.Sqlmock
+		socketDirs []string
+		exp        *Exporter
+		ch         chan prometheus.Metric
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		var err error
+		firstDB, firstMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		secondDB, secondMock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		socketDirs = []string{"/run/pgbouncer-2", "/run/pgbouncer-1"}
+		exp = &Exporter{
+			ctx:     ctx,
+			Metrics: newMetrics(),
+			drainingSocketDirs: func() []string {
+				return socketDirs
+			},
+			drainingPools: map[string]pool.Pooler{
+				"/run/pgbouncer-2": fakePooler{db: firstDB},
+				"/run/pgbouncer-1": fakePooler{db: secondDB},
+			},
+		}
+		ch = make(chan prometheus.Metric, 1000)
+	})
+
+	AfterEach(func() {
+		Expect(firstMock.ExpectationsWereMet()).To(Succeed())
+		Expect(secondMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("sums the connections of every pool of every replaced process", func() {
+		firstMock.ExpectQuery("SHOW POOLS;").
+			WillReturnRows(sqlmock.NewRows([]string{"database", "user", "cl_active", "cl_waiting", "sv_active"}).
+				AddRow("app", "app", 3, 1, 2).
+				AddRow("pgbouncer", "pgbouncer", 1, 0, 0))
+		secondMock.ExpectQuery("SHOW POOLS;").
+			WillReturnRows(sqlmock.NewRows([]string{"database", "user", "cl_active", "cl_waiting", "sv_active",
+				"pool_mode"}).
+				AddRow("app", "app", 2, 0, 2, "session"))
+
+		exp.collectDrainingProcesses(ch)
+
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.Processes)).To(BeEquivalentTo(2))
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.ClActive)).To(BeEquivalentTo(6))
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.ClWaiting)).To(BeEquivalentTo(1))
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.SvActive)).To(BeEquivalentTo(4))
+	})
+
+	It("skips the processes terminating while being queried", func() {
+		firstMock.ExpectQuery("SHOW POOLS;").
+			WillReturnRows(sqlmock.NewRows([]string{"database", "user", "cl_active", "cl_waiting", "sv_active"}).
+				AddRow("app", "app", 3, 1, 2))
+		secondMock.ExpectQuery("SHOW POOLS;").WillReturnError(errors.New("connection closed"))
+
+		exp.collectDrainingProcesses(ch)
+
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.Processes)).To(BeEquivalentTo(1))
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.ClActive)).To(BeEquivalentTo(3))
+	})
+
+	It("drops the connection pools of the terminated processes", func() {
+		socketDirs = nil
+
+		exp.collectDrainingProcesses(ch)
+
+		Expect(exp.drainingPools).To(BeEmpty())
+		Expect(testutil.ToFloat64(exp.Metrics.Draining.Processes)).To(BeZero())
+	})
+})
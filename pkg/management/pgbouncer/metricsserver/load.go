@@ -0,0 +1,177 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// loadRequestTimeout is the maximum time spent waiting
+// for the metrics of a PgBouncer instance
+const loadRequestTimeout = 10 * time.Second
+
+var (
+	clActiveMetricName          = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_pools", "cl_active")
+	clWaitingMetricName         = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_pools", "cl_waiting")
+	svActiveMetricName          = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_pools", "sv_active")
+	svIdleMetricName            = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_pools", "sv_idle")
+	avgWaitTimeMetricName       = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_stats", "avg_wait_time")
+	drainingClActiveMetricName  = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_draining", "cl_active")
+	drainingClWaitingMetricName = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_draining", "cl_waiting")
+	drainingSvActiveMetricName  = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer_draining", "sv_active")
+	pausedMetricName            = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer", "paused")
+	lastReloadTimeMetricName    = prometheus.BuildFQName(PrometheusNamespace, "pgbouncer", "last_reload_timestamp_seconds")
+)
+
+// Load is the load of a PgBouncer instance, as reported by its exporter,
+// together with the state of the instance. The connections include the
+// ones of the PgBouncer processes replaced by a restart.
+type Load struct {
+	// ClientsActive is the number of client connections linked
+	// to a server connection, in every pool
+	ClientsActive float64
+
+	// ClientsWaiting is the number of client connections waiting
+	// for a server connection, in every pool
+	ClientsWaiting float64
+
+	// ServersActive is the number of server connections
+	// linked to a client, in every pool
+	ServersActive float64
+
+	// ServersIdle is the number of server connections
+	// available for a client, in every pool
+	ServersIdle float64
+
+	// AverageWaitTime is the average time spent by clients waiting for
+	// a server connection, in the most loaded database
+	AverageWaitTime time.Duration
+
+	// Paused is true when PgBouncer is paused
+	Paused bool
+
+	// LastReloadTime is the time when PgBouncer loaded its configuration
+	// for the last time, zero when not reported
+	LastReloadTime time.Time
+}
+
+// LoadClient retrieves the load of the PgBouncer instances
+type LoadClient interface {
+	// GetLoad retrieves the load of the PgBouncer instance
+	// running in the passed Pod
+	GetLoad(ctx context.Context, pod *corev1.Pod) (Load, error)
+}
+
+type loadClientImpl struct {
+	*http.Client
+}
+
+// NewLoadClient creates a client retrieving the load of the
+// PgBouncer instances from their metrics endpoint
+func NewLoadClient() LoadClient {
+	return &loadClientImpl{
+		Client: &http.Client{Timeout: loadRequestTimeout},
+	}
+}
+
+// GetLoad implements the LoadClient interface
+func (c *loadClientImpl) GetLoad(ctx context.Context, pod *corev1.Pod) (Load, error) {
+	httpURL := url.Build("http", pod.Status.PodIP, url.PathMetrics, url.PgBouncerMetricsPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpURL, nil)
+	if err != nil {
+		return Load{}, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return Load{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return Load{}, fmt.Errorf("unexpected status code while reading the metrics of %s: %d",
+			pod.Name, resp.StatusCode)
+	}
+
+	return ParseLoad(resp.Body)
+}
+
+// ParseLoad extracts the load of a PgBouncer instance from the
+// metrics it exposes, in the Prometheus text format
+func ParseLoad(in io.Reader) (Load, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return Load{}, fmt.Errorf("while parsing PgBouncer metrics: %w", err)
+	}
+
+	var result Load
+	result.ClientsActive = sumGaugeValues(families[clActiveMetricName]) +
+		sumGaugeValues(families[drainingClActiveMetricName])
+	result.ClientsWaiting = sumGaugeValues(families[clWaitingMetricName]) +
+		sumGaugeValues(families[drainingClWaitingMetricName])
+	result.ServersActive = sumGaugeValues(families[svActiveMetricName]) +
+		sumGaugeValues(families[drainingSvActiveMetricName])
+	result.ServersIdle = sumGaugeValues(families[svIdleMetricName])
+	result.Paused = sumGaugeValues(families[pausedMetricName]) > 0
+	if timestamp := sumGaugeValues(families[lastReloadTimeMetricName]); timestamp > 0 {
+		result.LastReloadTime = time.Unix(int64(timestamp), 0)
+	}
+
+	if family, ok := families[avgWaitTimeMetricName]; ok {
+		for _, metric := range family.GetMetric() {
+			// avg_wait_time is expressed in microseconds
+			waitTime := time.Duration(getGaugeValue(metric)) * time.Microsecond
+			result.AverageWaitTime = max(result.AverageWaitTime, waitTime)
+		}
+	}
+
+	return result, nil
+}
+
+// sumGaugeValues sums the values of every gauge in the passed
+// family, which can be nil when the metric is not reported
+func sumGaugeValues(family *dto.MetricFamily) float64 {
+	var result float64
+	for _, metric := range family.GetMetric() {
+		result += getGaugeValue(metric)
+	}
+	return result
+}
+
+func getGaugeValue(metric *dto.Metric) float64 {
+	if metric.GetGauge() == nil {
+		return 0
+	}
+	return metric.GetGauge().GetValue()
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseLoad", func() {
+	It("aggregates the load of the PgBouncer instance", func() {
+		metrics := `# HELP cnpg_pgbouncer_pools_cl_waiting Client connections waiting.
+# TYPE cnpg_pgbouncer_pools_cl_waiting gauge
+cnpg_pgbouncer_pools_cl_waiting{database="app",user="app"} 3
+cnpg_pgbouncer_pools_cl_waiting{database="pgbouncer",user="pgbouncer"} 1
+# HELP cnpg_pgbouncer_stats_avg_wait_time Time spent by clients waiting for a server.
+# TYPE cnpg_pgbouncer_stats_avg_wait_time gauge
+cnpg_pgbouncer_stats_avg_wait_time{database="app"} 15000
+cnpg_pgbouncer_stats_avg_wait_time{database="pgbouncer"} 0
+`
+		load, err := ParseLoad(strings.NewReader(metrics))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(load.ClientsWaiting).To(BeEquivalentTo(4))
+		Expect(load.AverageWaitTime).To(Equal(15 * time.Millisecond))
+	})
+
+	It("reads the connection counts and the state of the PgBouncer instance", func() {
+		metrics := `# TYPE cnpg_pgbouncer_pools_cl_active gauge
+cnpg_pgbouncer_pools_cl_active{database="app",user="app"} 10
+cnpg_pgbouncer_pools_cl_active{database="pgbouncer",user="pgbouncer"} 1
+# TYPE cnpg_pgbouncer_pools_sv_active gauge
+cnpg_pgbouncer_pools_sv_active{database="app",user="app"} 5
+# TYPE cnpg_pgbouncer_pools_sv_idle gauge
+cnpg_pgbouncer_pools_sv_idle{database="app",user="app"} 2
+# TYPE cnpg_pgbouncer_paused gauge
+cnpg_pgbouncer_paused 1
+# TYPE cnpg_pgbouncer_last_reload_timestamp_seconds gauge
+cnpg_pgbouncer_last_reload_timestamp_seconds 1.7e+09
+`
+		load, err := ParseLoad(strings.NewReader(metrics))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(load.ClientsActive).To(BeEquivalentTo(11))
+		Expect(load.ServersActive).To(BeEquivalentTo(5))
+		Expect(load.ServersIdle).To(BeEquivalentTo(2))
+		Expect(load.Paused).To(BeTrue())
+		Expect(load.LastReloadTime).To(Equal(time.Unix(1700000000, 0)))
+	})
+
+	It("includes the connections of the replaced PgBouncer processes", func() {
+		metrics := `# TYPE cnpg_pgbouncer_pools_cl_active gauge
+cnpg_pgbouncer_pools_cl_active{database="app",user="app"} 10
+# TYPE cnpg_pgbouncer_pools_cl_waiting gauge
+cnpg_pgbouncer_pools_cl_waiting{database="app",user="app"} 1
+# TYPE cnpg_pgbouncer_pools_sv_active gauge
+cnpg_pgbouncer_pools_sv_active{database="app",user="app"} 5
+# TYPE cnpg_pgbouncer_draining_cl_active gauge
+cnpg_pgbouncer_draining_cl_active 4
+# TYPE cnpg_pgbouncer_draining_cl_waiting gauge
+cnpg_pgbouncer_draining_cl_waiting 2
+# TYPE cnpg_pgbouncer_draining_sv_active gauge
+cnpg_pgbouncer_draining_sv_active 3
+`
+		load, err := ParseLoad(strings.NewReader(metrics))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(load.ClientsActive).To(BeEquivalentTo(14))
+		Expect(load.ClientsWaiting).To(BeEquivalentTo(3))
+		Expect(load.ServersActive).To(BeEquivalentTo(8))
+	})
+
+	It("returns an empty load when the metrics are missing", func() {
+		load, err := ParseLoad(strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(load).To(BeZero())
+	})
+
+	It("fails on malformed metrics", func() {
+		_, err := ParseLoad(strings.NewReader("cnpg_pgbouncer_pools_cl_waiting{ 3\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -47,14 +47,22 @@ var (
 )
 
 // Setup configure the web statusServer for a certain PostgreSQL instance, and
-// must be invoked before starting the real web statusServer
-func Setup(ctx context.Context) error {
+// must be invoked before starting the real web statusServer. The metrics of
+// the PgBouncer processes replaced by a restart are collected from the Unix
+// socket directories returned by drainingSocketDirs.
+func Setup(ctx context.Context, drainingSocketDirs func() []string) error {
 	// create the exporter and serve it on the /metrics endpoint
 	registry = prometheus.NewRegistry()
-	exporter = NewExporter(ctx)
+	exporter = NewExporter(ctx, drainingSocketDirs)
 	if err := registry.Register(exporter); err != nil {
 		return fmt.Errorf("while registering PgBouncer exporters: %w", err)
 	}
+	if err := registry.Register(pausedGauge); err != nil {
+		return fmt.Errorf("while registering the PgBouncer state exporters: %w", err)
+	}
+	if err := registry.Register(lastReloadTimeGauge); err != nil {
+		return fmt.Errorf("while registering the PgBouncer state exporters: %w", err)
+	}
 	if err := registry.Register(collectors.NewGoCollector()); err != nil {
 		return fmt.Errorf("while registering Go exporters: %w", err)
 	}
@@ -82,6 +90,12 @@ func ListenAndServe() error {
 	return err
 }
 
+// GetGatherer returns the gatherer of the metrics exposed by the
+// metrics server, which can be used to push them elsewhere
+func GetGatherer() prometheus.Gatherer {
+	return registry
+}
+
 // Shutdown stops the web metrics server
 func Shutdown() error {
 	return server.Shutdown(context.Background())
@@ -33,7 +33,7 @@ var _ = Describe("MetricsServer", func() {
 		})
 
 		It("should register exporters and collectors successfully", func(ctx SpecContext) {
-			err := Setup(ctx)
+			err := Setup(ctx, func() []string { return nil })
 			Expect(err).NotTo(HaveOccurred())
 
 			mfs, err := registry.Gather()
@@ -48,6 +48,7 @@ var _ = Describe("MetricsServer", func() {
 			Expect(exporter.Metrics.ShowLists).NotTo(BeNil())
 			Expect(exporter.Metrics.ShowPools).NotTo(BeNil())
 			Expect(exporter.Metrics.ShowStats).NotTo(BeNil())
+			Expect(exporter.Metrics.Draining).NotTo(BeNil())
 		})
 	})
 })
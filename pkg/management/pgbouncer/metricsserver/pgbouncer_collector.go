@@ -42,6 +42,12 @@ type Exporter struct {
 	ctx     context.Context
 	Metrics *metrics
 	pool    pool.Pooler
+
+	// drainingSocketDirs returns the Unix socket directories of the
+	// PgBouncer processes replaced by a restart, whose connection
+	// pools are kept in drainingPools
+	drainingSocketDirs func() []string
+	drainingPools      map[string]pool.Pooler
 }
 
 // metrics here are related to the exporter itself, which is instrumented to
@@ -55,13 +61,17 @@ type metrics struct {
 	ShowLists          ShowListsMetrics
 	ShowPools          *ShowPoolsMetrics
 	ShowStats          *ShowStatsMetrics
+	Draining           *DrainingMetrics
 }
 
-// NewExporter creates an exporter
-func NewExporter(ctx context.Context) *Exporter {
+// NewExporter creates an exporter for the PgBouncer process accepting the
+// new connections and for the replaced ones, whose Unix socket directories
+// are returned by drainingSocketDirs
+func NewExporter(ctx context.Context, drainingSocketDirs func() []string) *Exporter {
 	return &Exporter{
-		ctx:     ctx,
-		Metrics: newMetrics(),
+		ctx:                ctx,
+		Metrics:            newMetrics(),
+		drainingSocketDirs: drainingSocketDirs,
 	}
 }
 
@@ -102,6 +112,7 @@ func newMetrics() *metrics {
 		ShowLists: NewShowListsMetrics(subsystem),
 		ShowPools: NewShowPoolsMetrics(subsystem),
 		ShowStats: NewShowStatsMetrics(subsystem),
+		Draining:  NewDrainingMetrics(subsystem),
 	}
 }
 
@@ -114,12 +125,14 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.ShowLists.Describe(ch)
 	e.Metrics.ShowPools.Describe(ch)
 	e.Metrics.ShowStats.Describe(ch)
+	e.Metrics.Draining.Describe(ch)
 }
 
 // Collect implements prometheus.Collector, collecting the Metrics values to
 // export.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.collectPgBouncerMetrics(ch)
+	e.collectDrainingProcesses(ch)
 
 	ch <- e.Metrics.CollectionsTotal
 	ch <- e.Metrics.Error
@@ -147,7 +160,8 @@ func (e *Exporter) collectPgBouncerMetrics(ch chan<- prometheus.Metric) {
 	e.collectShowStats(ch, db)
 }
 
-// GetPgBouncerDB gets a connection to the admin user db "pgbouncer" on this instance
+// GetPgBouncerDB gets a connection to the admin user db "pgbouncer" on the
+// PgBouncer process accepting the new connections
 func (e *Exporter) GetPgBouncerDB() (*sql.DB, error) {
 	return e.ConnectionPool().Connection("pgbouncer")
 }
@@ -0,0 +1,59 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsserver
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// These metrics describe the state of PgBouncer as managed by the
+// instance manager, which is not reported by the administrative console
+var (
+	pausedGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: PrometheusNamespace,
+		Subsystem: "pgbouncer",
+		Name:      "paused",
+		Help:      "1 if PgBouncer is paused, 0 otherwise.",
+	})
+
+	lastReloadTimeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: PrometheusNamespace,
+		Subsystem: "pgbouncer",
+		Name:      "last_reload_timestamp_seconds",
+		Help:      "Time of the last load of the PgBouncer configuration, as a Unix timestamp.",
+	})
+)
+
+// SetPaused records whether PgBouncer is paused or not
+func SetPaused(paused bool) {
+	if paused {
+		pausedGauge.Set(1)
+	} else {
+		pausedGauge.Set(0)
+	}
+}
+
+// SetLastReloadTime records the time when PgBouncer
+// loaded its configuration for the last time
+func SetLastReloadTime(t time.Time) {
+	lastReloadTimeGauge.Set(float64(t.Unix()))
+}
@@ -118,8 +118,17 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 		defaultAuthenticationMethod = "md5"
 	}
 
+	roles := cluster.GetRolesWithClientCertificate()
+	certificateRoles := make([]string, len(roles))
+	for i, role := range roles {
+		certificateRoles[i] = role.Name
+	}
+
 	return postgres.CreateHBARules(
 		cluster.Spec.PostgresConfiguration.PgHBA,
+		certificateRoles,
+		cluster.GetPoolerCertificateMappings(),
+		cluster.Annotations[utils.FenceClientConnectionsAnnotationName] == "true",
 		defaultAuthenticationMethod,
 		buildLDAPConfigString(cluster, ldapBindPassword))
 }
@@ -217,23 +226,28 @@ func quoteHbaLiteral(literal string) string {
 
 // generatePostgresqlIdent generates the pg_ident.conf content given
 // a set of additional pg_ident lines that is usually taken from the
-// Cluster configuration
-func (instance *Instance) generatePostgresqlIdent(additionalLines []string) (string, error) {
+// Cluster configuration, and the roles the poolers can connect as
+func (instance *Instance) generatePostgresqlIdent(
+	additionalLines []string,
+	poolerCertificateMappings []postgres.PoolerCertificateMapping,
+) (string, error) {
 	return postgres.CreateIdentRules(
 		additionalLines,
 		getCurrentUserOrDefaultToInsecureMapping(),
+		poolerCertificateMappings,
 	)
 }
 
 // RefreshPGIdent generates and writes down the pg_ident.conf file given
 // a set of additional pg_ident lines that is usually taken from the
-// Cluster configuration
+// Cluster configuration, and the roles the poolers can connect as
 func (instance *Instance) RefreshPGIdent(
 	ctx context.Context,
 	additionalLines []string,
+	poolerCertificateMappings []postgres.PoolerCertificateMapping,
 ) (postgresIdentChanged bool, err error) {
 	// Generate pg_ident.conf file
-	pgIdentContent, err := instance.generatePostgresqlIdent(additionalLines)
+	pgIdentContent, err := instance.generatePostgresqlIdent(additionalLines, poolerCertificateMappings)
 	if err != nil {
 		return false, nil
 	}
@@ -417,7 +431,7 @@ func createPostgresqlConfiguration(
 
 	// Set temporary tablespaces
 	for _, tablespace := range cluster.Spec.Tablespaces {
-		if tablespace.Temporary {
+		if tablespace.Temporary && tablespace.Ensure != apiv1.EnsureAbsent {
 			info.TemporaryTablespaces = append(info.TemporaryTablespaces, tablespace.Name)
 		}
 	}
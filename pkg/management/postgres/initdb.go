@@ -125,6 +125,44 @@ type InitInfo struct {
 
 	// TablespaceMapFile holds the content returned by pg_stop_backup. Needed for a hot backup restore
 	TablespaceMapFile []byte
+
+	// ResumeLogicalImport is true when the existing data directory has been
+	// created by an interrupted logical import, which is resumed
+	ResumeLogicalImport bool
+}
+
+// CanResumeLogicalImport checks if the existing data directory has been
+// created by an interrupted logical import, whose progress recorded in the
+// cluster status allows it to be resumed instead of starting over
+func (info InitInfo) CanResumeLogicalImport(ctx context.Context) (bool, error) {
+	contextLogger := log.FromContext(ctx).WithValues("pgdata", info.PgData)
+
+	pgDataExists, err := fileutils.FileExists(info.PgData)
+	if err != nil || !pgDataExists {
+		return false, err
+	}
+
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return false, err
+	}
+
+	cluster, err := info.loadCluster(ctx, typedClient)
+	if err != nil {
+		return false, err
+	}
+
+	if !logicalimport.CanResume(cluster) {
+		return false, nil
+	}
+
+	if out, err := info.GetInstance(nil).GetPgControldata(); err != nil {
+		contextLogger.Info("pg_controldata check on existing directory failed, the logical import "+
+			"cannot be resumed", "err", err, "out", out)
+		return false, nil
+	}
+
+	return true, nil
 }
 
 // EnsureTargetDirectoriesDoNotExist ensures that the target data and WAL directories do not exist.
@@ -473,9 +511,10 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		return err
 	}
 
-	err = info.CreateDataDirectory()
-	if err != nil {
-		return err
+	if !info.ResumeLogicalImport {
+		if err := info.CreateDataDirectory(); err != nil {
+			return err
+		}
 	}
 
 	instance := info.GetInstance(cluster)
@@ -513,13 +552,16 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 
 	// Configure the instance and run the logical import process
 	if err := instance.WithActiveInstance(func() error {
-		err = info.ConfigureNewInstance(instance)
-		if err != nil {
-			return fmt.Errorf("while configuring new instance: %w", err)
+		// The instance of an interrupted import has already been configured
+		if !info.ResumeLogicalImport {
+			err = info.ConfigureNewInstance(instance)
+			if err != nil {
+				return fmt.Errorf("while configuring new instance: %w", err)
+			}
 		}
 
 		if isImportBootstrap {
-			err = executeLogicalImport(ctx, typedClient, instance, cluster)
+			err = executeLogicalImport(ctx, typedClient, instance, cluster, info.ResumeLogicalImport)
 			if err != nil {
 				return fmt.Errorf("while executing logical import: %w", err)
 			}
@@ -551,6 +593,7 @@ func executeLogicalImport(
 	client ctrl.Client,
 	instance *Instance,
 	cluster *apiv1.Cluster,
+	resume bool,
 ) error {
 	destinationPool := instance.ConnectionPool()
 	defer destinationPool.ShutdownConnections()
@@ -564,9 +607,9 @@ func executeLogicalImport(
 	cloneType := cluster.Spec.Bootstrap.InitDB.Import.Type
 	switch cloneType {
 	case apiv1.MicroserviceSnapshotType:
-		return logicalimport.Microservice(ctx, cluster, destinationPool, originPool)
+		return logicalimport.Microservice(ctx, client, cluster, destinationPool, originPool, resume)
 	case apiv1.MonolithSnapshotType:
-		return logicalimport.Monolith(ctx, cluster, destinationPool, originPool)
+		return logicalimport.Monolith(ctx, client, cluster, destinationPool, originPool, resume)
 	default:
 		return fmt.Errorf("unrecognized clone type %s", cloneType)
 	}
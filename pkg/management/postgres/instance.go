@@ -44,6 +44,7 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/fileutils/compatibility"
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/types"
 	"go.uber.org/atomic"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
@@ -209,6 +210,10 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// clientConnectionsFenceLSN is the write-ahead log location reached
+	// after the client connections have been fenced, or empty if they are not
+	clientConnectionsFenceLSN atomic.String
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
 
@@ -275,6 +280,20 @@ func (instance *Instance) IsFenced() bool {
 	return instance.fenced.Load()
 }
 
+// GetClientConnectionsFenceLSN gets the write-ahead log location reached
+// after the client connections have been fenced, or an empty string if
+// they are not fenced
+func (instance *Instance) GetClientConnectionsFenceLSN() types.LSN {
+	return types.LSN(instance.clientConnectionsFenceLSN.Load())
+}
+
+// SetClientConnectionsFenceLSN records the write-ahead log location reached
+// after the client connections have been fenced. An empty location means
+// the client connections are not fenced
+func (instance *Instance) SetClientConnectionsFenceLSN(lsn types.LSN) {
+	instance.clientConnectionsFenceLSN.Store(string(lsn))
+}
+
 // CanCheckReadiness checks whether the instance should be checked for readiness
 func (instance *Instance) CanCheckReadiness() bool {
 	return instance.canCheckReadiness.Load()
@@ -369,7 +388,7 @@ func (instance *Instance) VerifyPgDataCoherence(ctx context.Context) error {
 	}
 
 	// creates a bare pg_ident.conf that only grants local access
-	_, err := instance.RefreshPGIdent(ctx, nil)
+	_, err := instance.RefreshPGIdent(ctx, nil, nil)
 	return err
 }
 
@@ -842,9 +861,12 @@ func (instance *Instance) GetPgVersion() (semver.Version, error) {
 	return *parsedVersion, nil
 }
 
+// InstanceManagerApplicationName is the application name used by the
+// connections opened by the instance manager
+const InstanceManagerApplicationName = "cnpg-instance-manager"
+
 // ConnectionPool gets or initializes the connection pool for this instance
 func (instance *Instance) ConnectionPool() pool.Pooler {
-	const applicationName = "cnpg-instance-manager"
 	if instance.pool == nil {
 		socketDir := GetSocketDir()
 		dsn := fmt.Sprintf(
@@ -852,7 +874,7 @@ func (instance *Instance) ConnectionPool() pool.Pooler {
 			socketDir,
 			GetServerPort(),
 			"postgres",
-			applicationName,
+			InstanceManagerApplicationName,
 		)
 
 		instance.pool = pool.NewPostgresqlConnectionPool(dsn)
@@ -22,19 +22,67 @@ package logicalimport
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/jackc/pgx/v5"
 	"k8s.io/utils/strings/slices"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/system/compatibility"
 )
 
 type databaseSnapshotter struct {
-	cluster *apiv1.Cluster
+	cluster  *apiv1.Cluster
+	progress *progressTracker
+}
+
+// startImport prepares the dumps directory and the progress tracking of
+// the import. Unless an interrupted import is being resumed, the dumps
+// left over by previous attempts are removed
+func (ds *databaseSnapshotter) startImport(ctx context.Context) error {
+	if err := createDumpsDirectory(); err != nil {
+		return err
+	}
+
+	if !ds.progress.isResuming() {
+		if err := cleanDumpDirectory(); err != nil {
+			return err
+		}
+	}
+
+	return ds.progress.start(ctx)
+}
+
+// completeDatabase executes the last step of the import of a database,
+// that is collecting its statistics or, with online imports, creating the
+// logical replication that keeps it aligned with the source database
+func (ds *databaseSnapshotter) completeDatabase(
+	ctx context.Context,
+	destination pool.Pooler,
+	origin pool.Pooler,
+	sourceDatabase string,
+	database string,
+) error {
+	if ds.progress.isDone(sourceDatabase, stepComplete) {
+		return nil
+	}
+
+	var err error
+	if ds.cluster.Spec.Bootstrap.InitDB.Import.Online {
+		err = ds.createOnlineReplication(ctx, destination, origin, sourceDatabase, database)
+	} else {
+		err = ds.analyzeDatabase(ctx, destination, sourceDatabase, database)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ds.progress.markDone(ctx, sourceDatabase, stepComplete)
 }
 
 func (ds *databaseSnapshotter) getDatabaseList(ctx context.Context, target pool.Pooler) ([]string, error) {
@@ -101,6 +149,20 @@ func (ds *databaseSnapshotter) exportDatabases(
 	}
 
 	for _, database := range databases {
+		exported, err := ds.isExported(database)
+		if err != nil {
+			return err
+		}
+		if exported {
+			contextLogger.Info("database already exported, skipping", "databaseName", database)
+			continue
+		}
+
+		// Remove any incomplete export left by an interrupted import
+		if err := os.RemoveAll(generateFileNameForDatabase(database)); err != nil {
+			return err
+		}
+
 		contextLogger.Info("exporting database", "databaseName", database)
 		dsn := target.GetDsn(database)
 		options := []string{
@@ -115,15 +177,33 @@ func (ds *databaseSnapshotter) exportDatabases(
 		contextLogger.Info("Running pg_dump", "cmd", pgDump,
 			"options", options)
 		pgDumpCommand := exec.Command(pgDump, options...) // #nosec
-		err := execlog.RunStreaming(pgDumpCommand, pgDump)
+		err = execlog.RunStreaming(pgDumpCommand, pgDump)
 		if err != nil {
 			return fmt.Errorf("error in pg_dump, %w", err)
 		}
+
+		if err := ds.progress.markDone(ctx, database, stepDump); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// isExported checks if a database has already been exported, or restored,
+// by an interrupted import whose dump can be reused
+func (ds *databaseSnapshotter) isExported(database string) (bool, error) {
+	if ds.progress.isDone(database, stepRestore) {
+		return true, nil
+	}
+
+	if !ds.progress.isDone(database, stepDump) {
+		return false, nil
+	}
+
+	return fileutils.FileExists(generateFileNameForDatabase(database))
+}
+
 func (ds *databaseSnapshotter) importDatabases(
 	ctx context.Context,
 	target pool.Pooler,
@@ -133,6 +213,15 @@ func (ds *databaseSnapshotter) importDatabases(
 	contextLogger := log.FromContext(ctx)
 
 	for _, database := range databases {
+		if ds.progress.isDone(database, stepRestore) {
+			contextLogger.Info("database already imported, skipping", "databaseName", database)
+			continue
+		}
+
+		if err := ds.dropIncompleteDatabase(ctx, target, database); err != nil {
+			return err
+		}
+
 		for _, section := range ds.getSectionsToExecute() {
 			targetDatabase := target.GetDsn(database)
 			contextLogger.Info(
@@ -163,6 +252,7 @@ func (ds *databaseSnapshotter) importDatabases(
 			}
 
 			options = append(options, extraOptions...)
+			options = append(options, ds.getJobsOptions(database)...)
 			options = append(options, alwaysPresentOptions...)
 
 			contextLogger.Info("Running pg_restore",
@@ -175,11 +265,77 @@ func (ds *databaseSnapshotter) importDatabases(
 				return fmt.Errorf("error while executing pg_restore, section:%s, %w", section, err)
 			}
 		}
+
+		if err := ds.markRestored(ctx, target, database); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// markRestored records the completion of the restore of a database, after
+// having flushed it to disk. The import runs with fsync disabled, and the
+// progress must not refer to data that a node crash could lose
+func (ds *databaseSnapshotter) markRestored(
+	ctx context.Context,
+	target pool.Pooler,
+	sourceDatabase string,
+) error {
+	if ds.progress == nil {
+		return nil
+	}
+
+	db, err := target.Connection(postgresDatabase)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, "CHECKPOINT"); err != nil {
+		return err
+	}
+	compatibility.Sync()
+
+	return ds.progress.markDone(ctx, sourceDatabase, stepRestore)
+}
+
+// dropIncompleteDatabase drops a database whose restore has been
+// interrupted, so that it can be imported again from scratch
+func (ds *databaseSnapshotter) dropIncompleteDatabase(
+	ctx context.Context,
+	target pool.Pooler,
+	database string,
+) error {
+	if !ds.progress.isResuming() {
+		return nil
+	}
+
+	exists, err := ds.databaseExists(target, database)
+	if err != nil || !exists {
+		return err
+	}
+
+	db, err := target.Connection(postgresDatabase)
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("dropping the incompletely imported database", "databaseName", database)
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE %s", pgx.Identifier{database}.Sanitize()))
+	return err
+}
+
+// getJobsOptions returns the options setting the number of concurrent
+// jobs `pg_restore` uses to import the passed source database
+func (ds *databaseSnapshotter) getJobsOptions(database string) []string {
+	jobs := ds.cluster.Spec.Bootstrap.InitDB.Import.GetPgRestoreJobs(database)
+	if jobs <= 1 {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("--jobs=%d", jobs)}
+}
+
 func (ds *databaseSnapshotter) importDatabaseContent(
 	ctx context.Context,
 	target pool.Pooler,
@@ -225,6 +381,7 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 		}
 
 		options = append(options, extraOptions...)
+		options = append(options, ds.getJobsOptions(database)...)
 		options = append(options, alwaysPresentOptions...)
 
 		contextLogger.Info("Running pg_restore",
@@ -295,25 +452,31 @@ func (ds *databaseSnapshotter) executePostImportQueries(
 	return nil
 }
 
-func (ds *databaseSnapshotter) analyze(
+// analyzeDatabase collects the statistics of an imported database, whose
+// progress is tracked with the name of the source database
+func (ds *databaseSnapshotter) analyzeDatabase(
 	ctx context.Context,
 	target pool.Pooler,
-	databases []string,
+	sourceDatabase string,
+	database string,
 ) error {
 	contextLogger := log.FromContext(ctx)
 
-	for _, database := range databases {
-		contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
-		db, err := target.Connection(database)
-		if err != nil {
-			return err
-		}
-		if _, err := db.Exec("ANALYZE VERBOSE"); err != nil {
-			return err
-		}
+	if ds.progress.isDone(sourceDatabase, stepAnalyze) {
+		contextLogger.Info("database already analyzed, skipping", "databaseName", database)
+		return nil
 	}
 
-	return nil
+	contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
+	db, err := target.Connection(database)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("ANALYZE VERBOSE"); err != nil {
+		return err
+	}
+
+	return ds.progress.markDone(ctx, sourceDatabase, stepAnalyze)
 }
 
 // dropExtensionsFromDatabase will drop every extension installed in a database.
@@ -371,7 +534,9 @@ func (ds *databaseSnapshotter) getSectionsToExecute() []string {
 		postData = "post-data"
 	)
 
-	if ds.cluster.Spec.Bootstrap.InitDB.Import.SchemaOnly {
+	// In an online import, the data is copied by logical replication
+	importSpec := ds.cluster.Spec.Bootstrap.InitDB.Import
+	if importSpec.SchemaOnly || importSpec.Online {
 		return []string{preData, postData}
 	}
 
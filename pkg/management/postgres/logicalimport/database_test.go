@@ -123,9 +123,25 @@ var _ = Describe("databaseSnapshotter methods test", func() {
 		})
 	})
 
+	It("should set the pg_restore jobs of every database", func() {
+		ds.cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+			InitDB: &apiv1.BootstrapInitDB{
+				Import: &apiv1.Import{
+					DatabaseOptions: []apiv1.ImportDatabaseOptions{
+						{Name: "db1", PgRestoreJobs: 4},
+						{Name: "db2", PgRestoreJobs: 1},
+					},
+				},
+			},
+		}
+		Expect(ds.getJobsOptions("db1")).To(Equal([]string{"--jobs=4"}))
+		Expect(ds.getJobsOptions("db2")).To(BeEmpty())
+		Expect(ds.getJobsOptions("db3")).To(BeEmpty())
+	})
+
 	It("should run analyze", func(ctx SpecContext) {
 		mock.ExpectExec("ANALYZE VERBOSE").WillReturnResult(sqlmock.NewResult(0, 0))
-		err := ds.analyze(ctx, fp, []string{"test"})
+		err := ds.analyzeDatabase(ctx, fp, "test", "test")
 		Expect(err).ToNot(HaveOccurred())
 	})
 
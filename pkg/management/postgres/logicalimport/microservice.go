@@ -23,27 +23,35 @@ import (
 	"context"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 )
 
-// Microservice executes the microservice clone type
+// Microservice executes the microservice clone type. When resume is true,
+// the steps already completed by an interrupted import are skipped
 func Microservice(
 	ctx context.Context,
+	cli client.Client,
 	cluster *apiv1.Cluster,
 	destination pool.Pooler,
 	origin pool.Pooler,
+	resume bool,
 ) error {
 	contextLogger := log.FromContext(ctx)
-	ds := databaseSnapshotter{cluster: cluster}
+	ds := databaseSnapshotter{
+		cluster:  cluster,
+		progress: &progressTracker{client: cli, cluster: cluster, resuming: resume},
+	}
 	initDB := cluster.Spec.Bootstrap.InitDB
 	databases := initDB.Import.Databases
+	sourceDatabase := databases[0]
 
-	contextLogger.Info("starting microservice clone process")
+	contextLogger.Info("starting microservice clone process", "resuming", resume)
 
-	if err := createDumpsDirectory(); err != nil {
-		return nil
+	if err := ds.startImport(ctx); err != nil {
+		return err
 	}
 
 	if err := ds.exportDatabases(
@@ -55,36 +63,48 @@ func Microservice(
 		return err
 	}
 
-	if err := ds.dropExtensionsFromDatabase(
-		ctx,
-		destination,
-		initDB.Database,
-	); err != nil {
-		return err
-	}
-
-	if err := ds.importDatabaseContent(
-		ctx,
-		destination,
-		databases[0],
-		initDB.Database,
-		initDB.Owner,
-		initDB.Import.PgRestoreExtraOptions,
-	); err != nil {
-		return err
+	if !ds.progress.isDone(sourceDatabase, stepRestore) {
+		if err := ds.dropExtensionsFromDatabase(
+			ctx,
+			destination,
+			initDB.Database,
+		); err != nil {
+			return err
+		}
+
+		if err := ds.importDatabaseContent(
+			ctx,
+			destination,
+			sourceDatabase,
+			initDB.Database,
+			initDB.Owner,
+			initDB.Import.PgRestoreExtraOptions,
+		); err != nil {
+			return err
+		}
+
+		if err := ds.markRestored(ctx, destination, sourceDatabase); err != nil {
+			return err
+		}
 	}
 
 	if err := cleanDumpDirectory(); err != nil {
 		return err
 	}
 
-	if err := ds.executePostImportQueries(
-		ctx,
-		destination,
-		initDB.Database,
-	); err != nil {
-		return err
+	if !ds.progress.isDone(sourceDatabase, stepPostImportSQL) {
+		if err := ds.executePostImportQueries(
+			ctx,
+			destination,
+			initDB.Database,
+		); err != nil {
+			return err
+		}
+
+		if err := ds.progress.markDone(ctx, sourceDatabase, stepPostImportSQL); err != nil {
+			return err
+		}
 	}
 
-	return ds.analyze(ctx, destination, []string{initDB.Database})
+	return ds.completeDatabase(ctx, destination, origin, sourceDatabase, initDB.Database)
 }
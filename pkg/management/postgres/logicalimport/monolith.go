@@ -23,40 +23,51 @@ import (
 	"context"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 )
 
-// Monolith executes the monolith clone type
+// Monolith executes the monolith clone type. When resume is true, the
+// databases already imported by an interrupted import are skipped
 func Monolith(
 	ctx context.Context,
+	cli client.Client,
 	cluster *apiv1.Cluster,
 	destination pool.Pooler,
 	origin pool.Pooler,
+	resume bool,
 ) error {
 	contextLogger := log.FromContext(ctx)
-	contextLogger.Info("starting monolith clone process")
+	contextLogger.Info("starting monolith clone process", "resuming", resume)
 
-	if len(cluster.Spec.Bootstrap.InitDB.Import.Roles) > 0 {
+	ds := databaseSnapshotter{
+		cluster:  cluster,
+		progress: &progressTracker{client: cli, cluster: cluster, resuming: resume},
+	}
+
+	if err := ds.startImport(ctx); err != nil {
+		return err
+	}
+
+	// Roles are cloned before any database is exported, so they are
+	// already in place when an interrupted import is resumed
+	if !resume && len(cluster.Spec.Bootstrap.InitDB.Import.Roles) > 0 {
 		if err := cloneRoles(ctx, cluster, destination, origin); err != nil {
 			return err
 		}
+
 		if err := cloneRoleInheritance(ctx, destination, origin); err != nil {
 			return err
 		}
 	}
 
-	ds := databaseSnapshotter{cluster: cluster}
 	databases, err := ds.getDatabaseList(ctx, origin)
 	if err != nil {
 		return err
 	}
 
-	if err := createDumpsDirectory(); err != nil {
-		return err
-	}
-
 	if err := ds.exportDatabases(
 		ctx,
 		origin,
@@ -79,5 +90,11 @@ func Monolith(
 		return err
 	}
 
-	return ds.analyze(ctx, destination, databases)
+	for _, database := range databases {
+		if err := ds.completeDatabase(ctx, destination, origin, database, database); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
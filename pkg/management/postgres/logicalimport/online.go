@@ -0,0 +1,165 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logicalimport
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
+)
+
+// OnlineImportSubscriptionName is the name of the subscription created
+// by an online import in every imported database
+const OnlineImportSubscriptionName = "cnpg_import"
+
+// GetOnlineImportSlotName gets the name of the replication slot, and of
+// the publication, used to import a database. As they live in the source
+// cluster, the name needs to be unique across every cluster importing
+// from it
+func GetOnlineImportSlotName(cluster *apiv1.Cluster, database string) string {
+	hash := sha256.Sum256(fmt.Appendf(nil, "%s/%s/%s", cluster.Namespace, cluster.Name, database))
+	return fmt.Sprintf("cnpg_import_%x", hash[:8])
+}
+
+// createOnlineReplication creates a publication in the source database and
+// a disabled subscription in the imported one. The subscription creates the
+// replication slot in the source, retaining every change happening from now
+// on, and is enabled by the instance manager once the cluster is running
+func (ds *databaseSnapshotter) createOnlineReplication(
+	ctx context.Context,
+	destination pool.Pooler,
+	origin pool.Pooler,
+	sourceDatabase string,
+	database string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	importSpec := ds.cluster.Spec.Bootstrap.InitDB.Import
+	externalCluster, ok := ds.cluster.ExternalCluster(importSpec.Source.ExternalCluster)
+	if !ok {
+		return fmt.Errorf("missing external cluster")
+	}
+
+	slotName := GetOnlineImportSlotName(ds.cluster, database)
+
+	destinationDB, err := destination.Connection(database)
+	if err != nil {
+		return err
+	}
+
+	// The subscription may have been created by the interrupted import
+	// being resumed. Its slot is detached, to be dropped as a stale one
+	if ds.progress.isResuming() {
+		if err := dropOnlineImportSubscription(ctx, destinationDB); err != nil {
+			return fmt.Errorf("while dropping the subscription in database %s: %w", database, err)
+		}
+	}
+
+	originDB, err := origin.Connection(sourceDatabase)
+	if err != nil {
+		return err
+	}
+
+	contextLogger.Info("creating the online import publication in the source database",
+		"sourceDatabase", sourceDatabase, "publicationName", slotName)
+
+	// A replication slot may be left over by a previous import attempt
+	if _, err := originDB.ExecContext(
+		ctx,
+		"SELECT pg_catalog.pg_drop_replication_slot(slot_name) FROM pg_catalog.pg_replication_slots "+
+			"WHERE slot_name = $1 AND NOT active",
+		slotName,
+	); err != nil {
+		return fmt.Errorf("while dropping the stale replication slot %s: %w", slotName, err)
+	}
+
+	for _, query := range toOnlineImportPublicationSQL(slotName) {
+		if _, err := originDB.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("while creating the publication in database %s: %w", sourceDatabase, err)
+		}
+	}
+
+	contextLogger.Info("creating the online import subscription",
+		"databaseName", database, "slotName", slotName)
+
+	connString := external.GetServerConnectionString(&externalCluster, sourceDatabase)
+	if _, err := destinationDB.ExecContext(ctx, toOnlineImportSubscriptionSQL(slotName, connString)); err != nil {
+		return fmt.Errorf("while creating the subscription in database %s: %w", database, err)
+	}
+
+	return nil
+}
+
+// dropOnlineImportSubscription drops the online import subscription, if
+// present, without dropping its replication slot in the source database
+func dropOnlineImportSubscription(ctx context.Context, db *sql.DB) error {
+	var exists bool
+	if err := db.QueryRowContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM pg_catalog.pg_subscription s "+
+			"JOIN pg_catalog.pg_database d ON s.subdbid = d.oid "+
+			"WHERE s.subname = $1 AND d.datname = pg_catalog.current_database())",
+		OnlineImportSubscriptionName,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	subscriptionName := pgx.Identifier{OnlineImportSubscriptionName}.Sanitize()
+	for _, query := range []string{
+		fmt.Sprintf("ALTER SUBSCRIPTION %s SET (slot_name = NONE)", subscriptionName),
+		fmt.Sprintf("DROP SUBSCRIPTION %s", subscriptionName),
+	} {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toOnlineImportPublicationSQL(publicationName string) []string {
+	return []string{
+		fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pgx.Identifier{publicationName}.Sanitize()),
+		fmt.Sprintf("CREATE PUBLICATION %s FOR ALL TABLES", pgx.Identifier{publicationName}.Sanitize()),
+	}
+}
+
+func toOnlineImportSubscriptionSQL(slotName string, connString string) string {
+	return fmt.Sprintf(
+		"CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s "+
+			"WITH (enabled = false, slot_name = %s, copy_data = true)",
+		pgx.Identifier{OnlineImportSubscriptionName}.Sanitize(),
+		pq.QuoteLiteral(connString),
+		pgx.Identifier{slotName}.Sanitize(),
+		pq.QuoteLiteral(slotName),
+	)
+}
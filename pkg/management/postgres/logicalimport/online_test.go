@@ -0,0 +1,152 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logicalimport
+
+import (
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("online import", func() {
+	var (
+		ds   databaseSnapshotter
+		fp   fakePooler
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		ds = databaseSnapshotter{
+			cluster: &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: apiv1.ClusterSpec{
+					Bootstrap: &apiv1.BootstrapConfiguration{
+						InitDB: &apiv1.BootstrapInitDB{
+							Import: &apiv1.Import{
+								Source:    apiv1.ImportSource{ExternalCluster: "source"},
+								Type:      apiv1.MicroserviceSnapshotType,
+								Databases: []string{"sourcedb"},
+								Online:    true,
+							},
+						},
+					},
+					ExternalClusters: []apiv1.ExternalCluster{
+						{
+							Name:                 "source",
+							ConnectionParameters: map[string]string{"host": "source-rw"},
+						},
+					},
+				},
+			},
+		}
+
+		db, dbMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock = dbMock
+		fp = fakePooler{
+			db: db,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("generates slot names which are unique per cluster and database", func() {
+		slotName := GetOnlineImportSlotName(ds.cluster, "app")
+		Expect(slotName).To(HavePrefix("cnpg_import_"))
+		Expect(slotName).To(HaveLen(len("cnpg_import_") + 16))
+		Expect(GetOnlineImportSlotName(ds.cluster, "app")).To(Equal(slotName))
+		Expect(GetOnlineImportSlotName(ds.cluster, "other")).ToNot(Equal(slotName))
+
+		otherCluster := ds.cluster.DeepCopy()
+		otherCluster.Name = "other-cluster"
+		Expect(GetOnlineImportSlotName(otherCluster, "app")).ToNot(Equal(slotName))
+	})
+
+	It("only restores the schema", func() {
+		Expect(ds.getSectionsToExecute()).To(Equal([]string{"pre-data", "post-data"}))
+	})
+
+	It("creates the publication in the source and the subscription in the destination", func(ctx SpecContext) {
+		slotName := GetOnlineImportSlotName(ds.cluster, "app")
+
+		mock.ExpectExec("SELECT pg_catalog.pg_drop_replication_slot(slot_name) FROM pg_catalog.pg_replication_slots " +
+			"WHERE slot_name = $1 AND NOT active").
+			WithArgs(slotName).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf("DROP PUBLICATION IF EXISTS \"%s\"", slotName)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf("CREATE PUBLICATION \"%s\" FOR ALL TABLES", slotName)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf(
+			"CREATE SUBSCRIPTION \"cnpg_import\" CONNECTION 'dbname=''sourcedb'' host=''source-rw''' "+
+				"PUBLICATION \"%s\" WITH (enabled = false, slot_name = '%s', copy_data = true)",
+			slotName, slotName)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(ds.createOnlineReplication(ctx, fp, fp, "sourcedb", "app")).To(Succeed())
+	})
+
+	It("drops the subscription left over by the interrupted import being resumed", func(ctx SpecContext) {
+		ds.progress = &progressTracker{cluster: ds.cluster, resuming: true}
+		slotName := GetOnlineImportSlotName(ds.cluster, "app")
+
+		mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM pg_catalog.pg_subscription s " +
+			"JOIN pg_catalog.pg_database d ON s.subdbid = d.oid " +
+			"WHERE s.subname = $1 AND d.datname = pg_catalog.current_database())").
+			WithArgs(OnlineImportSubscriptionName).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec("ALTER SUBSCRIPTION \"cnpg_import\" SET (slot_name = NONE)").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DROP SUBSCRIPTION \"cnpg_import\"").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SELECT pg_catalog.pg_drop_replication_slot(slot_name) FROM pg_catalog.pg_replication_slots " +
+			"WHERE slot_name = $1 AND NOT active").
+			WithArgs(slotName).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf("DROP PUBLICATION IF EXISTS \"%s\"", slotName)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf("CREATE PUBLICATION \"%s\" FOR ALL TABLES", slotName)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(fmt.Sprintf(
+			"CREATE SUBSCRIPTION \"cnpg_import\" CONNECTION 'dbname=''sourcedb'' host=''source-rw''' "+
+				"PUBLICATION \"%s\" WITH (enabled = false, slot_name = '%s', copy_data = true)",
+			slotName, slotName)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(ds.createOnlineReplication(ctx, fp, fp, "sourcedb", "app")).To(Succeed())
+	})
+
+	It("fails when the source external cluster is missing", func(ctx SpecContext) {
+		ds.cluster.Spec.ExternalClusters = nil
+		Expect(ds.createOnlineReplication(ctx, fp, fp, "sourcedb", "app")).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,156 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logicalimport
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	clusterstatus "github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+)
+
+// importStep is a step of the import of a database
+type importStep string
+
+const (
+	stepDump          importStep = "dump"
+	stepRestore       importStep = "restore"
+	stepPostImportSQL importStep = "postImportSQL"
+	stepAnalyze       importStep = "analyze"
+	stepComplete      importStep = "complete"
+)
+
+// progressTracker records the progress of the import of every database
+// in the status of the cluster, allowing an interrupted import to be
+// resumed without starting over
+type progressTracker struct {
+	client  client.Client
+	cluster *apiv1.Cluster
+
+	// resuming is true when the import has been interrupted and
+	// the existing data directory is being reused
+	resuming bool
+}
+
+// CanResume checks if the progress recorded in the cluster status allows
+// an interrupted import to be resumed on the existing data directory
+func CanResume(cluster *apiv1.Cluster) bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil ||
+		cluster.Spec.Bootstrap.InitDB.Import == nil {
+		return false
+	}
+
+	status := cluster.Status.LogicalImport
+	if status == nil || len(status.Databases) == 0 {
+		return false
+	}
+
+	// The database of a microservice import is created while configuring
+	// the new instance, and can only be reused once it has been restored
+	importSpec := cluster.Spec.Bootstrap.InitDB.Import
+	if importSpec.Type == apiv1.MicroserviceSnapshotType {
+		if len(importSpec.Databases) != 1 {
+			return false
+		}
+		database := status.GetDatabase(importSpec.Databases[0])
+		return database != nil && database.RestoreTime != nil
+	}
+
+	return true
+}
+
+// start prepares the progress tracking of the import. Unless the import
+// is being resumed, the progress recorded by previous attempts is reset
+func (p *progressTracker) start(ctx context.Context) error {
+	if p == nil || p.resuming {
+		return nil
+	}
+
+	return clusterstatus.PatchWithOptimisticLock(
+		ctx,
+		p.client,
+		p.cluster,
+		clusterstatus.SetLogicalImport(&apiv1.LogicalImportStatus{StartTime: ptr.To(metav1.Now())}),
+	)
+}
+
+// isResuming checks if an interrupted import is being resumed
+func (p *progressTracker) isResuming() bool {
+	return p != nil && p.resuming
+}
+
+// isDone checks if a step of the import of a database has been completed
+func (p *progressTracker) isDone(database string, step importStep) bool {
+	if p == nil {
+		return false
+	}
+
+	status := p.cluster.Status.LogicalImport.GetDatabase(database)
+	if status == nil {
+		return false
+	}
+
+	return *getStepTime(status, step) != nil
+}
+
+// markDone records the completion of a step of the import of a database
+func (p *progressTracker) markDone(ctx context.Context, database string, step importStep) error {
+	if p == nil {
+		return nil
+	}
+
+	logicalImport := p.cluster.Status.LogicalImport.DeepCopy()
+	if logicalImport == nil {
+		logicalImport = &apiv1.LogicalImportStatus{}
+	}
+
+	status := logicalImport.GetDatabase(database)
+	if status == nil {
+		logicalImport.Databases = append(logicalImport.Databases, apiv1.LogicalImportDatabaseStatus{Name: database})
+		status = &logicalImport.Databases[len(logicalImport.Databases)-1]
+	}
+	*getStepTime(status, step) = ptr.To(metav1.Now())
+
+	return clusterstatus.PatchWithOptimisticLock(
+		ctx,
+		p.client,
+		p.cluster,
+		clusterstatus.SetLogicalImport(logicalImport),
+	)
+}
+
+func getStepTime(status *apiv1.LogicalImportDatabaseStatus, step importStep) **metav1.Time {
+	switch step {
+	case stepDump:
+		return &status.DumpTime
+	case stepRestore:
+		return &status.RestoreTime
+	case stepPostImportSQL:
+		return &status.PostImportSQLTime
+	case stepAnalyze:
+		return &status.AnalyzeTime
+	default:
+		return &status.CompletionTime
+	}
+}
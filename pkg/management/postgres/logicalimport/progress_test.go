@@ -0,0 +1,153 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logicalimport
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("logical import progress", func() {
+	var (
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Import: &apiv1.Import{
+							Type:      apiv1.MonolithSnapshotType,
+							Databases: []string{"db1", "db2"},
+						},
+					},
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+	})
+
+	It("resets the progress when starting a new import", func(ctx SpecContext) {
+		cluster.Status.LogicalImport = &apiv1.LogicalImportStatus{
+			Databases: []apiv1.LogicalImportDatabaseStatus{{Name: "db1", DumpTime: ptr.To(metav1.Now())}},
+		}
+		progress := &progressTracker{client: fakeClient, cluster: cluster}
+
+		Expect(progress.start(ctx)).To(Succeed())
+		Expect(progress.isDone("db1", stepDump)).To(BeFalse())
+
+		var stored apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		Expect(stored.Status.LogicalImport).ToNot(BeNil())
+		Expect(stored.Status.LogicalImport.StartTime).ToNot(BeNil())
+		Expect(stored.Status.LogicalImport.Databases).To(BeEmpty())
+	})
+
+	It("keeps the progress when resuming an import", func(ctx SpecContext) {
+		cluster.Status.LogicalImport = &apiv1.LogicalImportStatus{
+			Databases: []apiv1.LogicalImportDatabaseStatus{{Name: "db1", DumpTime: ptr.To(metav1.Now())}},
+		}
+		progress := &progressTracker{client: fakeClient, cluster: cluster, resuming: true}
+
+		Expect(progress.start(ctx)).To(Succeed())
+		Expect(progress.isResuming()).To(BeTrue())
+		Expect(progress.isDone("db1", stepDump)).To(BeTrue())
+		Expect(progress.isDone("db1", stepRestore)).To(BeFalse())
+	})
+
+	It("records the completed steps of every database", func(ctx SpecContext) {
+		progress := &progressTracker{client: fakeClient, cluster: cluster}
+		Expect(progress.start(ctx)).To(Succeed())
+
+		Expect(progress.markDone(ctx, "db1", stepDump)).To(Succeed())
+		Expect(progress.markDone(ctx, "db1", stepRestore)).To(Succeed())
+		Expect(progress.markDone(ctx, "db2", stepDump)).To(Succeed())
+
+		var stored apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &stored)).To(Succeed())
+		Expect(stored.Status.LogicalImport.Databases).To(HaveLen(2))
+		db1 := stored.Status.LogicalImport.GetDatabase("db1")
+		Expect(db1.DumpTime).ToNot(BeNil())
+		Expect(db1.RestoreTime).ToNot(BeNil())
+		Expect(db1.CompletionTime).To(BeNil())
+		Expect(progress.isDone("db2", stepDump)).To(BeTrue())
+		Expect(progress.isDone("db2", stepRestore)).To(BeFalse())
+	})
+
+	It("ignores the progress when not tracking it", func(ctx SpecContext) {
+		var progress *progressTracker
+		Expect(progress.start(ctx)).To(Succeed())
+		Expect(progress.markDone(ctx, "db1", stepDump)).To(Succeed())
+		Expect(progress.isDone("db1", stepDump)).To(BeFalse())
+		Expect(progress.isResuming()).To(BeFalse())
+	})
+
+	Context("CanResume", func() {
+		It("requires some recorded progress", func() {
+			Expect(CanResume(cluster)).To(BeFalse())
+
+			cluster.Status.LogicalImport = &apiv1.LogicalImportStatus{StartTime: ptr.To(metav1.Now())}
+			Expect(CanResume(cluster)).To(BeFalse())
+
+			cluster.Status.LogicalImport.Databases = []apiv1.LogicalImportDatabaseStatus{
+				{Name: "db1", DumpTime: ptr.To(metav1.Now())},
+			}
+			Expect(CanResume(cluster)).To(BeTrue())
+		})
+
+		It("requires the restore of a microservice import to be completed", func() {
+			cluster.Spec.Bootstrap.InitDB.Import.Type = apiv1.MicroserviceSnapshotType
+			cluster.Spec.Bootstrap.InitDB.Import.Databases = []string{"db1"}
+			cluster.Status.LogicalImport = &apiv1.LogicalImportStatus{
+				Databases: []apiv1.LogicalImportDatabaseStatus{{Name: "db1", DumpTime: ptr.To(metav1.Now())}},
+			}
+			Expect(CanResume(cluster)).To(BeFalse())
+
+			cluster.Status.LogicalImport.Databases[0].RestoreTime = ptr.To(metav1.Now())
+			Expect(CanResume(cluster)).To(BeTrue())
+		})
+
+		It("requires an import bootstrap", func() {
+			cluster.Spec.Bootstrap = nil
+			cluster.Status.LogicalImport = &apiv1.LogicalImportStatus{
+				Databases: []apiv1.LogicalImportDatabaseStatus{{Name: "db1", DumpTime: ptr.To(metav1.Now())}},
+			}
+			Expect(CanResume(cluster)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,258 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+)
+
+// fileSinkQueueSize is the number of entries a file sink buffers
+// while the volume is slow, before discarding them
+const fileSinkQueueSize = 1024
+
+// activeFileSinks tracks the latest file sink created for every path, so
+// that a sink replacing another one only starts writing after the previous
+// one flushed its queue
+var (
+	activeFileSinksMu sync.Mutex
+	activeFileSinks   = make(map[string]*fileSink)
+)
+
+// fileSink writes the log entries, one JSON object per line, to a file
+// which is rotated when it grows over the maximum size. The rotated files
+// are named like the original one followed by `.1`, `.2` and so on, `.1`
+// being the most recent. The entries are queued and written by a dedicated
+// goroutine, so that a slow volume never blocks PostgreSQL: entries are
+// discarded when the queue is full
+type fileSink struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	entries chan *logEntry
+	closing chan struct{}
+	done    chan struct{}
+
+	// closeErr is the error closing the file, set before done is closed
+	closeErr error
+
+	// previous is the sink that was writing to the same path
+	// before this one, if it was still running
+	previous *fileSink
+
+	// dropping is true while the entries are being discarded
+	dropping atomic.Bool
+
+	// file, size and failing are only used by the writing goroutine
+	// once the sink has been created
+	file    *os.File
+	size    int64
+	failing bool
+}
+
+// newFileSink creates a new file sink, appending to the existing file,
+// and starts writing the entries
+func newFileSink(path string, maxSize int64, maxFiles int) (*fileSink, error) {
+	sink := &fileSink{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+		entries:  make(chan *logEntry, fileSinkQueueSize),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	activeFileSinksMu.Lock()
+	sink.previous = activeFileSinks[path]
+	activeFileSinks[path] = sink
+	activeFileSinksMu.Unlock()
+
+	go sink.run()
+	return sink, nil
+}
+
+// run writes the queued entries until the sink is closed
+func (s *fileSink) run() {
+	defer close(s.done)
+	defer s.deactivate()
+
+	if s.previous != nil {
+		select {
+		case <-s.previous.done:
+			// The previous sink may have written or rotated the
+			// file after it was opened by this one
+			if err := s.reopen(); err != nil {
+				log.Error(err, "Error while opening the log file", "path", s.path)
+			}
+
+		case <-s.closing:
+			// This sink has been closed while the previous one is still
+			// in use, as it happens when the configuration including it
+			// could not be applied. It never received any entry.
+			s.closeErr = s.file.Close()
+			return
+		}
+		s.previous = nil
+	}
+
+	for entry := range s.entries {
+		err := s.writeLine(entry.line)
+		switch {
+		case err != nil && !s.failing:
+			log.Error(err, "Error while writing to the log file, discarding records", "path", s.path)
+		case err == nil && s.failing:
+			log.Info("Resumed writing to the log file", "path", s.path)
+		}
+		s.failing = err != nil
+	}
+
+	if s.file != nil {
+		s.closeErr = s.file.Close()
+		s.file = nil
+	}
+}
+
+// deactivate removes this sink from the active ones, unless it has
+// already been replaced. The previous sink, when still in use, becomes
+// the active one again
+func (s *fileSink) deactivate() {
+	activeFileSinksMu.Lock()
+	defer activeFileSinksMu.Unlock()
+
+	if activeFileSinks[s.path] != s {
+		return
+	}
+
+	if s.previous != nil {
+		activeFileSinks[s.path] = s.previous
+	} else {
+		delete(activeFileSinks, s.path)
+	}
+}
+
+// reopen closes the log file, if open, and opens it again
+func (s *fileSink) reopen() error {
+	if s.file != nil {
+		err := s.file.Close()
+		s.file = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.open()
+}
+
+// open opens the log file, taking note of its size
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file, shifting the rotated ones and
+// discarding the oldest, and opens a new one
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+// write implements the sink interface
+func (s *fileSink) write(entry *logEntry) {
+	select {
+	case s.entries <- entry:
+		s.dropping.Store(false)
+	default:
+		if !s.dropping.Swap(true) {
+			log.Info("The log file queue is full, discarding records", "path", s.path)
+		}
+	}
+}
+
+// writeLine writes a line to the log file, rotating it if needed.
+// It must only be called by the writing goroutine
+func (s *fileSink) writeLine(line []byte) error {
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := s.file.Write(line)
+	s.size += int64(written)
+	return err
+}
+
+// close implements the sink interface, waiting for the queued
+// entries to be written
+func (s *fileSink) close(ctx context.Context) error {
+	close(s.closing)
+	close(s.entries)
+
+	select {
+	case <-s.done:
+		return s.closeErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -24,6 +24,7 @@ package logpipe
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -44,6 +45,7 @@ type lineHandler func(line []byte)
 type LineLogPipe struct {
 	fileName string
 	handler  lineHandler
+	writer   RecordWriter
 
 	initialized *concurrency.Executed
 	exited      *concurrency.Executed
@@ -75,18 +77,106 @@ func NewJSONLineLogPipe(fileName string) *LineLogPipe {
 
 // NewRawLineLogPipe returns a logPipe for raw output
 func NewRawLineLogPipe(fileName, name string) *LineLogPipe {
-	logger := log.WithName(name).WithValues("source", fileName)
-
-	return &LineLogPipe{
+	pipe := &LineLogPipe{
 		fileName: fileName,
-		handler: func(line []byte) {
-			if len(line) != 0 {
-				logger.Info(string(line))
-			}
+		writer: &rawLineRecordWriter{
+			logger: log.WithName(name).WithValues("source", fileName),
 		},
 		initialized: concurrency.NewExecuted(),
 		exited:      concurrency.NewExecuted(),
 	}
+	pipe.handler = func(line []byte) {
+		if len(line) != 0 {
+			pipe.writer.Write(newRawLineRecord(name, line))
+		}
+	}
+
+	return pipe
+}
+
+// SetRouter dispatches the lines through the passed Router, using the
+// instance manager logger for the `stdout` sink. It must be called before
+// starting the pipe, and it has no effect on the pipes for json format
+func (p *LineLogPipe) SetRouter(router *Router) {
+	if p.writer != nil {
+		p.writer = router.NewWriter(p.writer)
+	}
+}
+
+// rawLineRecord is a line written by PostgreSQL, or by one of the
+// processes it runs, to its standard error
+type rawLineRecord struct {
+	name     string
+	severity string
+	line     string
+}
+
+// rawLineLevels maps the levels of the instance manager logger
+// to the PostgreSQL severities
+var rawLineLevels = map[string]string{
+	"error":   "ERROR",
+	"warning": "WARNING",
+	"info":    "INFO",
+	"debug":   "DEBUG1",
+	"trace":   "DEBUG5",
+}
+
+// newRawLineRecord creates a record for the passed line. The lines written
+// in JSON format by the instance manager subcommands, like `wal-archive`
+// and `wal-restore`, take the name and the severity of their logger
+func newRawLineRecord(name string, line []byte) *rawLineRecord {
+	record := &rawLineRecord{
+		name:     name,
+		severity: defaultSeverity,
+		line:     string(line),
+	}
+
+	var loggerLine struct {
+		Logger string `json:"logger"`
+		Level  string `json:"level"`
+	}
+	if line[0] == '{' && json.Unmarshal(line, &loggerLine) == nil {
+		if loggerLine.Logger != "" {
+			record.name = loggerLine.Logger
+		}
+		if severity, ok := rawLineLevels[loggerLine.Level]; ok {
+			record.severity = severity
+		}
+	}
+
+	return record
+}
+
+// GetName implements the NamedRecord interface
+func (r *rawLineRecord) GetName() string {
+	return r.name
+}
+
+// GetSeverity implements the SeverityRecord interface
+func (r *rawLineRecord) GetSeverity() string {
+	return r.severity
+}
+
+// MarshalJSON implements the json.Marshaler interface, embedding
+// the lines in JSON format and quoting the other ones
+func (r *rawLineRecord) MarshalJSON() ([]byte, error) {
+	if json.Valid([]byte(r.line)) {
+		return []byte(r.line), nil
+	}
+
+	return json.Marshal(r.line)
+}
+
+// rawLineRecordWriter writes the raw lines to the instance manager logger
+type rawLineRecordWriter struct {
+	logger log.Logger
+}
+
+// Write implements the RecordWriter interface
+func (w *rawLineRecordWriter) Write(record NamedRecord) {
+	if rawLine, ok := record.(*rawLineRecord); ok {
+		w.logger.Info(rawLine.line)
+	}
 }
 
 // Start a new goroutine running the logging collector core, reading
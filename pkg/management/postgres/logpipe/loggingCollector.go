@@ -127,3 +127,8 @@ func (r *LoggingRecord) FromCSV(content []string) NamedRecord {
 func (r *LoggingRecord) GetName() string {
 	return LoggingCollectorRecordName
 }
+
+// GetSeverity implements the SeverityRecord interface
+func (r *LoggingRecord) GetSeverity() string {
+	return r.ErrorSeverity
+}
@@ -45,6 +45,7 @@ type LogPipe struct {
 	fileName        string
 	record          CSVRecordParser
 	fieldsValidator FieldsValidator
+	writer          RecordWriter
 
 	initialized *concurrency.Executed
 	exited      *concurrency.Executed
@@ -62,12 +63,20 @@ func NewLogPipe() *LogPipe {
 		fileName:        filepath.Join(postgres.LogPath, postgres.LogFileName+".csv"),
 		record:          NewPgAuditLoggingDecorator(),
 		fieldsValidator: LogFieldValidator,
+		writer:          &LogRecordWriter{},
 
 		initialized: concurrency.NewExecuted(),
 		exited:      concurrency.NewExecuted(),
 	}
 }
 
+// SetRouter dispatches the log records through the passed Router, using the
+// instance manager logger for the `stdout` sink. It must be called before
+// starting the pipe
+func (p *LogPipe) SetRouter(router *Router) {
+	p.writer = router.NewWriter(p.writer)
+}
+
 // GetInitializedCondition returns the condition that can be checked in order to
 // be sure initialization has been done
 func (p *LogPipe) GetInitializedCondition() *concurrency.Executed {
@@ -151,7 +160,7 @@ func (p *LogPipe) collectLogsFromFile(ctx context.Context) error {
 	// the cancellation signal happened
 	go func() {
 		defer close(errChan)
-		errChan <- p.streamLogFromCSVFile(ctx, f, &LogRecordWriter{})
+		errChan <- p.streamLogFromCSVFile(ctx, f, p.writer)
 	}()
 	select {
 	case <-ctx.Done():
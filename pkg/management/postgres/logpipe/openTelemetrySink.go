@@ -0,0 +1,97 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"strings"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/telemetry"
+)
+
+// openTelemetryScope is the instrumentation scope of the exported log records
+const openTelemetryScope = "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logpipe"
+
+// openTelemetrySink pushes the log entries to an OpenTelemetry collector.
+// The entries are exported in batches by a background goroutine of the
+// OpenTelemetry SDK, which discards them when the collector can't keep up
+type openTelemetrySink struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+// newOpenTelemetrySink creates a new OpenTelemetry sink
+func newOpenTelemetrySink(
+	ctx context.Context,
+	telemetryExporter *telemetry.Exporter,
+	config *apiv1.OpenTelemetryLogSinkConfiguration,
+) (*openTelemetrySink, error) {
+	provider, err := telemetryExporter.NewLoggerProvider(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &openTelemetrySink{
+		provider: provider,
+		logger:   provider.Logger(openTelemetryScope),
+	}, nil
+}
+
+// write implements the sink interface
+func (s *openTelemetrySink) write(entry *logEntry) {
+	var record otellog.Record
+	record.SetObservedTimestamp(entry.timestamp)
+	record.SetSeverity(openTelemetrySeverity(entry.severity))
+	record.SetSeverityText(entry.severity)
+	record.SetBody(otellog.StringValue(string(entry.record)))
+	record.AddAttributes(otellog.String("logger", entry.logger))
+
+	s.logger.Emit(context.Background(), record)
+}
+
+// close implements the sink interface
+func (s *openTelemetrySink) close(ctx context.Context) error {
+	return s.provider.Shutdown(ctx)
+}
+
+// openTelemetrySeverity maps the PostgreSQL severities to
+// the OpenTelemetry ones
+func openTelemetrySeverity(severity string) otellog.Severity {
+	switch {
+	case strings.HasPrefix(severity, "DEBUG"):
+		return otellog.SeverityDebug
+	case severity == "NOTICE":
+		return otellog.SeverityInfo2
+	case severity == "WARNING":
+		return otellog.SeverityWarn
+	case severity == "ERROR":
+		return otellog.SeverityError
+	case severity == "FATAL":
+		return otellog.SeverityFatal
+	case severity == "PANIC":
+		return otellog.SeverityFatal4
+	default:
+		return otellog.SeverityInfo
+	}
+}
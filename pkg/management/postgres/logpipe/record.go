@@ -33,3 +33,9 @@ type CSVRecordParser interface {
 type NamedRecord interface {
 	GetName() string
 }
+
+// SeverityRecord is the interface for records carrying the severity
+// of the message, using the PostgreSQL levels
+type SeverityRecord interface {
+	GetSeverity() string
+}
@@ -0,0 +1,352 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/telemetry"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// defaultSeverity is the severity of the records not carrying one
+	defaultSeverity = "LOG"
+
+	// closeTimeout is the maximum time we wait for the pending records
+	// to be flushed when the sinks are closed
+	closeTimeout = 10 * time.Second
+)
+
+// severityLevels maps the PostgreSQL severities to their position in
+// the `log_min_messages` order
+var severityLevels = map[string]int{
+	"DEBUG5":  0,
+	"DEBUG4":  1,
+	"DEBUG3":  2,
+	"DEBUG2":  3,
+	"DEBUG1":  4,
+	"INFO":    5,
+	"NOTICE":  6,
+	"WARNING": 7,
+	"ERROR":   8,
+	"LOG":     9,
+	"FATAL":   10,
+	"PANIC":   11,
+}
+
+// sink is a destination of the log records, other than the standard
+// output of the instance manager
+type sink interface {
+	// write sends the entry to the destination. It must be safe to
+	// call it concurrently and must not block on slow destinations
+	write(entry *logEntry)
+
+	// close flushes the pending entries and releases the resources
+	close(ctx context.Context) error
+}
+
+// logEntry is a log record serialized once and shared between the sinks
+type logEntry struct {
+	timestamp time.Time
+	logger    string
+	severity  string
+
+	// record is the JSON representation of the record
+	record []byte
+
+	// line is the JSON representation of the record and of its metadata,
+	// terminated by a newline, as written by the file and the syslog sinks
+	line []byte
+}
+
+// newLogEntry serializes the passed record
+func newLogEntry(record NamedRecord) (*logEntry, error) {
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &logEntry{
+		timestamp: time.Now(),
+		logger:    record.GetName(),
+		severity:  getSeverity(record),
+		record:    recordJSON,
+	}
+
+	entry.line, err = json.Marshal(struct {
+		Timestamp string          `json:"ts"`
+		Logger    string          `json:"logger"`
+		Record    json.RawMessage `json:"record"`
+	}{
+		Timestamp: entry.timestamp.UTC().Format(time.RFC3339Nano),
+		Logger:    entry.logger,
+		Record:    recordJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entry.line = append(entry.line, '\n')
+
+	return entry, nil
+}
+
+// getSeverity returns the severity of the record, defaulting to LOG
+// for the records not carrying a known one
+func getSeverity(record NamedRecord) string {
+	if severityRecord, ok := record.(SeverityRecord); ok {
+		severity := strings.ToUpper(severityRecord.GetSeverity())
+		if _, known := severityLevels[severity]; known {
+			return severity
+		}
+	}
+
+	return defaultSeverity
+}
+
+// route is a rule choosing the sinks of the log records
+type route struct {
+	loggers     []string
+	minSeverity int
+	sinks       []string
+}
+
+// matches checks if the passed logger and severity match the rule
+func (r *route) matches(logger, severity string) bool {
+	if severityLevels[severity] < r.minSeverity {
+		return false
+	}
+
+	if len(r.loggers) == 0 {
+		return true
+	}
+
+	for _, name := range r.loggers {
+		if logger == name || strings.HasPrefix(logger, name+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Router dispatches the log records to the sinks chosen by the routes of
+// the logging configuration of the cluster. Records not matching any route
+// are written to the standard output. It can be reconfigured at runtime.
+// A nil Router is valid and writes every record to the standard output
+type Router struct {
+	telemetryExporter *telemetry.Exporter
+
+	mu     sync.RWMutex
+	config *apiv1.LoggingConfiguration
+	sinks  map[string]sink
+	routes []route
+}
+
+// NewRouter creates a new Router writing every record to the standard
+// output until a configuration is applied. The passed exporter is used
+// to create the OpenTelemetry sinks
+func NewRouter(telemetryExporter *telemetry.Exporter) *Router {
+	return &Router{
+		telemetryExporter: telemetryExporter,
+	}
+}
+
+// Configure applies the passed configuration, flushing and closing the
+// previous sinks. When the new sinks cannot be created, the previous
+// configuration is kept. Applying the configuration currently in use is a no-op
+func (r *Router) Configure(ctx context.Context, config *apiv1.LoggingConfiguration) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	unchanged := reflect.DeepEqual(config, r.config)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	sinks := make(map[string]sink)
+	var routes []route
+	if config != nil {
+		for _, sinkConfig := range config.Sinks {
+			newSink, err := r.newSink(ctx, sinkConfig)
+			if err != nil {
+				return errors.Join(
+					fmt.Errorf("while creating the %q log sink: %w", sinkConfig.Name, err),
+					closeSinks(ctx, sinks))
+			}
+			sinks[sinkConfig.Name] = newSink
+		}
+
+		routes = make([]route, len(config.Routes))
+		for i, routeConfig := range config.Routes {
+			routes[i] = route{
+				loggers:     routeConfig.Loggers,
+				minSeverity: severityLevels[routeConfig.MinSeverity],
+				sinks:       routeConfig.Sinks,
+			}
+		}
+	}
+
+	return closeSinks(ctx, r.swap(config.DeepCopy(), sinks, routes))
+}
+
+// Shutdown flushes the pending records and closes the sinks. The
+// following records are written to the standard output
+func (r *Router) Shutdown(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	return closeSinks(ctx, r.swap(nil, nil, nil))
+}
+
+// swap replaces the configuration in use, returning the previous sinks
+// which are no more reachable by the writers and can be closed
+func (r *Router) swap(
+	config *apiv1.LoggingConfiguration,
+	sinks map[string]sink,
+	routes []route,
+) map[string]sink {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previousSinks := r.sinks
+	r.config = config
+	r.sinks = sinks
+	r.routes = routes
+	return previousSinks
+}
+
+// NewWriter returns a RecordWriter routing the records through this Router,
+// using the passed writer for the `stdout` sink
+func (r *Router) NewWriter(stdout RecordWriter) RecordWriter {
+	if r == nil {
+		return stdout
+	}
+
+	return &routedRecordWriter{
+		router: r,
+		stdout: stdout,
+	}
+}
+
+// newSink creates the sink described by the passed configuration
+func (r *Router) newSink(ctx context.Context, config apiv1.LogSinkConfiguration) (sink, error) {
+	switch {
+	case config.File != nil:
+		return newFileSink(
+			filepath.Join(postgres.LogSinksPath, config.Name+".log"),
+			config.File.GetMaxSize(),
+			config.File.GetMaxFiles(),
+		)
+
+	case config.Syslog != nil:
+		return newSyslogSink(config.Syslog)
+
+	case config.OpenTelemetry != nil:
+		return newOpenTelemetrySink(ctx, r.telemetryExporter, config.OpenTelemetry)
+
+	default:
+		return nil, errors.New("no sink type specified")
+	}
+}
+
+// write sends the record to the sinks of the first matching route
+func (r *Router) write(record NamedRecord, stdout RecordWriter) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	logger, severity := record.GetName(), getSeverity(record)
+
+	var sinkNames []string
+	for i := range r.routes {
+		if r.routes[i].matches(logger, severity) {
+			sinkNames = r.routes[i].sinks
+			break
+		}
+	}
+
+	if sinkNames == nil {
+		stdout.Write(record)
+		return
+	}
+
+	var entry *logEntry
+	for _, name := range sinkNames {
+		if name == apiv1.LogSinkStdout {
+			stdout.Write(record)
+			continue
+		}
+
+		destination, ok := r.sinks[name]
+		if !ok {
+			continue
+		}
+
+		if entry == nil {
+			var err error
+			if entry, err = newLogEntry(record); err != nil {
+				log.Error(err, "Error while serializing a log record", "logger", logger)
+				return
+			}
+		}
+		destination.write(entry)
+	}
+}
+
+// closeSinks closes the passed sinks, collecting the errors
+func closeSinks(ctx context.Context, sinks map[string]sink) error {
+	ctx, cancel := context.WithTimeout(ctx, closeTimeout)
+	defer cancel()
+
+	errs := make([]error, 0, len(sinks))
+	for name, s := range sinks {
+		if err := s.close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("while closing the %q log sink: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// routedRecordWriter is a RecordWriter sending the records to a Router
+type routedRecordWriter struct {
+	router *Router
+	stdout RecordWriter
+}
+
+// Write implements the RecordWriter interface
+func (w *routedRecordWriter) Write(record NamedRecord) {
+	w.router.write(record, w.stdout)
+}
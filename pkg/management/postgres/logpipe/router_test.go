@@ -0,0 +1,202 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeSink is a sink keeping track of the received entries
+type fakeSink struct {
+	mu      sync.Mutex
+	entries []*logEntry
+	closed  bool
+}
+
+func (s *fakeSink) write(entry *logEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+func (s *fakeSink) close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+var _ = Describe("Log router", func() {
+	var (
+		audit  *fakeSink
+		remote *fakeSink
+		stdout *SpyRecordWriter
+		writer RecordWriter
+	)
+
+	BeforeEach(func() {
+		audit = &fakeSink{}
+		remote = &fakeSink{}
+		stdout = &SpyRecordWriter{}
+
+		router := NewRouter(nil)
+		router.swap(
+			&apiv1.LoggingConfiguration{},
+			map[string]sink{"audit": audit, "remote": remote},
+			[]route{
+				{loggers: []string{PgAuditRecordName}, sinks: []string{"audit"}},
+				{loggers: []string{"wal"}, sinks: []string{"remote", apiv1.LogSinkStdout}},
+				{minSeverity: severityLevels["ERROR"], sinks: []string{"remote"}},
+			},
+		)
+		writer = router.NewWriter(stdout)
+	})
+
+	It("sends the records to the sinks of the first matching route", func() {
+		auditRecord := NewPgAuditLoggingDecorator()
+		auditRecord.ErrorSeverity = "ERROR"
+		writer.Write(auditRecord)
+
+		Expect(audit.entries).To(HaveLen(1))
+		Expect(audit.entries[0].logger).To(Equal(PgAuditRecordName))
+		Expect(remote.entries).To(BeEmpty())
+		Expect(stdout.records).To(BeEmpty())
+	})
+
+	It("matches the children of the loggers and writes to stdout when requested", func() {
+		writer.Write(newRawLineRecord(LoggingCollectorRecordName,
+			[]byte(`{"level":"info","logger":"wal-archive","msg":"Archived WAL file"}`)))
+
+		Expect(remote.entries).To(HaveLen(1))
+		Expect(remote.entries[0].logger).To(Equal("wal-archive"))
+		Expect(stdout.records).To(HaveLen(1))
+	})
+
+	It("filters the records by severity", func() {
+		writer.Write(&LoggingRecord{ErrorSeverity: "FATAL", Message: "terminating connection"})
+		Expect(remote.entries).To(HaveLen(1))
+		Expect(remote.entries[0].severity).To(Equal("FATAL"))
+
+		var line struct {
+			Logger string        `json:"logger"`
+			Record LoggingRecord `json:"record"`
+		}
+		Expect(json.Unmarshal(remote.entries[0].line, &line)).To(Succeed())
+		Expect(line.Logger).To(Equal(LoggingCollectorRecordName))
+		Expect(line.Record.Message).To(Equal("terminating connection"))
+	})
+
+	It("writes the records not matching any route to stdout", func() {
+		writer.Write(&LoggingRecord{ErrorSeverity: "WARNING"})
+		writer.Write(newRawLineRecord(LoggingCollectorRecordName,
+			[]byte(`{"level":"info","logger":"instance-manager","msg":"Starting"}`)))
+
+		Expect(stdout.records).To(HaveLen(2))
+		Expect(audit.entries).To(BeEmpty())
+		Expect(remote.entries).To(BeEmpty())
+	})
+
+	It("writes everything to stdout when it is nil", func() {
+		var nilRouter *Router
+		Expect(nilRouter.Configure(context.Background(), &apiv1.LoggingConfiguration{})).To(Succeed())
+
+		nilRouter.NewWriter(stdout).Write(&LoggingRecord{})
+		Expect(stdout.records).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Log router configuration", func() {
+	It("replaces the sinks when the configuration changes", func(ctx SpecContext) {
+		router := NewRouter(nil)
+		config := &apiv1.LoggingConfiguration{
+			Sinks: []apiv1.LogSinkConfiguration{
+				{Name: "remote", Syslog: &apiv1.SyslogLogSinkConfiguration{Address: "127.0.0.1:1"}},
+			},
+			Routes: []apiv1.LogRouteConfiguration{
+				{MinSeverity: "WARNING", Sinks: []string{"remote"}},
+			},
+		}
+		Expect(router.Configure(ctx, config)).To(Succeed())
+		Expect(router.sinks).To(HaveKey("remote"))
+		Expect(router.routes).To(ConsistOf(route{
+			minSeverity: severityLevels["WARNING"],
+			sinks:       []string{"remote"},
+		}))
+		remote := router.sinks["remote"]
+
+		Expect(router.Configure(ctx, config.DeepCopy())).To(Succeed())
+		Expect(router.sinks["remote"]).To(BeIdenticalTo(remote))
+
+		Expect(router.Configure(ctx, nil)).To(Succeed())
+		Expect(router.sinks).To(BeEmpty())
+		Expect(router.routes).To(BeEmpty())
+	})
+
+	It("keeps the previous configuration when the sinks cannot be created", func(ctx SpecContext) {
+		router := NewRouter(nil)
+		previous := &fakeSink{}
+		router.swap(&apiv1.LoggingConfiguration{}, map[string]sink{"audit": previous}, nil)
+
+		err := router.Configure(ctx, &apiv1.LoggingConfiguration{
+			Sinks: []apiv1.LogSinkConfiguration{
+				{
+					Name:          "collector",
+					OpenTelemetry: &apiv1.OpenTelemetryLogSinkConfiguration{Endpoint: "http://collector:4318"},
+				},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(router.sinks).To(HaveKeyWithValue("audit", previous))
+		Expect(previous.closed).To(BeFalse())
+
+		Expect(router.Shutdown(ctx)).To(Succeed())
+		Expect(previous.closed).To(BeTrue())
+	})
+})
+
+var _ = Describe("Raw line records", func() {
+	It("takes the logger and the severity of the instance manager subcommands", func() {
+		record := newRawLineRecord(LoggingCollectorRecordName,
+			[]byte(`{"level":"error","logger":"wal-restore","msg":"WAL file not found"}`))
+		Expect(record.GetName()).To(Equal("wal-restore"))
+		Expect(record.GetSeverity()).To(Equal("ERROR"))
+
+		recordJSON, err := json.Marshal(record)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recordJSON).To(MatchJSON(`{"level":"error","logger":"wal-restore","msg":"WAL file not found"}`))
+	})
+
+	It("uses the name of the pipe for the other lines", func() {
+		record := newRawLineRecord(LoggingCollectorRecordName, []byte("could not open file"))
+		Expect(record.GetName()).To(Equal(LoggingCollectorRecordName))
+		Expect(record.GetSeverity()).To(Equal(defaultSeverity))
+
+		recordJSON, err := json.Marshal(record)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(recordJSON)).To(Equal(`"could not open file"`))
+	})
+})
@@ -0,0 +1,132 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File log sink", func() {
+	It("rotates the file when it grows over the maximum size", func(ctx SpecContext) {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		sink, err := newFileSink(path, 10, 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			sink.write(&logEntry{line: []byte(line)})
+		}
+		Expect(sink.close(ctx)).To(Succeed())
+
+		Expect(os.ReadFile(path)).To(BeEquivalentTo("fourth\n"))
+		Expect(os.ReadFile(path + ".1")).To(BeEquivalentTo("third\n"))
+		Expect(os.ReadFile(path + ".2")).To(BeEquivalentTo("second\n"))
+		Expect(path + ".3").ToNot(BeAnExistingFile())
+	})
+
+	It("appends to the existing file", func(ctx SpecContext) {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		Expect(os.WriteFile(path, []byte("existing\n"), 0o600)).To(Succeed())
+
+		sink, err := newFileSink(path, 1024, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.size).To(BeEquivalentTo(len("existing\n")))
+
+		sink.write(&logEntry{line: []byte("new\n")})
+		Expect(sink.close(ctx)).To(Succeed())
+		Expect(os.ReadFile(path)).To(BeEquivalentTo("existing\nnew\n"))
+	})
+})
+
+var _ = Describe("Replaced file log sinks", func() {
+	It("writes after the previous sink flushed its queue", func(ctx SpecContext) {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		previous, err := newFileSink(path, 1024, 2)
+		Expect(err).ToNot(HaveOccurred())
+		previous.write(&logEntry{line: []byte("previous\n")})
+
+		sink, err := newFileSink(path, 1024, 2)
+		Expect(err).ToNot(HaveOccurred())
+		sink.write(&logEntry{line: []byte("new\n")})
+
+		Expect(previous.close(ctx)).To(Succeed())
+		Expect(sink.close(ctx)).To(Succeed())
+		Expect(os.ReadFile(path)).To(BeEquivalentTo("previous\nnew\n"))
+	})
+
+	It("restores the previous sink when the new one is closed unused", func(ctx SpecContext) {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		previous, err := newFileSink(path, 1024, 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		sink, err := newFileSink(path, 1024, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.close(ctx)).To(Succeed())
+
+		activeFileSinksMu.Lock()
+		Expect(activeFileSinks[path]).To(BeIdenticalTo(previous))
+		activeFileSinksMu.Unlock()
+
+		previous.write(&logEntry{line: []byte("previous\n")})
+		Expect(previous.close(ctx)).To(Succeed())
+		Expect(os.ReadFile(path)).To(BeEquivalentTo("previous\n"))
+
+		activeFileSinksMu.Lock()
+		Expect(activeFileSinks).ToNot(HaveKey(path))
+		activeFileSinksMu.Unlock()
+	})
+})
+
+var _ = Describe("Syslog log sink", func() {
+	It("forwards the entries to the syslog server", func(ctx SpecContext) {
+		server, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(server.Close)
+
+		sink, err := newSyslogSink(&apiv1.SyslogLogSinkConfiguration{
+			Address:  server.LocalAddr().String(),
+			Facility: "local3",
+			Tag:      "cluster-example-1",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		sink.write(&logEntry{severity: "ERROR", line: []byte(`{"logger":"postgres"}` + "\n")})
+		Expect(sink.close(ctx)).To(Succeed())
+
+		buffer := make([]byte, 1024)
+		Expect(server.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+		n, _, err := server.ReadFrom(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		// local3 (19) * 8 + err (3)
+		message := string(buffer[:n])
+		Expect(message).To(HavePrefix("<155>"))
+		Expect(message).To(ContainSubstring("cluster-example-1["))
+		Expect(strings.TrimSpace(message)).To(HaveSuffix(`{"logger":"postgres"}`))
+	})
+})
@@ -0,0 +1,188 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"log/syslog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// syslogQueueSize is the number of entries a syslog sink buffers
+	// while the server is slow or unreachable, before discarding them
+	syslogQueueSize = 1024
+
+	// defaultSyslogTag is the default tag of the syslog messages
+	defaultSyslogTag = "postgres"
+)
+
+// syslogFacilities maps the facility names to their syslog values
+var syslogFacilities = map[string]syslog.Priority{
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// syslogSink forwards the log entries to a syslog server. The entries are
+// queued and sent by a dedicated goroutine, so that an unreachable server
+// never blocks PostgreSQL: entries are discarded when the queue is full.
+// The connection is established lazily and restored after a failure
+type syslogSink struct {
+	network  string
+	address  string
+	facility syslog.Priority
+	tag      string
+
+	entries chan *logEntry
+	done    chan struct{}
+
+	// dropping is true while the entries are being discarded
+	dropping atomic.Bool
+
+	// writer and failing are only used by the sending goroutine
+	writer  *syslog.Writer
+	failing bool
+}
+
+// newSyslogSink creates a new syslog sink and starts sending the entries
+func newSyslogSink(config *apiv1.SyslogLogSinkConfiguration) (*syslogSink, error) {
+	sink := &syslogSink{
+		network:  config.Protocol,
+		address:  config.Address,
+		facility: syslog.LOG_LOCAL0,
+		tag:      config.Tag,
+		entries:  make(chan *logEntry, syslogQueueSize),
+		done:     make(chan struct{}),
+	}
+	if sink.network == "" {
+		sink.network = "udp"
+	}
+	if facility, ok := syslogFacilities[config.Facility]; ok {
+		sink.facility = facility
+	}
+	if sink.tag == "" {
+		sink.tag = defaultSyslogTag
+	}
+
+	go sink.run()
+	return sink, nil
+}
+
+// write implements the sink interface
+func (s *syslogSink) write(entry *logEntry) {
+	select {
+	case s.entries <- entry:
+		s.dropping.Store(false)
+	default:
+		if !s.dropping.Swap(true) {
+			log.Info("The syslog queue is full, discarding records", "address", s.address)
+		}
+	}
+}
+
+// run sends the queued entries until the sink is closed
+func (s *syslogSink) run() {
+	defer close(s.done)
+
+	for entry := range s.entries {
+		err := s.send(entry)
+		switch {
+		case err != nil && !s.failing:
+			log.Error(err, "Error while forwarding the log records to syslog, discarding them",
+				"address", s.address)
+		case err == nil && s.failing:
+			log.Info("Resumed forwarding the log records to syslog", "address", s.address)
+		}
+		s.failing = err != nil
+	}
+
+	if s.writer != nil {
+		_ = s.writer.Close()
+	}
+}
+
+// send writes an entry to the syslog server, connecting to it if needed
+func (s *syslogSink) send(entry *logEntry) error {
+	if s.writer == nil {
+		writer, err := syslog.Dial(s.network, s.address, s.facility|syslog.LOG_INFO, s.tag)
+		if err != nil {
+			return err
+		}
+		s.writer = writer
+	}
+
+	if err := s.writeWithSeverity(entry); err != nil {
+		_ = s.writer.Close()
+		s.writer = nil
+		return err
+	}
+
+	return nil
+}
+
+// writeWithSeverity writes the entry using the syslog severity
+// corresponding to the PostgreSQL one
+func (s *syslogSink) writeWithSeverity(entry *logEntry) error {
+	message := string(entry.line)
+
+	switch {
+	case strings.HasPrefix(entry.severity, "DEBUG"):
+		return s.writer.Debug(message)
+	case entry.severity == "NOTICE":
+		return s.writer.Notice(message)
+	case entry.severity == "WARNING":
+		return s.writer.Warning(message)
+	case entry.severity == "ERROR":
+		return s.writer.Err(message)
+	case entry.severity == "FATAL":
+		return s.writer.Crit(message)
+	case entry.severity == "PANIC":
+		return s.writer.Emerg(message)
+	default:
+		return s.writer.Info(message)
+	}
+}
+
+// close implements the sink interface, waiting for the queued
+// entries to be sent
+func (s *syslogSink) close(ctx context.Context) error {
+	close(s.entries)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
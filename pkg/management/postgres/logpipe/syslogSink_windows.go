@@ -0,0 +1,51 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logpipe
+
+import (
+	"context"
+	"errors"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// errSyslogNotSupported is returned when a syslog sink is requested
+// on Windows, where the log/syslog package is not available
+var errSyslogNotSupported = errors.New("the syslog log sink is not supported on Windows")
+
+// syslogSink is a placeholder for the syslog sink, which is only
+// available on Unix systems, where the instance manager runs
+type syslogSink struct{}
+
+// newSyslogSink always fails on Windows
+func newSyslogSink(_ *apiv1.SyslogLogSinkConfiguration) (*syslogSink, error) {
+	return nil, errSyslogNotSupported
+}
+
+// write implements the sink interface
+func (s *syslogSink) write(_ *logEntry) {}
+
+// close implements the sink interface
+func (s *syslogSink) close(_ context.Context) error {
+	return nil
+}
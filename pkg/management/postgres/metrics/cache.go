@@ -0,0 +1,106 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queryCacheKey identifies the results of a user query on a database
+type queryCacheKey struct {
+	name     string
+	database string
+}
+
+// queryCacheEntry are the results of the last execution of a user query
+type queryCacheEntry struct {
+	userQuery   UserQuery
+	metrics     []prometheus.Metric
+	duration    time.Duration
+	collectedAt time.Time
+}
+
+// QueryResultsCache keeps the metrics generated by the user queries,
+// which are served instead of running the queries again until they
+// are older than the `cache_seconds` of the query.
+// The cache can be shared between different QueriesCollector,
+// so that it survives a reload of the user queries
+type QueryResultsCache struct {
+	mu      sync.Mutex
+	entries map[queryCacheKey]*queryCacheEntry
+}
+
+// NewQueryResultsCache creates a new empty cache
+func NewQueryResultsCache() *QueryResultsCache {
+	return &QueryResultsCache{
+		entries: make(map[queryCacheKey]*queryCacheEntry),
+	}
+}
+
+// get returns the results of the query, if they are newer than the
+// cache duration of the query, and the query has not changed since
+func (c *QueryResultsCache) get(key queryCacheKey, userQuery UserQuery, now time.Time) (*queryCacheEntry, bool) {
+	if userQuery.CacheSeconds == 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !reflect.DeepEqual(entry.userQuery, userQuery) ||
+		now.Sub(entry.collectedAt) >= time.Duration(userQuery.CacheSeconds)*time.Second {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// set stores the results of a query, if they need to be cached
+func (c *QueryResultsCache) set(key queryCacheKey, entry *queryCacheEntry) {
+	if entry.userQuery.CacheSeconds == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+}
+
+// retain removes the results of the queries not included in the passed set
+func (c *QueryResultsCache) retain(keys map[queryCacheKey]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if _, ok := keys[key]; !ok {
+			delete(c.entries, key)
+		}
+	}
+}
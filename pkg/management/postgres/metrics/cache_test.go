@@ -0,0 +1,164 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QueryResultsCache", func() {
+	var (
+		cache     *QueryResultsCache
+		key       queryCacheKey
+		userQuery UserQuery
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		cache = NewQueryResultsCache()
+		key = queryCacheKey{name: "bloat", database: "app"}
+		userQuery = UserQuery{Query: "SELECT 1", CacheSeconds: 300}
+		now = time.Now()
+	})
+
+	It("serves the results until they expire", func() {
+		cache.set(key, &queryCacheEntry{userQuery: userQuery, collectedAt: now})
+
+		entry, found := cache.get(key, userQuery, now.Add(299*time.Second))
+		Expect(found).To(BeTrue())
+		Expect(entry.collectedAt).To(Equal(now))
+
+		_, found = cache.get(key, userQuery, now.Add(300*time.Second))
+		Expect(found).To(BeFalse())
+	})
+
+	It("doesn't serve the results of a query which has changed", func() {
+		cache.set(key, &queryCacheEntry{userQuery: userQuery, collectedAt: now})
+
+		changedQuery := userQuery
+		changedQuery.Query = "SELECT 2"
+		_, found := cache.get(key, changedQuery, now)
+		Expect(found).To(BeFalse())
+	})
+
+	It("doesn't cache the results of queries without cache_seconds", func() {
+		userQuery.CacheSeconds = 0
+		cache.set(key, &queryCacheEntry{userQuery: userQuery, collectedAt: now})
+		Expect(cache.entries).To(BeEmpty())
+	})
+
+	It("forgets the results of the queries not executed anymore", func() {
+		otherKey := queryCacheKey{name: "bloat", database: "postgres"}
+		cache.set(key, &queryCacheEntry{userQuery: userQuery, collectedAt: now})
+		cache.set(otherKey, &queryCacheEntry{userQuery: userQuery, collectedAt: now})
+
+		cache.retain(map[queryCacheKey]struct{}{otherKey: {}})
+		Expect(cache.entries).To(HaveLen(1))
+		Expect(cache.entries).To(HaveKey(otherKey))
+	})
+})
+
+var _ = Describe("user queries execution", func() {
+	It("serves the cached results without connecting to the database", func() {
+		collector := NewQueriesCollector("cnpg", nil, "app")
+		collector.SetConcurrency(2)
+
+		userQuery := UserQuery{Query: "SELECT 1", CacheSeconds: 300}
+		desc := prometheus.NewDesc("cnpg_bloat_ratio", "test", nil, nil)
+		var tasks []userQueryTask
+		for _, database := range []string{"app", "postgres", "template1"} {
+			collector.cache.set(queryCacheKey{name: "bloat", database: database}, &queryCacheEntry{
+				userQuery:   userQuery,
+				metrics:     []prometheus.Metric{prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1)},
+				duration:    2 * time.Second,
+				collectedAt: time.Now().Add(-time.Minute),
+			})
+			tasks = append(tasks, userQueryTask{
+				name:      "bloat",
+				database:  database,
+				collector: QueryCollector{userQuery: userQuery},
+			})
+		}
+
+		ch := make(chan prometheus.Metric, 10)
+		collector.runUserQueryTasks(tasks, ch)
+		Expect(ch).To(HaveLen(3))
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector.userQueryDuration, collector.userQueryCacheAge)
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveLen(2))
+		for _, family := range families {
+			Expect(family.GetMetric()).To(HaveLen(3))
+			switch family.GetName() {
+			case "cnpg_user_query_duration_seconds":
+				Expect(family.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(2))
+			case "cnpg_user_query_cache_age_seconds":
+				Expect(family.GetMetric()[0].GetGauge().GetValue()).To(BeNumerically(">=", 60))
+			}
+		}
+	})
+
+	It("sets the statement timeout of the query", func() {
+		var (
+			db   *sql.DB
+			mock sqlmock.Sqlmock
+			err  error
+		)
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SET application_name").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET standard_conforming_strings").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET ROLE TO pg_monitor").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET LOCAL statement_timeout TO 5000").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(1))
+		mock.ExpectCommit()
+
+		userQuery := UserQuery{
+			Query:          "SELECT 1 AS value",
+			TimeoutSeconds: 5,
+			Metrics: []Mapping{
+				{"value": ColumnMapping{Usage: GAUGE, Description: "test"}},
+			},
+		}
+		mappings, variableLabels := userQuery.ToMetricMap("cnpg_test")
+		collector := QueryCollector{
+			namespace:      "test",
+			userQuery:      userQuery,
+			columnMapping:  mappings,
+			variableLabels: variableLabels,
+		}
+
+		metrics, err := collector.collectMetrics(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics).To(HaveLen(1))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -28,6 +28,7 @@ import (
 	"path"
 	"regexp"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/blang/semver"
@@ -56,6 +57,22 @@ type QueriesCollector struct {
 
 	errorUserQueries      *prometheus.CounterVec
 	errorUserQueriesGauge prometheus.Gauge
+	userQueryDuration     *prometheus.GaugeVec
+	userQueryCacheAge     *prometheus.GaugeVec
+
+	// cache keeps the results of the queries having cache_seconds set
+	cache *QueryResultsCache
+
+	// concurrency is the maximum number of queries executed
+	// at the same time during a scrape
+	concurrency int
+}
+
+// userQueryTask is the execution of a user query on a target database
+type userQueryTask struct {
+	name      string
+	database  string
+	collector QueryCollector
 }
 
 // Name returns the name of this collector, as supplied by the user in the configMap
@@ -69,6 +86,8 @@ var isPathPattern = regexp.MustCompile(`[][*?]`)
 func (q QueriesCollector) Collect(ch chan<- prometheus.Metric) error {
 	// Reset before collecting
 	q.errorUserQueries.Reset()
+	q.userQueryDuration.Reset()
+	q.userQueryCacheAge.Reset()
 
 	err := q.collectUserQueries(ch)
 	if err != nil {
@@ -78,6 +97,8 @@ func (q QueriesCollector) Collect(ch chan<- prometheus.Metric) error {
 	// Add errors into errorUserQueriesVec and errorUserQueriesGauge metrics
 	q.errorUserQueriesGauge.Collect(ch)
 	q.errorUserQueries.Collect(ch)
+	q.userQueryDuration.Collect(ch)
+	q.userQueryCacheAge.Collect(ch)
 
 	return nil
 }
@@ -92,6 +113,7 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 	// we need to get them just once
 	var allAccessibleDatabasesCache []string
 
+	var tasks []userQueryTask
 	for name, userQuery := range q.userQueries {
 		queryLogger := log.WithValues("query", name)
 		collector := QueryCollector{
@@ -105,8 +127,6 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 			continue
 		}
 
-		queryLogger.Debug("Collecting data")
-
 		targetDatabases := userQuery.TargetDatabases
 		if len(targetDatabases) == 0 {
 			targetDatabases = append(targetDatabases, q.defaultDBName)
@@ -129,24 +149,82 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 
 		allTargetDatabases := q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache)
 		for targetDatabase := range allTargetDatabases {
-			conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
-			if err != nil {
-				q.reportUserQueryErrorMetric(name + ": " + err.Error())
-				continue
-			}
-
-			err = collector.collect(conn, ch)
-			if err != nil {
-				queryLogger.Error(err, "Error collecting user query",
-					"targetDatabase", targetDatabase)
-				// Increment metrics counters.
-				q.reportUserQueryErrorMetric(name + " on db " + targetDatabase + ": " + err.Error())
-			}
+			tasks = append(tasks, userQueryTask{
+				name:      name,
+				database:  targetDatabase,
+				collector: collector,
+			})
 		}
 	}
+
+	q.runUserQueryTasks(tasks, ch)
 	return nil
 }
 
+// runUserQueryTasks executes the passed tasks, running at most
+// the configured number of them at the same time
+func (q *QueriesCollector) runUserQueryTasks(tasks []userQueryTask, ch chan<- prometheus.Metric) {
+	executedTasks := make(map[queryCacheKey]struct{}, len(tasks))
+	semaphore := make(chan struct{}, max(q.concurrency, 1))
+
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		executedTasks[queryCacheKey{name: task.name, database: task.database}] = struct{}{}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			q.runUserQueryTask(task, ch)
+		}()
+	}
+	wg.Wait()
+
+	// Forget the results of the queries which are not executed anymore
+	q.cache.retain(executedTasks)
+}
+
+// runUserQueryTask sends the metrics of a user query, executing it
+// unless its results are still in the cache
+func (q *QueriesCollector) runUserQueryTask(task userQueryTask, ch chan<- prometheus.Metric) {
+	queryLogger := log.WithValues("query", task.name, "targetDatabase", task.database)
+	key := queryCacheKey{name: task.name, database: task.database}
+	now := time.Now()
+
+	entry, found := q.cache.get(key, task.collector.userQuery, now)
+	if found {
+		queryLogger.Debug("Serving cached data")
+	} else {
+		queryLogger.Debug("Collecting data")
+
+		conn, err := q.instance.ConnectionPool().Connection(task.database)
+		if err != nil {
+			q.reportUserQueryErrorMetric(task.name + ": " + err.Error())
+			return
+		}
+
+		entry = &queryCacheEntry{userQuery: task.collector.userQuery, collectedAt: now}
+		entry.metrics, err = task.collector.collectMetrics(conn)
+		entry.duration = time.Since(now)
+		if err != nil {
+			queryLogger.Error(err, "Error collecting user query")
+			// Increment metrics counters.
+			q.reportUserQueryErrorMetric(task.name + " on db " + task.database + ": " + err.Error())
+		} else {
+			q.cache.set(key, entry)
+		}
+	}
+
+	for _, metric := range entry.metrics {
+		ch <- metric
+	}
+	q.userQueryDuration.WithLabelValues(task.name, task.database).Set(entry.duration.Seconds())
+	q.userQueryCacheAge.WithLabelValues(task.name, task.database).Set(now.Sub(entry.collectedAt).Seconds())
+}
+
 func (q QueriesCollector) toBeChecked(name string, userQuery UserQuery, isPrimary bool, queryLogger log.Logger) bool {
 	if (userQuery.Primary || userQuery.Master) && !isPrimary { // wokeignore:rule=master
 		queryLogger.Debug("Skipping because runs only on primary")
@@ -251,6 +329,8 @@ func (q QueriesCollector) Describe(ch chan<- *prometheus.Desc) {
 	// add error user queries description
 	q.errorUserQueries.Describe(ch)
 	q.errorUserQueriesGauge.Describe(ch)
+	q.userQueryDuration.Describe(ch)
+	q.userQueryCacheAge.Describe(ch)
 }
 
 // NewQueriesCollector creates a new PgCollector working over a set of custom queries
@@ -277,9 +357,34 @@ func NewQueriesCollector(
 			Name:      "last_error",
 			Help:      "1 if the last collection ended with error, 0 otherwise.",
 		}),
+		userQueryDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: name,
+			Subsystem: "user_query",
+			Name:      "duration_seconds",
+			Help:      "Time spent in the last execution of the user query, in seconds.",
+		}, []string{"query", "datname"}),
+		userQueryCacheAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: name,
+			Subsystem: "user_query",
+			Name:      "cache_age_seconds",
+			Help:      "Time elapsed since the last execution of the user query, 0 if it has just been executed.",
+		}, []string{"query", "datname"}),
+		cache:       NewQueryResultsCache(),
+		concurrency: 1,
 	}
 }
 
+// SetResultsCache sets the cache keeping the results of the user queries
+func (q *QueriesCollector) SetResultsCache(cache *QueryResultsCache) {
+	q.cache = cache
+}
+
+// SetConcurrency sets the maximum number of user queries
+// executed at the same time during a scrape
+func (q *QueriesCollector) SetConcurrency(concurrency int) {
+	q.concurrency = concurrency
+}
+
 // ParseQueries parses a YAML file containing custom queries and add it
 // to the set of gathered one
 func (q *QueriesCollector) ParseQueries(customQueries []byte) error {
@@ -331,6 +436,23 @@ type QueryCollector struct {
 	variableLabels VariableSet
 }
 
+// collectMetrics retrieves metrics from query and returns them
+func (c QueryCollector) collectMetrics(conn *sql.DB) ([]prometheus.Metric, error) {
+	ch := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
+	go func() {
+		var result []prometheus.Metric
+		for metric := range ch {
+			result = append(result, metric)
+		}
+		done <- result
+	}()
+
+	err := c.collect(conn, ch)
+	close(ch)
+	return <-done, err
+}
+
 // collect retrieves metrics from query and exposes them to prometheus
 func (c QueryCollector) collect(conn *sql.DB, ch chan<- prometheus.Metric) error {
 	tx, err := createMonitoringTx(conn)
@@ -344,6 +466,13 @@ func (c QueryCollector) collect(conn *sql.DB, ch chan<- prometheus.Metric) error
 		}
 	}()
 
+	if timeout := c.userQuery.TimeoutSeconds; timeout > 0 {
+		// The timeout only applies to the monitoring transaction
+		if _, err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout TO %d", timeout*1000)); err != nil {
+			return err
+		}
+	}
+
 	shouldBeCollected, err := c.userQuery.isCollectable(tx)
 	if err != nil {
 		return err
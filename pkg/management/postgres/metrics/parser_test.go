@@ -57,6 +57,7 @@ some_query:
   predicate_query: |
     SELECT 1 as row FROM some_table WHERE some filters
   cache_seconds: 100
+  timeout_seconds: 10
   metrics:
   - datname:
       usage: "LABEL"
@@ -81,6 +82,7 @@ some_query:
 		Expect(result["some_query"].Primary).To(BeFalse())
 		Expect(result["some_query"].TargetDatabases).To(ContainElements("test", "app"))
 		Expect(result["some_query"].CacheSeconds).To(BeEquivalentTo(100))
+		Expect(result["some_query"].TimeoutSeconds).To(BeEquivalentTo(10))
 		Expect(result["some_query"].Master).To(BeFalse()) // wokeignore:rule=master
 		Expect(result["some_query"].Metrics).To(HaveLen(2))
 		Expect(result["some_query"].Metrics[0]["datname"].Usage).To(Equal(ColumnUsage("LABEL")))
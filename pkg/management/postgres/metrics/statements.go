@@ -0,0 +1,490 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// statementsSubsystem is the subsystem of the metrics exported
+	// by the StatementsCollector
+	statementsSubsystem = "pg_stat_statements"
+
+	// statementsDeltaInterval is the minimum length of the windows the
+	// deltas are computed over. Having a fixed window, instead of the
+	// time elapsed since the previous scrape, keeps the deltas independent
+	// of the scrape interval and of the number of scrapers
+	statementsDeltaInterval = time.Minute
+
+	// otherStatementsQueryID is the value of the queryid label of the
+	// series aggregating the statements not ranking in any top N
+	otherStatementsQueryID = "other"
+
+	// statementsExtensionQuery checks if pg_stat_statements is available
+	statementsExtensionQuery = "SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_extension " +
+		"WHERE extname = 'pg_stat_statements')"
+
+	// statementsQuery reads the statistics of the statements, aggregating
+	// the ones executed by different users and at different nesting levels
+	statementsQuery = `SELECT d.datname,
+	s.queryid::text,
+	COALESCE(min(s.query), ''),
+	sum(s.calls),
+	sum(s.total_exec_time),
+	sum(s.rows),
+	sum(s.shared_blks_hit + s.local_blks_hit),
+	sum(s.shared_blks_read + s.local_blks_read + s.temp_blks_read),
+	sum(s.shared_blks_written + s.local_blks_written + s.temp_blks_written)
+FROM pg_stat_statements($1) s
+JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+WHERE s.queryid IS NOT NULL
+GROUP BY d.datname, s.queryid`
+)
+
+// statementKey identifies a statement across the databases
+type statementKey struct {
+	database string
+	queryID  string
+}
+
+// statementStats are the statistics of a statement
+type statementStats struct {
+	query         string
+	calls         float64
+	execTime      float64
+	rows          float64
+	blocksHit     float64
+	blocksRead    float64
+	blocksWritten float64
+}
+
+// statementColumn is a statistic exported for every statement, both
+// as a cumulative counter and as the delta over the last window
+type statementColumn struct {
+	name  string
+	help  string
+	value func(stats *statementStats) float64
+}
+
+var statementColumns = []statementColumn{
+	{
+		name:  "calls",
+		help:  "Number of times the statement was executed",
+		value: func(stats *statementStats) float64 { return stats.calls },
+	},
+	{
+		name:  "exec_time_seconds",
+		help:  "Time spent executing the statement, in seconds",
+		value: func(stats *statementStats) float64 { return stats.execTime / 1000 },
+	},
+	{
+		name:  "rows",
+		help:  "Number of rows retrieved or affected by the statement",
+		value: func(stats *statementStats) float64 { return stats.rows },
+	},
+	{
+		name:  "blocks_hit",
+		help:  "Number of shared and local buffer hits of the statement",
+		value: func(stats *statementStats) float64 { return stats.blocksHit },
+	},
+	{
+		name:  "blocks_read",
+		help:  "Number of shared, local and temporary blocks read by the statement",
+		value: func(stats *statementStats) float64 { return stats.blocksRead },
+	},
+	{
+		name:  "blocks_written",
+		help:  "Number of shared, local and temporary blocks written by the statement",
+		value: func(stats *statementStats) float64 { return stats.blocksWritten },
+	},
+}
+
+// statementRankings are the criteria used to choose the exported statements
+var statementRankings = []func(stats *statementStats) float64{
+	func(stats *statementStats) float64 { return stats.execTime },
+	func(stats *statementStats) float64 { return stats.calls },
+	func(stats *statementStats) float64 { return stats.blocksRead + stats.blocksWritten },
+}
+
+// statementsDescs are the descriptors of the metrics exported
+// by the StatementsCollector
+type statementsDescs struct {
+	totals        []*prometheus.Desc
+	deltas        []*prometheus.Desc
+	statements    *prometheus.Desc
+	deltaInterval *prometheus.Desc
+}
+
+// StatementsCollector exports the statistics of the most expensive
+// statements tracked by pg_stat_statements. To keep the cardinality
+// bounded, only the top N statements by execution time, calls and
+// blocks read and written are exported for each database, while the
+// other ones are aggregated in the deltas. The deltas are computed
+// over windows of at least statementsDeltaInterval, and the ones of the
+// last completed window are exported until the next one completes
+type StatementsCollector struct {
+	namespace string
+
+	mu     sync.Mutex
+	config *apiv1.PgStatStatementsConfiguration
+	descs  statementsDescs
+
+	// windowStart holds the statistics read when the current
+	// window started, at windowStartTime
+	windowStart     map[statementKey]statementStats
+	windowStartTime time.Time
+
+	// deltas holds the difference of the statistics over the
+	// last completed window, lasting deltaInterval
+	deltas        map[statementKey]statementStats
+	deltaInterval time.Duration
+}
+
+// NewStatementsCollector creates a new StatementsCollector, which is
+// disabled until a configuration is applied
+func NewStatementsCollector(namespace string) *StatementsCollector {
+	return &StatementsCollector{namespace: namespace}
+}
+
+// Name is the name of the collector
+func (c *StatementsCollector) Name() string {
+	return "PgStatStatements"
+}
+
+// Configure applies the passed configuration, a nil one
+// disables the collector
+func (c *StatementsCollector) Configure(config *apiv1.PgStatStatementsConfiguration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if reflect.DeepEqual(config, c.config) {
+		return
+	}
+
+	c.config = config.DeepCopy()
+	if c.config == nil {
+		c.resetDeltas()
+		return
+	}
+
+	labels := []string{"datname", "queryid"}
+	if c.config.IncludeQueryText {
+		labels = append(labels, "query")
+	}
+
+	c.descs = statementsDescs{
+		totals: make([]*prometheus.Desc, len(statementColumns)),
+		deltas: make([]*prometheus.Desc, len(statementColumns)),
+		statements: prometheus.NewDesc(
+			prometheus.BuildFQName(c.namespace, statementsSubsystem, "statements"),
+			"Number of statements tracked for the database, including the ones not exported",
+			[]string{"datname"}, nil),
+		deltaInterval: prometheus.NewDesc(
+			prometheus.BuildFQName(c.namespace, statementsSubsystem, "delta_interval_seconds"),
+			"Length of the window the deltas are computed over",
+			nil, nil),
+	}
+	for i, column := range statementColumns {
+		c.descs.totals[i] = prometheus.NewDesc(
+			prometheus.BuildFQName(c.namespace, statementsSubsystem, column.name+"_total"),
+			column.help,
+			labels, nil)
+		c.descs.deltas[i] = prometheus.NewDesc(
+			prometheus.BuildFQName(c.namespace, statementsSubsystem, column.name+"_delta"),
+			column.help+", over the last window",
+			labels, nil)
+	}
+}
+
+// IsEnabled returns true if the collector has been configured
+func (c *StatementsCollector) IsEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.config != nil
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *StatementsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.config == nil {
+		return
+	}
+
+	for i := range statementColumns {
+		ch <- c.descs.totals[i]
+		ch <- c.descs.deltas[i]
+	}
+	ch <- c.descs.statements
+	ch <- c.descs.deltaInterval
+}
+
+// Collect reads the statistics from pg_stat_statements and sends the
+// metrics to the channel. Nothing is collected when the extension
+// is not available
+func (c *StatementsCollector) Collect(db *sql.DB, ch chan<- prometheus.Metric) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.config == nil {
+		return nil
+	}
+
+	var available bool
+	if err := db.QueryRow(statementsExtensionQuery).Scan(&available); err != nil {
+		return fmt.Errorf("while checking for pg_stat_statements: %w", err)
+	}
+	if !available {
+		c.resetDeltas()
+		return nil
+	}
+
+	current, err := c.readStatements(db)
+	if err != nil {
+		return err
+	}
+
+	c.updateDeltas(current, time.Now())
+	hasDeltas := c.deltas != nil
+
+	statementsByDatabase := make(map[string][]statementKey)
+	for key := range current {
+		statementsByDatabase[key.database] = append(statementsByDatabase[key.database], key)
+	}
+
+	selected := make(map[statementKey]struct{})
+	for database, keys := range statementsByDatabase {
+		ch <- prometheus.MustNewConstMetric(
+			c.descs.statements, prometheus.GaugeValue, float64(len(keys)), database)
+
+		for key := range selectTopStatements(keys, current, c.config.GetTopN()) {
+			selected[key] = struct{}{}
+			stats := current[key]
+			labels := c.buildLabels(key.database, key.queryID, stats.query)
+			c.sendStatementMetrics(ch, c.descs.totals, prometheus.CounterValue, &stats, labels)
+			if delta, ok := c.deltas[key]; ok {
+				c.sendStatementMetrics(ch, c.descs.deltas, prometheus.GaugeValue, &delta, labels)
+			}
+		}
+	}
+
+	if !hasDeltas {
+		return nil
+	}
+
+	others := make(map[string]*statementStats)
+	for key, delta := range c.deltas {
+		if _, ok := selected[key]; ok {
+			continue
+		}
+		if others[key.database] == nil {
+			others[key.database] = &statementStats{}
+		}
+		addStatementStats(others[key.database], delta)
+	}
+	for database, other := range others {
+		labels := c.buildLabels(database, otherStatementsQueryID, "")
+		c.sendStatementMetrics(ch, c.descs.deltas, prometheus.GaugeValue, other, labels)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.descs.deltaInterval, prometheus.GaugeValue, c.deltaInterval.Seconds())
+
+	return nil
+}
+
+// updateDeltas starts the first window, or completes the current one
+// when it lasted at least statementsDeltaInterval, computing the deltas
+// of the statistics over it and starting a new window
+func (c *StatementsCollector) updateDeltas(current map[statementKey]statementStats, now time.Time) {
+	if c.windowStart == nil {
+		c.windowStart = current
+		c.windowStartTime = now
+		return
+	}
+
+	if now.Sub(c.windowStartTime) < statementsDeltaInterval {
+		return
+	}
+
+	c.deltas = make(map[statementKey]statementStats, len(current))
+	for key, stats := range current {
+		delta := stats
+		if windowStartStats, ok := c.windowStart[key]; ok {
+			delta = subtractStatementStats(stats, windowStartStats)
+		}
+		c.deltas[key] = delta
+	}
+	c.deltaInterval = now.Sub(c.windowStartTime)
+	c.windowStart = current
+	c.windowStartTime = now
+}
+
+// resetDeltas forgets the windows, which will restart
+// from the next collection
+func (c *StatementsCollector) resetDeltas() {
+	c.windowStart = nil
+	c.windowStartTime = time.Time{}
+	c.deltas = nil
+	c.deltaInterval = 0
+}
+
+// readStatements reads the statistics of the statements
+// of the monitored databases
+func (c *StatementsCollector) readStatements(db *sql.DB) (map[statementKey]statementStats, error) {
+	rows, err := db.Query(statementsQuery, c.config.IncludeQueryText)
+	if err != nil {
+		return nil, fmt.Errorf("while reading pg_stat_statements: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[statementKey]statementStats)
+	for rows.Next() {
+		var (
+			key   statementKey
+			stats statementStats
+		)
+		if err := rows.Scan(
+			&key.database,
+			&key.queryID,
+			&stats.query,
+			&stats.calls,
+			&stats.execTime,
+			&stats.rows,
+			&stats.blocksHit,
+			&stats.blocksRead,
+			&stats.blocksWritten,
+		); err != nil {
+			return nil, fmt.Errorf("while scanning pg_stat_statements: %w", err)
+		}
+
+		if len(c.config.Databases) > 0 && !slices.Contains(c.config.Databases, key.database) {
+			continue
+		}
+		result[key] = stats
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while reading pg_stat_statements: %w", err)
+	}
+
+	return result, nil
+}
+
+// buildLabels returns the label values of a statement
+func (c *StatementsCollector) buildLabels(database, queryID, query string) []string {
+	if !c.config.IncludeQueryText {
+		return []string{database, queryID}
+	}
+	return []string{database, queryID, normalizeQueryText(query, c.config.GetQueryTextMaxLength())}
+}
+
+// sendStatementMetrics sends a metric for every column of the statement
+func (c *StatementsCollector) sendStatementMetrics(
+	ch chan<- prometheus.Metric,
+	descs []*prometheus.Desc,
+	valueType prometheus.ValueType,
+	stats *statementStats,
+	labels []string,
+) {
+	for i, column := range statementColumns {
+		ch <- prometheus.MustNewConstMetric(descs[i], valueType, column.value(stats), labels...)
+	}
+}
+
+// selectTopStatements returns the union of the top N statements of
+// every ranking. Ties are broken by the queryid, to have the selection
+// stable between scrapes
+func selectTopStatements(
+	keys []statementKey,
+	stats map[statementKey]statementStats,
+	topN int,
+) map[statementKey]struct{} {
+	result := make(map[statementKey]struct{})
+	ranked := slices.Clone(keys)
+	for _, ranking := range statementRankings {
+		slices.SortFunc(ranked, func(a, b statementKey) int {
+			statsA, statsB := stats[a], stats[b]
+			if byValue := cmp.Compare(ranking(&statsB), ranking(&statsA)); byValue != 0 {
+				return byValue
+			}
+			return cmp.Compare(a.queryID, b.queryID)
+		})
+		for _, key := range ranked[:min(topN, len(ranked))] {
+			result[key] = struct{}{}
+		}
+	}
+	return result
+}
+
+// subtractStatementStats computes the difference between two readings of
+// the statistics of a statement. When the statistics have been reset in
+// the meantime, the current reading is the difference
+func subtractStatementStats(current, previous statementStats) statementStats {
+	if current.calls < previous.calls {
+		return current
+	}
+
+	return statementStats{
+		query:         current.query,
+		calls:         current.calls - previous.calls,
+		execTime:      current.execTime - previous.execTime,
+		rows:          current.rows - previous.rows,
+		blocksHit:     current.blocksHit - previous.blocksHit,
+		blocksRead:    current.blocksRead - previous.blocksRead,
+		blocksWritten: current.blocksWritten - previous.blocksWritten,
+	}
+}
+
+// addStatementStats adds the statistics of a statement to the target ones
+func addStatementStats(target *statementStats, stats statementStats) {
+	target.calls += stats.calls
+	target.execTime += stats.execTime
+	target.rows += stats.rows
+	target.blocksHit += stats.blocksHit
+	target.blocksRead += stats.blocksRead
+	target.blocksWritten += stats.blocksWritten
+}
+
+// normalizeQueryText collapses the whitespaces of the query text, which
+// has already been normalized by pg_stat_statements, and truncates it
+func normalizeQueryText(query string, maxLength int) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if runes := []rune(query); len(runes) > maxLength {
+		return string(runes[:maxLength])
+	}
+	return query
+}
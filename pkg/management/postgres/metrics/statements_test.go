@@ -0,0 +1,239 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var statementsColumns = []string{
+	"datname", "queryid", "query", "calls", "total_exec_time", "rows",
+	"blocks_hit", "blocks_read", "blocks_written",
+}
+
+// statementsTestCollector adapts a StatementsCollector to the
+// prometheus.Collector interface
+type statementsTestCollector struct {
+	collector *StatementsCollector
+	db        *sql.DB
+}
+
+func (c statementsTestCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.collector.Describe(ch)
+}
+
+func (c statementsTestCollector) Collect(ch chan<- prometheus.Metric) {
+	Expect(c.collector.Collect(c.db, ch)).To(Succeed())
+}
+
+// collectStatements runs a collection and returns the metrics
+// indexed by their name
+func collectStatements(
+	collector *StatementsCollector,
+	db *sql.DB,
+) map[string][]*dto.Metric {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(statementsTestCollector{collector: collector, db: db})
+	families, err := registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	result := make(map[string][]*dto.Metric)
+	for _, family := range families {
+		result[family.GetName()] = family.GetMetric()
+	}
+	return result
+}
+
+// getLabel returns the value of the named label of a metric
+func getLabel(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+// findStatement returns the metric having the passed queryid
+func findStatement(metrics []*dto.Metric, queryID string) *dto.Metric {
+	for _, metric := range metrics {
+		if getLabel(metric, "queryid") == queryID {
+			return metric
+		}
+	}
+	return nil
+}
+
+var _ = Describe("pg_stat_statements collector", func() {
+	var (
+		db        *sql.DB
+		mock      sqlmock.Sqlmock
+		collector *StatementsCollector
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		collector = NewStatementsCollector("cnpg")
+		collector.Configure(&apiv1.PgStatStatementsConfiguration{TopN: 1})
+	})
+
+	expectStatements := func(rows *sqlmock.Rows) {
+		mock.ExpectQuery("pg_extension").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery("FROM pg_stat_statements").
+			WithArgs(false).
+			WillReturnRows(rows)
+	}
+
+	It("doesn't query the database when disabled", func() {
+		collector.Configure(nil)
+		Expect(collector.IsEnabled()).To(BeFalse())
+		Expect(collectStatements(collector, db)).To(BeEmpty())
+	})
+
+	It("doesn't export anything when the extension is not available", func() {
+		mock.ExpectQuery("pg_extension").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		Expect(collectStatements(collector, db)).To(BeEmpty())
+	})
+
+	It("exports the top statements of every ranking and the deltas", func() {
+		// 1 is the slowest, 2 the most called, 3 the one with most I/O
+		// and 4 doesn't rank in any top 1
+		expectStatements(sqlmock.NewRows(statementsColumns).
+			AddRow("app", "1", "", 10, 50000, 10, 0, 0, 0).
+			AddRow("app", "2", "", 1000, 1000, 1000, 0, 0, 0).
+			AddRow("app", "3", "", 1, 100, 1, 0, 500, 500).
+			AddRow("app", "4", "", 5, 10, 5, 0, 0, 0))
+
+		metrics := collectStatements(collector, db)
+		calls := metrics["cnpg_pg_stat_statements_calls_total"]
+		Expect(calls).To(HaveLen(3))
+		Expect(findStatement(calls, "1").GetCounter().GetValue()).To(BeEquivalentTo(10))
+		Expect(findStatement(calls, "2").GetCounter().GetValue()).To(BeEquivalentTo(1000))
+		Expect(findStatement(calls, "3").GetCounter().GetValue()).To(BeEquivalentTo(1))
+		Expect(findStatement(calls, "4")).To(BeNil())
+		Expect(findStatement(metrics["cnpg_pg_stat_statements_exec_time_seconds_total"], "1").
+			GetCounter().GetValue()).To(BeEquivalentTo(50))
+		Expect(metrics["cnpg_pg_stat_statements_statements"][0].GetGauge().GetValue()).To(BeEquivalentTo(4))
+
+		// deltas need a completed window
+		Expect(metrics).ToNot(HaveKey("cnpg_pg_stat_statements_calls_delta"))
+		Expect(metrics).ToNot(HaveKey("cnpg_pg_stat_statements_delta_interval_seconds"))
+		collector.windowStartTime = collector.windowStartTime.Add(-statementsDeltaInterval)
+
+		// statement 2 has been reset, 4 executed again, and 5 is new
+		expectStatements(sqlmock.NewRows(statementsColumns).
+			AddRow("app", "1", "", 15, 60000, 15, 0, 0, 0).
+			AddRow("app", "2", "", 20, 20, 20, 0, 0, 0).
+			AddRow("app", "3", "", 1, 100, 1, 0, 500, 500).
+			AddRow("app", "4", "", 7, 12, 7, 0, 0, 0).
+			AddRow("app", "5", "", 3, 3, 3, 0, 0, 0))
+
+		metrics = collectStatements(collector, db)
+		deltas := metrics["cnpg_pg_stat_statements_calls_delta"]
+		Expect(findStatement(deltas, "1").GetGauge().GetValue()).To(BeEquivalentTo(5))
+		Expect(findStatement(deltas, "2").GetGauge().GetValue()).To(BeEquivalentTo(20))
+		Expect(findStatement(deltas, "3").GetGauge().GetValue()).To(BeEquivalentTo(0))
+		Expect(findStatement(deltas, otherStatementsQueryID).GetGauge().GetValue()).To(BeEquivalentTo(5))
+		Expect(metrics["cnpg_pg_stat_statements_delta_interval_seconds"][0].GetGauge().GetValue()).
+			To(BeNumerically(">=", statementsDeltaInterval.Seconds()))
+	})
+
+	It("keeps the deltas of the last window until the next one completes", func() {
+		expectStatements(sqlmock.NewRows(statementsColumns).
+			AddRow("app", "1", "", 10, 100, 10, 0, 0, 0))
+		collectStatements(collector, db)
+		collector.windowStartTime = collector.windowStartTime.Add(-statementsDeltaInterval)
+
+		expectStatements(sqlmock.NewRows(statementsColumns).
+			AddRow("app", "1", "", 15, 150, 15, 0, 0, 0))
+		deltas := collectStatements(collector, db)["cnpg_pg_stat_statements_calls_delta"]
+		Expect(findStatement(deltas, "1").GetGauge().GetValue()).To(BeEquivalentTo(5))
+
+		// A scrape inside the current window, like the one of another
+		// scraper, doesn't change the deltas
+		expectStatements(sqlmock.NewRows(statementsColumns).
+			AddRow("app", "1", "", 17, 170, 17, 0, 0, 0))
+		metrics := collectStatements(collector, db)
+		Expect(findStatement(metrics["cnpg_pg_stat_statements_calls_delta"], "1").GetGauge().GetValue()).
+			To(BeEquivalentTo(5))
+		Expect(findStatement(metrics["cnpg_pg_stat_statements_calls_total"], "1").GetCounter().GetValue()).
+			To(BeEquivalentTo(17))
+
+		collector.windowStartTime = collector.windowStartTime.Add(-statementsDeltaInterval)
+		expectStatements(sqlmock.NewRows(statementsColumns).
+			AddRow("app", "1", "", 20, 200, 20, 0, 0, 0))
+		deltas = collectStatements(collector, db)["cnpg_pg_stat_statements_calls_delta"]
+		Expect(findStatement(deltas, "1").GetGauge().GetValue()).To(BeEquivalentTo(5))
+	})
+
+	It("exports the normalized query text when requested", func() {
+		collector.Configure(&apiv1.PgStatStatementsConfiguration{
+			TopN:               1,
+			IncludeQueryText:   true,
+			QueryTextMaxLength: 20,
+			Databases:          []string{"app"},
+		})
+
+		mock.ExpectQuery("pg_extension").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery("FROM pg_stat_statements").
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows(statementsColumns).
+				AddRow("app", "1", "SELECT *\n  FROM   users WHERE id = $1", 1, 1, 1, 0, 0, 0).
+				AddRow("postgres", "2", "SELECT 1", 1, 1, 1, 0, 0, 0))
+
+		calls := collectStatements(collector, db)["cnpg_pg_stat_statements_calls_total"]
+		Expect(calls).To(HaveLen(1))
+		Expect(getLabel(calls[0], "datname")).To(Equal("app"))
+		Expect(getLabel(calls[0], "query")).To(Equal("SELECT * FROM users "))
+	})
+})
+
+var _ = Describe("selectTopStatements", func() {
+	It("breaks ties by queryid", func() {
+		stats := map[statementKey]statementStats{
+			{database: "app", queryID: "b"}: {calls: 1},
+			{database: "app", queryID: "a"}: {calls: 1},
+			{database: "app", queryID: "c"}: {calls: 1},
+		}
+		keys := []statementKey{{"app", "c"}, {"app", "b"}, {"app", "a"}}
+
+		Expect(selectTopStatements(keys, stats, 1)).To(Equal(map[statementKey]struct{}{
+			{database: "app", queryID: "a"}: {},
+		}))
+	})
+})
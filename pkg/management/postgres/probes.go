@@ -115,6 +115,7 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	}
 
 	result.InstanceArch = instance.GetArchitecture()
+	result.ClientConnectionsFenceLSN = instance.GetClientConnectionsFenceLSN()
 
 	result.ExecutableHash, err = executablehash.Get()
 	if err != nil {
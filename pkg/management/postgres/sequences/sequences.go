@@ -0,0 +1,99 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequences
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetSequencesQuery is the query returning, as a JSON array, the status of
+// every sequence defined in the database
+const GetSequencesQuery = `
+WITH seqs AS (
+    SELECT 
+        sequencename AS sq_name,
+        schemaname AS sq_namespace,
+        last_value AS sq_value,
+        CURRENT_TIMESTAMP AS ts
+    FROM pg_catalog.pg_sequences s
+)
+SELECT pg_catalog.json_agg(seqs) FROM seqs
+`
+
+// SequenceStatus represent the status of a sequence in a certain moment
+type SequenceStatus struct {
+	// The name of the sequence
+	Name string `json:"sq_name"`
+
+	// The namespace where the sequence is defined
+	Namespace string `json:"sq_namespace"`
+
+	// The last value emitted from the sequence
+	Value *int64 `json:"sq_value"`
+}
+
+// QualifiedName gets the qualified name of this sequence
+func (status *SequenceStatus) QualifiedName() string {
+	return fmt.Sprintf(
+		"%s.%s",
+		pgx.Identifier{status.Namespace}.Sanitize(),
+		pgx.Identifier{status.Name}.Sanitize(),
+	)
+}
+
+// SequenceMap is a map between a qualified sequence name
+// and its current value
+type SequenceMap map[string]*int64
+
+// ParseSequenceMap decodes the output of GetSequencesQuery
+func ParseSequenceMap(output []byte) (SequenceMap, error) {
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+
+	var records []SequenceStatus
+	if err := json.Unmarshal(output, &records); err != nil {
+		return nil, fmt.Errorf("while decoding JSON output: %w", err)
+	}
+
+	result := make(SequenceMap)
+	for i := range records {
+		result[records[i].QualifiedName()] = records[i].Value
+	}
+
+	return result, nil
+}
+
+// GetSequenceMap gets the status of the sequences of the database
+// the passed connection is open on
+func GetSequenceMap(ctx context.Context, db *sql.DB) (SequenceMap, error) {
+	var output sql.NullString
+	if err := db.QueryRowContext(ctx, GetSequencesQuery).Scan(&output); err != nil {
+		return nil, fmt.Errorf("while executing query: %w", err)
+	}
+
+	return ParseSequenceMap([]byte(output.String))
+}
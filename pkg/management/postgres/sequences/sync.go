@@ -0,0 +1,115 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequences
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// CreateSyncStatements creates the SQL statements needed to synchronize
+// the sequences in the destination database with the status of the
+// source database, sorted by the name of the sequence
+func CreateSyncStatements(source, destination SequenceMap, offset int64) []string {
+	var statements []string
+
+	for _, name := range slices.Sorted(maps.Keys(destination)) {
+		targetValue, ok := source[name]
+		if !ok {
+			// This sequence is not available in the source database,
+			// there's no need to update it
+			continue
+		}
+
+		sqlTargetValue := "NULL"
+		if targetValue != nil {
+			sqlTargetValue = fmt.Sprintf("%d", *targetValue)
+			if offset != 0 {
+				sqlTargetValue = fmt.Sprintf("%s + %d", sqlTargetValue, offset)
+			}
+		}
+
+		statements = append(statements, fmt.Sprintf(
+			"SELECT pg_catalog.setval(%s, %v);",
+			pq.QuoteLiteral(name),
+			sqlTargetValue))
+	}
+
+	return statements
+}
+
+// CreateSyncScript creates a SQL script to synchronize the sequences
+// in the destination database with the status of the source database
+func CreateSyncScript(source, destination SequenceMap, offset int64) string {
+	var script strings.Builder
+	for _, statement := range CreateSyncStatements(source, destination, offset) {
+		script.WriteString(statement)
+		script.WriteString("\n")
+	}
+
+	return script.String()
+}
+
+// Synchronize updates the sequences of the destination database with the
+// values they have in the source database, adding the passed offset.
+// The sequences are updated inside a single transaction, and the number
+// of updated sequences is returned
+func Synchronize(ctx context.Context, source, destination *sql.DB, offset int64) (int, error) {
+	sourceStatus, err := GetSequenceMap(ctx, source)
+	if err != nil {
+		return 0, fmt.Errorf("while getting sequences status from the source database: %w", err)
+	}
+
+	destinationStatus, err := GetSequenceMap(ctx, destination)
+	if err != nil {
+		return 0, fmt.Errorf("while getting sequences status from the destination database: %w", err)
+	}
+
+	statements := CreateSyncStatements(sourceStatus, destinationStatus, offset)
+	if len(statements) == 0 {
+		return 0, nil
+	}
+
+	tx, err := destination.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("while starting the sequences update transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return 0, fmt.Errorf("while updating sequences: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("while committing the sequences update: %w", err)
+	}
+
+	return len(statements), nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequences
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/utils/ptr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sequences synchronization script", func() {
+	source := SequenceMap{
+		`"public"."orders_id_seq"`:    ptr.To(int64(42)),
+		`"public"."customers_id_seq"`: ptr.To(int64(7)),
+		`"public"."unused_id_seq"`:    nil,
+		`"sales"."items_id_seq"`:      ptr.To(int64(1)),
+	}
+	destination := SequenceMap{
+		`"public"."orders_id_seq"`:    ptr.To(int64(1)),
+		`"public"."customers_id_seq"`: nil,
+		`"public"."unused_id_seq"`:    ptr.To(int64(3)),
+		`"public"."local_id_seq"`:     ptr.To(int64(5)),
+	}
+
+	It("only updates the sequences existing in both databases, in order", func() {
+		Expect(CreateSyncStatements(source, destination, 0)).To(Equal([]string{
+			`SELECT pg_catalog.setval('"public"."customers_id_seq"', 7);`,
+			`SELECT pg_catalog.setval('"public"."orders_id_seq"', 42);`,
+			`SELECT pg_catalog.setval('"public"."unused_id_seq"', NULL);`,
+		}))
+	})
+
+	It("adds the offset to the values", func() {
+		Expect(CreateSyncScript(source, destination, 10)).To(Equal(
+			`SELECT pg_catalog.setval('"public"."customers_id_seq"', 7 + 10);` + "\n" +
+				`SELECT pg_catalog.setval('"public"."orders_id_seq"', 42 + 10);` + "\n" +
+				`SELECT pg_catalog.setval('"public"."unused_id_seq"', NULL);` + "\n",
+		))
+	})
+
+	It("decodes the sequences status", func() {
+		result, err := ParseSequenceMap([]byte(
+			`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":42},` +
+				`{"sq_name":"items_id_seq","sq_namespace":"sales","sq_value":null}]`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(HaveLen(2))
+		Expect(result).To(HaveKeyWithValue(`"public"."orders_id_seq"`, HaveValue(BeEquivalentTo(42))))
+		Expect(result).To(HaveKeyWithValue(`"sales"."items_id_seq"`, BeNil()))
+	})
+
+	It("handles databases without sequences", func() {
+		result, err := ParseSequenceMap([]byte("\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeEmpty())
+	})
+})
+
+var _ = Describe("sequences synchronization", func() {
+	It("updates the destination sequences in a transaction", func(ctx SpecContext) {
+		sourceDB, sourceMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		destinationDB, destinationMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		sourceMock.ExpectQuery(GetSequencesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"json_agg"}).AddRow(
+				`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":42}]`))
+		destinationMock.ExpectQuery(GetSequencesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"json_agg"}).AddRow(
+				`[{"sq_name":"orders_id_seq","sq_namespace":"public","sq_value":1}]`))
+		destinationMock.ExpectBegin()
+		destinationMock.ExpectExec(`SELECT pg_catalog.setval('"public"."orders_id_seq"', 42 + 5);`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		destinationMock.ExpectCommit()
+
+		updated, err := Synchronize(ctx, sourceDB, destinationDB, 5)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(Equal(1))
+		Expect(sourceMock.ExpectationsWereMet()).To(Succeed())
+		Expect(destinationMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("doesn't open a transaction when there's nothing to update", func(ctx SpecContext) {
+		sourceDB, sourceMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		destinationDB, destinationMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		sourceMock.ExpectQuery(GetSequencesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"json_agg"}).AddRow(nil))
+		destinationMock.ExpectQuery(GetSequencesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"json_agg"}).AddRow(nil))
+
+		updated, err := Synchronize(ctx, sourceDB, destinationDB, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeZero())
+		Expect(sourceMock.ExpectationsWereMet()).To(Succeed())
+		Expect(destinationMock.ExpectationsWereMet()).To(Succeed())
+	})
+})
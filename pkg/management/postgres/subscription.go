@@ -0,0 +1,144 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SubscriptionRuntimeState is the state of the apply worker of a logical
+// replication subscription, as reported by `pg_stat_subscription` and
+// `pg_stat_subscription_stats`
+type SubscriptionRuntimeState struct {
+	// Name is the name of the subscription
+	Name string
+
+	// DatabaseName is the name of the database containing the subscription
+	DatabaseName string
+
+	// Enabled is true if the subscription is enabled
+	Enabled bool
+
+	// WorkerActive is true if the apply worker is running
+	WorkerActive bool
+
+	// ReceivedLSN is the last WAL location received by the apply worker,
+	// empty if the worker is not running
+	ReceivedLSN string
+
+	// ApplyLag is the time elapsed since the last WAL location has been
+	// reported to the publisher, nil if unknown
+	ApplyLag *time.Duration
+
+	// ApplyErrorCount is the number of errors raised while applying
+	// changes. Always zero before PostgreSQL 15
+	ApplyErrorCount int64
+
+	// SyncErrorCount is the number of errors raised during the initial
+	// table synchronization. Always zero before PostgreSQL 15
+	SyncErrorCount int64
+}
+
+// GetSubscriptionsRuntimeState returns the state of the apply workers of
+// every subscription defined in the instance. As the subscriptions
+// catalog is shared, any database connection can be used
+func GetSubscriptionsRuntimeState(
+	ctx context.Context,
+	db *sql.DB,
+	pgMajorVersion int,
+) ([]SubscriptionRuntimeState, error) {
+	rows, err := db.QueryContext(ctx, getSubscriptionsRuntimeStateQuery(pgMajorVersion))
+	if err != nil {
+		return nil, fmt.Errorf("while getting the subscriptions runtime state: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []SubscriptionRuntimeState
+	for rows.Next() {
+		var (
+			state    SubscriptionRuntimeState
+			applyLag sql.NullFloat64
+		)
+		if err := rows.Scan(
+			&state.Name,
+			&state.DatabaseName,
+			&state.Enabled,
+			&state.WorkerActive,
+			&state.ReceivedLSN,
+			&applyLag,
+			&state.ApplyErrorCount,
+			&state.SyncErrorCount,
+		); err != nil {
+			return nil, fmt.Errorf("while scanning the subscriptions runtime state: %w", err)
+		}
+		if applyLag.Valid {
+			lag := time.Duration(applyLag.Float64 * float64(time.Second))
+			state.ApplyLag = &lag
+		}
+		result = append(result, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("while getting the subscriptions runtime state: %w", err)
+	}
+
+	return result, nil
+}
+
+// getSubscriptionsRuntimeStateQuery builds the query used to detect the
+// state of the subscription apply workers, depending on the PostgreSQL
+// major version
+func getSubscriptionsRuntimeStateQuery(pgMajorVersion int) string {
+	// Table synchronization workers have a relid, and parallel apply
+	// workers (PostgreSQL 16+) a leader_pid. PostgreSQL 17 reports
+	// the worker type explicitly
+	applyWorkerFilter := "w.relid IS NULL"
+	switch {
+	case pgMajorVersion >= 17:
+		applyWorkerFilter = "w.worker_type = 'apply'"
+	case pgMajorVersion >= 16:
+		applyWorkerFilter = "w.relid IS NULL AND w.leader_pid IS NULL"
+	}
+
+	// pg_stat_subscription_stats is available since PostgreSQL 15
+	errorCounters := "0, 0"
+	statsJoin := ""
+	if pgMajorVersion >= 15 {
+		errorCounters = "COALESCE(st.apply_error_count, 0), COALESCE(st.sync_error_count, 0)"
+		statsJoin = "LEFT JOIN pg_catalog.pg_stat_subscription_stats st ON st.subid = s.oid"
+	}
+
+	return fmt.Sprintf(`SELECT s.subname, d.datname, s.subenabled,
+		w.pid IS NOT NULL,
+		COALESCE(w.received_lsn::text, ''),
+		EXTRACT(EPOCH FROM pg_catalog.now() - w.latest_end_time),
+		%s
+		FROM pg_catalog.pg_subscription s
+		JOIN pg_catalog.pg_database d ON d.oid = s.subdbid
+		LEFT JOIN pg_catalog.pg_stat_subscription w ON w.subid = s.oid AND %s
+		%s
+		ORDER BY d.datname, s.subname`,
+		errorCounters, applyWorkerFilter, statsJoin)
+}
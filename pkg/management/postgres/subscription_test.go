@@ -0,0 +1,71 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("subscriptions runtime state", func() {
+	columns := []string{
+		"subname", "datname", "subenabled", "worker_active", "received_lsn",
+		"apply_lag", "apply_error_count", "sync_error_count",
+	}
+
+	It("reads the state of the apply workers", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(getSubscriptionsRuntimeStateQuery(17)).WillReturnRows(
+			sqlmock.NewRows(columns).
+				AddRow("sub_active", "app", true, true, "0/3000060", 1.5, 0, 0).
+				AddRow("sub_failing", "app", true, false, "", nil, 3, 1))
+
+		states, err := GetSubscriptionsRuntimeState(ctx, db, 17)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(states).To(HaveLen(2))
+
+		Expect(states[0].Name).To(Equal("sub_active"))
+		Expect(states[0].DatabaseName).To(Equal("app"))
+		Expect(states[0].WorkerActive).To(BeTrue())
+		Expect(states[0].ReceivedLSN).To(Equal("0/3000060"))
+		Expect(states[0].ApplyLag).To(HaveValue(Equal(1500 * time.Millisecond)))
+
+		Expect(states[1].WorkerActive).To(BeFalse())
+		Expect(states[1].ApplyLag).To(BeNil())
+		Expect(states[1].ApplyErrorCount).To(BeEquivalentTo(3))
+		Expect(states[1].SyncErrorCount).To(BeEquivalentTo(1))
+
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("adapts the query to the PostgreSQL version", func() {
+		Expect(getSubscriptionsRuntimeStateQuery(14)).ToNot(ContainSubstring("pg_stat_subscription_stats"))
+		Expect(getSubscriptionsRuntimeStateQuery(14)).To(ContainSubstring("w.relid IS NULL"))
+		Expect(getSubscriptionsRuntimeStateQuery(15)).To(ContainSubstring("pg_stat_subscription_stats"))
+		Expect(getSubscriptionsRuntimeStateQuery(16)).To(ContainSubstring("w.leader_pid IS NULL"))
+		Expect(getSubscriptionsRuntimeStateQuery(17)).To(ContainSubstring("w.worker_type = 'apply'"))
+	})
+})
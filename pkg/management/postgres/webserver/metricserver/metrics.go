@@ -40,6 +40,10 @@ type MetricsServer struct {
 	// exporter is the exporter for predefined queries and for
 	// custom ones
 	exporter *Exporter
+
+	// registry is the registry containing all the metrics
+	// exposed by this server
+	registry *prometheus.Registry
 }
 
 // New configure the web statusServer for a certain PostgreSQL instance, and
@@ -74,7 +78,14 @@ func New(serverInstance *postgres.Instance, exporter *Exporter) (*MetricsServer,
 	metricServer := &MetricsServer{
 		Webserver: webserver.NewWebServer(server),
 		exporter:  exporter,
+		registry:  registry,
 	}
 
 	return metricServer, nil
 }
+
+// GetGatherer returns the gatherer of the metrics exposed by this server,
+// which can be used to push them elsewhere
+func (ms *MetricsServer) GetGatherer() prometheus.Gatherer {
+	return ms.registry
+}
@@ -58,6 +58,13 @@ type Exporter struct {
 
 	// pluginCollector is used to collect metrics from plugins
 	pluginCollector m.PluginCollector
+
+	// statements exports the statistics of pg_stat_statements
+	statements *m.StatementsCollector
+
+	// queryResultsCache keeps the results of the custom queries
+	// across the reloads of their definitions
+	queryResultsCache *m.QueryResultsCache
 }
 
 // metrics here are related to the exporter itself, which is instrumented to
@@ -80,6 +87,24 @@ type metrics struct {
 	FencingOn                    prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
 	NodesUsed                    prometheus.Gauge
+	SubscriptionMetrics          SubscriptionMetrics
+	TemporaryTablespaceMetrics   TemporaryTablespaceMetrics
+}
+
+// TemporaryTablespaceMetrics describes the temporary files stored
+// in the temporary tablespaces of the cluster
+type TemporaryTablespaceMetrics struct {
+	Files *prometheus.GaugeVec
+	Bytes *prometheus.GaugeVec
+}
+
+// SubscriptionMetrics describes the state of the logical replication
+// subscriptions, and is only collected on the primary
+type SubscriptionMetrics struct {
+	WorkerActive    *prometheus.GaugeVec
+	ApplyLag        *prometheus.GaugeVec
+	ApplyErrorCount *prometheus.GaugeVec
+	SyncErrorCount  *prometheus.GaugeVec
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -102,6 +127,9 @@ func NewExporter(instance *postgres.Instance, pluginCollector m.PluginCollector)
 		Metrics:         newMetrics(),
 		getCluster:      clusterGetter,
 		pluginCollector: pluginCollector,
+		statements:      m.NewStatementsCollector(PrometheusNamespace),
+
+		queryResultsCache: m.NewQueryResultsCache(),
 	}
 }
 
@@ -271,6 +299,47 @@ func newMetrics() *metrics {
 					"fsync_writethrough, otherwise zero). Only available on PG 14 to 17.",
 			}, []string{"stats_reset"}),
 		},
+		SubscriptionMetrics: SubscriptionMetrics{
+			WorkerActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "subscription_worker_active",
+				Help:      "1 if the apply worker of the subscription is running, 0 otherwise",
+			}, []string{"datname", "subname"}),
+			ApplyLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "subscription_apply_lag_seconds",
+				Help: "Time elapsed since the apply worker of the subscription reported the last " +
+					"WAL location to the publisher. Only available while the apply worker is running",
+			}, []string{"datname", "subname"}),
+			ApplyErrorCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "subscription_apply_error_count",
+				Help:      "Number of errors raised while applying changes. Only available on PG 15+",
+			}, []string{"datname", "subname"}),
+			SyncErrorCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "subscription_sync_error_count",
+				Help:      "Number of errors raised during the initial table synchronization. Only available on PG 15+",
+			}, []string{"datname", "subname"}),
+		},
+		TemporaryTablespaceMetrics: TemporaryTablespaceMetrics{
+			Files: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "temporary_tablespace_files",
+				Help:      "Number of temporary files stored in the temporary tablespace",
+			}, []string{"tablespace"}),
+			Bytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "temporary_tablespace_bytes",
+				Help:      "Size in bytes of the temporary files stored in the temporary tablespace",
+			}, []string{"tablespace"}),
+		},
 	}
 }
 
@@ -292,10 +361,17 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.SubscriptionMetrics.WorkerActive.Describe(ch)
+	e.Metrics.SubscriptionMetrics.ApplyLag.Describe(ch)
+	e.Metrics.SubscriptionMetrics.ApplyErrorCount.Describe(ch)
+	e.Metrics.SubscriptionMetrics.SyncErrorCount.Describe(ch)
+	e.Metrics.TemporaryTablespaceMetrics.Files.Describe(ch)
+	e.Metrics.TemporaryTablespaceMetrics.Bytes.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
 	}
+	e.statements.Describe(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalRecords.Describe(ch)
@@ -336,6 +412,12 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.SubscriptionMetrics.WorkerActive.Collect(ch)
+	e.Metrics.SubscriptionMetrics.ApplyLag.Collect(ch)
+	e.Metrics.SubscriptionMetrics.ApplyErrorCount.Collect(ch)
+	e.Metrics.SubscriptionMetrics.SyncErrorCount.Collect(ch)
+	e.Metrics.TemporaryTablespaceMetrics.Files.Collect(ch)
+	e.Metrics.TemporaryTablespaceMetrics.Bytes.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalRecords.Collect(ch)
@@ -407,6 +489,17 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.CollectionDuration.WithLabelValues(label).Set(time.Since(collectionStart).Seconds())
 	}
 
+	if e.statements.IsEnabled() {
+		label := "Collect." + e.statements.Name()
+		collectionStart := time.Now()
+		if err := e.statements.Collect(db, ch); err != nil {
+			log.Error(err, "Error during collection", "collector", e.statements.Name())
+			e.Metrics.PgCollectionErrors.WithLabelValues(label).Inc()
+			e.Metrics.Error.Set(1)
+		}
+		e.Metrics.CollectionDuration.WithLabelValues(label).Set(time.Since(collectionStart).Seconds())
+	}
+
 	isPrimary, err := e.instance.IsPrimary()
 	if err != nil {
 		log.Error(err, "unable to get if primary")
@@ -428,6 +521,22 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.collectFromPrimaryLastFailedBackupTimestamp()
 	}
 
+	e.Metrics.SubscriptionMetrics.reset()
+	if isPrimary {
+		if err := collectSubscriptionMetrics(e, db); err != nil {
+			log.Error(err, "while collecting subscription metrics")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.Subscriptions").Inc()
+		}
+	}
+
+	e.Metrics.TemporaryTablespaceMetrics.reset()
+	if err := collectTemporaryTablespaceMetrics(e, db); err != nil {
+		log.Error(err, "while collecting temporary tablespace metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.TemporaryTablespaces").Inc()
+	}
+
 	if err := collectPGWalArchiveMetric(e); err != nil {
 		log.Error(err, "while collecting WAL archive metrics", "path", specs.PgWalArchiveStatusPath)
 		e.Metrics.Error.Set(1)
@@ -602,11 +711,22 @@ type PgCollector interface {
 	Describe(ch chan<- *prometheus.Desc)
 }
 
-// SetCustomQueries sets the custom queries from the passed content
+// SetCustomQueries sets the custom queries from the passed content. The
+// cached results of the previous queries are kept, and they are served
+// if the definition of the corresponding query has not changed
 func (e *Exporter) SetCustomQueries(queries *m.QueriesCollector) {
+	if queries != nil {
+		queries.SetResultsCache(e.queryResultsCache)
+	}
 	e.queries = queries
 }
 
+// SetStatementsConfiguration configures the exporter of the pg_stat_statements
+// statistics, which is disabled when the passed configuration is nil
+func (e *Exporter) SetStatementsConfiguration(config *apiv1.PgStatStatementsConfiguration) {
+	e.statements.Configure(config)
+}
+
 // DefaultQueries is the set of default queries for postgresql
 var DefaultQueries = m.UserQueries{
 	"collector": m.UserQuery{
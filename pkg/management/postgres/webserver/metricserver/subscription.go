@@ -0,0 +1,67 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// reset removes the metrics of the subscriptions that don't
+// exist anymore
+func (m SubscriptionMetrics) reset() {
+	m.WorkerActive.Reset()
+	m.ApplyLag.Reset()
+	m.ApplyErrorCount.Reset()
+	m.SyncErrorCount.Reset()
+}
+
+func collectSubscriptionMetrics(e *Exporter, db *sql.DB) error {
+	version, err := e.instance.GetPgVersion()
+	if err != nil {
+		return err
+	}
+
+	states, err := postgres.GetSubscriptionsRuntimeState(context.Background(), db, int(version.Major)) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	subscriptionMetrics := e.Metrics.SubscriptionMetrics
+	for _, state := range states {
+		workerActive := 0.0
+		if state.WorkerActive {
+			workerActive = 1
+		}
+		subscriptionMetrics.WorkerActive.WithLabelValues(state.DatabaseName, state.Name).Set(workerActive)
+		if state.ApplyLag != nil {
+			subscriptionMetrics.ApplyLag.WithLabelValues(state.DatabaseName, state.Name).
+				Set(state.ApplyLag.Seconds())
+		}
+		subscriptionMetrics.ApplyErrorCount.WithLabelValues(state.DatabaseName, state.Name).
+			Set(float64(state.ApplyErrorCount))
+		subscriptionMetrics.SyncErrorCount.WithLabelValues(state.DatabaseName, state.Name).
+			Set(float64(state.SyncErrorCount))
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// temporaryTablespaceUsageSQL reports the temporary files stored in each
+// of the given tablespaces. pg_ls_tmpdir ignores a missing temporary
+// directory, which is created by PostgreSQL only when needed
+const temporaryTablespaceUsageSQL = `
+SELECT t.spcname, count(f.name), COALESCE(sum(f.size), 0)
+FROM pg_catalog.pg_tablespace t
+LEFT JOIN LATERAL pg_catalog.pg_ls_tmpdir(t.oid) f ON true
+WHERE t.spcname = ANY($1)
+GROUP BY t.spcname
+`
+
+// reset removes the metrics of the tablespaces that are not
+// temporary anymore
+func (m TemporaryTablespaceMetrics) reset() {
+	m.Files.Reset()
+	m.Bytes.Reset()
+}
+
+func collectTemporaryTablespaceMetrics(e *Exporter, db *sql.DB) error {
+	cluster, err := e.getCluster()
+	if err != nil {
+		return err
+	}
+
+	var tablespaces []string
+	for _, tbsConfig := range cluster.Spec.Tablespaces {
+		if tbsConfig.Temporary && tbsConfig.Ensure != apiv1.EnsureAbsent {
+			tablespaces = append(tablespaces, tbsConfig.Name)
+		}
+	}
+	if len(tablespaces) == 0 {
+		return nil
+	}
+
+	rows, err := db.Query(temporaryTablespaceUsageSQL, pq.Array(tablespaces))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var name string
+		var files, bytes float64
+		if err := rows.Scan(&name, &files, &bytes); err != nil {
+			return err
+		}
+		e.Metrics.TemporaryTablespaceMetrics.Files.WithLabelValues(name).Set(files)
+		e.Metrics.TemporaryTablespaceMetrics.Bytes.WithLabelValues(name).Set(bytes)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricserver
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("temporary tablespace metrics", func() {
+	var exporter *Exporter
+
+	BeforeEach(func() {
+		exporter = NewExporter(postgres.NewInstance(), fakePluginCollector{})
+		exporter.getCluster = func() (*apiv1.Cluster, error) {
+			return &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+				Spec: apiv1.ClusterSpec{
+					Tablespaces: []apiv1.TablespaceConfiguration{
+						{Name: "scratch", Temporary: true},
+						{Name: "retired", Temporary: true, Ensure: apiv1.EnsureAbsent},
+						{Name: "data"},
+					},
+				},
+			}, nil
+		}
+	})
+
+	It("reports the temporary files of the temporary tablespaces", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery("pg_ls_tmpdir").
+			WithArgs(pq.Array([]string{"scratch"})).
+			WillReturnRows(sqlmock.NewRows([]string{"spcname", "count", "sum"}).
+				AddRow("scratch", 3, 1048576))
+
+		Expect(collectTemporaryTablespaceMetrics(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.TemporaryTablespaceMetrics.Files)
+		registry.MustRegister(exporter.Metrics.TemporaryTablespaceMetrics.Bytes)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		files := getMetric(metrics, "cnpg_collector_temporary_tablespace_files")
+		Expect(files).ToNot(BeNil())
+		Expect(files.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(3))
+
+		bytes := getMetric(metrics, "cnpg_collector_temporary_tablespace_bytes")
+		Expect(bytes).ToNot(BeNil())
+		Expect(bytes.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(1048576))
+	})
+
+	It("doesn't query the database without temporary tablespaces", func() {
+		exporter.getCluster = func() (*apiv1.Cluster, error) {
+			return &apiv1.Cluster{}, nil
+		}
+
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(collectTemporaryTablespaceMetrics(exporter, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,75 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// logsPath is the path, relative to the configured endpoint,
+// receiving the OTLP logs
+const logsPath = "/v1/logs"
+
+// errExporterNotAvailable is returned when a logger provider is
+// requested from a nil Exporter
+var errExporterNotAvailable = errors.New("OpenTelemetry exporter not available")
+
+// NewLoggerProvider creates a logger provider pushing the log records in
+// batches to the endpoint of the passed configuration. The records are
+// described by the same resource attributes of the metrics and of the spans.
+// The caller is responsible for shutting down the returned provider
+func (e *Exporter) NewLoggerProvider(
+	ctx context.Context,
+	config *apiv1.OpenTelemetryLogSinkConfiguration,
+) (*sdklog.LoggerProvider, error) {
+	if e == nil {
+		return nil, errExporterNotAvailable
+	}
+
+	endpoint, err := parseEndpoint(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(endpoint.Host),
+		otlploghttp.WithURLPath(joinPath(endpoint, logsPath)),
+	}
+	if endpoint.Scheme == "http" {
+		options = append(options, otlploghttp.WithInsecure())
+	}
+
+	exporter, err := otlploghttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("while creating the OTLP logs exporter: %w", err)
+	}
+
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(e.buildResource(config.ResourceAttributes)),
+	), nil
+}
@@ -0,0 +1,308 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package telemetry contains the code needed to push the metrics and the
+// traces of the instance manager and of the PgBouncer manager to an
+// OpenTelemetry collector, using the OTLP/HTTP protocol
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	otelprometheus "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// metricsPath is the path, relative to the configured endpoint,
+	// receiving the OTLP metrics
+	metricsPath = "/v1/metrics"
+
+	// tracesPath is the path, relative to the configured endpoint,
+	// receiving the OTLP traces
+	tracesPath = "/v1/traces"
+
+	// shutdownTimeout is the maximum time we wait for the pending
+	// data to be flushed when the exporter is reconfigured or stopped
+	shutdownTimeout = 10 * time.Second
+
+	// instrumentationName is the name of the tracer used to create spans
+	instrumentationName = "github.com/cloudnative-pg/cloudnative-pg"
+)
+
+// Exporter pushes the metrics collected by a Prometheus gatherer and
+// the reconciliation traces to an OpenTelemetry collector. It can be
+// reconfigured at runtime, and it does nothing until a configuration
+// is applied. A nil Exporter is valid and never exports anything
+type Exporter struct {
+	serviceName string
+	gatherer    prometheus.Gatherer
+	attributes  map[string]string
+
+	mu             sync.RWMutex
+	config         *apiv1.OpenTelemetryConfiguration
+	meterProvider  *sdkmetric.MeterProvider
+	tracerProvider *sdktrace.TracerProvider
+}
+
+// NewExporter creates a new Exporter for the metrics of the passed gatherer.
+// The service name and the passed attributes are attached to every
+// exported metric and span
+func NewExporter(
+	serviceName string,
+	gatherer prometheus.Gatherer,
+	attributes map[string]string,
+) *Exporter {
+	return &Exporter{
+		serviceName: serviceName,
+		gatherer:    gatherer,
+		attributes:  attributes,
+	}
+}
+
+// Configure applies the passed configuration, flushing and stopping the
+// previous providers when needed. A nil configuration disables the exports.
+// Applying the configuration currently in use is a no-op. The previous
+// providers are stopped after being replaced, without holding the lock,
+// so that the spans can be started while the pending data is flushed
+func (e *Exporter) Configure(ctx context.Context, config *apiv1.OpenTelemetryConfiguration) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	if reflect.DeepEqual(config, e.config) {
+		e.mu.Unlock()
+		return nil
+	}
+
+	meterProvider, tracerProvider, err := e.newProviders(ctx, config)
+	previousMeterProvider, previousTracerProvider := e.meterProvider, e.tracerProvider
+	e.meterProvider, e.tracerProvider = meterProvider, tracerProvider
+	e.config = nil
+	if err == nil {
+		e.config = config.DeepCopy()
+	}
+	e.mu.Unlock()
+
+	return errors.Join(err, shutdownProviders(ctx, previousMeterProvider, previousTracerProvider))
+}
+
+// newProviders creates the providers required by the passed configuration,
+// which are nil when the corresponding signal is disabled
+func (e *Exporter) newProviders(
+	ctx context.Context,
+	config *apiv1.OpenTelemetryConfiguration,
+) (*sdkmetric.MeterProvider, *sdktrace.TracerProvider, error) {
+	if config == nil {
+		return nil, nil, nil
+	}
+
+	endpoint, err := parseEndpoint(config.Endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := e.buildResource(config.ResourceAttributes)
+
+	var meterProvider *sdkmetric.MeterProvider
+	if config.IsMetricsEnabled() {
+		if meterProvider, err = e.newMeterProvider(ctx, endpoint, config.GetExportInterval(), res); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var tracerProvider *sdktrace.TracerProvider
+	if config.IsTracesEnabled() {
+		if tracerProvider, err = newTracerProvider(ctx, endpoint, res); err != nil {
+			return nil, nil, errors.Join(err, shutdownProviders(ctx, meterProvider, nil))
+		}
+	}
+
+	return meterProvider, tracerProvider, nil
+}
+
+// StartSpan starts a new span, which will be exported if traces are
+// enabled. The returned span must be ended by the caller
+func (e *Exporter) StartSpan(
+	ctx context.Context,
+	name string,
+	attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	var provider trace.TracerProvider = noop.NewTracerProvider()
+	if e != nil {
+		e.mu.RLock()
+		if e.tracerProvider != nil {
+			provider = e.tracerProvider
+		}
+		e.mu.RUnlock()
+	}
+
+	return provider.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// Shutdown flushes the pending data and stops the exports
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	meterProvider, tracerProvider := e.meterProvider, e.tracerProvider
+	e.meterProvider, e.tracerProvider = nil, nil
+	e.config = nil
+	e.mu.Unlock()
+
+	return shutdownProviders(ctx, meterProvider, tracerProvider)
+}
+
+// shutdownProviders flushes and stops the passed providers, which can be nil
+func shutdownProviders(
+	ctx context.Context,
+	meterProvider *sdkmetric.MeterProvider,
+	tracerProvider *sdktrace.TracerProvider,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if meterProvider != nil {
+		errs = append(errs, meterProvider.Shutdown(ctx))
+	}
+	if tracerProvider != nil {
+		errs = append(errs, tracerProvider.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+// buildResource creates the OpenTelemetry resource describing this
+// process. User defined attributes cannot override the ones set by
+// the operator
+func (e *Exporter) buildResource(userAttributes map[string]string) *resource.Resource {
+	attributes := make([]attribute.KeyValue, 0, len(userAttributes)+len(e.attributes)+1)
+	for key, value := range userAttributes {
+		if _, ok := e.attributes[key]; ok {
+			continue
+		}
+		attributes = append(attributes, attribute.String(key, value))
+	}
+	for key, value := range e.attributes {
+		attributes = append(attributes, attribute.String(key, value))
+	}
+	attributes = append(attributes, attribute.String("service.name", e.serviceName))
+
+	return resource.NewSchemaless(attributes...)
+}
+
+// newMeterProvider creates a meter provider periodically pushing the
+// metrics of the gatherer to the passed endpoint
+func (e *Exporter) newMeterProvider(
+	ctx context.Context,
+	endpoint *url.URL,
+	interval time.Duration,
+	res *resource.Resource,
+) (*sdkmetric.MeterProvider, error) {
+	options := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint.Host),
+		otlpmetrichttp.WithURLPath(joinPath(endpoint, metricsPath)),
+	}
+	if endpoint.Scheme == "http" {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("while creating the OTLP metrics exporter: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(
+		exporter,
+		sdkmetric.WithInterval(interval),
+		sdkmetric.WithProducer(otelprometheus.NewMetricProducer(otelprometheus.WithGatherer(e.gatherer))),
+	)
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(res),
+	), nil
+}
+
+// newTracerProvider creates a tracer provider pushing the spans in
+// batches to the passed endpoint
+func newTracerProvider(
+	ctx context.Context,
+	endpoint *url.URL,
+	res *resource.Resource,
+) (*sdktrace.TracerProvider, error) {
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint.Host),
+		otlptracehttp.WithURLPath(joinPath(endpoint, tracesPath)),
+	}
+	if endpoint.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("while creating the OTLP traces exporter: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// parseEndpoint parses the URL of an OTLP/HTTP endpoint
+func parseEndpoint(rawURL string) (*url.URL, error) {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the OpenTelemetry endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme for the OpenTelemetry endpoint: %q", endpoint.Scheme)
+	}
+
+	return endpoint, nil
+}
+
+// joinPath appends the signal specific path to the path of the endpoint,
+// as OpenTelemetry SDKs do with OTEL_EXPORTER_OTLP_ENDPOINT
+func joinPath(endpoint *url.URL, signalPath string) string {
+	return strings.TrimSuffix(endpoint.Path, "/") + signalPath
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © contributors to CloudNativePG, established as
+CloudNativePG a Series of LF Projects, LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	otellog "go.opentelemetry.io/otel/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeCollector records the paths of the OTLP requests it receives,
+// and can be blocked to simulate a slow collector
+type fakeCollector struct {
+	mu      sync.Mutex
+	paths   []string
+	blocked chan struct{}
+}
+
+func (f *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.paths = append(f.paths, r.URL.Path)
+	blocked := f.blocked
+	f.mu.Unlock()
+
+	if blocked != nil {
+		<-blocked
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeCollector) block() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blocked = make(chan struct{})
+}
+
+func (f *fakeCollector) unblock() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	close(f.blocked)
+	f.blocked = nil
+}
+
+func (f *fakeCollector) receivedPaths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.paths...)
+}
+
+var _ = Describe("OpenTelemetry exporter", func() {
+	var (
+		collector *fakeCollector
+		server    *httptest.Server
+		exporter  *Exporter
+	)
+
+	BeforeEach(func() {
+		collector = &fakeCollector{}
+		server = httptest.NewServer(collector)
+		DeferCleanup(server.Close)
+
+		registry := prometheus.NewRegistry()
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "cnpg_test_gauge"})
+		gauge.Set(42)
+		registry.MustRegister(gauge)
+
+		exporter = NewExporter("cnpg-instance", registry, map[string]string{
+			"k8s.pod.name": "cluster-example-1",
+		})
+	})
+
+	It("pushes metrics and traces to the configured endpoint", func(ctx SpecContext) {
+		err := exporter.Configure(ctx, &apiv1.OpenTelemetryConfiguration{
+			Endpoint:       server.URL + "/otlp/",
+			ExportInterval: &metav1.Duration{Duration: 50 * time.Millisecond},
+			Metrics:        ptr.To(true),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, span := exporter.StartSpan(ctx, "reconcile")
+		span.End()
+
+		Eventually(collector.receivedPaths).Should(ContainElement("/otlp/v1/metrics"))
+
+		Expect(exporter.Shutdown(ctx)).To(Succeed())
+		Expect(collector.receivedPaths()).To(ContainElement("/otlp/v1/traces"))
+	})
+
+	It("only exports the enabled signals", func(ctx SpecContext) {
+		err := exporter.Configure(ctx, &apiv1.OpenTelemetryConfiguration{
+			Endpoint: server.URL,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exporter.meterProvider).To(BeNil())
+		Expect(exporter.tracerProvider).ToNot(BeNil())
+
+		_, span := exporter.StartSpan(ctx, "reconcile")
+		span.End()
+
+		Expect(exporter.Shutdown(ctx)).To(Succeed())
+		Expect(collector.receivedPaths()).To(ConsistOf("/v1/traces"))
+	})
+
+	It("exports the metrics only when they are enabled", func(ctx SpecContext) {
+		err := exporter.Configure(ctx, &apiv1.OpenTelemetryConfiguration{
+			Endpoint: server.URL,
+			Metrics:  ptr.To(true),
+			Traces:   ptr.To(false),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exporter.meterProvider).ToNot(BeNil())
+		Expect(exporter.tracerProvider).To(BeNil())
+		Expect(exporter.Shutdown(ctx)).To(Succeed())
+	})
+
+	It("replaces the providers before stopping the previous ones", func(ctx SpecContext) {
+		Expect(exporter.Configure(ctx, &apiv1.OpenTelemetryConfiguration{Endpoint: server.URL})).To(Succeed())
+		previousTracerProvider := exporter.tracerProvider
+
+		// The previous provider is flushed while the new one is being used
+		_, span := exporter.StartSpan(ctx, "before")
+		span.End()
+		collector.block()
+		done := make(chan error)
+		go func() {
+			done <- exporter.Configure(ctx, &apiv1.OpenTelemetryConfiguration{
+				Endpoint:           server.URL,
+				ResourceAttributes: map[string]string{"environment": "test"},
+			})
+		}()
+		Eventually(collector.receivedPaths).Should(ContainElement("/v1/traces"))
+
+		Eventually(func() bool {
+			exporter.mu.RLock()
+			defer exporter.mu.RUnlock()
+			return exporter.tracerProvider != previousTracerProvider
+		}).Should(BeTrue())
+		_, span = exporter.StartSpan(ctx, "reconcile")
+		Expect(span.IsRecording()).To(BeTrue())
+		span.End()
+
+		collector.unblock()
+		Expect(<-done).To(Succeed())
+		Expect(exporter.Shutdown(ctx)).To(Succeed())
+	})
+
+	It("keeps the providers when the configuration doesn't change", func(ctx SpecContext) {
+		config := &apiv1.OpenTelemetryConfiguration{Endpoint: server.URL, Metrics: ptr.To(true)}
+		Expect(exporter.Configure(ctx, config)).To(Succeed())
+		meterProvider, tracerProvider := exporter.meterProvider, exporter.tracerProvider
+
+		Expect(exporter.Configure(ctx, config.DeepCopy())).To(Succeed())
+		Expect(exporter.meterProvider).To(BeIdenticalTo(meterProvider))
+		Expect(exporter.tracerProvider).To(BeIdenticalTo(tracerProvider))
+
+		Expect(exporter.Configure(ctx, nil)).To(Succeed())
+		Expect(exporter.meterProvider).To(BeNil())
+		Expect(exporter.tracerProvider).To(BeNil())
+	})
+
+	It("rejects endpoints without an HTTP scheme", func(ctx SpecContext) {
+		err := exporter.Configure(ctx, &apiv1.OpenTelemetryConfiguration{Endpoint: "otel-collector:4318"})
+		Expect(err).To(HaveOccurred())
+		Expect(exporter.config).To(BeNil())
+	})
+
+	It("gives the operator attributes precedence over the user defined ones", func() {
+		res := exporter.buildResource(map[string]string{
+			"k8s.pod.name": "another-pod",
+			"environment":  "test",
+		})
+
+		value, ok := res.Set().Value("k8s.pod.name")
+		Expect(ok).To(BeTrue())
+		Expect(value.AsString()).To(Equal("cluster-example-1"))
+		value, ok = res.Set().Value("environment")
+		Expect(ok).To(BeTrue())
+		Expect(value.AsString()).To(Equal("test"))
+		value, ok = res.Set().Value("service.name")
+		Expect(ok).To(BeTrue())
+		Expect(value.AsString()).To(Equal("cnpg-instance"))
+	})
+
+	It("creates logger providers pushing to the configured endpoint", func(ctx SpecContext) {
+		provider, err := exporter.NewLoggerProvider(ctx, &apiv1.OpenTelemetryLogSinkConfiguration{
+			Endpoint: server.URL + "/otlp",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var record otellog.Record
+		record.SetBody(otellog.StringValue("test"))
+		provider.Logger("test").Emit(ctx, record)
+
+		Expect(provider.Shutdown(ctx)).To(Succeed())
+		Expect(collector.receivedPaths()).To(ConsistOf("/otlp/v1/logs"))
+	})
+
+	It("refuses to create logger providers from a nil exporter", func(ctx SpecContext) {
+		var nilExporter *Exporter
+		_, err := nilExporter.NewLoggerProvider(ctx, &apiv1.OpenTelemetryLogSinkConfiguration{
+			Endpoint: server.URL,
+		})
+		Expect(err).To(MatchError(errExporterNotAvailable))
+	})
+
+	It("creates non-recording spans when not configured", func(ctx SpecContext) {
+		var nilExporter *Exporter
+		_, span := nilExporter.StartSpan(ctx, "reconcile")
+		Expect(span.IsRecording()).To(BeFalse())
+		span.End()
+	})
+})
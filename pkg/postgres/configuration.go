@@ -105,7 +105,18 @@ local all all peer map=local
 hostssl postgres streaming_replica all cert map=cnpg_streaming_replica
 hostssl replication streaming_replica all cert map=cnpg_streaming_replica
 hostssl all cnpg_pooler_pgbouncer all cert map=cnpg_pooler_pgbouncer
-
+{{ if .ClientConnectionsFenced }}
+# Reject the connections of the client applications, as they are fenced
+host all postgres all {{.DefaultAuthenticationMethod}}
+host all all all reject
+{{ end }}
+{{- if .CertificateRoles }}
+# Require client certificate authentication for the managed roles
+# having a client certificate issued by the operator
+{{- range $role := .CertificateRoles }}
+hostssl all "{{ $role }}" all cert{{ if index $.PoolerCertificateRoles $role }} map=cnpg_pooler_certificate{{ end }}
+{{- end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -142,7 +153,23 @@ cnpg_streaming_replica streaming_replica streaming_replica
 
 # Grant cnpg_pooler_pgbouncer access ('cnpg_pooler_pgbouncer' user map)
 cnpg_pooler_pgbouncer cnpg_pooler_pgbouncer cnpg_pooler_pgbouncer
-
+{{ if .PoolerCertificateMappings }}
+# Grant access to the managed roles having a client certificate issued
+# by the operator, directly and through the poolers authenticating their
+# clients with certificates ('cnpg_pooler_certificate' user map).
+# Every pooler has a dedicated client certificate, which is also used
+# by its auth query user, and can only connect as the roles its
+# clients are mapped to
+{{- range $role := .PoolerCertificateRoles }}
+cnpg_pooler_certificate "{{ $role }}" "{{ $role }}"
+{{- end }}
+{{- range $mapping := .PoolerCertificateMappings }}
+cnpg_pooler_pgbouncer "{{ $mapping.CommonName }}" cnpg_pooler_pgbouncer
+{{- range $role := $mapping.Roles }}
+cnpg_pooler_certificate "{{ $mapping.CommonName }}" "{{ $role }}"
+{{- end }}
+{{- end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -248,6 +275,10 @@ cnpg_pooler_pgbouncer cnpg_pooler_pgbouncer cnpg_pooler_pgbouncer
 	// `.csv` and `.log` as needed.
 	LogFileName = "postgres"
 
+	// LogSinksPath is the path of the folder where the file log sinks
+	// write the log records
+	LogSinksPath = "/var/log/postgresql"
+
 	// CNPGConfigSha256 is the parameter to be used to inject the sha256 of the
 	// config in the custom.conf file
 	CNPGConfigSha256 = "cnpg.config_sha256"
@@ -585,23 +616,51 @@ var (
 	}
 )
 
+// PoolerCertificateMapping allows a pooler, identified by the common
+// name of its client certificate, to connect as a set of roles on
+// behalf of its clients
+type PoolerCertificateMapping struct {
+	// The common name of the client certificate of the pooler
+	CommonName string
+
+	// The roles the pooler can connect as
+	Roles []string
+}
+
 // CreateHBARules will create the content of pg_hba.conf file given
-// the rules set by the cluster spec
+// the rules set by the cluster spec and the roles required to
+// authenticate with a client certificate. The certificates of the roles
+// the poolers can connect as, according to poolerCertificateMappings, are
+// checked through the 'cnpg_pooler_certificate' user map. When
+// clientConnectionsFenced is set, only the superuser and the replicas
+// are allowed to connect
 func CreateHBARules(
 	hba []string,
+	certificateRoles []string,
+	poolerCertificateMappings []PoolerCertificateMapping,
+	clientConnectionsFenced bool,
 	defaultAuthenticationMethod, ldapConfigString string,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
 		UserRules                   []string
+		CertificateRoles            []string
+		PoolerCertificateRoles      map[string]bool
+		ClientConnectionsFenced     bool
 		LDAPConfiguration           string
 		DefaultAuthenticationMethod string
 	}{
 		UserRules:                   hba,
+		CertificateRoles:            certificateRoles,
+		PoolerCertificateRoles:      make(map[string]bool),
+		ClientConnectionsFenced:     clientConnectionsFenced,
 		LDAPConfiguration:           ldapConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
 	}
+	for _, role := range getPoolerCertificateRoles(poolerCertificateMappings) {
+		templateData.PoolerCertificateRoles[role] = true
+	}
 
 	if err := hbaTemplate.Execute(&hbaContent, templateData); err != nil {
 		return "", err
@@ -611,16 +670,25 @@ func CreateHBARules(
 }
 
 // CreateIdentRules will create the content of pg_ident.conf file given
-// the rules set by the cluster spec
-func CreateIdentRules(ident []string, username string) (string, error) {
+// the rules set by the cluster spec and the roles the poolers can
+// connect as with their client certificate
+func CreateIdentRules(
+	ident []string,
+	username string,
+	poolerCertificateMappings []PoolerCertificateMapping,
+) (string, error) {
 	var identContent bytes.Buffer
 
 	templateData := struct {
-		Mappings []string
-		Username string
+		Mappings                  []string
+		Username                  string
+		PoolerCertificateRoles    []string
+		PoolerCertificateMappings []PoolerCertificateMapping
 	}{
-		Mappings: ident,
-		Username: username,
+		Mappings:                  ident,
+		Username:                  username,
+		PoolerCertificateRoles:    getPoolerCertificateRoles(poolerCertificateMappings),
+		PoolerCertificateMappings: poolerCertificateMappings,
 	}
 
 	if err := identTemplate.Execute(&identContent, templateData); err != nil {
@@ -630,6 +698,17 @@ func CreateIdentRules(ident []string, username string) (string, error) {
 	return identContent.String(), nil
 }
 
+// getPoolerCertificateRoles returns the sorted names of the
+// roles at least one pooler can connect as
+func getPoolerCertificateRoles(poolerCertificateMappings []PoolerCertificateMapping) []string {
+	var roles []string
+	for _, mapping := range poolerCertificateMappings {
+		roles = append(roles, mapping.Roles...)
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
 // PgConfiguration wraps configuration parameters with some checks
 type PgConfiguration struct {
 	configs map[string]string
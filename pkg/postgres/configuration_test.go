@@ -238,19 +238,51 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, nil, nil, false, "md5", "")).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, nil, nil, false, "this-one", "")).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, nil, nil, false, "defaultAuthenticationMethod", "ldapConfigString")).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("requires client certificate authentication for the passed roles", func() {
+		hba, err := CreateHBARules(specRules, []string{"dante", "petrarca"}, nil, false, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring(
+			"\nhostssl all \"dante\" all cert\nhostssl all \"petrarca\" all cert\n"))
+		Expect(strings.Index(hba, "\"dante\"")).To(BeNumerically("<", strings.Index(hba, "\ntwo\n")))
+	})
+
+	It("lets the poolers connect as the roles their clients are mapped to", func() {
+		hba, err := CreateHBARules(
+			specRules,
+			[]string{"dante", "petrarca"},
+			[]PoolerCertificateMapping{{CommonName: "cnpg_pooler_certificate_pooler", Roles: []string{"dante"}}},
+			false, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring("\nhostssl all \"dante\" all cert map=cnpg_pooler_certificate\n"))
+		Expect(hba).To(ContainSubstring("\nhostssl all \"petrarca\" all cert\n"))
+	})
+
+	It("rejects every client except the superuser when the client connections are fenced", func() {
+		hba, err := CreateHBARules(specRules, []string{"dante"}, nil, true, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring("\nhost all postgres all md5\nhost all all all reject\n"))
+		Expect(strings.Index(hba, "reject")).To(BeNumerically("<", strings.Index(hba, "\"dante\"")))
+		Expect(strings.Index(hba, "reject")).To(BeNumerically(">", strings.Index(hba, "streaming_replica")))
+	})
+
+	It("doesn't add client certificate rules when not needed", func() {
+		Expect(CreateHBARules(specRules, nil, nil, false, "md5", "")).ToNot(
+			ContainSubstring("having a client certificate"))
+	})
 })
 
 var _ = Describe("pg_ident.conf generation", func() {
@@ -259,15 +291,36 @@ var _ = Describe("pg_ident.conf generation", func() {
 	}
 
 	It("contains the default map when no mappings are added", func() {
-		Expect(CreateIdentRules(make([]string, 0), "someone")).To(
+		Expect(CreateIdentRules(make([]string, 0), "someone", nil)).To(
 			ContainSubstring("\nlocal someone postgres\n"))
 	})
 
 	It("contains the default map and additional mappings when added", func() {
-		rules, _ := CreateIdentRules(specRules, "someone")
+		rules, _ := CreateIdentRules(specRules, "someone", nil)
 		Expect(rules).To(ContainSubstring("\nlocal someone postgres\n"))
 		Expect(rules).To(ContainSubstring("\ntest someone else\n"))
 	})
+
+	It("maps the certificate of every pooler to the roles its clients are mapped to", func() {
+		rules, err := CreateIdentRules(nil, "someone", []PoolerCertificateMapping{
+			{CommonName: "cnpg_pooler_certificate_one", Roles: []string{"dante", "petrarca"}},
+			{CommonName: "cnpg_pooler_certificate_two", Roles: []string{"dante"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(ContainSubstring(
+			"\ncnpg_pooler_certificate \"dante\" \"dante\"\ncnpg_pooler_certificate \"petrarca\" \"petrarca\"\n"))
+		Expect(rules).To(ContainSubstring(
+			"\ncnpg_pooler_pgbouncer \"cnpg_pooler_certificate_one\" cnpg_pooler_pgbouncer\n" +
+				"cnpg_pooler_certificate \"cnpg_pooler_certificate_one\" \"dante\"\n" +
+				"cnpg_pooler_certificate \"cnpg_pooler_certificate_one\" \"petrarca\"\n" +
+				"cnpg_pooler_pgbouncer \"cnpg_pooler_certificate_two\" cnpg_pooler_pgbouncer\n" +
+				"cnpg_pooler_certificate \"cnpg_pooler_certificate_two\" \"dante\"\n"))
+		Expect(rules).ToNot(ContainSubstring("cnpg_pooler_certificate cnpg_pooler_pgbouncer"))
+	})
+
+	It("doesn't add the pooler certificate map when not needed", func() {
+		Expect(CreateIdentRules(nil, "someone", nil)).ToNot(ContainSubstring("cnpg_pooler_certificate"))
+	})
 })
 
 var _ = Describe("pgaudit", func() {
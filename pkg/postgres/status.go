@@ -54,6 +54,10 @@ type PostgresqlStatus struct {
 	// Hash of the current PostgreSQL configuration
 	LoadedConfigurationHash string `json:"loadedConfigurationHash,omitempty"`
 
+	// The write-ahead log location reached after the client connections
+	// have been fenced, empty when they are not fenced
+	ClientConnectionsFenceLSN types.LSN `json:"clientConnectionsFenceLSN,omitempty"`
+
 	// Archiver status
 	LastArchivedWAL     string `json:"lastArchivedWAL,omitempty"`
 	LastArchivedWALTime string `json:"lastArchivedWALTime,omitempty"`
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // EnsureInstancePVCGroupIsDeleted ensures that all the expected pvc for a given instance are deleted
@@ -64,3 +65,31 @@ func EnsureInstancePVCGroupIsDeleted(
 
 	return nil
 }
+
+// deleteRetiredTablespacePVCs deletes the PVCs of the tablespaces that have
+// been dropped from PostgreSQL. Kubernetes will defer their removal until
+// the Pods using them have been rolled out
+func deleteRetiredTablespacePVCs(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	pvcs []corev1.PersistentVolumeClaim,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		if pvc.DeletionTimestamp != nil || !isRetiredTablespacePVC(cluster, *pvc) {
+			continue
+		}
+
+		contextLogger.Info("Deleting PVC of retired tablespace",
+			"pvc", pvc.Name,
+			"tablespace", pvc.Labels[utils.TablespaceNameLabelName])
+		if err := c.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting PVC %s of a retired tablespace: %w", pvc.Name, err)
+		}
+	}
+
+	return nil
+}
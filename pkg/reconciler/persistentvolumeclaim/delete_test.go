@@ -31,6 +31,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -89,3 +90,57 @@ var _ = Describe("EnsureInstancePVCGroupIsDeleted", func() {
 		}
 	})
 })
+
+var _ = Describe("deleteRetiredTablespacePVCs", func() {
+	const namespace = "default"
+
+	newTablespacePVC := func(name, tablespaceName string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					utils.PvcRoleLabelName:        string(utils.PVCRolePgTablespace),
+					utils.TablespaceNameLabelName: tablespaceName,
+				},
+			},
+		}
+	}
+
+	It("deletes only the PVCs of the dropped tablespaces", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{
+					{Name: "fast"},
+					{Name: "archive", Ensure: apiv1.EnsureAbsent},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				TablespacesStatus: []apiv1.TablespaceState{
+					{Name: "fast", State: apiv1.TablespaceStatusReconciled},
+					{Name: "archive", State: apiv1.TablespaceStatusDropped},
+				},
+			},
+		}
+
+		pvcs := []corev1.PersistentVolumeClaim{
+			newTablespacePVC("test-cluster-1-tbs-fast", "fast"),
+			newTablespacePVC("test-cluster-1-tbs-archive", "archive"),
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(&pvcs[0], &pvcs[1]).
+			Build()
+
+		Expect(deleteRetiredTablespacePVCs(ctx, fakeClient, cluster, pvcs)).To(Succeed())
+
+		var pvc corev1.PersistentVolumeClaim
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: pvcs[0].Name, Namespace: namespace}, &pvc)).
+			To(Succeed())
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: pvcs[1].Name, Namespace: namespace}, &pvc)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -45,6 +45,10 @@ func Reconcile(
 		return res, err
 	}
 
+	if err := deleteRetiredTablespacePVCs(ctx, c, cluster, pvcs); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if err := reconcileExistingPVCs(ctx, c, cluster, pvcs); err != nil {
 		if apierrs.IsConflict(err) {
 			contextLogger.Debug("Conflict error while reconciling PVCs", "error", err)
@@ -145,7 +145,10 @@ func getExpectedPVCsFromCluster(cluster *apiv1.Cluster, instanceName string) []e
 	if cluster.ShouldCreateWalArchiveVolume() {
 		roles = append(roles, NewPgWalCalculator())
 	}
-	for _, tbsConfig := range cluster.Spec.Tablespaces {
+	for _, tbsConfig := range cluster.GetProvisionedTablespaces() {
+		if tbsConfig.IsEphemeral() {
+			continue
+		}
 		roles = append(roles, NewPgTablespaceCalculator(tbsConfig.Name))
 	}
 	return buildExpectedPVCs(instanceName, roles)
@@ -150,4 +150,16 @@ var _ = Describe("instance with tablespace test", func() {
 			Expect(pvc.name).Should(Equal(pvc.calculator.GetName(instanceName)))
 		}
 	})
+
+	It("doesn't expect a pvc for tablespaces hosted on ephemeral volumes", func() {
+		ephemeralCluster := cluster.DeepCopy()
+		ephemeralCluster.Spec.Tablespaces = append(ephemeralCluster.Spec.Tablespaces, apiv1.TablespaceConfiguration{
+			Name:      "scratch",
+			Temporary: true,
+			Ephemeral: &apiv1.TablespaceEphemeralStorage{},
+		})
+
+		expectedPVCs := getExpectedPVCsFromCluster(ephemeralCluster, instanceName)
+		Expect(expectedPVCs).Should(HaveLen(5))
+	})
 })
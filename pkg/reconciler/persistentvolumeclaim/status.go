@@ -162,7 +162,7 @@ func classifyPVC(
 	instanceName string,
 ) status {
 	// PVC to ignore
-	if pvc.DeletionTimestamp != nil || hasUnknownStatus(ctx, pvc) {
+	if pvc.DeletionTimestamp != nil || hasUnknownStatus(ctx, pvc) || isRetiredTablespacePVC(cluster, pvc) {
 		return ignored
 	}
 
@@ -193,6 +193,16 @@ func classifyPVC(
 	return dangling
 }
 
+// isRetiredTablespacePVC checks if the PVC belongs to a tablespace that
+// has been dropped and is going to be deleted
+func isRetiredTablespacePVC(cluster *apiv1.Cluster, pvc corev1.PersistentVolumeClaim) bool {
+	if pvc.Labels[utils.PvcRoleLabelName] != string(utils.PVCRolePgTablespace) {
+		return false
+	}
+
+	return cluster.IsTablespaceRetired(pvc.Labels[utils.TablespaceNameLabelName])
+}
+
 // hasJob checks if the PVC has a corresponding Job
 func hasJob(pvc corev1.PersistentVolumeClaim, jobList []batchv1.Job) bool {
 	// check if the PVC has a corresponding Job
@@ -76,3 +76,25 @@ func SetPGDataImageInfo(imageInfo *apiv1.ImageInfo) Transaction {
 		cluster.Status.PGDataImageInfo = imageInfo
 	}
 }
+
+// SetOnlineImport is a transaction that sets the status of the online import
+func SetOnlineImport(onlineImport *apiv1.OnlineImportStatus) Transaction {
+	return func(cluster *apiv1.Cluster) {
+		cluster.Status.OnlineImport = onlineImport
+	}
+}
+
+// SetLogicalImport is a transaction that sets the progress of the logical import
+func SetLogicalImport(logicalImport *apiv1.LogicalImportStatus) Transaction {
+	return func(cluster *apiv1.Cluster) {
+		cluster.Status.LogicalImport = logicalImport
+	}
+}
+
+// SetExtensionUpdates is a transaction that sets the status of the automatic
+// extension updates
+func SetExtensionUpdates(extensionUpdates *apiv1.ExtensionUpdatesStatus) Transaction {
+	return func(cluster *apiv1.Cluster) {
+		cluster.Status.ExtensionUpdates = extensionUpdates
+	}
+}
@@ -179,9 +179,22 @@ func computeTemplateHash(pooler *apiv1.Pooler, operatorImageName string) (string
 	})
 }
 
+// getDeploymentStrategy returns the strategy requested by the user or,
+// by default, a rolling update that never reduces the number of the
+// ready PgBouncer Pods. The old Pods close the client connections
+// once the running transactions complete.
 func getDeploymentStrategy(strategy *appsv1.DeploymentStrategy) appsv1.DeploymentStrategy {
 	if strategy != nil {
 		return *strategy.DeepCopy()
 	}
-	return appsv1.DeploymentStrategy{}
+
+	maxUnavailable := intstr.FromInt32(0)
+	maxSurge := intstr.FromInt32(1)
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		},
+	}
 }
@@ -38,6 +38,9 @@ func ServiceAccount(pooler *apiv1.Pooler) *corev1.ServiceAccount {
 // Role creates a role for a given pooler
 func Role(pooler *apiv1.Pooler) *v1.Role {
 	secretNames := []string{pooler.GetAuthQuerySecretName()}
+	if pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.CertificateAuthentication != nil {
+		secretNames = append(secretNames, pooler.GetCertificateAuthenticationSecretName())
+	}
 	if pooler.Status.Secrets != nil {
 		if pooler.Status.Secrets.ServerCA.Name != "" {
 			secretNames = append(secretNames, pooler.Status.Secrets.ServerCA.Name)
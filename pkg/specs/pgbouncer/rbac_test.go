@@ -67,6 +67,16 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 		})
 	})
 
+	Context("when the pooler authenticates its clients through certificates", func() {
+		It("allows reading the client certificate of the pooler", func() {
+			pooler.Spec.PgBouncer = &apiv1.PgBouncerSpec{
+				CertificateAuthentication: &apiv1.PgBouncerCertificateAuthentication{},
+			}
+			role := Role(pooler)
+			Expect(role.Rules[2].ResourceNames).To(ContainElement(pooler.GetCertificateAuthenticationSecretName()))
+		})
+	})
+
 	Context("when creating a RoleBinding", func() {
 		It("returns the correct RoleBinding", func() {
 			roleBinding := RoleBinding(pooler)
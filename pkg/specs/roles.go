@@ -29,8 +29,23 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
-// CreateRole create a role with the permissions needed by the instance manager
-func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
+// DeclarativeReferences are the secrets and the config maps referenced by
+// the Database and Role objects of a cluster, indexed by name. Their value
+// is the resource version of the ones allowing being referenced, and is
+// empty for the others
+type DeclarativeReferences struct {
+	SecretVersions    map[string]string
+	ConfigMapVersions map[string]string
+}
+
+// CreateRole create a role with the permissions needed by the instance manager.
+// The instance manager can only read the secrets and the config maps
+// referenced by the Database and Role objects which allow it
+func CreateRole(
+	cluster apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	references DeclarativeReferences,
+) rbacv1.Role {
 	rules := []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -43,7 +58,7 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"get",
 				"watch",
 			},
-			ResourceNames: getInvolvedConfigMapNames(cluster),
+			ResourceNames: getInvolvedConfigMapNames(cluster, references),
 		},
 		{
 			APIGroups: []string{
@@ -56,7 +71,7 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"get",
 				"watch",
 			},
-			ResourceNames: getInvolvedSecretNames(cluster, backupOrigin),
+			ResourceNames: getInvolvedSecretNames(cluster, backupOrigin, references),
 		},
 		{
 			APIGroups: []string{
@@ -213,6 +228,61 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"update",
 			},
 		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"roles",
+			},
+			Verbs: []string{
+				"get",
+				"update",
+				"list",
+				"watch",
+			},
+			ResourceNames: []string{},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"roles/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"scheduledsqls",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+			ResourceNames: []string{},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"scheduledsqls/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"postgresql.cnpg.io",
@@ -257,7 +327,11 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	}
 }
 
-func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
+func getInvolvedSecretNames(
+	cluster apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	references DeclarativeReferences,
+) []string {
 	involvedSecretNames := []string{
 		cluster.GetReplicationSecretName(),
 		cluster.GetClientCASecretName(),
@@ -277,11 +351,12 @@ func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) [
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(cluster)...)
+	involvedSecretNames = append(involvedSecretNames, getAllowedReferences(references.SecretVersions)...)
 
 	return cleanupResourceList(involvedSecretNames)
 }
 
-func getInvolvedConfigMapNames(cluster apiv1.Cluster) []string {
+func getInvolvedConfigMapNames(cluster apiv1.Cluster, references DeclarativeReferences) []string {
 	involvedConfigMapNames := []string{
 		cluster.Name,
 	}
@@ -294,6 +369,8 @@ func getInvolvedConfigMapNames(cluster apiv1.Cluster) []string {
 		}
 	}
 
+	involvedConfigMapNames = append(involvedConfigMapNames, getAllowedReferences(references.ConfigMapVersions)...)
+
 	return cleanupResourceList(involvedConfigMapNames)
 }
 
@@ -466,7 +543,57 @@ func managedRolesSecrets(cluster apiv1.Cluster) []string {
 		if secretName != "" {
 			secretNames = append(secretNames, secretName)
 		}
+		if secretName != "" && role.PasswordRotation != nil {
+			secretNames = append(secretNames, role.GetPreviousPasswordSecretName())
+		}
 	}
 
 	return secretNames
 }
+
+// GetDeclarativeReferences returns the secrets and the config maps
+// referenced by the passed Database and Role objects, with an empty
+// resource version
+func GetDeclarativeReferences(databases []apiv1.Database, roles []apiv1.Role) DeclarativeReferences {
+	result := DeclarativeReferences{
+		SecretVersions:    make(map[string]string),
+		ConfigMapVersions: make(map[string]string),
+	}
+	for _, database := range databases {
+		for _, mapping := range database.Spec.UserMappings {
+			if mapping.CredentialsSecret == nil || mapping.CredentialsSecret.Name == "" {
+				continue
+			}
+			result.SecretVersions[mapping.CredentialsSecret.Name] = ""
+		}
+		if database.Spec.Migrations != nil {
+			for _, ref := range database.Spec.Migrations.SecretRefs {
+				result.SecretVersions[ref.Name] = ""
+			}
+			for _, ref := range database.Spec.Migrations.ConfigMapRefs {
+				result.ConfigMapVersions[ref.Name] = ""
+			}
+		}
+	}
+	for _, role := range roles {
+		if role.Spec.DisablePassword || role.Spec.PasswordSecret == nil || role.Spec.PasswordSecret.Name == "" {
+			continue
+		}
+		result.SecretVersions[role.Spec.PasswordSecret.Name] = ""
+	}
+
+	return result
+}
+
+// getAllowedReferences returns the names of the referenced objects
+// allowing it, which have a resource version
+func getAllowedReferences(versions map[string]string) []string {
+	var result []string
+	for name, version := range versions {
+		if version != "" {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
@@ -20,6 +20,8 @@ SPDX-License-Identifier: Apache-2.0
 package specs
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -165,14 +167,14 @@ var _ = Describe("Roles", func() {
 	}
 
 	It("are created with the cluster name for pure k8s", func() {
-		serviceAccount := CreateRole(cluster, nil)
+		serviceAccount := CreateRole(cluster, nil, DeclarativeReferences{})
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules).To(HaveLen(15))
+		Expect(serviceAccount.Rules).To(HaveLen(19))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
-		serviceAccount := CreateRole(cluster, &backupOrigin)
+		serviceAccount := CreateRole(cluster, &backupOrigin, DeclarativeReferences{})
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
 		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf("thisTest", "testConfigMapKeySelector"))
@@ -281,7 +283,7 @@ var _ = Describe("Secrets", func() {
 	})
 
 	It("should contain default secrets only", func() {
-		Expect(getInvolvedSecretNames(cluster, nil)).To(Equal([]string{
+		Expect(getInvolvedSecretNames(cluster, nil, DeclarativeReferences{})).To(Equal([]string{
 			"thisTest-app",
 			"thisTest-ca",
 			"thisTest-replication",
@@ -291,7 +293,7 @@ var _ = Describe("Secrets", func() {
 	})
 
 	It("should created an ordered string list with the backup secrets", func() {
-		Expect(getInvolvedSecretNames(cluster, &backup)).To(Equal([]string{
+		Expect(getInvolvedSecretNames(cluster, &backup, DeclarativeReferences{})).To(Equal([]string{
 			"aws-status-secret-test",
 			"azure-storage-key-secret-test",
 			"google-application-secret-test",
@@ -302,6 +304,75 @@ var _ = Describe("Secrets", func() {
 			"thisTest-superuser",
 		}))
 	})
+
+	It("should contain the secrets of the user mappings of the databases allowing it", func() {
+		databases := []apiv1.Database{
+			{
+				Spec: apiv1.DatabaseSpec{
+					UserMappings: []apiv1.UserMappingSpec{
+						{
+							User:              "app",
+							Server:            "remote",
+							CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-credentials"},
+						},
+						{
+							User:              "PUBLIC",
+							Server:            "remote",
+							CredentialsSecret: &apiv1.LocalObjectReference{Name: "public-credentials"},
+						},
+					},
+				},
+			},
+		}
+		references := GetDeclarativeReferences(databases, nil)
+		Expect(references.SecretVersions).To(Equal(map[string]string{
+			"remote-credentials": "",
+			"public-credentials": "",
+		}))
+		references.SecretVersions["remote-credentials"] = "42"
+		Expect(getInvolvedSecretNames(cluster, nil, references)).To(Equal([]string{
+			"remote-credentials",
+			"thisTest-app",
+			"thisTest-ca",
+			"thisTest-replication",
+			"thisTest-server",
+			"thisTest-superuser",
+		}))
+	})
+
+	It("should contain the password secrets of the roles allowing it", func() {
+		roles := []apiv1.Role{
+			{
+				Spec: apiv1.RoleSpec{
+					RoleConfiguration: apiv1.RoleConfiguration{
+						Name:           "reader",
+						PasswordSecret: &apiv1.LocalObjectReference{Name: "reader-password"},
+					},
+				},
+			},
+			{
+				Spec: apiv1.RoleSpec{
+					RoleConfiguration: apiv1.RoleConfiguration{
+						Name:            "writer",
+						DisablePassword: true,
+					},
+				},
+			},
+		}
+		references := GetDeclarativeReferences(nil, roles)
+		Expect(references.SecretVersions).To(Equal(map[string]string{"reader-password": ""}))
+		Expect(getInvolvedSecretNames(cluster, nil, references)).ToNot(ContainElement("reader-password"))
+
+		references.SecretVersions["reader-password"] = "42"
+		Expect(getInvolvedSecretNames(cluster, nil, references)).To(Equal([]string{
+			"reader-password",
+			"thisTest-app",
+			"thisTest-ca",
+			"thisTest-replication",
+			"thisTest-server",
+			"thisTest-superuser",
+		}))
+	})
 })
 
 var _ = Describe("Managed Roles", func() {
@@ -338,6 +409,15 @@ var _ = Describe("Managed Roles", func() {
 						},
 						DisablePassword: true,
 					},
+					{
+						Name: "role5",
+						PasswordSecret: &apiv1.LocalObjectReference{
+							Name: "my_secret5",
+						},
+						PasswordRotation: &apiv1.PasswordRotationPolicy{
+							Interval: metav1.Duration{Duration: 720 * time.Hour},
+						},
+					},
 				},
 			},
 		},
@@ -345,8 +425,8 @@ var _ = Describe("Managed Roles", func() {
 
 	It("gets the list of secrets needed by the managed roles", func() {
 		Expect(managedRolesSecrets(cluster)).
-			To(ConsistOf("my_secret1", "my_secret3"))
-		serviceAccount := CreateRole(cluster, nil)
+			To(ConsistOf("my_secret1", "my_secret3", "my_secret5", "my_secret5-previous"))
+		serviceAccount := CreateRole(cluster, nil, DeclarativeReferences{})
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
 		var secretsPolicy v1.PolicyRule
@@ -358,3 +438,46 @@ var _ = Describe("Managed Roles", func() {
 		Expect(secretsPolicy.ResourceNames).To(ContainElements("my_secret1", "my_secret3"))
 	})
 })
+
+var _ = Describe("Database schema migrations", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "thisTest",
+			Namespace: "default",
+		},
+	}
+	databases := []apiv1.Database{
+		{
+			Spec: apiv1.DatabaseSpec{
+				Migrations: &apiv1.DatabaseMigrations{
+					SQLRefs: apiv1.SQLRefs{
+						SecretRefs: []apiv1.SecretKeySelector{
+							{LocalObjectReference: apiv1.LocalObjectReference{Name: "secret-migrations"}, Key: "V1__init.sql"},
+						},
+						ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+							{LocalObjectReference: apiv1.LocalObjectReference{Name: "cm-migrations"}, Key: "V2__index.sql"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	It("references the sources of the migrations", func() {
+		references := GetDeclarativeReferences(databases, nil)
+		Expect(references.SecretVersions).To(Equal(map[string]string{"secret-migrations": ""}))
+		Expect(references.ConfigMapVersions).To(Equal(map[string]string{"cm-migrations": ""}))
+	})
+
+	It("grants access only to the sources of the migrations allowing it", func() {
+		role := CreateRole(cluster, nil, DeclarativeReferences{
+			SecretVersions:    map[string]string{"secret-migrations": "", "allowed-secret": "42"},
+			ConfigMapVersions: map[string]string{"cm-migrations": "43"},
+		})
+		Expect(role.Rules[0].Resources).To(ConsistOf("configmaps"))
+		Expect(role.Rules[0].ResourceNames).To(ContainElement("cm-migrations"))
+		Expect(role.Rules[1].Resources).To(ConsistOf("secrets"))
+		Expect(role.Rules[1].ResourceNames).To(ContainElement("allowed-secret"))
+		Expect(role.Rules[1].ResourceNames).NotTo(ContainElement("secret-migrations"))
+	})
+})
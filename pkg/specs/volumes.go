@@ -126,12 +126,30 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 			})
 	}
 
+	if cluster.ShouldCreateLogSinksVolume() {
+		result = append(result,
+			corev1.Volume{
+				Name: "log-sinks",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						SizeLimit: cluster.GetLogSinksVolumeSizeLimit(),
+					},
+				},
+			})
+	}
+
 	// we should create volumeMounts in fixed sequence as podSpec will store it in annotation and
 	// later it will be  retrieved to do deepEquals
 	if cluster.ContainsTablespaces() {
 		// Try to get a fix order of name
 		tbsNames := getSortedTablespaceList(cluster)
 		for i := range tbsNames {
+			tbsConfig := cluster.GetTablespaceConfiguration(tbsNames[i])
+			if tbsConfig.IsEphemeral() {
+				result = append(result, createTablespaceEphemeralVolume(tbsConfig))
+				continue
+			}
+
 			result = append(result,
 				corev1.Volume{
 					Name: VolumeMountNameForTablespace(tbsNames[i]),
@@ -257,6 +275,15 @@ func CreatePostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if cluster.ShouldCreateLogSinksVolume() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "log-sinks",
+				MountPath: postgres.LogSinksPath,
+			},
+		)
+	}
+
 	if cluster.ShouldCreateProjectedVolume() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
@@ -287,9 +314,10 @@ func CreatePostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 
 func getSortedTablespaceList(cluster *apiv1.Cluster) []string {
 	// Try to get a fix order of name
-	tbsNames := make([]string, len(cluster.Spec.Tablespaces))
+	tablespaces := cluster.GetProvisionedTablespaces()
+	tbsNames := make([]string, len(tablespaces))
 	i := 0
-	for _, tbsConfig := range cluster.Spec.Tablespaces {
+	for _, tbsConfig := range tablespaces {
 		tbsNames[i] = tbsConfig.Name
 		i++
 	}
@@ -312,6 +340,23 @@ func createEphemeralVolume(cluster *apiv1.Cluster) corev1.Volume {
 	}
 }
 
+// createTablespaceEphemeralVolume creates the volume hosting a tablespace
+// whose data doesn't need to survive the Pod
+func createTablespaceEphemeralVolume(tbsConfig *apiv1.TablespaceConfiguration) corev1.Volume {
+	volumeSource := corev1.VolumeSource{}
+	if tbsConfig.Ephemeral.VolumeSource != nil {
+		volumeSource.Ephemeral = tbsConfig.Ephemeral.VolumeSource.DeepCopy()
+	} else {
+		volumeSource.EmptyDir = &corev1.EmptyDirVolumeSource{
+			SizeLimit: tbsConfig.Ephemeral.SizeLimit,
+		}
+	}
+	return corev1.Volume{
+		Name:         VolumeMountNameForTablespace(tbsConfig.Name),
+		VolumeSource: volumeSource,
+	}
+}
+
 func createProjectedVolume(cluster *apiv1.Cluster) corev1.Volume {
 	return corev1.Volume{
 		Name: "projected",
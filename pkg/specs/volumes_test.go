@@ -524,6 +524,113 @@ var _ = Describe("createEphemeralVolume", func() {
 	})
 })
 
+var _ = Describe("createTablespaceEphemeralVolume", func() {
+	It("should create an emptyDir volume with the requested size limit", func() {
+		quantity := resource.MustParse("2Gi")
+		volume := createTablespaceEphemeralVolume(&apiv1.TablespaceConfiguration{
+			Name:      "scratch",
+			Temporary: true,
+			Ephemeral: &apiv1.TablespaceEphemeralStorage{SizeLimit: &quantity},
+		})
+
+		Expect(volume.Name).To(Equal("scratch"))
+		Expect(volume.VolumeSource.Ephemeral).To(BeNil())
+		Expect(*volume.VolumeSource.EmptyDir.SizeLimit).To(Equal(quantity))
+	})
+
+	It("should create a generic ephemeral volume when a volume source is specified", func() {
+		const storageClass = "fast-local"
+		volume := createTablespaceEphemeralVolume(&apiv1.TablespaceConfiguration{
+			Name:      "scratch",
+			Temporary: true,
+			Ephemeral: &apiv1.TablespaceEphemeralStorage{
+				VolumeSource: &corev1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							StorageClassName: ptr.To(storageClass),
+						},
+					},
+				},
+			},
+		})
+
+		Expect(volume.Name).To(Equal("scratch"))
+		Expect(volume.VolumeSource.EmptyDir).To(BeNil())
+		Expect(*volume.VolumeSource.Ephemeral.VolumeClaimTemplate.Spec.StorageClassName).To(Equal(storageClass))
+	})
+})
+
+var _ = Describe("log sinks volume", func() {
+	It("should not be created without file sinks", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{
+							Name:   "remote",
+							Syslog: &apiv1.SyslogLogSinkConfiguration{Address: "syslog:514"},
+						},
+					},
+				},
+			},
+		}
+
+		Expect(createPostgresVolumes(&cluster, "pod-1")).NotTo(ContainElement(
+			HaveField("Name", "log-sinks")))
+		Expect(CreatePostgresVolumeMounts(cluster)).NotTo(ContainElement(
+			HaveField("Name", "log-sinks")))
+	})
+
+	It("should be limited to the space used by the file sinks by default", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{
+							Name: "audit",
+							File: &apiv1.FileLogSinkConfiguration{},
+						},
+					},
+				},
+			},
+		}
+
+		quantity := resource.MustParse("600Mi")
+		volumes := createPostgresVolumes(&cluster, "pod-1")
+		Expect(volumes).To(ContainElement(SatisfyAll(
+			HaveField("Name", "log-sinks"),
+			HaveField("VolumeSource.EmptyDir.SizeLimit.Value()", quantity.Value()),
+		)))
+	})
+
+	It("should be created with the requested size limit when a file sink is defined", func() {
+		quantity := resource.MustParse("5Gi")
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Logging: &apiv1.LoggingConfiguration{
+					Sinks: []apiv1.LogSinkConfiguration{
+						{
+							Name: "audit",
+							File: &apiv1.FileLogSinkConfiguration{},
+						},
+					},
+					VolumeSizeLimit: &quantity,
+				},
+			},
+		}
+
+		volumes := createPostgresVolumes(&cluster, "pod-1")
+		Expect(volumes).To(ContainElement(SatisfyAll(
+			HaveField("Name", "log-sinks"),
+			HaveField("VolumeSource.EmptyDir.SizeLimit", &quantity),
+		)))
+		Expect(CreatePostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      "log-sinks",
+			MountPath: postgres.LogSinksPath,
+		}))
+	})
+})
+
 var _ = Describe("ImageVolume Extensions", func() {
 	var cluster apiv1.Cluster
 
@@ -22,7 +22,14 @@ SPDX-License-Identifier: Apache-2.0
 
 package compatibility
 
+import "syscall"
+
 // SetCoredumpFilter for darwin compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// Sync commits the filesystem caches to disk
+func Sync() {
+	syscall.Sync()
+}
@@ -25,6 +25,7 @@ package compatibility
 
 import (
 	"os"
+	"syscall"
 )
 
 // SetCoredumpFilter set the value of /proc/self/coredump_filter
@@ -32,3 +33,8 @@ func SetCoredumpFilter(coredumpFilter string) error {
 	coredumpFilterFile := "/proc/self/coredump_filter"
 	return os.WriteFile(coredumpFilterFile, []byte(coredumpFilter), 0o600)
 }
+
+// Sync commits the filesystem caches to disk
+func Sync() {
+	syscall.Sync()
+}
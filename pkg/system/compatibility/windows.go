@@ -27,3 +27,6 @@ package compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// Sync for Windows compatibility
+func Sync() {}
@@ -106,6 +106,12 @@ const (
 	// IsManagedLabelName is the name of the label used to indicate a '.spec.managed' resource
 	IsManagedLabelName = MetadataNamespace + "/isManaged"
 
+	// DeclarativeReferenceLabelName is the name of the label allowing a
+	// secret or a config map to be referenced by the Database and Role objects,
+	// whose content is read by the instance manager. It must be set
+	// to `true`
+	DeclarativeReferenceLabelName = MetadataNamespace + "/declarativeReference"
+
 	// PluginNameLabelName is the name of the label to be applied to services
 	// to have them detected as CNPG-i plugins
 	PluginNameLabelName = MetadataNamespace + "/pluginName"
@@ -264,6 +270,25 @@ const (
 	// This feature enables quorum-based check before failover, ensuring
 	// no data loss at the expense of availability.
 	FailoverQuorumAnnotationName = AlphaMetadataNamespace + "/failoverQuorum"
+
+	// SubscriptionSyncSequencesAnnotationName is the name of the annotation
+	// used to request the synchronization of the sequences of a subscription.
+	// Every time its value changes, a new synchronization is performed
+	SubscriptionSyncSequencesAnnotationName = MetadataNamespace + "/syncSequences"
+
+	// CompleteOnlineImportAnnotationName is the name of the annotation
+	// used to request the cutover of an online import. When it is set on
+	// the cluster, the primary synchronizes the sequences and drops the
+	// subscriptions, together with the publications in the source
+	CompleteOnlineImportAnnotationName = MetadataNamespace + "/completeOnlineImport"
+
+	// FenceClientConnectionsAnnotationName is the name of the annotation
+	// used to reject the connections of the client applications to a
+	// cluster, which only accepts the ones of the superuser and of the
+	// replicas. When it is set, the primary terminates the existing client
+	// connections and reports the write-ahead log location reached after
+	// them. It is set by the cutover phase of a blue-green upgrade
+	FenceClientConnectionsAnnotationName = MetadataNamespace + "/fenceClientConnections"
 )
 
 type annotationStatus string
@@ -456,6 +481,12 @@ func AnnotateAppArmor(object *metav1.ObjectMeta, spec *corev1.PodSpec, annotatio
 	}
 }
 
+// IsDeclarativeReferenceAllowed checks if the given secret or config map
+// can be referenced by the Database and Role objects
+func IsDeclarativeReferenceAllowed(object metav1.Object) bool {
+	return object.GetLabels()[DeclarativeReferenceLabelName] == "true"
+}
+
 // IsReconciliationDisabled checks if the reconciliation loop is disabled on the given resource
 func IsReconciliationDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[ReconciliationLoopAnnotationName] == string(annotationStatusDisabled)
@@ -64,6 +64,9 @@ const (
 
 	// pgControlDataBytesPerWALSegment reports the size of the WAL segments
 	pgControlDataBytesPerWALSegment pgControlDataKey = "Bytes per WAL segment"
+
+	// pgControlDataCatalogVersionNumber is the version of the system catalogs
+	pgControlDataCatalogVersionNumber pgControlDataKey = "Catalog version number"
 )
 
 // PgControlData represents the parsed output of pg_controldata
@@ -120,6 +123,15 @@ func (p PgControlData) GetDataPageChecksumVersion() (string, error) {
 	return value, nil
 }
 
+// GetCatalogVersionNumber returns the version of the system catalogs
+func (p PgControlData) GetCatalogVersionNumber() (string, error) {
+	value, ok := p[pgControlDataCatalogVersionNumber]
+	if !ok {
+		return "", fmt.Errorf("no '%s' section in pg_controldata output", pgControlDataCatalogVersionNumber)
+	}
+	return value, nil
+}
+
 // GetBytesPerWALSegment returns the size of the WAL segments
 func (p PgControlData) GetBytesPerWALSegment() (int, error) {
 	value, ok := p[pgControlDataBytesPerWALSegment]
@@ -98,6 +98,15 @@ var _ = Describe("pg_controldata output parser", func() {
 		output := ParsePgControldataOutput("")
 		Expect(output).To(BeEmpty())
 	})
+
+	It("extracts the catalog version number", func() {
+		catalogVersion, err := ParsePgControldataOutput(fakeControlData).GetCatalogVersionNumber()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(catalogVersion).To(Equal("202201241"))
+
+		_, err = ParsePgControldataOutput("").GetCatalogVersionNumber()
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("promotion token creation", func() {